@@ -0,0 +1,205 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// idBaseFlag is handled by this wrapper, all other flags are passed to the generator as they are
+const idBaseFlag = "idBase"
+
+// generatorValueFlags are the generator flags taking a value, i.e. those not to be confused with the source path
+var generatorValueFlags = map[string]bool{"out": true, "model": true, "persist": true}
+
+// extractIdBase removes the -idBase flag from the given arguments and returns its value (0 if not given)
+func extractIdBase(args []string) (idBase uint64, rest []string, err error) {
+	for i := 0; i < len(args); i++ {
+		var name, value, hasValue = splitFlag(args[i])
+		if name != idBaseFlag {
+			rest = append(rest, args[i])
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return 0, nil, errors.New("flag needs an argument: -" + idBaseFlag)
+			}
+			i++
+			value = args[i]
+		}
+
+		if idBase, err = strconv.ParseUint(value, 10, 32); err != nil || idBase == 0 {
+			return 0, nil, fmt.Errorf("invalid value %q for flag -%s: expecting a positive number", value, idBaseFlag)
+		}
+	}
+	return idBase, rest, nil
+}
+
+// splitFlag parses "-name", "--name" and "-name=value" arguments; name is empty if arg isn't a flag
+func splitFlag(arg string) (name, value string, hasValue bool) {
+	if len(arg) < 2 || arg[0] != '-' {
+		return "", "", false
+	}
+	name = strings.TrimPrefix(arg[1:], "-")
+	if pos := strings.Index(name, "="); pos >= 0 {
+		return name[:pos], name[pos+1:], true
+	}
+	return name, "", false
+}
+
+// modelFilePath determines the model JSON file the generator would use for the given (generator) arguments
+func modelFilePath(args []string) string {
+	var source string
+	for i := 0; i < len(args); i++ {
+		var name, value, hasValue = splitFlag(args[i])
+		if name == "" {
+			if source == "" {
+				source = args[i]
+			}
+			continue
+		}
+
+		if generatorValueFlags[name] && !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		if (name == "model" || name == "persist") && value != "" {
+			return value
+		}
+	}
+
+	if source == "" {
+		source = os.Getenv("GOFILE")
+	}
+	source = strings.TrimSuffix(source, "/...")
+
+	var dir = source
+	if info, err := os.Stat(source); err != nil || !info.IsDir() {
+		dir = filepath.Dir(source)
+	}
+	return filepath.Join(dir, "objectbox-model.json")
+}
+
+// idBasePlaceholder is the name of the entity temporarily added to a new model file to make the generator allocate
+// entity IDs starting at the requested base; the generator always assigns ID 1 to the first entity of an empty model.
+const idBasePlaceholder = "ObjectBoxIdBasePlaceholder"
+
+// generateWithIdBase runs the generator so that a newly created model file allocates entity, index and relation IDs
+// starting at idBase, e.g. to keep the IDs of several packages distinct for objectbox.Builder.MergeModels().
+// An existing model file is left as it is - IDs that have already been assigned can't be changed.
+func generateWithIdBase(args []string, idBase uint64) error {
+	var path = modelFilePath(args)
+	if _, err := os.Stat(path); err == nil || idBase <= 1 {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	// the generator assigns "last ID + 1" to new elements: let the last IDs reference a placeholder entity...
+	var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	var entityUid = uint64(random.Int63n(1<<62) + 1)
+	var propertyUid = entityUid + 1
+	var uid = entityUid + 2
+	var model = map[string]interface{}{
+		"_note1": "KEEP THIS FILE! Check it into a version control system (VCS) like git.",
+		"_note2": "ObjectBox manages crucial IDs for your object model. See docs for details.",
+		"_note3": "If you have VCS merge conflicts, you must resolve them according to ObjectBox docs.",
+		"entities": []interface{}{map[string]interface{}{
+			"id":             fmt.Sprintf("%d:%d", idBase-1, entityUid),
+			"lastPropertyId": fmt.Sprintf("1:%d", propertyUid),
+			"name":           idBasePlaceholder,
+			"properties": []interface{}{map[string]interface{}{
+				"id":    fmt.Sprintf("1:%d", propertyUid),
+				"name":  "Id",
+				"type":  6,
+				"flags": 1,
+			}},
+		}},
+		"lastEntityId":              fmt.Sprintf("%d:%d", idBase-1, entityUid),
+		"lastIndexId":               fmt.Sprintf("%d:%d", idBase-1, uid),
+		"lastRelationId":            fmt.Sprintf("%d:%d", idBase-1, uid),
+		"modelVersion":              5,
+		"modelVersionParserMinimum": 5,
+		"retiredEntityUids":         []uint64{},
+		"retiredIndexUids":          []uint64{uid},
+		"retiredPropertyUids":       []uint64{},
+		"retiredRelationUids":       []uint64{uid},
+		"version":                   1,
+	}
+	if err := writeJSON(path, model); err != nil {
+		return err
+	}
+
+	// ... let the generator create the actual entities in a separate process...
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	var cmd = exec.Command(executable, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("generator failed: %s", err)
+	}
+
+	// ... and drop (retire) the placeholder again; the caller runs the generator once more to update the code
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keep UIDs exact, float64 can't represent all of them
+	if err := decoder.Decode(&model); err != nil {
+		return fmt.Errorf("can't parse %s: %s", path, err)
+	}
+
+	var entities []interface{}
+	for _, entity := range model["entities"].([]interface{}) {
+		if entity.(map[string]interface{})["name"] != idBasePlaceholder {
+			entities = append(entities, entity)
+		}
+	}
+	if len(entities) == 0 {
+		os.Remove(path)
+		return errors.New("no entities found")
+	}
+	model["entities"] = entities
+	model["retiredEntityUids"] = append(model["retiredEntityUids"].([]interface{}), entityUid)
+	model["retiredPropertyUids"] = append(model["retiredPropertyUids"].([]interface{}), propertyUid)
+	return writeJSON(path, model)
+}
+
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -31,6 +31,12 @@ or
 	objectbox-gogen clean {path}
 		to remove the generated files instead of creating them - this removes *.obx.go and objectbox-model.go but keeps objectbox-model.json
 
+or
+
+	objectbox-gogen report [-model {model-file}] {path}
+		to print a JSON report mapping entities/properties to their IDs/UIDs and the differences between the committed
+		objectbox-model.json and the current source code; exits with status 1 on a difference so it can be used in CI
+
 path:
   * a source file path or a valid path pattern as accepted by the go tool (e.g. ./...)
   * if not given, the generator expects GOFILE environment variable to be set
@@ -40,6 +46,9 @@ Available flags:
     	getters should return a struct value (a copy) instead of a struct pointer
   -help
     	print this help
+  -idBase uint
+    	the first entity/index/relation ID to allocate when creating a new model JSON file, e.g. 1000 - use distinct
+    	ranges for packages whose models are combined by objectbox.Builder.MergeModels(); ignored for existing files
   -out string
     	output path for generated source files
   -persist string
@@ -53,9 +62,28 @@ To learn more about different configuration and annotations for entities, see do
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/objectbox/objectbox-generator/cmd/objectbox-gogen"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == reportCommand {
+		os.Exit(runReport(os.Args[2:]))
+	}
+
+	idBase, args, err := extractIdBase(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if idBase > 0 {
+		if err := generateWithIdBase(args, idBase); err != nil {
+			fmt.Fprintf(os.Stderr, "can't create the model with -%s: %s\n", idBaseFlag, err)
+			os.Exit(2)
+		}
+		os.Args = append(os.Args[:1], args...)
+	}
 	gogen.Main()
 }
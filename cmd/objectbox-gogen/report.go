@@ -0,0 +1,321 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// reportCommand is the name of the subcommand printing the ID/UID mapping and model drift report
+const reportCommand = "report"
+
+const reportUsage = `Usage:
+	objectbox-gogen report [flags] {path}
+		to print a JSON report mapping entities and properties to their IDs/UIDs and listing differences
+		("drift") between the committed model JSON file and the model the current source code would produce.
+		The committed files are left untouched; exits with status 1 if a drift is detected.
+
+	path is a source file or a package directory; for a directory, all files with an objectbox-gogen go:generate
+	directive are processed
+
+Available flags:
+`
+
+// modelJSON mirrors the parts of objectbox-model.json relevant for the report
+type modelJSON struct {
+	Entities       []*entityJSON `json:"entities"`
+	LastEntityId   string        `json:"lastEntityId"`
+	LastIndexId    string        `json:"lastIndexId"`
+	LastRelationId string        `json:"lastRelationId"`
+}
+
+type entityJSON struct {
+	Id             string          `json:"id"`
+	LastPropertyId string          `json:"lastPropertyId"`
+	Name           string          `json:"name"`
+	Properties     []*propertyJSON `json:"properties"`
+	Relations      []*relationJSON `json:"relations,omitempty"`
+}
+
+type propertyJSON struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	Type           int    `json:"type"`
+	Flags          int    `json:"flags,omitempty"`
+	IndexId        string `json:"indexId,omitempty"`
+	RelationTarget string `json:"relationTarget,omitempty"`
+}
+
+type relationJSON struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	TargetId string `json:"targetId"`
+}
+
+// report is printed to the standard output as JSON
+type report struct {
+	Model    string        `json:"model"`
+	InSync   bool          `json:"inSync"`
+	Entities []*entityJSON `json:"entities"`
+	Drift    []drift       `json:"drift"`
+}
+
+// drift describes a single difference between the committed and the current model
+type drift struct {
+	Kind      string `json:"kind"`
+	Entity    string `json:"entity,omitempty"`
+	Element   string `json:"element,omitempty"`
+	Committed string `json:"committed,omitempty"`
+	Current   string `json:"current,omitempty"`
+}
+
+// runReport executes the "report" subcommand and returns the process exit code
+func runReport(args []string) int {
+	var flags = flag.NewFlagSet(reportCommand, flag.ContinueOnError)
+	var modelFile = flags.String("model", "", "path to the model information persistence file (JSON); "+
+		"defaults to objectbox-model.json in the source directory")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), reportUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var source = flags.Arg(0)
+	if source == "" {
+		source = os.Getenv("GOFILE")
+	}
+	// patterns like "./..." aren't supported, the report is created for a single package (directory)
+	source = strings.TrimSuffix(source, "/...")
+	if source == "" {
+		flags.Usage()
+		return 2
+	}
+
+	if *modelFile == "" {
+		var dir = source
+		if info, err := os.Stat(source); err != nil || !info.IsDir() {
+			dir = filepath.Dir(source)
+		}
+		*modelFile = filepath.Join(dir, "objectbox-model.json")
+	}
+
+	result, err := createReport(source, *modelFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't create the report: %s\n", err)
+		return 2
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't create the report: %s\n", err)
+		return 2
+	}
+	fmt.Println(string(data))
+
+	if !result.InSync {
+		return 1
+	}
+	return 0
+}
+
+func createReport(source, modelFile string) (*report, error) {
+	committedData, err := ioutil.ReadFile(modelFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var committed modelJSON
+	if err = json.Unmarshal(committedData, &committed); err != nil {
+		return nil, fmt.Errorf("can't parse %s: %s", modelFile, err)
+	}
+
+	// run the generator on a copy of the model file, writing the generated code to a temporary directory
+	tempDir, err := ioutil.TempDir("", "objectbox-gogen-report")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	var tempModelFile = filepath.Join(tempDir, "objectbox-model.json")
+	if err = ioutil.WriteFile(tempModelFile, committedData, 0644); err != nil {
+		return nil, err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := reportSources(source)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range sources {
+		var cmd = exec.Command(executable, "-model", tempModelFile, "-out", tempDir, file)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("generator failed for %s: %s\n%s", file, err, output)
+		}
+	}
+
+	currentData, err := ioutil.ReadFile(tempModelFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var current modelJSON
+	if err = json.Unmarshal(currentData, &current); err != nil {
+		return nil, fmt.Errorf("can't parse the generated model: %s", err)
+	}
+
+	var result = &report{
+		Model:    modelFile,
+		Entities: current.Entities,
+		Drift:    modelDrift(&committed, &current),
+	}
+	result.InSync = len(result.Drift) == 0
+	return result, nil
+}
+
+// reportSources returns the files to run the generator on: the given file or, for a directory, all files in it
+// containing a `//go:generate` directive calling objectbox-gogen, i.e. the files `go generate` would process.
+func reportSources(source string) ([]string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return []string{source}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(source, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, file := range files {
+		if strings.HasSuffix(file, ".obx.go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			if strings.HasPrefix(line, "//go:generate ") && strings.Contains(line, "objectbox-gogen") {
+				result = append(result, file)
+				break
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no files with a go:generate directive calling objectbox-gogen found in %s", source)
+	}
+	return result, nil
+}
+
+// modelDrift lists all differences between the two models, matching entities and properties by name
+func modelDrift(committed, current *modelJSON) []drift {
+	var result = []drift{}
+	var compare = func(kind, entity, element, committedValue, currentValue string) {
+		if committedValue != currentValue {
+			result = append(result, drift{kind, entity, element, committedValue, currentValue})
+		}
+	}
+
+	compare("lastEntityId", "", "", committed.LastEntityId, current.LastEntityId)
+	compare("lastIndexId", "", "", committed.LastIndexId, current.LastIndexId)
+	compare("lastRelationId", "", "", committed.LastRelationId, current.LastRelationId)
+
+	var committedEntities = make(map[string]*entityJSON)
+	for _, e := range committed.Entities {
+		committedEntities[e.Name] = e
+	}
+
+	for _, e := range current.Entities {
+		var old = committedEntities[e.Name]
+		if old == nil {
+			result = append(result, drift{Kind: "entityAdded", Entity: e.Name, Current: e.Id})
+			continue
+		}
+		delete(committedEntities, e.Name)
+
+		compare("entityId", e.Name, "", old.Id, e.Id)
+		compare("lastPropertyId", e.Name, "", old.LastPropertyId, e.LastPropertyId)
+
+		var oldProperties = make(map[string]*propertyJSON)
+		for _, p := range old.Properties {
+			oldProperties[p.Name] = p
+		}
+		for _, p := range e.Properties {
+			var oldP = oldProperties[p.Name]
+			if oldP == nil {
+				result = append(result, drift{Kind: "propertyAdded", Entity: e.Name, Element: p.Name, Current: p.Id})
+				continue
+			}
+			delete(oldProperties, p.Name)
+			compare("propertyId", e.Name, p.Name, oldP.Id, p.Id)
+			compare("propertyType", e.Name, p.Name, fmt.Sprint(oldP.Type), fmt.Sprint(p.Type))
+			compare("propertyFlags", e.Name, p.Name, fmt.Sprint(oldP.Flags), fmt.Sprint(p.Flags))
+			compare("indexId", e.Name, p.Name, oldP.IndexId, p.IndexId)
+			compare("relationTarget", e.Name, p.Name, oldP.RelationTarget, p.RelationTarget)
+		}
+		for _, p := range old.Properties {
+			if oldProperties[p.Name] != nil {
+				result = append(result, drift{Kind: "propertyRemoved", Entity: e.Name, Element: p.Name, Committed: p.Id})
+			}
+		}
+
+		var oldRelations = make(map[string]*relationJSON)
+		for _, r := range old.Relations {
+			oldRelations[r.Name] = r
+		}
+		for _, r := range e.Relations {
+			var oldR = oldRelations[r.Name]
+			if oldR == nil {
+				result = append(result, drift{Kind: "relationAdded", Entity: e.Name, Element: r.Name, Current: r.Id})
+				continue
+			}
+			delete(oldRelations, r.Name)
+			compare("relationId", e.Name, r.Name, oldR.Id, r.Id)
+			compare("relationTargetId", e.Name, r.Name, oldR.TargetId, r.TargetId)
+		}
+		for _, r := range old.Relations {
+			if oldRelations[r.Name] != nil {
+				result = append(result, drift{Kind: "relationRemoved", Entity: e.Name, Element: r.Name, Committed: r.Id})
+			}
+		}
+	}
+
+	for _, e := range committed.Entities {
+		if committedEntities[e.Name] != nil {
+			result = append(result, drift{Kind: "entityRemoved", Entity: e.Name, Committed: e.Id})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"fmt"
+	"time"
+)
+
+// OperationKind identifies the type of an Operation passed to Box.ApplyBatch()
+type OperationKind string
+
+const (
+	// OperationPut inserts or updates the object, see Box.Put()
+	OperationPut OperationKind = "put"
+
+	// OperationUpdate updates the object, failing if it doesn't exist, see Box.Update()
+	OperationUpdate OperationKind = "update"
+
+	// OperationRemove removes the object with the given ID (or the ID of the given object); an object that doesn't
+	// exist (anymore) is not an error
+	OperationRemove OperationKind = "remove"
+)
+
+// Operation is a single write executed by Box.ApplyBatch()
+type Operation struct {
+	Kind   OperationKind
+	Object interface{} // the object to put or update; for removals, used to read the ID if Id is 0
+	Id     uint64      // the ID of the object to remove
+}
+
+// OperationError is returned by Box.ApplyBatch() if an operation failed; none of the operations were applied
+type OperationError struct {
+	Index     int // of the failed operation in the batch
+	Operation Operation
+	Err       error
+}
+
+func (err *OperationError) Error() string {
+	return fmt.Sprintf("operation %d (%s) failed: %s", err.Index, err.Operation.Kind, err.Err)
+}
+
+// Unwrap returns the error of the failed operation
+func (err *OperationError) Unwrap() error {
+	return err.Err
+}
+
+// ApplyBatch executes the given puts, updates and removals in the given order in a single write transaction, e.g. to
+// apply a batch of changes received from a remote system atomically. Returns the ID of each operation's object; for
+// puts of new objects, it's the newly assigned ID (which is also set on the object).
+// If any operation fails, the transaction is rolled back and an *OperationError identifying the operation is returned.
+// Note: IDs assigned to new objects by preceding puts stay set on the objects even if the batch was rolled back.
+func (box *Box) ApplyBatch(ops []Operation) (ids []uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.ApplyBatch", time.Now(), &err)
+	}
+
+	if err := box.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	ids = make([]uint64, len(ops))
+	err = box.ObjectBox.RunInWriteTx(func() error {
+		for i, op := range ops {
+			var err error
+			if ids[i], err = box.apply(op); err != nil {
+				return &OperationError{Index: i, Operation: op, Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (box *Box) apply(op Operation) (id uint64, err error) {
+	if op.Object == nil && op.Kind != OperationRemove {
+		return 0, fmt.Errorf("no object given")
+	}
+
+	switch op.Kind {
+	case OperationPut:
+		return box.put(op.Object, true, cPutModePut)
+	case OperationUpdate:
+		return box.put(op.Object, true, cPutModeUpdate)
+	case OperationRemove:
+		if id = op.Id; id == 0 {
+			if op.Object == nil {
+				return 0, fmt.Errorf("neither an ID nor an object given")
+			}
+			if id, err = box.entity.binding.GetId(op.Object); err != nil {
+				return 0, err
+			}
+		}
+		_, err = box.RemoveIds(id)
+		return id, err
+	}
+	return 0, fmt.Errorf("unknown operation kind %q", op.Kind)
+}
@@ -23,6 +23,10 @@ package objectbox
 import "C"
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -44,16 +48,32 @@ import (
 // There is a small time window in which the data may not have been committed durably yet.
 type AsyncBox struct {
 	box    *Box
-	cAsync *C.OBX_async
-	cOwned bool // whether the cAsync resource is owned by this struct
+	cAsync *C.OBX_async // only set if owned, i.e. created by NewAsyncBox(); see handle()
+	cOwned bool         // whether the cAsync resource is owned by this struct
+
+	// AsyncErrorListener, see SetErrorListener()
+	errorListener atomic.Value
 }
 
+// AsyncErrorListener receives errors of asynchronous operations, see AsyncBox.SetErrorListener().
+// The id is the ID of the affected object or 0 if the failed operation isn't known.
+type AsyncErrorListener func(err error, entityId TypeId, id uint64)
+
 // NewAsyncBox creates a new async box with the given operation timeout in case an async queue is full.
 // The returned struct must be freed explicitly using the Close() method.
 // It's usually preferable to use Box::Async() which takes care of resource management and doesn't require closing.
 func NewAsyncBox(ob *ObjectBox, entityId TypeId, timeoutMs uint64) (*AsyncBox, error) {
+	if err := ob.enter(); err != nil {
+		return nil, err
+	}
+	defer ob.leave()
+
+	box, err := ob.BoxOrError(entityId)
+	if err != nil {
+		return nil, err
+	}
 	var async = &AsyncBox{
-		box:    ob.InternalBox(entityId),
+		box:    box,
 		cOwned: true,
 	}
 
@@ -64,6 +84,14 @@ func NewAsyncBox(ob *ObjectBox, entityId TypeId, timeoutMs uint64) (*AsyncBox, e
 		return nil, err
 	}
 
+	// tracked so that it can be closed together with the store if the user doesn't close it
+	ob.boxesMutex.Lock()
+	if ob.asyncBoxes == nil {
+		ob.asyncBoxes = make(map[*AsyncBox]bool)
+	}
+	ob.asyncBoxes[async] = true
+	ob.boxesMutex.Unlock()
+
 	return async, nil
 }
 
@@ -71,9 +99,18 @@ func NewAsyncBox(ob *ObjectBox, entityId TypeId, timeoutMs uint64) (*AsyncBox, e
 // Not necessary for the standard (shared) instance from box.Async(); Close() can still be called for those:
 // it just won't have any effect.
 func (async *AsyncBox) Close() error {
-	if !async.cOwned || async.cAsync == nil {
+	if !async.cOwned {
 		return nil
 	}
+
+	var ob = async.box.ObjectBox
+	ob.boxesMutex.Lock()
+	defer ob.boxesMutex.Unlock()
+
+	if !ob.asyncBoxes[async] {
+		return nil // already closed, possibly by closing the store
+	}
+	delete(ob.asyncBoxes, async)
 	var cAsync = async.cAsync
 	async.cAsync = nil
 	return cCall(func() C.obx_err {
@@ -81,16 +118,29 @@ func (async *AsyncBox) Close() error {
 	})
 }
 
+// handle returns the native async queue: the owned one or the one shared by the boxes of the entity.
+// Must only be called between ObjectBox.enter() and leave().
+func (async *AsyncBox) handle() *C.OBX_async {
+	if async.cOwned {
+		return async.cAsync
+	}
+	return async.box.cAsync
+}
+
 func (async *AsyncBox) put(object interface{}, mode int) (uint64, error) {
+	if err := async.box.ObjectBox.enterAccepting(); err != nil {
+		return 0, err
+	}
+	defer async.box.ObjectBox.leave()
+
 	entity := async.box.entity
 	idFromObject, err := entity.binding.GetId(object)
 	if err != nil {
 		return 0, err
 	}
 
-	if entity.hasRelations {
-		return 0, errors.New("asynchronous Put/Insert/Update is currently not supported on entities that have" +
-			" relations because it could result in partial inserts/broken relations")
+	if err := async.checkPutSupported(); err != nil {
+		return 0, err
 	}
 
 	id, err := async.box.idForPut(idFromObject)
@@ -98,15 +148,8 @@ func (async *AsyncBox) put(object interface{}, mode int) (uint64, error) {
 		return 0, err
 	}
 
-	err = async.box.withObjectBytes(object, id, func(bytes []byte) error {
-		return cCall(func() C.obx_err {
-			return C.obx_async_put5(async.cAsync, C.obx_id(id), unsafe.Pointer(&bytes[0]), C.size_t(len(bytes)),
-				C.OBXPutMode(mode))
-		})
-	})
-
-	if err != nil {
-		return 0, err
+	if err := async.enqueuePut(object, id, mode); err != nil {
+		return 0, async.failed(err, id)
 	}
 
 	// update the id on the object
@@ -117,6 +160,29 @@ func (async *AsyncBox) put(object interface{}, mode int) (uint64, error) {
 	return id, nil
 }
 
+// checkPutSupported rejects puts of entities that need to be checked or updated in the put's transaction
+func (async *AsyncBox) checkPutSupported() error {
+	if async.box.entity.hasRelations {
+		return errors.New("asynchronous Put/Insert/Update is currently not supported on entities that have" +
+			" relations because it could result in partial inserts/broken relations")
+	}
+	if async.box.entity.version != nil {
+		return errors.New("asynchronous Put/Insert/Update is not supported on entities with a version property " +
+			"because the version can't be checked")
+	}
+	return nil
+}
+
+// enqueuePut flattens the object with the given (already assigned) ID and submits it to the async queue
+func (async *AsyncBox) enqueuePut(object interface{}, id uint64, mode int) error {
+	return async.box.withObjectBytes(object, id, func(bytes []byte) error {
+		return cCall(func() C.obx_err {
+			return C.obx_async_put5(async.handle(), C.obx_id(id), unsafe.Pointer(&bytes[0]), C.size_t(len(bytes)),
+				C.OBXPutMode(mode))
+		})
+	})
+}
+
 // Put inserts/updates a single object asynchronously.
 // When inserting a new object, the ID property on the passed object will be assigned a new ID the entity would hold
 // if the insert is ultimately successful. The newly assigned ID may not become valid if the insert fails.
@@ -151,25 +217,198 @@ func (async *AsyncBox) Remove(object interface{}) error {
 
 // RemoveId deletes a single object asynchronously.
 func (async *AsyncBox) RemoveId(id uint64) error {
-	return cCall(func() C.obx_err {
-		return C.obx_async_remove(async.cAsync, C.obx_id(id))
-	})
+	if err := async.box.ObjectBox.enterAccepting(); err != nil {
+		return err
+	}
+	defer async.box.ObjectBox.leave()
+
+	if async.box.entity.softDelete != nil {
+		return errors.New("asynchronous Remove is not supported on entities in the soft-delete mode " +
+			"because it would delete the objects permanently")
+	}
+
+	if err := cCall(func() C.obx_err {
+		return C.obx_async_remove(async.handle(), C.obx_id(id))
+	}); err != nil {
+		return async.failed(err, id)
+	}
+	return nil
+}
+
+// PutMany inserts/updates multiple objects asynchronously.
+// The given argument must be a slice of the object type this AsyncBox represents (pointers to objects).
+// IDs for new objects are reserved in chunks (like Box.PutMany does) and assigned to the objects right away;
+// they may not become valid if the asynchronous put ultimately fails.
+//
+// Returns: IDs of the put objects (in the same order).
+//
+// Note: the objects are submitted one by one, in separate async operations. If an error occurs, objects submitted
+// before the failure are not withdrawn from the queue; their IDs are returned together with the error and already
+// set on the objects.
+func (async *AsyncBox) PutMany(objects interface{}) (ids []uint64, err error) {
+	if err := async.box.ObjectBox.enterAccepting(); err != nil {
+		return nil, err
+	}
+	defer async.box.ObjectBox.leave()
+
+	if err := async.checkPutSupported(); err != nil {
+		return nil, err
+	}
+
+	var slice = reflect.ValueOf(objects)
+	var count = slice.Len()
+	if count == 0 {
+		return []uint64{}, nil
+	}
+
+	ids = make([]uint64, count)
+
+	// 10k is the limit currently enforced by obx_box_ids_for_put, see Box.PutMany()
+	const chunkSize = 10000
+	for start := 0; start < count; start += chunkSize {
+		var end = start + chunkSize
+		if end > count {
+			end = count
+		}
+
+		if enqueued, err := async.putManyObjects(slice, ids, start, end); err != nil {
+			return ids[:start+enqueued], err
+		}
+	}
+
+	return ids, nil
+}
+
+// putManyObjects enqueues a subset of objects, setting their IDs as an outArgument and on each new object as soon as
+// it's enqueued. Returns the number of objects enqueued, also in case of an error.
+func (async *AsyncBox) putManyObjects(objects reflect.Value, outIds []uint64, start, end int) (int, error) {
+	var binding = async.box.entity.binding
+
+	// indexes of new objects (zero IDs) in the `outIds` slice
+	var indexesNewObjects = make([]int, 0)
+
+	for index := start; index < end; index++ {
+		if id, err := binding.GetId(objects.Index(index).Interface()); err != nil {
+			return 0, err
+		} else if id > 0 {
+			outIds[index] = id
+		} else {
+			indexesNewObjects = append(indexesNewObjects, index)
+		}
+	}
+
+	// reserve IDs for all new objects of this chunk at once
+	firstNewId, err := async.box.idsForPut(len(indexesNewObjects))
+	if err != nil {
+		return 0, err
+	}
+	var enqueued = 0
+	var isNew = make(map[int]bool, len(indexesNewObjects))
+	for i, index := range indexesNewObjects {
+		outIds[index] = firstNewId + uint64(i)
+		isNew[index] = true
+	}
+
+	for index := start; index < end; index++ {
+		var object = objects.Index(index).Interface()
+		if err := async.enqueuePut(object, outIds[index], cPutModePut); err != nil {
+			return enqueued, async.failed(err, outIds[index])
+		}
+		enqueued++
+
+		if isNew[index] {
+			if err := binding.SetId(object, outIds[index]); err != nil {
+				return enqueued, fmt.Errorf("setting ID on objects[%v] failed: %s", index, err)
+			}
+		}
+	}
+
+	return enqueued, nil
+}
+
+// RemoveIds deletes multiple objects asynchronously.
+// Note that, like with RemoveId, a missing object is not reported as an error.
+func (async *AsyncBox) RemoveIds(ids ...uint64) error {
+	for _, id := range ids {
+		if err := async.RemoveId(id); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // AwaitCompletion waits for all (including future) async submissions to be completed (the async queue becomes idle for
 // a moment). Currently this is not limited to the single entity this AsyncBox is working on but all entities in the
 // store. Returns an error if shutting down or an error occurred
 func (async *AsyncBox) AwaitCompletion() error {
-	return cCallBool(func() bool {
-		return bool(C.obx_store_await_async_completion(async.box.ObjectBox.store))
-	})
+	cStore, err := async.box.ObjectBox.enterStore()
+	if err != nil {
+		return err
+	}
+	defer async.box.ObjectBox.leave()
+	if err := cCallBool(func() bool {
+		return bool(C.obx_store_await_async_completion(cStore))
+	}); err != nil {
+		return async.failed(err, 0)
+	}
+	return nil
 }
 
 // AwaitSubmitted for previously submitted async operations to be completed (the async queue does not have to become idle).
 // Currently this is not limited to the single entity this AsyncBox is working on but all entities in the store.
 // Returns an error if shutting down or an error occurred
 func (async *AsyncBox) AwaitSubmitted() error {
-	return cCallBool(func() bool {
-		return bool(C.obx_store_await_async_submitted(async.box.ObjectBox.store))
-	})
+	cStore, err := async.box.ObjectBox.enterStore()
+	if err != nil {
+		return err
+	}
+	defer async.box.ObjectBox.leave()
+	if err := cCallBool(func() bool {
+		return bool(C.obx_store_await_async_submitted(cStore))
+	}); err != nil {
+		return async.failed(err, 0)
+	}
+	return nil
+}
+
+// PutWithTimeout is like Put() but waits at most the given time (with millisecond precision) for a free slot if the
+// async queue is full, instead of the timeout this AsyncBox was created with.
+// Note: this creates a temporary AsyncBox so prefer NewAsyncBox() if many objects are put with the same timeout.
+func (async *AsyncBox) PutWithTimeout(object interface{}, timeout time.Duration) (id uint64, err error) {
+	custom, err := NewAsyncBox(async.box.ObjectBox, async.box.entity.id, uint64(timeout/time.Millisecond))
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if closeErr := custom.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if listener, _ := async.errorListener.Load().(AsyncErrorListener); listener != nil {
+		custom.errorListener.Store(listener)
+	}
+	return custom.put(object, cPutModePut)
+}
+
+// SetErrorListener sets a function receiving the errors of asynchronous operations of this AsyncBox, e.g. when an
+// object can't be enqueued because the queue is full, and errors reported by AwaitCompletion() and AwaitSubmitted(),
+// e.g. when the store has reached its maximum size (see Builder.MaxSizeInKb()) while processing the queue.
+// The errors are still returned by the respective methods as well; the listener allows to handle them in one place.
+// The listener is called synchronously by the goroutine executing the operation; pass nil to remove it.
+//
+// Note: the native async queue doesn't report failures of individual operations after they were enqueued; such
+// failures are only reported (without the object ID) by the next AwaitCompletion() or AwaitSubmitted() call.
+func (async *AsyncBox) SetErrorListener(listener AsyncErrorListener) {
+	async.errorListener.Store(listener)
+}
+
+// failed notifies the error listener or, if there's none, the logger (if any) and returns the given error
+func (async *AsyncBox) failed(err error, id uint64) error {
+	if listener, _ := async.errorListener.Load().(AsyncErrorListener); listener != nil {
+		listener(err, async.box.entity.id, id)
+	} else if logger := getLogger(); logger != nil {
+		logger.Error("objectbox: async operation failed", "entity", async.box.entity.name, "id", id, "error", err)
+	}
+	return err
 }
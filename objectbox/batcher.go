@@ -0,0 +1,191 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+
+// checks each of the given IDs in a single cgo call
+static obx_err obx_go_box_contains_each(OBX_box* box, const obx_id* ids, bool* out_contains, size_t count) {
+	for (size_t i = 0; i < count; i++) {
+		obx_err err = obx_box_contains(box, ids[i], &out_contains[i]);
+		if (err != OBX_SUCCESS) return err;
+	}
+	return OBX_SUCCESS;
+}
+*/
+import "C"
+
+import (
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// maxReadBatchSize limits the number of requests collected in a single batch; a full batch is executed immediately
+const maxReadBatchSize = 1000
+
+// readBatcher coalesces small reads (Box.Get, Box.Contains, Box.Count) issued concurrently during a short time window
+// into a single transaction and a minimal number of native calls, see Builder.BatchReads().
+type readBatcher struct {
+	box    *Box
+	window time.Duration
+	mutex  sync.Mutex
+	next   *readBatch // the batch currently collecting requests, if any
+}
+
+type readBatch struct {
+	getIds      []uint64
+	containsIds []uint64
+	count       bool
+
+	once sync.Once
+	done chan struct{}
+
+	// results, valid after done is closed
+	objects     []interface{}
+	contains    []C.bool
+	countResult uint64
+	err         error
+}
+
+func newReadBatcher(box *Box, window time.Duration) *readBatcher {
+	return &readBatcher{box: box, window: window}
+}
+
+// applicable returns false if the request must be executed directly, i.e. without batching. This is the case if the
+// caller is running inside a transaction: it must see its (uncommitted) state. Transactions of other goroutines don't
+// prevent batching.
+func (batcher *readBatcher) applicable() bool {
+	return supportsResultArray && !inTx()
+}
+
+// enqueue adds a request to the current batch (using the given function) and waits until the batch is executed
+func (batcher *readBatcher) enqueue(add func(batch *readBatch) int) (*readBatch, int) {
+	batcher.mutex.Lock()
+	var batch = batcher.next
+	if batch == nil {
+		batch = &readBatch{done: make(chan struct{})}
+		batcher.next = batch
+		time.AfterFunc(batcher.window, func() { batcher.execute(batch) })
+	}
+
+	var index = add(batch)
+	if len(batch.getIds)+len(batch.containsIds) >= maxReadBatchSize {
+		batcher.next = nil
+		go batcher.execute(batch)
+	}
+	batcher.mutex.Unlock()
+
+	<-batch.done
+	return batch, index
+}
+
+func (batcher *readBatcher) execute(batch *readBatch) {
+	batch.once.Do(func() {
+		batcher.mutex.Lock()
+		if batcher.next == batch {
+			batcher.next = nil
+		}
+		batcher.mutex.Unlock()
+
+		// reads done while loading the objects (e.g. eager relations) bypass batching and are part of this transaction
+		batch.err = batcher.box.ObjectBox.RunInReadTx(func() error {
+			return batcher.read(batch)
+		})
+		close(batch.done)
+	})
+}
+
+func (batcher *readBatcher) read(batch *readBatch) error {
+	var box = batcher.box
+
+	if len(batch.getIds) > 0 {
+		cIds, err := goIdsArrayToC(batch.getIds)
+		if err != nil {
+			return err
+		}
+		bytesArray, err := cGetBytesArray(func() *C.OBX_bytes_array {
+			defer cIds.free()
+			return C.obx_box_get_many(box.cBox, cIds.cArray)
+		})
+		if err != nil {
+			return err
+		}
+
+		batch.objects = make([]interface{}, len(bytesArray))
+		for i, bytesData := range bytesArray {
+			if bytesData != nil { // nil if not found
+				if batch.objects[i], err = box.entity.load(box.ObjectBox, bytesData); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(batch.containsIds) > 0 {
+		batch.contains = make([]C.bool, len(batch.containsIds))
+		if err := cCall(func() C.obx_err {
+			return C.obx_go_box_contains_each(box.cBox, (*C.obx_id)(unsafe.Pointer(&batch.containsIds[0])),
+				&batch.contains[0], C.size_t(len(batch.containsIds)))
+		}); err != nil {
+			return err
+		}
+	}
+
+	if batch.count {
+		var cResult C.uint64_t
+		if err := cCall(func() C.obx_err { return C.obx_box_count(box.cBox, 0, &cResult) }); err != nil {
+			return err
+		}
+		batch.countResult = uint64(cResult)
+	}
+
+	return nil
+}
+
+func (batcher *readBatcher) get(id uint64) (interface{}, error) {
+	batch, index := batcher.enqueue(func(batch *readBatch) int {
+		batch.getIds = append(batch.getIds, id)
+		return len(batch.getIds) - 1
+	})
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	return batch.objects[index], nil
+}
+
+func (batcher *readBatcher) contains(id uint64) (bool, error) {
+	batch, index := batcher.enqueue(func(batch *readBatch) int {
+		batch.containsIds = append(batch.containsIds, id)
+		return len(batch.containsIds) - 1
+	})
+	if batch.err != nil {
+		return false, batch.err
+	}
+	return bool(batch.contains[index]), nil
+}
+
+// count requests in the same batch share a single native call
+func (batcher *readBatcher) count() (uint64, error) {
+	batch, _ := batcher.enqueue(func(batch *readBatch) int {
+		batch.count = true
+		return 0
+	})
+	return batch.countResult, batch.err
+}
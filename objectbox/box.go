@@ -19,40 +19,75 @@ package objectbox
 /*
 #include <stdlib.h>
 #include "objectbox.h"
+
+// collects the relation target IDs of each of the given source IDs in a single cgo call; on error, no arrays are kept
+static obx_err obx_go_box_rel_get_ids_each(OBX_box* box, obx_schema_id relation_id, const obx_id* ids,
+										   OBX_id_array** out_arrays, size_t count) {
+	for (size_t i = 0; i < count; i++) {
+		out_arrays[i] = obx_box_rel_get_ids(box, relation_id, ids[i]);
+		if (out_arrays[i] == NULL) {
+			obx_err err = obx_last_error_code();
+			while (i > 0) obx_id_array_free(out_arrays[--i]);
+			return err;
+		}
+	}
+	return OBX_SUCCESS;
+}
+
+// applies the changes to a standalone relation of a single source object in a single cgo call
+static obx_err obx_go_box_rel_apply(OBX_box* box, obx_schema_id relation_id, obx_id source_id,
+									const obx_id* put_ids, size_t put_count,
+									const obx_id* remove_ids, size_t remove_count) {
+	for (size_t i = 0; i < remove_count; i++) {
+		obx_err err = obx_box_rel_remove(box, relation_id, source_id, remove_ids[i]);
+		if (err != OBX_SUCCESS) return err;
+	}
+	for (size_t i = 0; i < put_count; i++) {
+		obx_err err = obx_box_rel_put(box, relation_id, source_id, put_ids[i]);
+		if (err != OBX_SUCCESS) return err;
+	}
+	return OBX_SUCCESS;
+}
 */
 import "C"
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"reflect"
 	"runtime"
+	"sync/atomic"
+	"time"
 	"unsafe"
-
-	"github.com/google/flatbuffers/go"
 )
 
 // Box provides CRUD access to objects of a common type
 type Box struct {
 	ObjectBox *ObjectBox
 	entity    *entity
-	cBox      *C.OBX_box
 	async     *AsyncBox
+	batcher   *readBatcher // only set if enabled by Builder.BatchReads()
+	cache     atomic.Value // *boxCache, see WithCache()
+
+	*boxHandles // cBox and the queue of async, shared by all boxes of the entity
 }
 
 const defaultSliceCapacity = 16
 
+// contextCheckInterval is the number of objects visited between checks whether a query context is done
+const contextCheckInterval = 64
+
 func newBox(ob *ObjectBox, entityId TypeId) (*Box, error) {
 	var box = &Box{
 		ObjectBox: ob,
 		entity:    ob.getEntityById(entityId),
 	}
 
-	if err := cCallBool(func() bool {
-		box.cBox = C.obx_box(ob.store, C.obx_schema_id(entityId))
-		return box.cBox != nil
-	}); err != nil {
-		return nil, err
+	if ob.options.batchWindow > 0 {
+		box.batcher = newReadBatcher(box, ob.options.batchWindow)
 	}
 
 	// NOTE this is different than NewAsyncBox in that it doesn't require explicit closing
@@ -60,16 +95,53 @@ func newBox(ob *ObjectBox, entityId TypeId) (*Box, error) {
 		box:    box,
 		cOwned: false,
 	}
+
+	// called with boxesMutex locked; the handles are shared with boxes dropped by ClearBoxCache()
+	if box.boxHandles = ob.boxHandles[entityId]; box.boxHandles != nil {
+		return box, nil
+	}
+
+	cStore, err := ob.enterStore()
+	if err != nil {
+		return nil, err
+	}
+	defer ob.leave()
+
+	var handles = &boxHandles{}
+	if err := cCallBool(func() bool {
+		handles.cBox = C.obx_box(cStore, C.obx_schema_id(entityId))
+		return handles.cBox != nil
+	}); err != nil {
+		return nil, err
+	}
+
 	if err := cCallBool(func() bool {
-		box.async.cAsync = C.obx_async(box.cBox)
-		return box.async.cAsync != nil
+		if ob.options.asyncTimeout > 0 {
+			handles.cAsync = C.obx_async_create(handles.cBox, C.uint64_t(ob.options.asyncTimeout/time.Millisecond))
+		} else {
+			handles.cAsync = C.obx_async(handles.cBox)
+		}
+		return handles.cAsync != nil
 	}); err != nil {
 		return nil, err
 	}
 
+	if ob.boxHandles == nil {
+		ob.boxHandles = make(map[TypeId]*boxHandles)
+	}
+	ob.boxHandles[entityId] = handles
+	box.boxHandles = handles
 	return box, nil
 }
 
+// boxHandles are the native handles of the boxes of an entity, shared by all boxes of the entity, including the ones
+// dropped by ClearBoxCache(), so that closing the store invalidates all of them. They're only used by operations
+// between ObjectBox.enter() and leave() (or inside a transaction) and cleared by ObjectBox.closeNativeLocked().
+type boxHandles struct {
+	cBox   *C.OBX_box
+	cAsync *C.OBX_async // the queue of Box.Async(), owned by the store unless created with a custom timeout
+}
+
 // Async provides access to the default Async Box for asynchronous operations. See AsyncBox for more information.
 func (box *Box) Async() *AsyncBox {
 	return box.async
@@ -89,6 +161,10 @@ func (box *Box) Query(conditions ...Condition) *Query {
 
 // QueryOrError is like Query() but with error handling; e.g. when you build conditions dynamically that may fail.
 func (box *Box) QueryOrError(conditions ...Condition) (query *Query, err error) {
+	return box.query(box.softDeleteConditions(conditions))
+}
+
+func (box *Box) query(conditions []Condition) (query *Query, err error) {
 	builder := newQueryBuilder(box.ObjectBox, box.entity.id)
 
 	defer func() {
@@ -103,7 +179,9 @@ func (box *Box) QueryOrError(conditions ...Condition) (query *Query, err error)
 		return nil, err
 	}
 
-	query, err = builder.Build(box)
+	if query, err = builder.Build(box); err == nil {
+		query.conditions = conditions
+	}
 
 	return // NOTE result might be overwritten by the deferred "closer" function
 }
@@ -118,6 +196,9 @@ func (box *Box) idForPut(idCandidate uint64) (id uint64, err error) {
 		id = uint64(C.obx_box_id_for_put(box.cBox, C.obx_id(idCandidate)))
 		if id == 0 {
 			err = createError()
+			if idCandidate != 0 && !box.entity.idSelfAssignable {
+				err = &idNotAssignableError{err}
+			}
 		}
 
 		runtime.UnlockOSThread()
@@ -125,6 +206,21 @@ func (box *Box) idForPut(idCandidate uint64) (id uint64, err error) {
 	return
 }
 
+// idNotAssignableError adds a hint to the error of putting an object with an ID not reserved from the ID sequence
+type idNotAssignableError struct {
+	err error
+}
+
+func (err *idNotAssignableError) Error() string {
+	return err.err.Error() + "; to put objects with IDs assigned by your code (e.g. mirrored from another system), " +
+		"annotate the ID field with `objectbox:\"id(assignable)\"`"
+}
+
+// Unwrap returns the native error
+func (err *idNotAssignableError) Unwrap() error {
+	return err.err
+}
+
 func (box *Box) idsForPut(count int) (firstId uint64, err error) {
 	if count == 0 {
 		return 0, nil
@@ -142,6 +238,28 @@ func (box *Box) idsForPut(count int) (firstId uint64, err error) {
 }
 
 func (box *Box) put(object interface{}, alreadyInTx bool, putMode C.OBXPutMode) (id uint64, err error) {
+	return box.putRestorable(object, alreadyInTx, putMode, nil)
+}
+
+// putRestorable is like put; additionally, for an entity with a version property, it appends a function restoring the
+// original version of the object to `restores` (if given), to be called if the enclosing transaction is rolled back
+func (box *Box) putRestorable(object interface{}, alreadyInTx bool, putMode C.OBXPutMode, restores *[]func()) (
+	id uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		var operation = "Box.Put"
+		if putMode == cPutModeInsert {
+			operation = "Box.Insert"
+		} else if putMode == cPutModeUpdate {
+			operation = "Box.Update"
+		}
+		defer observeOperation(collector, operation, time.Now(), &err)
+	}
+
+	if err := box.ObjectBox.enter(); err != nil {
+		return 0, err
+	}
+	defer box.ObjectBox.leave()
+
 	idFromObject, err := box.entity.binding.GetId(object)
 	if err != nil {
 		return 0, err
@@ -159,8 +277,30 @@ func (box *Box) put(object interface{}, alreadyInTx bool, putMode C.OBXPutMode)
 		}
 	}
 
-	// for entities with relations, execute all Put/PutRelated inside a single transaction
-	if box.entity.hasRelations && !alreadyInTx {
+	if box.entity.version != nil {
+		// the version must be checked in the same transaction as the object is written
+		var write = func() error {
+			restore, err := box.checkVersion(object, id, idFromObject == 0)
+			if err != nil {
+				return err
+			}
+			if err := box.putOne(id, object, putMode); err != nil {
+				restore()
+				return err
+			}
+			if restores != nil {
+				*restores = append(*restores, restore)
+			}
+			return nil
+		}
+		if alreadyInTx {
+			err = write()
+		} else {
+			err = box.ObjectBox.RunInWriteTx(write)
+		}
+	} else if (box.entity.hasRelations || box.entity.quota != nil) && !alreadyInTx {
+		// for entities with relations, execute all Put/PutRelated inside a single transaction; quotas are checked
+		// in the same transaction as the object is written
 		err = box.ObjectBox.RunInWriteTx(func() error {
 			return box.putOne(id, object, putMode)
 		})
@@ -175,6 +315,8 @@ func (box *Box) put(object interface{}, alreadyInTx bool, putMode C.OBXPutMode)
 
 	if err != nil {
 		id = 0
+	} else {
+		box.ObjectBox.changeLog.record(box.entity.id, ChangePut, id)
 	}
 
 	return id, err
@@ -188,28 +330,52 @@ func (box *Box) putOne(id uint64, object interface{}, putMode C.OBXPutMode) erro
 	}
 
 	return box.withObjectBytes(object, id, func(bytes []byte) error {
-		return cCall(func() C.obx_err {
+		var quota = box.entity.quota
+		var quotaBytes uint64
+		if quota != nil {
+			var err error
+			if quotaBytes, err = quota.beforePut(box, id, len(bytes)); err != nil {
+				return err
+			}
+		}
+		var err = cCall(func() C.obx_err {
 			return C.obx_box_put5(box.cBox, C.obx_id(id), unsafe.Pointer(&bytes[0]), C.size_t(len(bytes)), putMode)
 		})
+		if err == nil && quota != nil {
+			quota.afterPut(quotaBytes)
+		}
+		return err
 	})
 }
 
 func (box *Box) withObjectBytes(object interface{}, id uint64, fn func([]byte) error) error {
-	var fbb = fbbPool.Get().(*flatbuffers.Builder)
+	return box.withSerializedObject(object, id, true, fn)
+}
+
+// withPlainObjectBytes is like withObjectBytes but passes the data before encoding it by the property codec (if any),
+// in the layout produced when decoding stored data, see propertyCodec.plain()
+func (box *Box) withPlainObjectBytes(object interface{}, id uint64, fn func([]byte) error) error {
+	return box.withSerializedObject(object, id, false, fn)
+}
 
+func (box *Box) withSerializedObject(object interface{}, id uint64, encode bool, fn func([]byte) error) error {
+	var fbb = acquireFbb()
 	err := box.entity.binding.Flatten(object, fbb, id)
 
 	if err == nil {
 		fbb.Finish(fbb.EndObject())
-		err = fn(fbb.FinishedBytes())
-	}
-
-	// put the fbb back to the pool for the others to use if it's reasonably small; don't use defer, it's slower
-	if cap(fbb.Bytes) < 1024*1024 {
-		fbb.Reset()
-		fbbPool.Put(fbb)
+		var bytes = fbb.FinishedBytes()
+		if codec := box.entity.codec; codec != nil && encode {
+			bytes, err = codec.encode(bytes)
+		} else if codec != nil {
+			bytes, err = codec.plain(bytes)
+		}
+		if err == nil {
+			err = fn(bytes)
+		}
 	}
 
+	releaseFbb(fbb)
 	return err
 }
 
@@ -222,6 +388,8 @@ func (box *Box) PutAsync(object interface{}) (id uint64, err error) {
 // Put synchronously inserts/updates a single object.
 // In case the ID is not specified, it would be assigned automatically (auto-increment).
 // When inserting, the ID property on the passed object will be assigned the new ID as well.
+// A new object can only be put with a given (non-zero) ID if the ID field is annotated `objectbox:"id(assignable)"`,
+// e.g. to keep the IDs of records mirrored from another system; otherwise, IDs are only assigned by ObjectBox.
 func (box *Box) Put(object interface{}) (id uint64, err error) {
 	return box.put(object, false, cPutModePut)
 }
@@ -241,6 +409,81 @@ func (box *Box) Update(object interface{}) error {
 	return err
 }
 
+// PutIfChanged is like Put but skips the write if the stored object has the same content, i.e. all persisted
+// properties are equal. Returns whether the object was written (new objects always are). Values of properties with
+// a codec (see ObjectBox.SetPropertyCodec()) are compared before encoding, i.e. the codec may be non-deterministic.
+// Use it to reduce write amplification when periodically storing a full state that rarely changes.
+// Note: only the object itself is compared; if it's unchanged, its related objects aren't put either.
+func (box *Box) PutIfChanged(object interface{}) (id uint64, changed bool, err error) {
+	id, err = box.entity.binding.GetId(object)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if id == 0 {
+		id, err = box.put(object, false, cPutModePut)
+		return id, err == nil, err
+	}
+
+	err = box.ObjectBox.RunInWriteTx(func() error {
+		var data *C.void
+		var dataSize C.size_t
+		var dataPtr = unsafe.Pointer(data)
+
+		var rc = C.obx_box_get(box.cBox, C.obx_id(id), &dataPtr, &dataSize)
+		if rc == 0 {
+			var stored []byte
+			cVoidPtrToByteSlice(dataPtr, int(dataSize), &stored)
+			if codec := box.entity.codec; codec != nil {
+				var err error
+				if stored, err = codec.decode(stored); err != nil {
+					return err
+				}
+			}
+			if err := box.withPlainObjectBytes(object, id, func(current []byte) error {
+				changed = !bytes.Equal(stored, current)
+				return nil
+			}); err != nil || !changed {
+				return err
+			}
+		} else if rc == C.OBX_NOT_FOUND {
+			changed = true
+		} else {
+			// NOTE: no need for manual runtime.LockOSThread() because we're inside a transaction
+			return createError()
+		}
+
+		_, err := box.put(object, true, cPutModePut)
+		return err
+	})
+
+	if err != nil {
+		return 0, false, err
+	}
+	return id, changed, nil
+}
+
+// ContentHash returns a stable hash (64-bit FNV-1a) of the persisted properties of the given object, e.g. to detect
+// changes without reading the stored object; equal objects have equal hashes. Values of properties with a codec
+// (see ObjectBox.SetPropertyCodec()) are hashed before encoding.
+// Note that the hash may change with the model, e.g. when properties are added or removed.
+func (box *Box) ContentHash(object interface{}) (uint64, error) {
+	id, err := box.entity.binding.GetId(object)
+	if err != nil {
+		return 0, err
+	}
+
+	var hash = fnv.New64a()
+	err = box.withPlainObjectBytes(object, id, func(bytes []byte) error {
+		_, err := hash.Write(bytes)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return hash.Sum64(), nil
+}
+
 // PutMany inserts multiple objects in a single transaction.
 // The given argument must be a slice of the object type this Box represents (pointers to objects).
 // In case IDs are not set on the objects, they would be assigned automatically (auto-increment).
@@ -252,6 +495,10 @@ func (box *Box) Update(object interface{}) error {
 //
 // Note: The slice may be empty or even nil; in both cases, an empty IDs slice and no error is returned.
 func (box *Box) PutMany(objects interface{}) (ids []uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.PutMany", time.Now(), &err)
+	}
+
 	var slice = reflect.ValueOf(objects)
 	var count = slice.Len()
 
@@ -263,10 +510,14 @@ func (box *Box) PutMany(objects interface{}) (ids []uint64, err error) {
 	// prepare the result, filled in below
 	ids = make([]uint64, count)
 
+	// versions incremented by the put, restored if the transaction is rolled back
+	var restores []func()
+
 	// Execute everything in a single single transaction - for performance and consistency.
 	// This is necessary even if count < chunkSize because of relations (PutRelated)
 	err = box.ObjectBox.RunInWriteTx(func() error {
-		if supportsResultArray {
+		// versioned objects and quotas are checked one by one, see ObjectBox.SetVersionProperty() and BoxQuota()
+		if supportsResultArray && box.entity.version == nil && box.entity.quota == nil {
 			// Process the data in chunks so that we don't consume too much memory.
 			const chunkSize = 10000 // 10k is the limit currently enforced by obx_box_ids_for_put, maybe make configurable
 
@@ -288,7 +539,7 @@ func (box *Box) PutMany(objects interface{}) (ids []uint64, err error) {
 			}
 		} else {
 			for i := 0; i < count; i++ {
-				id, err := box.put(slice.Index(i).Interface(), true, cPutModePut)
+				id, err := box.putRestorable(slice.Index(i).Interface(), true, cPutModePut, &restores)
 				if err != nil {
 					return err
 				}
@@ -301,11 +552,102 @@ func (box *Box) PutMany(objects interface{}) (ids []uint64, err error) {
 
 	if err != nil {
 		ids = nil
+		restoreVersions(restores)
 	}
 
 	return ids, err
 }
 
+// PutManyBestEffort is like PutMany but doesn't abort on the first object that can't be put, e.g. because of a unique
+// constraint violation; use it for bulk ingestion of data from unreliable sources. The objects are put in chunks of
+// 1000, each in its own write transaction; if a chunk fails, its objects are put again one by one, each in its own
+// transaction, skipping the failing ones. Successful chunks stay committed regardless of the following ones.
+//
+// Returns the IDs of the put objects (0 for the failed ones) and, if any object failed, the error of each object (nil
+// for the successful ones), both in the same order as the given objects.
+func (box *Box) PutManyBestEffort(objects interface{}) (ids []uint64, errs []error) {
+	if collector := metricsCollector(); collector != nil {
+		var err error
+		defer observeOperation(collector, "Box.PutManyBestEffort", time.Now(), &err)
+		defer func() {
+			if errs != nil {
+				err = errors.New("some objects could not be put")
+			}
+		}()
+	}
+
+	var slice = reflect.ValueOf(objects)
+	var count = slice.Len()
+	ids = make([]uint64, count)
+
+	var fail = func(index int, err error) {
+		if errs == nil {
+			errs = make([]error, count)
+		}
+		errs[index] = err
+	}
+
+	for start := 0; start < count; start += importBatchSize {
+		var end = start + importBatchSize
+		if end > count {
+			end = count
+		}
+
+		// remember the IDs of the objects so that new objects can be put again if the chunk is rolled back
+		var originalIds = make([]uint64, end-start)
+		for i := start; i < end; i++ {
+			var err error
+			if originalIds[i-start], err = box.entity.binding.GetId(slice.Index(i).Interface()); err != nil {
+				fail(i, err)
+			}
+		}
+
+		var restores []func()
+		var err = box.ObjectBox.RunInWriteTx(func() error {
+			for i := start; i < end; i++ {
+				if errs != nil && errs[i] != nil {
+					continue
+				}
+				id, err := box.putRestorable(slice.Index(i).Interface(), true, cPutModePut, &restores)
+				if err != nil {
+					return err
+				}
+				ids[i] = id
+			}
+			return nil
+		})
+		if err == nil {
+			continue
+		}
+		restoreVersions(restores)
+
+		for i := start; i < end; i++ {
+			if errs != nil && errs[i] != nil {
+				continue
+			}
+			var object = slice.Index(i).Interface()
+			ids[i] = 0
+			if err := box.entity.binding.SetId(object, originalIds[i-start]); err != nil {
+				fail(i, err)
+				continue
+			}
+			restores = nil
+			err := box.ObjectBox.RunInWriteTx(func() error {
+				var err error
+				ids[i], err = box.putRestorable(object, true, cPutModePut, &restores)
+				return err
+			})
+			if err != nil {
+				ids[i] = 0
+				box.entity.binding.SetId(object, originalIds[i-start])
+				restoreVersions(restores)
+				fail(i, err)
+			}
+		}
+	}
+	return ids, errs
+}
+
 // putManyObjects inserts a subset of objects, setting their IDs as an outArgument.
 // Requires to be called inside a write transaction, i.e. from the ObjectBox.RunInWriteTx() callback.
 // The caller of this method (PutMany) already sliced up the data into chunks to mitigate memory consumption.
@@ -388,6 +730,7 @@ func (box *Box) putManyObjects(objects reflect.Value, outIds []uint64, start, en
 		}
 	}
 
+	box.ObjectBox.changeLog.record(box.entity.id, ChangePut, outIds[start:end]...)
 	return nil
 }
 
@@ -402,10 +745,34 @@ func (box *Box) Remove(object interface{}) error {
 }
 
 // RemoveId deletes a single object
-func (box *Box) RemoveId(id uint64) error {
-	return cCall(func() C.obx_err {
-		return C.obx_box_remove(box.cBox, C.obx_id(id))
+func (box *Box) RemoveId(id uint64) (err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.Remove", time.Now(), &err)
+	}
+
+	if err := box.ObjectBox.enter(); err != nil {
+		return err
+	}
+	defer box.ObjectBox.leave()
+
+	if box.entity.softDelete != nil {
+		if count, err := box.softRemove([]uint64{id}); err != nil {
+			return err
+		} else if count == 0 {
+			return fmt.Errorf("object with ID %d not found", id)
+		}
+		return nil
+	}
+
+	err = box.entity.quota.remove(box, []uint64{id}, func() error {
+		return cCall(func() C.obx_err {
+			return C.obx_box_remove(box.cBox, C.obx_id(id))
+		})
 	})
+	if err == nil {
+		box.ObjectBox.changeLog.record(box.entity.id, ChangeRemove, id)
+	}
+	return err
 }
 
 // RemoveIds deletes multiple objects at once.
@@ -413,26 +780,67 @@ func (box *Box) RemoveId(id uint64) error {
 // Note that this method will not fail if an object is not found (e.g. already removed).
 // In case you need to strictly check whether all of the objects exist before removing them,
 // you can execute multiple box.Contains() and box.Remove() inside a single write transaction.
-func (box *Box) RemoveIds(ids ...uint64) (uint64, error) {
+func (box *Box) RemoveIds(ids ...uint64) (count uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.RemoveIds", time.Now(), &err)
+	}
+
+	if err := box.ObjectBox.enter(); err != nil {
+		return 0, err
+	}
+	defer box.ObjectBox.leave()
+
+	if box.entity.softDelete != nil {
+		return box.softRemove(ids)
+	}
+	return box.removeIds(ids)
+}
+
+// removeIds deletes the given objects permanently, regardless of the soft-delete mode
+func (box *Box) removeIds(ids []uint64) (uint64, error) {
 	cIds, err := goIdsArrayToC(ids)
 	if err != nil {
 		return 0, err
 	}
 
+	defer cIds.free()
+
 	var cResult C.uint64_t
-	err = cCall(func() C.obx_err {
-		defer cIds.free()
-		return C.obx_box_remove_many(box.cBox, cIds.cArray, &cResult)
+	err = box.entity.quota.remove(box, ids, func() error {
+		return cCall(func() C.obx_err {
+			return C.obx_box_remove_many(box.cBox, cIds.cArray, &cResult)
+		})
 	})
+	if err == nil {
+		box.ObjectBox.changeLog.record(box.entity.id, ChangeRemove, ids...)
+	}
 	return uint64(cResult), err
 }
 
 // RemoveAll removes all stored objects.
 // This is much faster than removing objects one by one in a loop.
-func (box *Box) RemoveAll() error {
-	return cCall(func() C.obx_err {
+func (box *Box) RemoveAll() (err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.RemoveAll", time.Now(), &err)
+	}
+
+	if err := box.ObjectBox.enter(); err != nil {
+		return err
+	}
+	defer box.ObjectBox.leave()
+
+	if box.entity.softDelete != nil {
+		return box.softRemoveAll()
+	}
+
+	err = cCall(func() C.obx_err {
 		return C.obx_box_remove_all(box.cBox, nil)
 	})
+	if err == nil {
+		box.entity.quota.removedAll()
+		box.ObjectBox.changeLog.record(box.entity.id, ChangeRemoveAll, 0)
+	}
+	return err
 }
 
 // Count returns a number of objects stored
@@ -442,7 +850,24 @@ func (box *Box) Count() (uint64, error) {
 
 // CountMax returns a number of objects stored (up to a given maximum)
 // passing limit=0 is the same as calling Count() - counts all objects without a limit
-func (box *Box) CountMax(limit uint64) (uint64, error) {
+func (box *Box) CountMax(limit uint64) (count uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.Count", time.Now(), &err)
+	}
+
+	if err := box.ObjectBox.enter(); err != nil {
+		return 0, err
+	}
+	defer box.ObjectBox.leave()
+
+	if box.entity.softDelete != nil {
+		return box.softCount(limit)
+	}
+
+	if limit == 0 && box.batcher != nil && box.batcher.applicable() {
+		return box.batcher.count()
+	}
+
 	var cResult C.uint64_t
 	if err := cCall(func() C.obx_err { return C.obx_box_count(box.cBox, C.uint64_t(limit), &cResult) }); err != nil {
 		return 0, err
@@ -452,6 +877,16 @@ func (box *Box) CountMax(limit uint64) (uint64, error) {
 
 // IsEmpty checks whether the box contains any objects
 func (box *Box) IsEmpty() (bool, error) {
+	if err := box.ObjectBox.enter(); err != nil {
+		return false, err
+	}
+	defer box.ObjectBox.leave()
+
+	if box.entity.softDelete != nil {
+		count, err := box.softCount(1)
+		return count == 0, err
+	}
+
 	var cResult C.bool
 	if err := cCall(func() C.obx_err { return C.obx_box_is_empty(box.cBox, &cResult) }); err != nil {
 		return false, err
@@ -462,9 +897,50 @@ func (box *Box) IsEmpty() (bool, error) {
 // Get reads a single object.
 //
 // Returns an interface that should be cast to the appropriate type.
-// Returns nil in case the object with the given ID doesn't exist.
+// Returns nil in case the object with the given ID doesn't exist (or has been removed in the soft-delete mode).
 // The cast is done automatically when using the generated BoxFor* code.
 func (box *Box) Get(id uint64) (object interface{}, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.Get", time.Now(), &err)
+	}
+
+	object, err = box.get(id)
+	if object != nil && box.entity.softDelete != nil && box.softDeleted(object) {
+		object = nil
+	}
+	return object, err
+}
+
+// GetWithDeleted is like Get() but also returns an object removed in the soft-delete mode, see
+// ObjectBox.SetSoftDelete(). For other entities, it's the same as Get().
+func (box *Box) GetWithDeleted(id uint64) (object interface{}, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.Get", time.Now(), &err)
+	}
+
+	return box.get(id)
+}
+
+func (box *Box) get(id uint64) (object interface{}, err error) {
+	if im := box.ObjectBox.identityMaps.current(); im != nil {
+		if object, found := im.get(box.entity.id, id); found {
+			return object, nil
+		}
+		defer func() {
+			if err == nil && object != nil {
+				im.put(box.entity.id, id, object)
+			}
+		}()
+	}
+
+	if cache := box.applicableCache(); cache != nil {
+		return cache.getOne(id)
+	}
+
+	if box.batcher != nil && box.batcher.applicable() {
+		return box.batcher.get(id)
+	}
+
 	// we need a read-transaction to keep the data in dataPtr untouched (by concurrent write) until we can read it
 	// as well as making sure the relations read in binding.Load represent a consistent state
 	err = box.ObjectBox.RunInReadTx(func() error {
@@ -476,7 +952,7 @@ func (box *Box) Get(id uint64) (object interface{}, err error) {
 		if rc == 0 {
 			var bytes []byte
 			cVoidPtrToByteSlice(dataPtr, int(dataSize), &bytes)
-			object, err = box.entity.binding.Load(box.ObjectBox, bytes)
+			object, err = box.entity.load(box.ObjectBox, bytes)
 			return err
 		} else if rc == C.OBX_NOT_FOUND {
 			object = nil
@@ -492,6 +968,47 @@ func (box *Box) Get(id uint64) (object interface{}, err error) {
 	return object, err
 }
 
+// GetBytes passes the raw FlatBuffers data of the object with the given ID to fn, without decoding it to an object.
+// This is an advanced API, e.g. for custom decoders or passing the data on as-is, avoiding the copies made by Get().
+//
+// The data references the database memory directly: it's only valid until fn returns and must not be modified.
+// Copy the slice if you need to keep it. fn isn't called and found=false is returned if the object doesn't exist.
+func (box *Box) GetBytes(id uint64, fn func(data []byte) error) (found bool, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.GetBytes", time.Now(), &err)
+	}
+
+	if err = box.ObjectBox.enter(); err != nil {
+		return false, err
+	}
+	defer box.ObjectBox.leave()
+
+	// the read transaction keeps the data untouched (by concurrent writes) until fn returns
+	err = box.ObjectBox.RunInReadTx(func() error {
+		var data *C.void
+		var dataSize C.size_t
+		var dataPtr = unsafe.Pointer(data)
+
+		var rc = C.obx_box_get(box.cBox, C.obx_id(id), &dataPtr, &dataSize)
+		if rc == 0 {
+			found = true
+			var bytes []byte
+			cVoidPtrToByteSlice(dataPtr, int(dataSize), &bytes)
+			return fn(bytes)
+		} else if rc == C.OBX_NOT_FOUND {
+			return nil
+		}
+		return createError()
+	})
+
+	return found, err
+}
+
+// Load decodes an object from its raw FlatBuffers data, e.g. as passed to the GetBytes() callback, using the binding.
+func (box *Box) Load(data []byte) (object interface{}, err error) {
+	return box.entity.load(box.ObjectBox, data)
+}
+
 // GetMany reads multiple objects at once.
 //
 // Returns a slice of objects that should be cast to the appropriate type.
@@ -499,9 +1016,25 @@ func (box *Box) Get(id uint64) (object interface{}, err error) {
 // If any of the objects doesn't exist, its position in the return slice
 //  is nil or an empty object (depends on the binding)
 func (box *Box) GetMany(ids ...uint64) (slice interface{}, err error) {
-	const existingOnly = false
-	if cIds, err := goIdsArrayToC(ids); err != nil {
-		return nil, err
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.GetMany", time.Now(), &err)
+	}
+
+	if cache := box.applicableCache(); cache != nil {
+		slice, err = cache.getMany(ids)
+	} else {
+		slice, err = box.getMany(ids)
+	}
+	if err == nil && box.entity.softDelete != nil {
+		slice = box.softFilter(slice, false)
+	}
+	return slice, err
+}
+
+func (box *Box) getMany(ids []uint64) (slice interface{}, err error) {
+	const existingOnly = false
+	if cIds, err := goIdsArrayToC(ids); err != nil {
+		return nil, err
 	} else if supportsResultArray {
 		defer cIds.free()
 		return box.readManyObjects(existingOnly, func() *C.OBX_bytes_array { return C.obx_box_get_many(box.cBox, cIds.cArray) })
@@ -510,7 +1043,7 @@ func (box *Box) GetMany(ids ...uint64) (slice interface{}, err error) {
 			defer cIds.free()
 			return C.obx_box_visit_many(box.cBox, cIds.cArray, dataVisitor, visitorArg)
 		}
-		return box.readUsingVisitor(existingOnly, cFn)
+		return box.readUsingVisitor(existingOnly, cFn, readOptions{})
 	}
 }
 
@@ -519,19 +1052,28 @@ func (box *Box) GetMany(ids ...uint64) (slice interface{}, err error) {
 // Returns a slice of objects that should be cast to the appropriate type.
 // The cast is done automatically when using the generated BoxFor* code.
 func (box *Box) GetManyExisting(ids ...uint64) (slice interface{}, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.GetManyExisting", time.Now(), &err)
+	}
+
 	const existingOnly = true
-	if cIds, err := goIdsArrayToC(ids); err != nil {
+	cIds, err := goIdsArrayToC(ids)
+	if err != nil {
 		return nil, err
 	} else if supportsResultArray {
 		defer cIds.free()
-		return box.readManyObjects(existingOnly, func() *C.OBX_bytes_array { return C.obx_box_get_many(box.cBox, cIds.cArray) })
+		slice, err = box.readManyObjects(existingOnly, func() *C.OBX_bytes_array { return C.obx_box_get_many(box.cBox, cIds.cArray) })
 	} else {
 		var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
 			defer cIds.free()
 			return C.obx_box_visit_many(box.cBox, cIds.cArray, dataVisitor, visitorArg)
 		}
-		return box.readUsingVisitor(existingOnly, cFn)
+		slice, err = box.readUsingVisitor(existingOnly, cFn, readOptions{})
 	}
+	if err == nil && box.entity.softDelete != nil {
+		slice = box.softFilter(slice, true)
+	}
+	return slice, err
 }
 
 // GetAll reads all stored objects.
@@ -539,18 +1081,184 @@ func (box *Box) GetManyExisting(ids ...uint64) (slice interface{}, err error) {
 // Returns a slice of objects that should be cast to the appropriate type.
 // The cast is done automatically when using the generated BoxFor* code.
 func (box *Box) GetAll() (slice interface{}, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.GetAll", time.Now(), &err)
+	}
+
+	if box.entity.softDelete != nil {
+		return box.softGetAll()
+	}
+
 	const existingOnly = true
-	if supportsResultArray {
+	var underPressure, maxObjects = box.ObjectBox.memoryPressure()
+	if supportsResultArray && !underPressure {
 		return box.readManyObjects(existingOnly, func() *C.OBX_bytes_array { return C.obx_box_get_all(box.cBox) })
 	}
 
 	var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
 		return C.obx_box_visit_all(box.cBox, dataVisitor, visitorArg)
 	}
-	return box.readUsingVisitor(existingOnly, cFn)
+
+	// preallocate the result to avoid repeated reallocation when reading many objects
+	var options = readOptions{maxObjects: maxObjects}
+	if err := box.ObjectBox.enter(); err != nil {
+		return nil, err
+	}
+	var cCount C.uint64_t
+	if cCall(func() C.obx_err { return C.obx_box_count(box.cBox, C.uint64_t(maxObjects), &cCount) }) == nil {
+		options.capacity = uint64(cCount)
+	}
+	box.ObjectBox.leave()
+	return box.readUsingVisitor(existingOnly, cFn, options)
+}
+
+// GetAllInto reads all stored objects into the slice the given pointer points to, e.g. `*[]Entity` or `*[]*Entity`
+// (matching the type returned by GetAll()). The slice is truncated and its backing array is reused if it has enough
+// capacity, reducing allocations (and GC pressure) when reading all objects repeatedly.
+// If the read fails, the slice keeps its previous elements; to do so, they're copied before reading, so pass a slice
+// truncated to zero length (e.g. `objects = objects[:0]`) if you don't need them in that case.
+func (box *Box) GetAllInto(slicePtr interface{}) (err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.GetAll", time.Now(), &err)
+	}
+
+	var expectedType = reflect.TypeOf(box.entity.binding.MakeSlice(0))
+	var ptr = reflect.ValueOf(slicePtr)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Type() != expectedType {
+		return fmt.Errorf("expected a non-nil pointer to %s, got %T", expectedType, slicePtr)
+	}
+
+	var previous = ptr.Elem()
+	var into = previous.Slice(0, 0).Interface()
+
+	// the read overwrites the elements in the backing array; restore them if it fails
+	if previous.Len() > 0 {
+		var backup = reflect.MakeSlice(expectedType, previous.Len(), previous.Len())
+		reflect.Copy(backup, previous)
+		defer func() {
+			if err != nil {
+				reflect.Copy(previous, backup)
+			}
+		}()
+	}
+
+	const existingOnly = true
+	var slice interface{}
+	var underPressure, maxObjects = box.ObjectBox.memoryPressure()
+	if box.entity.softDelete != nil {
+		slice, err = box.softGetAll()
+	} else if supportsResultArray && !underPressure {
+		slice, err = box.readManyObjectsWith(existingOnly, func() *C.OBX_bytes_array {
+			return C.obx_box_get_all(box.cBox)
+		}, readOptions{into: into})
+	} else {
+		var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
+			return C.obx_box_visit_all(box.cBox, dataVisitor, visitorArg)
+		}
+		slice, err = box.readUsingVisitor(existingOnly, cFn, readOptions{maxObjects: maxObjects, into: into})
+	}
+	if err != nil {
+		return err
+	}
+
+	var result = reflect.ValueOf(slice)
+
+	// don't keep the previous objects referenced by the backing array beyond the new length
+	if result.Pointer() == previous.Pointer() {
+		var zero = reflect.Zero(expectedType.Elem())
+		for i := result.Len(); i < previous.Len(); i++ {
+			previous.Index(i).Set(zero)
+		}
+	}
+
+	ptr.Elem().Set(result)
+	return nil
+}
+
+// VisitAll calls fn for each stored object, in a single read transaction, until it returns false.
+// Unlike GetAll(), the objects are passed to fn one by one as they are read, without collecting them in a slice first,
+// which keeps memory usage low when processing a large number of objects, e.g. on embedded devices.
+func (box *Box) VisitAll(fn func(object interface{}) bool) (err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.VisitAll", time.Now(), &err)
+	}
+
+	if box.entity.softDelete != nil {
+		var visit = fn
+		fn = func(object interface{}) bool {
+			return box.softDeleted(object) || visit(object)
+		}
+	}
+
+	return box.visit(func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_box_visit_all(box.cBox, dataVisitor, visitorArg)
+	}, fn)
+}
+
+// VisitIds calls fn for each of the objects with the given IDs, in a single read transaction, until it returns false.
+// Like VisitAll(), this avoids collecting the objects in a slice as GetMany() does.
+// The objects are visited in the order of the given IDs; fn receives nil for objects that don't exist (or have been
+// removed in the soft-delete mode).
+func (box *Box) VisitIds(ids []uint64, fn func(object interface{}) bool) (err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.VisitIds", time.Now(), &err)
+	}
+
+	if box.entity.softDelete != nil {
+		var visit = fn
+		fn = func(object interface{}) bool {
+			if object != nil && box.softDeleted(object) {
+				object = nil
+			}
+			return visit(object)
+		}
+	}
+
+	cIds, err := goIdsArrayToC(ids)
+	if err != nil {
+		return err
+	}
+	defer cIds.free()
+
+	return box.visit(func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_box_visit_many(box.cBox, cIds.cArray, dataVisitor, visitorArg)
+	}, fn)
+}
+
+// visit loads the objects passed by cFn to the visitor and calls fn for each of them until it returns false
+func (box *Box) visit(cFn func(visitorArg unsafe.Pointer) C.obx_err, fn func(object interface{}) bool) error {
+	var loadErr error
+	visitor, err := dataVisitorRegister(func(bytes []byte) bool {
+		if bytes == nil {
+			return fn(nil) // not found
+		}
+		object, err := box.entity.load(box.ObjectBox, bytes)
+		if err != nil {
+			loadErr = err
+			return false
+		}
+		return fn(object)
+	})
+	if err != nil {
+		return err
+	}
+	defer dataVisitorUnregister(visitor)
+
+	err = box.ObjectBox.RunInReadTx(func() error {
+		return cCall(func() C.obx_err { return cFn(unsafe.Pointer(&visitor)) })
+	})
+	if err == nil {
+		err = loadErr
+	}
+	return err
 }
 
 func (box *Box) readManyObjects(existingOnly bool, cFn func() *C.OBX_bytes_array) (slice interface{}, err error) {
+	return box.readManyObjectsWith(existingOnly, cFn, readOptions{})
+}
+
+// readManyObjectsWith reads the objects using the given options; maxObjects and ctx are not supported
+func (box *Box) readManyObjectsWith(existingOnly bool, cFn func() *C.OBX_bytes_array, options readOptions) (slice interface{}, err error) {
 	// we need a read-transaction to keep the data in dataPtr untouched (by concurrent write) until we can read it
 	// as well as making sure the relations read in binding.Load represent a consistent state
 	err = box.ObjectBox.RunInReadTx(func() error {
@@ -559,22 +1267,23 @@ func (box *Box) readManyObjects(existingOnly bool, cFn func() *C.OBX_bytes_array
 			return err
 		}
 
-		var binding = box.entity.binding
-		slice = binding.MakeSlice(len(bytesArray))
+		var appender = newSliceAppender(box.entity, len(bytesArray), options.into)
 		for _, bytesData := range bytesArray {
 			if bytesData == nil {
 				// may be nil if an object on this index was not found (can happen with GetMany)
 				if !existingOnly {
-					slice = binding.AppendToSlice(slice, nil)
+					appender.append(nil)
 				}
 				continue
 			}
 
-			object, err := binding.Load(box.ObjectBox, bytesData)
-			if err != nil {
+			if err := appender.load(box.ObjectBox, bytesData); err != nil {
 				return err
 			}
-			slice = binding.AppendToSlice(slice, object)
+		}
+		slice = appender.result()
+		if options.decoded != nil {
+			atomic.AddUint64(options.decoded, appender.decoded)
 		}
 		return nil
 	})
@@ -586,25 +1295,56 @@ func (box *Box) readManyObjects(existingOnly bool, cFn func() *C.OBX_bytes_array
 	return slice, err
 }
 
+// readOptions configure readUsingVisitor() and readManyObjectsWith()
+type readOptions struct {
+	maxObjects uint64          // if non-zero, the read fails with ErrResultBudgetExceeded when there are more objects
+	ctx        context.Context // if set, the visit is aborted early, returning ctx.Err(), once the context is done
+	capacity   uint64          // initial capacity of the result slice; defaultSliceCapacity if zero
+	into       interface{}     // if set, the objects are appended to this slice instead of a new one
+	decoded    *uint64         // if set, the number of bytes loaded is added to this counter (atomically)
+}
+
 // this is a utility function to fetch objects using an obx_data_visitor
-func (box *Box) readUsingVisitor(existingOnly bool, cFn func(visitorArg unsafe.Pointer) C.obx_err) (slice interface{}, err error) {
-	var binding = box.entity.binding
+func (box *Box) readUsingVisitor(existingOnly bool, cFn func(visitorArg unsafe.Pointer) C.obx_err,
+	options readOptions) (slice interface{}, err error) {
+	var appender *sliceAppender
 	var visitor uint32
+	var count uint64
+	var maxObjects = options.maxObjects
+	var ctx = options.ctx
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
 	visitor, err = dataVisitorRegister(func(bytes []byte) bool {
+		if maxObjects != 0 && count == maxObjects {
+			err = ErrResultBudgetExceeded
+			return false
+		}
+		count++
+
+		// checking the context requires synchronization, only do it once in a while
+		if done != nil && count%contextCheckInterval == 0 {
+			select {
+			case <-done:
+				err = ctx.Err()
+				return false
+			default:
+			}
+		}
+
 		// may be nil if an object on this index was not found (can happen with GetMany)
 		if bytes == nil {
 			if !existingOnly {
-				slice = binding.AppendToSlice(slice, nil)
+				appender.append(nil)
 			}
 			return true
 		}
 
-		object, err2 := binding.Load(box.ObjectBox, bytes)
-		if err2 != nil {
+		if err2 := appender.load(box.ObjectBox, bytes); err2 != nil {
 			err = err2
 			return false
 		}
-		slice = binding.AppendToSlice(slice, object)
 		return true
 	})
 	if err != nil {
@@ -612,7 +1352,17 @@ func (box *Box) readUsingVisitor(existingOnly bool, cFn func(visitorArg unsafe.P
 	}
 	defer dataVisitorUnregister(visitor)
 
-	slice = binding.MakeSlice(defaultSliceCapacity)
+	if done != nil {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var capacity = defaultSliceCapacity
+	if options.capacity > 0 {
+		capacity = int(options.capacity)
+	}
+	appender = newSliceAppender(box.entity, capacity, options.into)
 
 	// we need a read-transaction to keep the data in dataPtr untouched (by concurrent write) until we can read it
 	// as well as making sure the relations read in binding.Load represent a consistent state
@@ -625,13 +1375,97 @@ func (box *Box) readUsingVisitor(existingOnly bool, cFn func(visitorArg unsafe.P
 		return nil, err2
 	} else if err != nil {
 		return nil, err
-	} else {
-		return slice, nil
 	}
+
+	if options.decoded != nil {
+		atomic.AddUint64(options.decoded, appender.decoded)
+	}
+	return appender.result(), nil
+}
+
+// appendBatchSize is the number of objects collected before they're appended using AppendManyToSlice()
+const appendBatchSize = 64
+
+// sliceAppender builds the result of bulk reads, using ObjectBindingAppendMany if the binding supports it
+type sliceAppender struct {
+	entity  *entity
+	binding ObjectBinding
+	many    ObjectBindingAppendMany // nil if not supported by the binding
+	slice   interface{}
+	pending []interface{}
+	decoded uint64 // number of bytes loaded
 }
 
-// Contains checks whether an object with the given ID is stored.
+// newSliceAppender creates an appender for a new slice with the given capacity or for the given slice (if not nil)
+func newSliceAppender(entity *entity, capacity int, into interface{}) *sliceAppender {
+	var binding = entity.binding
+	var appender = &sliceAppender{entity: entity, binding: binding, slice: into}
+	if into == nil {
+		appender.slice = binding.MakeSlice(capacity)
+	}
+	if many, ok := binding.(ObjectBindingAppendMany); ok {
+		appender.many = many
+		if capacity > appendBatchSize {
+			capacity = appendBatchSize
+		}
+		appender.pending = make([]interface{}, 0, capacity)
+	}
+	return appender
+}
+
+// load constructs the object from the serialized bytes and appends it
+func (appender *sliceAppender) load(ob *ObjectBox, bytes []byte) (err error) {
+	appender.decoded += uint64(len(bytes))
+	object, err := appender.entity.load(ob, bytes)
+	if err == nil {
+		appender.append(object)
+	}
+	return err
+}
+
+func (appender *sliceAppender) append(object interface{}) {
+	if appender.many == nil {
+		appender.slice = appender.binding.AppendToSlice(appender.slice, object)
+		return
+	}
+
+	appender.pending = append(appender.pending, object)
+	if len(appender.pending) == appendBatchSize {
+		appender.flush()
+	}
+}
+
+func (appender *sliceAppender) flush() {
+	if len(appender.pending) > 0 {
+		appender.slice = appender.many.AppendManyToSlice(appender.slice, appender.pending)
+		for i := range appender.pending {
+			appender.pending[i] = nil // don't keep the objects referenced
+		}
+		appender.pending = appender.pending[:0]
+	}
+}
+
+// result returns the slice with all the appended objects
+func (appender *sliceAppender) result() interface{} {
+	appender.flush()
+	return appender.slice
+}
+
+// Contains checks whether an object with the given ID is stored (and hasn't been removed in the soft-delete mode).
 func (box *Box) Contains(id uint64) (bool, error) {
+	if err := box.ObjectBox.enter(); err != nil {
+		return false, err
+	}
+	defer box.ObjectBox.leave()
+
+	if box.entity.softDelete != nil {
+		return box.softContains([]uint64{id})
+	}
+
+	if box.batcher != nil && box.batcher.applicable() {
+		return box.batcher.contains(id)
+	}
+
 	var cResult C.bool
 	if err := cCall(func() C.obx_err { return C.obx_box_contains(box.cBox, C.obx_id(id), &cResult) }); err != nil {
 		return false, err
@@ -641,6 +1475,15 @@ func (box *Box) Contains(id uint64) (bool, error) {
 
 // ContainsIds checks whether all of the given objects are stored in DB.
 func (box *Box) ContainsIds(ids ...uint64) (bool, error) {
+	if err := box.ObjectBox.enter(); err != nil {
+		return false, err
+	}
+	defer box.ObjectBox.leave()
+
+	if box.entity.softDelete != nil {
+		return box.softContains(ids)
+	}
+
 	cIds, err := goIdsArrayToC(ids)
 	if err != nil {
 		return false, err
@@ -656,6 +1499,10 @@ func (box *Box) ContainsIds(ids ...uint64) (bool, error) {
 
 // RelationIds returns IDs of all target objects related to the given source object ID
 func (box *Box) RelationIds(relation *RelationToMany, sourceId uint64) ([]uint64, error) {
+	if err := box.enterRelation(relation); err != nil {
+		return nil, err
+	}
+	defer box.ObjectBox.leave()
 	targetBox, err := box.ObjectBox.box(relation.Target.Id)
 	if err != nil {
 		return nil, err
@@ -665,6 +1512,108 @@ func (box *Box) RelationIds(relation *RelationToMany, sourceId uint64) ([]uint64
 	})
 }
 
+// RelationIdsMany returns IDs of the target objects related to each of the given source object IDs, mapped by the
+// source ID. All sources are read in a single read transaction and a single native call, avoiding one call per source
+// object when hydrating the relations of many objects, e.g. of query results. Sources without targets map to an empty
+// slice.
+func (box *Box) RelationIdsMany(relation *RelationToMany, sourceIds []uint64) (map[uint64][]uint64, error) {
+	if err := box.checkRelation(relation); err != nil {
+		return nil, err
+	}
+	targetBox, err := box.ObjectBox.box(relation.Target.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make(map[uint64][]uint64, len(sourceIds))
+	if len(sourceIds) == 0 {
+		return result, nil
+	}
+
+	var cArrays = make([]*C.OBX_id_array, len(sourceIds))
+	err = box.ObjectBox.RunInReadTx(func() error {
+		return cCall(func() C.obx_err {
+			return C.obx_go_box_rel_get_ids_each(targetBox.cBox, C.obx_schema_id(relation.Id),
+				(*C.obx_id)(unsafe.Pointer(&sourceIds[0])), &cArrays[0], C.size_t(len(sourceIds)))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, cArray := range cArrays {
+		result[sourceIds[i]] = cIdsArrayToGo(cArray)
+		C.obx_id_array_free(cArray)
+	}
+	return result, nil
+}
+
+// BacklinkIds returns IDs of all source objects related to the given target object ID, i.e. navigates a standalone
+// many-to-many relation in the reverse direction
+func (box *Box) BacklinkIds(relation *RelationToMany, targetId uint64) ([]uint64, error) {
+	if err := box.enterRelation(relation); err != nil {
+		return nil, err
+	}
+	defer box.ObjectBox.leave()
+	sourceBox, err := box.ObjectBox.box(relation.Source.Id)
+	if err != nil {
+		return nil, err
+	}
+	return cGetIds(func() *C.OBX_id_array {
+		return C.obx_box_rel_get_backlink_ids(sourceBox.cBox, C.obx_schema_id(relation.Id), C.obx_id(targetId))
+	})
+}
+
+// PropertyBacklinkIds returns IDs of all objects whose to-one relation property points to the given target object ID
+func (box *Box) PropertyBacklinkIds(relation *RelationToOne, targetId uint64) ([]uint64, error) {
+	if err := box.ObjectBox.enter(); err != nil {
+		return nil, err
+	}
+	defer box.ObjectBox.leave()
+	sourceBox, err := box.ObjectBox.box(relation.Property.Entity.Id)
+	if err != nil {
+		return nil, err
+	}
+	return cGetIds(func() *C.OBX_id_array {
+		return C.obx_box_get_backlink_ids(sourceBox.cBox, C.obx_schema_id(relation.Property.Id), C.obx_id(targetId))
+	})
+}
+
+// Backlinks reads all source objects of the given relation pointing to the given target object ID.
+// The relation may be either a *RelationToMany or a *RelationToOne; the result is a slice of the source objects,
+// like the one returned by GetAll() of the source entity's Box.
+func (box *Box) Backlinks(relation interface{}, targetId uint64) (slice interface{}, err error) {
+	var sourceEntityId TypeId
+	var getIds func() ([]uint64, error)
+	switch rel := relation.(type) {
+	case *RelationToMany:
+		sourceEntityId = rel.Source.Id
+		getIds = func() ([]uint64, error) { return box.BacklinkIds(rel, targetId) }
+	case *RelationToOne:
+		sourceEntityId = rel.Property.Entity.Id
+		getIds = func() ([]uint64, error) { return box.PropertyBacklinkIds(rel, targetId) }
+	default:
+		return nil, fmt.Errorf("unsupported relation type %T", relation)
+	}
+
+	sourceBox, err := box.ObjectBox.box(sourceEntityId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = box.ObjectBox.RunInReadTx(func() error {
+		ids, err := getIds()
+		if err == nil {
+			slice, err = sourceBox.GetManyExisting(ids...)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slice, nil
+}
+
 // RelationReplace replaces all targets for a given source in a standalone many-to-many relation
 // It also inserts new related objects (with a 0 ID).
 func (box *Box) RelationReplace(relation *RelationToMany, sourceId uint64, sourceObject interface{},
@@ -677,12 +1626,32 @@ func (box *Box) RelationReplace(relation *RelationToMany, sourceId uint64, sourc
 		return err
 	}
 
+	return box.relationReplace(relation, sourceId, id != 0, nil, targetObjects)
+}
+
+// RelationReplaceKnown works like RelationReplace() but skips reading the current targets of the source object,
+// using previousTargetIds instead, e.g. as loaded together with the source object. Pass nil for a new source object.
+// The caller is responsible for previousTargetIds being up-to-date: relations to targets missing from it aren't
+// removed.
+func (box *Box) RelationReplaceKnown(relation *RelationToMany, sourceId uint64, previousTargetIds []uint64,
+	targetObjects interface{}) error {
+	return box.relationReplace(relation, sourceId, false, previousTargetIds, targetObjects)
+}
+
+// relationReplace computes the targets to add and to remove and applies them in a single native call.
+// If readPrevious is true, previousTargetIds are read from the database instead.
+func (box *Box) relationReplace(relation *RelationToMany, sourceId uint64, readPrevious bool,
+	previousTargetIds []uint64, targetObjects interface{}) error {
+	if err := box.checkRelation(relation); err != nil {
+		return err
+	}
+
 	sliceValue := reflect.ValueOf(targetObjects)
 
 	// If the slice was nil it would be handled as an empty slice and removed all relations.
 	// This would cause problems with lazy-loaded relations during update, if GetRelated wasn't called.
 	// Therefore, we preemptively prevent such updates and force users to explicitly pass an empty slice instead.
-	if sliceValue.IsNil() && id != 0 {
+	if sliceValue.IsNil() && (readPrevious || len(previousTargetIds) > 0) {
 		return fmt.Errorf("given NIL instead of an empty slice of target objects for relation ID %v - "+
 			"this is forbidden for updates due to potential code logic problems you may encounter when using "+
 			"lazy-loaded relations; pass an empty slice if you really want to remove all related entities", relation.Id)
@@ -690,22 +1659,24 @@ func (box *Box) RelationReplace(relation *RelationToMany, sourceId uint64, sourc
 
 	count := sliceValue.Len()
 
-	// make a map of related target entity IDs, marking those that were originally related but should be removed
-	var idsToRemove = make(map[uint64]bool)
-
 	return box.ObjectBox.RunInWriteTx(func() error {
-		if id != 0 {
-			oldRelIds, err := box.RelationIds(relation, sourceId)
-			if err != nil {
+		if readPrevious {
+			var err error
+			if previousTargetIds, err = box.RelationIds(relation, sourceId); err != nil {
 				return err
 			}
-			for _, rId := range oldRelIds {
-				idsToRemove[rId] = true
-			}
 		}
 
+		// make a map of related target entity IDs, marking those that were originally related but should be removed
+		var idsToRemove = make(map[uint64]bool, len(previousTargetIds))
+		for _, rId := range previousTargetIds {
+			idsToRemove[rId] = true
+		}
+
+		var idsToPut []uint64
 		if count > 0 {
 			var targetBox = box.ObjectBox.InternalBox(relation.Target.Id)
+			var seen = make(map[uint64]bool, count)
 
 			// walk over the current related objects, mark those that still exist, add the new ones
 			for i := 0; i < count; i++ {
@@ -726,31 +1697,52 @@ func (box *Box) RelationReplace(relation *RelationToMany, sourceId uint64, sourc
 					}
 				}
 
+				if seen[rId] {
+					continue
+				}
+				seen[rId] = true
+
 				if idsToRemove[rId] {
 					// old relation that still exists, keep it
 					delete(idsToRemove, rId)
 				} else {
 					// new relation, add it
-					if err := box.RelationPut(relation, sourceId, rId); err != nil {
-						return err
-					}
+					idsToPut = append(idsToPut, rId)
 				}
 			}
 		}
 
 		// remove those that were not found in the rSlice but were originally related to this entity
+		var idsToRemoveSlice = make([]uint64, 0, len(idsToRemove))
 		for rId := range idsToRemove {
-			if err := box.RelationRemove(relation, sourceId, rId); err != nil {
-				return err
-			}
+			idsToRemoveSlice = append(idsToRemoveSlice, rId)
 		}
 
-		return nil
+		if len(idsToPut) == 0 && len(idsToRemoveSlice) == 0 {
+			return nil
+		}
+
+		return cCall(func() C.obx_err {
+			return C.obx_go_box_rel_apply(box.cBox, C.obx_schema_id(relation.Id), C.obx_id(sourceId),
+				cIdsPtr(idsToPut), C.size_t(len(idsToPut)), cIdsPtr(idsToRemoveSlice), C.size_t(len(idsToRemoveSlice)))
+		})
 	})
 }
 
+// cIdsPtr returns a pointer to the first element of ids, or nil for an empty slice, to be passed with its length
+func cIdsPtr(ids []uint64) *C.obx_id {
+	if len(ids) == 0 {
+		return nil
+	}
+	return (*C.obx_id)(unsafe.Pointer(&ids[0]))
+}
+
 // RelationPut creates a relation between the given source & target objects
 func (box *Box) RelationPut(relation *RelationToMany, sourceId, targetId uint64) error {
+	if err := box.enterRelation(relation); err != nil {
+		return err
+	}
+	defer box.ObjectBox.leave()
 	return cCall(func() C.obx_err {
 		return C.obx_box_rel_put(box.cBox, C.obx_schema_id(relation.Id), C.obx_id(sourceId), C.obx_id(targetId))
 	})
@@ -758,7 +1750,123 @@ func (box *Box) RelationPut(relation *RelationToMany, sourceId, targetId uint64)
 
 // RelationRemove removes a relation between the given source & target objects
 func (box *Box) RelationRemove(relation *RelationToMany, sourceId, targetId uint64) error {
+	if err := box.enterRelation(relation); err != nil {
+		return err
+	}
+	defer box.ObjectBox.leave()
 	return cCall(func() C.obx_err {
 		return C.obx_box_rel_remove(box.cBox, C.obx_schema_id(relation.Id), C.obx_id(sourceId), C.obx_id(targetId))
 	})
 }
+
+// RelationAdd relates the given target objects to the source object in a standalone many-to-many relation, keeping
+// its existing targets. Targets with a zero ID are inserted first. The source object must have been put already.
+// Unlike RelationPut(), the IDs are read from the objects, which must be of the relation's source and target types.
+// Note: the generator (a separate module) doesn't generate typed per-relation methods (e.g. SetTags() or AddTag()); use
+// RelationAdd(), RelationRemoveTargets(), RelationSet() and RelationTargets() on the generated boxes instead.
+func (box *Box) RelationAdd(relation *RelationToMany, source interface{}, targets ...interface{}) error {
+	sourceId, err := box.relationSourceId(relation, source)
+	if err != nil {
+		return err
+	}
+
+	return box.ObjectBox.RunInWriteTx(func() error {
+		var targetBox = box.ObjectBox.InternalBox(relation.Target.Id)
+		for _, target := range targets {
+			targetId, err := targetBox.entity.binding.GetId(target)
+			if err != nil {
+				return err
+			} else if targetId == 0 {
+				if targetId, err = targetBox.Put(target); err != nil {
+					return err
+				}
+			}
+			if err := box.RelationPut(relation, sourceId, targetId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RelationRemoveTargets removes the relations between the source object and the given target objects in a standalone
+// many-to-many relation; the objects themselves are kept. Targets that aren't related are ignored.
+func (box *Box) RelationRemoveTargets(relation *RelationToMany, source interface{}, targets ...interface{}) error {
+	sourceId, err := box.relationSourceId(relation, source)
+	if err != nil {
+		return err
+	}
+
+	return box.ObjectBox.RunInWriteTx(func() error {
+		var targetBox = box.ObjectBox.InternalBox(relation.Target.Id)
+		for _, target := range targets {
+			targetId, err := targetBox.entity.binding.GetId(target)
+			if err != nil {
+				return err
+			} else if targetId == 0 {
+				continue // not stored yet, can't be related
+			}
+			if err := box.RelationRemove(relation, sourceId, targetId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RelationSet replaces all targets of the source object in a standalone many-to-many relation with the given slice
+// of target objects, inserting those with a zero ID; see RelationReplace(). The source object must have been put
+// already.
+func (box *Box) RelationSet(relation *RelationToMany, source interface{}, targets interface{}) error {
+	sourceId, err := box.relationSourceId(relation, source)
+	if err != nil {
+		return err
+	}
+	return box.RelationReplace(relation, sourceId, source, targets)
+}
+
+// RelationTargets reads the objects related to the source object in a standalone many-to-many relation.
+// Returns a slice of the target type, e.g. []*Tag, read in a single transaction.
+func (box *Box) RelationTargets(relation *RelationToMany, source interface{}) (slice interface{}, err error) {
+	sourceId, err := box.relationSourceId(relation, source)
+	if err != nil {
+		return nil, err
+	}
+
+	targetBox, err := box.ObjectBox.box(relation.Target.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = box.ObjectBox.RunInReadTx(func() error {
+		ids, err := box.RelationIds(relation, sourceId)
+		if err != nil {
+			return err
+		}
+		slice, err = targetBox.GetManyExisting(ids...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slice, nil
+}
+
+// relationSourceId verifies the relation starts at this box's entity and returns the ID of the (stored) source object
+func (box *Box) relationSourceId(relation *RelationToMany, source interface{}) (uint64, error) {
+	if err := box.checkRelation(relation); err != nil {
+		return 0, err
+	}
+	if relation.Source.Id != box.entity.id {
+		return 0, fmt.Errorf("relation %d starts at entity %d, use its box instead of the box for %s",
+			relation.Id, relation.Source.Id, box.entity.name)
+	}
+
+	sourceId, err := box.entity.binding.GetId(source)
+	if err != nil {
+		return 0, err
+	} else if sourceId == 0 {
+		return 0, fmt.Errorf("source object of relation %d must be put before relating it", relation.Id)
+	}
+	return sourceId, nil
+}
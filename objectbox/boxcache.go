@@ -0,0 +1,257 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"container/list"
+	"sync"
+	"unsafe"
+)
+
+// boxCache is an LRU cache of the data of objects read by Box.Get() and Box.GetMany(), see Box.WithCache().
+// The serialized data is cached instead of the objects so that each read returns a new instance.
+type boxCache struct {
+	box        *Box
+	capacity   int
+	mutex      sync.Mutex
+	entries    map[uint64]*list.Element
+	lru        *list.List // of *boxCacheEntry, the most recently used at the front
+	generation uint64     // incremented on each invalidation, protected by mutex
+
+	cObserver  *C.OBX_observer
+	callbackId cCallbackId
+}
+
+type boxCacheEntry struct {
+	id   uint64
+	data []byte // a copy of the serialized object
+}
+
+// WithCache enables an in-process LRU cache of up to size objects for Get() and GetMany() (read-through). The cache is
+// invalidated whenever a transaction changing objects of this entity is committed, i.e. it suits read-heavy
+// workloads dominated by repeated lookups of the same objects. Pass zero to disable the cache.
+//
+// The cache keeps the serialized data and each read constructs a new object from it, so the returned objects may be
+// modified by the caller. The cache isn't used inside explicit transactions (e.g. RunInWriteTx), so those always see
+// the current (possibly uncommitted) state. Reads missing the cache aren't batched (see Builder.BatchReads()).
+func (box *Box) WithCache(size int) error {
+	var ob = box.ObjectBox
+	cStore, err := ob.enterStore()
+	if err != nil {
+		return err
+	}
+	defer ob.leave()
+
+	ob.boxesMutex.Lock()
+	defer ob.boxesMutex.Unlock()
+
+	if previous := box.loadCache(); previous != nil {
+		box.cache.Store((*boxCache)(nil))
+		delete(ob.boxCaches, previous)
+		if err := previous.close(); err != nil {
+			return err
+		}
+	}
+
+	if size <= 0 {
+		return nil
+	}
+
+	var cache = &boxCache{
+		box:      box,
+		capacity: size,
+		entries:  make(map[uint64]*list.Element, size),
+		lru:      list.New(),
+	}
+
+	if cache.callbackId, err = cCallbackRegister(cVoidCallback(cache.invalidate)); err != nil {
+		return err
+	}
+	if err = cCallBool(func() bool {
+		cache.cObserver = C.obx_observe_single_type(cStore, C.obx_schema_id(box.entity.id),
+			(*C.obx_observer_single_type)(cVoidCallbackDispatchPtr), cache.callbackId.cPtr())
+		return cache.cObserver != nil
+	}); err != nil {
+		cCallbackUnregister(cache.callbackId)
+		return err
+	}
+
+	if ob.boxCaches == nil {
+		ob.boxCaches = make(map[*boxCache]bool)
+	}
+	ob.boxCaches[cache] = true
+	box.cache.Store(cache)
+	return nil
+}
+
+// loadCache returns the cache configured by WithCache(), if any
+func (box *Box) loadCache() *boxCache {
+	var cache, _ = box.cache.Load().(*boxCache)
+	return cache
+}
+
+// applicableCache returns the cache to use for a read, or nil if it's disabled or not applicable, i.e. if the caller
+// is running inside a transaction, which may contain uncommitted changes (the cache is only invalidated on commit).
+// Transactions of other goroutines don't prevent using the cache.
+func (box *Box) applicableCache() *boxCache {
+	if inTx() {
+		return nil
+	}
+	return box.loadCache()
+}
+
+// close stops observing the changes; must be called before the store is closed
+func (cache *boxCache) close() error {
+	var err = cCall(func() C.obx_err { return C.obx_observer_close(cache.cObserver) })
+	cCallbackUnregister(cache.callbackId)
+	return err
+}
+
+// invalidate is called by the observer after changes have been committed
+func (cache *boxCache) invalidate() {
+	cache.mutex.Lock()
+	cache.generation++
+	cache.entries = make(map[uint64]*list.Element, cache.capacity)
+	cache.lru.Init()
+	cache.mutex.Unlock()
+}
+
+// get returns the cached data, if present, and the current generation to pass to put() after reading the object
+func (cache *boxCache) get(id uint64) (data []byte, found bool, generation uint64) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, found := cache.entries[id]; found {
+		cache.lru.MoveToFront(element)
+		return element.Value.(*boxCacheEntry).data, true, cache.generation
+	}
+	return nil, false, cache.generation
+}
+
+// put adds the data read from the database unless the cache was invalidated since (the data may be outdated)
+func (cache *boxCache) put(id uint64, data []byte, generation uint64) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if generation != cache.generation {
+		return
+	}
+
+	if element, found := cache.entries[id]; found {
+		element.Value.(*boxCacheEntry).data = data
+		cache.lru.MoveToFront(element)
+		return
+	}
+
+	cache.entries[id] = cache.lru.PushFront(&boxCacheEntry{id: id, data: data})
+	if cache.lru.Len() > cache.capacity {
+		var oldest = cache.lru.Back()
+		cache.lru.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*boxCacheEntry).id)
+	}
+}
+
+// getOne returns a new instance of the object, constructed from the cached data or read from the database
+func (cache *boxCache) getOne(id uint64) (object interface{}, err error) {
+	if data, found, generation := cache.get(id); found {
+		return cache.box.entity.load(cache.box.ObjectBox, data)
+	} else if objects, err := cache.read([]uint64{id}, generation); err != nil {
+		return nil, err
+	} else {
+		return objects[0], nil
+	}
+}
+
+// getMany reads the objects missing in the cache in a single transaction and assembles the result
+func (cache *boxCache) getMany(ids []uint64) (slice interface{}, err error) {
+	var objects = make([]interface{}, len(ids))
+	var missingIds []uint64
+	var missingIndexes []int
+	var generation uint64
+	for i, id := range ids {
+		var data []byte
+		var found bool
+		if data, found, generation = cache.get(id); !found {
+			missingIds = append(missingIds, id)
+			missingIndexes = append(missingIndexes, i)
+		} else if objects[i], err = cache.box.entity.load(cache.box.ObjectBox, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(missingIds) > 0 {
+		read, err := cache.read(missingIds, generation)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range missingIndexes {
+			objects[i] = read[j]
+		}
+	}
+
+	var binding = cache.box.entity.binding
+	slice = binding.MakeSlice(len(ids))
+	for _, object := range objects {
+		slice = binding.AppendToSlice(slice, object)
+	}
+	return slice, nil
+}
+
+// read reads the objects with the given IDs (nil for the missing ones) in a single read transaction and adds their
+// data to the cache
+func (cache *boxCache) read(ids []uint64, generation uint64) (objects []interface{}, err error) {
+	var box = cache.box
+	objects = make([]interface{}, len(ids))
+	var datas = make([][]byte, len(ids))
+	err = box.ObjectBox.RunInReadTx(func() error {
+		for i, id := range ids {
+			var data *C.void
+			var dataSize C.size_t
+			var dataPtr = unsafe.Pointer(data)
+
+			var rc = C.obx_box_get(box.cBox, C.obx_id(id), &dataPtr, &dataSize)
+			if rc == C.OBX_NOT_FOUND {
+				continue
+			} else if rc != 0 {
+				return createError()
+			}
+
+			var bytes []byte
+			cVoidPtrToByteSlice(dataPtr, int(dataSize), &bytes)
+			datas[i] = append([]byte(nil), bytes...) // the bytes are only valid inside the transaction
+			if objects[i], err = box.entity.load(box.ObjectBox, datas[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, data := range datas {
+		if data != nil {
+			cache.put(ids[i], data, generation)
+		}
+	}
+	return objects, nil
+}
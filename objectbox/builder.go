@@ -24,7 +24,9 @@ import "C"
 
 import (
 	"fmt"
+	"os"
 	"runtime"
+	"time"
 	"unsafe"
 )
 
@@ -35,9 +37,47 @@ type Builder struct {
 
 	// these options are used when creating the underlying store using the C-api calls
 	// pointers are used to distinguish whether a value is present or not
-	directory   *string
-	maxSizeInKb *uint64
-	maxReaders  *uint
+	directory       *string
+	maxSizeInKb     *uint64
+	maxDataSizeInKb *uint64
+	maxReaders      *uint
+	fileMode        *os.FileMode
+
+	// async queue tuning, see Async*() options
+	asyncMaxQueueLength      *uint
+	asyncThrottleQueueLength *uint
+	asyncThrottleSleep       *time.Duration
+	asyncMaxInTxDuration     *time.Duration
+	asyncMaxInTxOperations   *uint
+
+	// opens the store read-only with a model from the history, see OpenWithModelVersion()
+	modelVersion *int
+
+	// see InternStrings()
+	internedProperties []*PropertyString
+
+	// see KV()
+	kv bool
+
+	// see UidProperties()
+	uidProperties []Property
+
+	// see OnSchemaChange()
+	onSchemaChange func(changes []SchemaChange) error
+
+	// see ValidateOnOpen()
+	validateOnOpen *ValidateOptions
+
+	// see OnEvent()
+	eventListeners []func(event StoreEvent)
+
+	// see WaitForWritableLock() and OpenIfLockedReadOnly()
+	lockWait             time.Duration
+	openIfLockedReadOnly bool
+
+	// see BoxQuota() and OnQuotaExceeded()
+	boxQuotas       map[TypeId]BoxQuota
+	onQuotaExceeded func(box *Box, err *QuotaExceededError) error
 
 	// these options are passed-through to the created ObjectBox struct
 	options
@@ -52,17 +92,17 @@ func NewBuilder() *Builder {
 			"Please see https://github.com/objectbox/objectbox-go on how to upgrade.\n" +
 			"Or, check https://github.com/objectbox/objectbox-c for the C library.")
 	} else if version.LessThan(VersionLibMinRecommended()) {
-		println("Note: the loaded ObjectBox C library should be updated.\n" +
-			"      Found ObjectBox version " + version.String() + ", but the minimum recommended version is " +
-			VersionLibMinRecommended().String() + ".")
+		if logger := getLogger(); logger != nil {
+			logger.Warn("objectbox: the loaded ObjectBox C library should be updated", "version", version.String(),
+				"recommended", VersionLibMinRecommended().String())
+		} else {
+			println("Note: the loaded ObjectBox C library should be updated.\n" +
+				"      Found ObjectBox version " + version.String() + ", but the minimum recommended version is " +
+				VersionLibMinRecommended().String() + ".")
+		}
 	}
 
-	return &Builder{
-		options: options{
-			// defaults
-			asyncTimeout: 1000, // 1s ; TODO make this 0 to use core default?
-		},
-	}
+	return &Builder{}
 }
 
 // Directory configures the path where the database is stored
@@ -77,24 +117,84 @@ func (builder *Builder) MaxSizeInKb(maxSizeInKb uint64) *Builder {
 	return builder
 }
 
+// MaxDataSizeInKb defines the maximum size the data stored in the database can take (default: no limit).
+// Unlike MaxSizeInKb(), this limit only applies to the actual data and allows removing objects once it's hit.
+// It must be lower than MaxSizeInKb().
+func (builder *Builder) MaxDataSizeInKb(maxDataSizeInKb uint64) *Builder {
+	builder.maxDataSizeInKb = &maxDataSizeInKb
+	return builder
+}
+
 // MaxReaders defines maximum concurrent readers (default: 126).
-// Increase only if you are getting errors (highly concurrent scenarios).
+// Increase only if you are getting errors (highly concurrent scenarios), e.g. "max readers exceeded".
+// Each OS thread that has executed a read transaction holds on to a reader slot until the thread ends, so servers
+// running many goroutines (and thus many threads) doing reads concurrently may need values around 200-500.
 func (builder *Builder) MaxReaders(maxReaders uint) *Builder {
 	builder.maxReaders = &maxReaders
 	return builder
 }
 
-// asyncTimeoutTBD configures the default enqueue timeout for async operations (default is 1 second).
-// See Box.PutAsync method doc for more information.
-// TODO: implement this option in core and use it
-func (builder *Builder) asyncTimeoutTBD(milliseconds uint) *Builder {
-	builder.asyncTimeout = milliseconds
+// FileMode defines the unix permissions of the database files and the directory, e.g. 0640 (default: 0644).
+// Only the permission bits are used.
+func (builder *Builder) FileMode(mode os.FileMode) *Builder {
+	builder.fileMode = &mode
+	return builder
+}
+
+// AsyncEnqueueTimeout configures how long async operations (see Box.Async()) wait for a free slot if the async queue
+// is full, before failing (default: chosen by the native library).
+func (builder *Builder) AsyncEnqueueTimeout(timeout time.Duration) *Builder {
+	builder.asyncTimeout = timeout
+	return builder
+}
+
+// AsyncMaxQueueLength defines the maximum number of async operations in the queue, new ones are rejected after
+// the enqueue timeout elapses (see AsyncEnqueueTimeout()).
+// Hitting this limit usually hints that async processing cannot keep up with the rate the data is produced.
+// Increasing this value isn't the only alternative, consider AsyncMaxInTx() too.
+func (builder *Builder) AsyncMaxQueueLength(length uint) *Builder {
+	builder.asyncMaxQueueLength = &length
+	return builder
+}
+
+// AsyncThrottle slows down producers (goroutines enqueuing async operations) once the queue reaches the given length:
+// each submission sleeps for the given time.
+func (builder *Builder) AsyncThrottle(atQueueLength uint, sleep time.Duration) *Builder {
+	builder.asyncThrottleQueueLength = &atQueueLength
+	builder.asyncThrottleSleep = &sleep
+	return builder
+}
+
+// AsyncMaxInTx limits the duration and the number of operations (the "in-flight" operations) executed by the async
+// queue in a single transaction before a commit is enforced. Relevant when the queue is constantly populated at a high
+// rate. Pass zero to keep the default for either of the values.
+func (builder *Builder) AsyncMaxInTx(maxDuration time.Duration, maxOperations uint) *Builder {
+	if maxDuration > 0 {
+		builder.asyncMaxInTxDuration = &maxDuration
+	}
+	if maxOperations > 0 {
+		builder.asyncMaxInTxOperations = &maxOperations
+	}
+	return builder
+}
+
+// BatchReads enables coalescing of small reads issued concurrently by many goroutines, e.g. in a server handling
+// many requests: Box.Get(), Box.Contains() and Box.Count() calls arriving within the given time window are executed
+// together, in a single read transaction and with fewer native calls. This trades a latency of up to `window` per call
+// for a lower per-call overhead, so it's only beneficial with a high number of concurrent point reads.
+// Reads issued while a transaction is active in the store (e.g. inside RunInReadTx) are never batched.
+// Disabled by default (window = 0).
+func (builder *Builder) BatchReads(window time.Duration) *Builder {
+	builder.batchWindow = window
 	return builder
 }
 
 // Model specifies schema for the database.
 //
 // Pass the result of the generated function ObjectBoxModel as an argument: Model(ObjectBoxModel())
+//
+// If the model is inconsistent, e.g. IDs/UIDs don't match the "last ID" declarations, the builder error (and the error
+// returned by BuildOrError) is a *ModelValidationError listing all the problems found.
 func (builder *Builder) Model(model *Model) *Builder {
 	if builder.Error != nil {
 		return builder
@@ -120,6 +220,14 @@ func (builder *Builder) Build() (*ObjectBox, error) {
 	return objectBox, nil
 }
 
+// getDirectory returns the configured directory or the one used by the core by default
+func (builder *Builder) getDirectory() string {
+	if builder.directory != nil {
+		return *builder.directory
+	}
+	return defaultDirectory
+}
+
 // BuildOrError validates the configuration and tries to init the ObjectBox.
 func (builder *Builder) BuildOrError() (*ObjectBox, error) {
 	if builder.Error != nil {
@@ -130,6 +238,37 @@ func (builder *Builder) BuildOrError() (*ObjectBox, error) {
 		return nil, fmt.Errorf("model is not defined")
 	}
 
+	if err := builder.checkBoxQuotas(); err != nil {
+		return nil, err
+	}
+
+	if builder.kv {
+		if err := builder.model.addKVEntity(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(builder.uidProperties) > 0 {
+		if err := builder.model.applyUidProperties(builder.uidProperties); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(builder.internedProperties) > 0 {
+		if err := builder.model.applyStringInterning(builder.internedProperties); err != nil {
+			return nil, err
+		}
+	}
+
+	ob, err := builder.open(builder.model, false)
+	if err != nil {
+		return builder.openLocked(err)
+	}
+	return ob, nil
+}
+
+// open creates the native store, consuming the given native model (unless opening a version from the model history).
+func (builder *Builder) open(model *Model, readOnly bool) (*ObjectBox, error) {
 	// for native calls/createError()
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
@@ -152,11 +291,81 @@ func (builder *Builder) BuildOrError() (*ObjectBox, error) {
 		C.obx_opt_max_db_size_in_kb(cOptions, C.uint64_t(*builder.maxSizeInKb))
 	}
 
+	if builder.maxDataSizeInKb != nil {
+		C.obx_opt_max_data_size_in_kb(cOptions, C.uint64_t(*builder.maxDataSizeInKb))
+	}
+
 	if builder.maxReaders != nil {
 		C.obx_opt_max_readers(cOptions, C.uint(*builder.maxReaders))
 	}
 
-	C.obx_opt_model(cOptions, builder.model.cModel)
+	if builder.fileMode != nil {
+		C.obx_opt_file_mode(cOptions, C.uint(builder.fileMode.Perm()))
+	}
+
+	if builder.asyncMaxQueueLength != nil {
+		C.obx_opt_async_max_queue_length(cOptions, C.size_t(*builder.asyncMaxQueueLength))
+	}
+
+	if builder.asyncThrottleQueueLength != nil {
+		C.obx_opt_async_throttle_at_queue_length(cOptions, C.size_t(*builder.asyncThrottleQueueLength))
+		C.obx_opt_async_throttle_micros(cOptions, C.uint32_t(*builder.asyncThrottleSleep/time.Microsecond))
+	}
+
+	if builder.asyncMaxInTxDuration != nil {
+		C.obx_opt_async_max_in_tx_duration(cOptions, C.uint32_t(*builder.asyncMaxInTxDuration/time.Microsecond))
+	}
+
+	if builder.asyncMaxInTxOperations != nil {
+		C.obx_opt_async_max_in_tx_operations(cOptions, C.uint32_t(*builder.asyncMaxInTxOperations))
+	}
+
+	if builder.validateOnOpen != nil {
+		applyValidateOnOpen(cOptions, builder.validateOnOpen)
+	}
+
+	// forwards to the Logger set by SetLogger() (if any)
+	C.obx_opt_log_callback(cOptions, cLogCallbackDispatchPtr, nil)
+
+	var directory = builder.getDirectory()
+
+	var schemaChanges []SchemaChange
+	if builder.modelVersion == nil {
+		var err error
+		if schemaChanges, err = schemaDiff(directory, builder.model); err == nil && len(schemaChanges) > 0 &&
+			builder.onSchemaChange != nil {
+			err = builder.onSchemaChange(schemaChanges)
+		}
+		if err != nil {
+			C.obx_opt_free(cOptions)
+			return nil, err
+		}
+	}
+
+	if readOnly {
+		C.obx_opt_read_only(cOptions, true)
+	}
+
+	if builder.modelVersion != nil {
+		version, err := findModelVersion(directory, *builder.modelVersion)
+		if err == nil {
+			var model = version.toModel()
+			if err = model.Error; err == nil {
+				C.obx_opt_model(cOptions, model.cModel)
+				C.obx_opt_read_only(cOptions, true)
+			}
+		}
+		if err != nil {
+			C.obx_opt_free(cOptions)
+			return nil, err
+		}
+	} else {
+		C.obx_opt_model(cOptions, model.cModel)
+	}
+
+	// read before obx_store_open() consumes the options; these may be defaults chosen by the core
+	var maxSizeInKb = uint64(C.obx_opt_get_max_db_size_in_kb(cOptions))
+	var maxDataSizeInKb = uint64(C.obx_opt_get_max_data_size_in_kb(cOptions))
 
 	// cOptions is consumed by obx_store_open() so no need to free it
 	cStore := C.obx_store_open(cOptions)
@@ -164,16 +373,53 @@ func (builder *Builder) BuildOrError() (*ObjectBox, error) {
 		return nil, createError()
 	}
 
+	// the model history is informational only, failing to update it mustn't prevent using the store
+	if builder.modelVersion == nil && !readOnly && !isInMemoryDirectory(directory) {
+		if err := recordModelVersion(directory, builder.model, builder.now()); err != nil {
+			logError("can't record the model version in the model history of "+directory, err)
+		}
+	}
+
 	ob := &ObjectBox{
-		store:          cStore,
-		entitiesById:   builder.model.entitiesById,
-		entitiesByName: builder.model.entitiesByName,
-		boxes:          make(map[TypeId]*Box, len(builder.model.entitiesById)),
-		options:        builder.options,
+		store:           cStore,
+		directory:       directory,
+		schema:          builder.model.snapshot(),
+		schemaChanges:   schemaChanges,
+		maxSizeInKb:     maxSizeInKb,
+		maxDataSizeInKb: maxDataSizeInKb,
+		entitiesById:    builder.model.entitiesById,
+		entitiesByName:  builder.model.entitiesByName,
+		boxes:           make(map[TypeId]*Box, len(builder.model.entitiesById)),
+		options:         builder.options,
+		changeLog:       newChangeLog(builder.changeLogCapacity, builder.now),
+		contention:      newContentionRecorder(builder.contentionBlockers),
+		queryCache:      newQueryCache(builder.queryCacheSize),
+		quotas:          builder.applyBoxQuotas(),
+	}
+
+	if !readOnly && builder.modelVersion == nil && !isInMemoryDirectory(directory) {
+		ob.lockOwner = writeLockInfo(directory, builder.now())
 	}
 
 	for _, entity := range builder.model.entitiesById {
 		entity.objectBox = ob
 	}
+	if builder.diagnosticsDirectory != "" {
+		ob.registerDiagnostics(builder.diagnosticsDirectory)
+	}
+
+	ob.events.now = ob.Now
+	for _, listener := range builder.eventListeners {
+		ob.events.subscribe(listener)
+	}
+	ob.events.emit(StoreEvent{Kind: StoreOpened})
+	if len(schemaChanges) > 0 {
+		if logger := getLogger(); logger != nil {
+			for _, change := range schemaChanges {
+				logger.Info("objectbox: model changed", "directory", directory, "change", change.String())
+			}
+		}
+		ob.events.emit(StoreEvent{Kind: StoreModelUpgraded, SchemaChanges: schemaChanges})
+	}
 	return ob, nil
 }
@@ -16,12 +16,13 @@
 
 package objectbox
 
-// This file implements externs defined in c-callbacks.go.
+// This file implements externs defined in c-callbacks.go and logger.go.
 // It needs to be separate or it would cause duplicate symbol errors during linking.
 // See https://golang.org/cmd/cgo/#hdr-C_references_to_Go for more details.
 
 /*
 #include <stdbool.h>
+#include <stddef.h>
 #include <stdint.h>
 */
 import "C"
@@ -62,3 +63,8 @@ func cVoidConstVoidCallbackDispatch(callbackIdPtr C.uintptr_t, arg unsafe.Pointe
 		callback.callVoidConstVoid(arg)
 	}
 }
+
+//export cLogCallbackDispatch
+func cLogCallbackDispatch(level C.int, message *C.char, size C.size_t, userData unsafe.Pointer) {
+	coreLogged(int(level), C.GoStringN(message, C.int(size)))
+}
@@ -162,7 +162,7 @@ func cCallbackLookup(id C.uintptr_t) cCallable {
 	fn, found := cCallbackMap[cCallbackId(id)]
 	if !found {
 		// this might happen in extraordinary circumstances, e.g. during shutdown if there are still some sync listeners
-		fmt.Println(fmt.Errorf("invalid C-API callback ID %d", id))
+		logError("callback dispatch failed", fmt.Errorf("invalid C-API callback ID %d", id))
 		return nil
 	}
 
@@ -23,296 +23,302 @@ package objectbox
 import "C"
 import (
 	"errors"
-	"runtime"
+	"github.com/objectbox/objectbox-go/objectbox/obxerr"
 )
 
 // provides wrappers for objectbox C-api calls, making sure the returned error belongs to this call.
 
 func cCall(fn func() C.obx_err) (err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	if rc := fn(); rc != 0 {
-		err = createError()
+		err = createCallError(pinned, rc)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return err
 }
 
 func cCallBool(fn func() bool) (err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	if successful := fn(); !successful {
-		err = createError()
+		err = createCallError(pinned, 0)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return err
 }
 
 // cGetIds converts the given C array to Go and frees the source
 func cGetIds(fn func() *C.OBX_id_array) (ids []uint64, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		ids = cIdsArrayToGo(cArray)
 		C.obx_id_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return ids, err
 }
 
 // cGetBytesArray converts the given C array to Go and frees the source
 func cGetBytesArray(fn func() *C.OBX_bytes_array) (array [][]byte, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		array = cBytesArrayToGo(cArray)
 		C.obx_bytes_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return array, err
 }
 
 // cGetStrings converts the given C array to Go and frees the source
 func cGetStrings(fn func() *C.OBX_string_array) (items []string, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cStringsArrayToGo(cArray)
 		C.obx_string_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetInts converts the given C array to Go and frees the source
 func cGetInts(fn func() *C.OBX_int64_array) (items []int, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cIntsArrayToGo(cArray)
 		C.obx_int64_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetUint64s converts the given C array to Go and frees the source
 func cGetUints(fn func() *C.OBX_int64_array) (items []uint, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cUintsArrayToGo(cArray)
 		C.obx_int64_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetInt64s converts the given C array to Go and frees the source
 func cGetInt64s(fn func() *C.OBX_int64_array) (items []int64, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cInt64sArrayToGo(cArray)
 		C.obx_int64_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetUint64s converts the given C array to Go and frees the source
 func cGetUint64s(fn func() *C.OBX_int64_array) (items []uint64, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cUint64sArrayToGo(cArray)
 		C.obx_int64_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetInt32s converts the given C array to Go and frees the source
 func cGetInt32s(fn func() *C.OBX_int32_array) (items []int32, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cInt32sArrayToGo(cArray)
 		C.obx_int32_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetUint32s converts the given C array to Go and frees the source
 func cGetUint32s(fn func() *C.OBX_int32_array) (items []uint32, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cUint32sArrayToGo(cArray)
 		C.obx_int32_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetInt16s converts the given C array to Go and frees the source
 func cGetInt16s(fn func() *C.OBX_int16_array) (items []int16, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cInt16sArrayToGo(cArray)
 		C.obx_int16_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetUint16s converts the given C array to Go and frees the source
 func cGetUint16s(fn func() *C.OBX_int16_array) (items []uint16, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cUint16sArrayToGo(cArray)
 		C.obx_int16_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetInt8s converts the given C array to Go and frees the source
 func cGetInt8s(fn func() *C.OBX_int8_array) (items []int8, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cInt8sArrayToGo(cArray)
 		C.obx_int8_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetUint8s converts the given C array to Go and frees the source
 func cGetUint8s(fn func() *C.OBX_int8_array) (items []uint8, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cUint8sArrayToGo(cArray)
 		C.obx_int8_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetBools converts the given C array to Go and frees the source
 func cGetBools(fn func() *C.OBX_int8_array) (items []bool, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cBoolsArrayToGo(cArray)
 		C.obx_int8_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetFloat64s converts the given C array to Go and frees the source
 func cGetFloat64s(fn func() *C.OBX_double_array) (items []float64, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cFloat64sArrayToGo(cArray)
 		C.obx_double_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // cGetFloat32s converts the given C array to Go and frees the source
 func cGetFloat32s(fn func() *C.OBX_float_array) (items []float32, err error) {
-	runtime.LockOSThread()
+	var pinned = pinThread()
 
 	var cArray = fn()
 	if cArray == nil {
-		err = createError()
+		err = createCallError(pinned, 0)
 	} else {
 		items = cFloat32sArrayToGo(cArray)
 		C.obx_float_array_free(cArray)
 	}
 
-	runtime.UnlockOSThread()
+	unpinThread(pinned)
 	return items, err
 }
 
 // createError fetches the latest error that happened in the c-api on a current-thread.
 // The c-api uses thread-local storage for the latest error so we need to lock the current goroutine to a thread.
 // Must only be called when runtime.LockOSThread() is active. Either use one of the above cCall-style functions or a TX.
+// The cCall-style functions respect the ThreadPinning policy, see createCallError().
 func createError() error {
 	msg := C.obx_last_error_message()
 	if msg == nil {
 		return errors.New("no error info available; please report")
 	}
-	return errors.New(C.GoString(msg))
+	var code = C.obx_last_error_code()
+	var err = &obxerr.Error{Code: int(code), Secondary: int(C.obx_last_error_secondary()), Message: C.GoString(msg)}
+	if isCorruptionError(code) {
+		reportCorruption(err)
+	}
+	return err
 }
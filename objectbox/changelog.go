@@ -0,0 +1,178 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChangeOperation identifies the kind of a Change
+type ChangeOperation string
+
+const (
+	// ChangePut - an object was inserted or updated
+	ChangePut ChangeOperation = "put"
+
+	// ChangeRemove - an object was removed
+	ChangeRemove ChangeOperation = "remove"
+
+	// ChangeRemoveAll - all objects of the entity were removed (Box.RemoveAll()); ObjectId is 0
+	ChangeRemoveAll ChangeOperation = "removeAll"
+)
+
+// Change is a single entry of the change log, see Builder.ChangeLog() and ObjectBox.Changes()
+type Change struct {
+	Seq       uint64 // sequence number, increasing by one with each change, starting at 1 when the store is opened
+	EntityId  TypeId
+	ObjectId  uint64
+	Operation ChangeOperation
+	Timestamp time.Time // when the operation was executed; changes in a transaction are recorded once it's committed
+}
+
+// ErrChangesUnavailable is returned by ObjectBox.Changes() if some of the requested changes aren't in the change log:
+// either because they have already been dropped to keep the configured capacity, or because the sequence number
+// is from a previous session (the change log starts again when the store is reopened).
+// The consumer has missed changes and needs to resynchronize, e.g. by reading all objects.
+var ErrChangesUnavailable = errors.New("the requested changes are not available in the change log anymore")
+
+// changeLog holds the state of Builder.ChangeLog()
+type changeLog struct {
+	capacity int
+	now      func() time.Time // see Builder.Clock()
+	mutex    sync.Mutex
+	changes  []Change // the latest changes, ordered by Seq, at most capacity
+	lastSeq  uint64
+
+	// changes done in the currently active write transaction, recorded once the outermost transaction is committed;
+	// there's only one write transaction at a time, other writers are blocked by the database until it's finished
+	txDepth int
+	pending []Change
+}
+
+// ChangeLog makes the store record put and remove operations (entity ID, object ID, operation and time) to feed
+// external systems, e.g. a message queue or an ETL process, see ObjectBox.Changes(). The log keeps the latest
+// capacity changes in memory, older ones are dropped; a zero capacity disables the log.
+// Changes done inside a transaction are only recorded if the transaction is committed.
+//
+// Note: operations executed by an AsyncBox are not recorded because they're only executed in the background.
+// Removing objects by their IDs records all the given IDs, even those of objects that didn't exist.
+func (builder *Builder) ChangeLog(capacity int) *Builder {
+	builder.changeLogCapacity = capacity
+	return builder
+}
+
+// Changes returns the changes recorded after the one with the given sequence number, the oldest first;
+// pass 0 to get all changes since the store was opened. Consumers keep the Seq of the last change they've
+// processed and pass it to the next call. Returns ErrChangesUnavailable if some of the changes have been dropped.
+func (ob *ObjectBox) Changes(since uint64) ([]Change, error) {
+	if ob.changeLog == nil {
+		return nil, errors.New("change log is not enabled, see Builder.ChangeLog()")
+	}
+	return ob.changeLog.since(since)
+}
+
+func newChangeLog(capacity int, now func() time.Time) *changeLog {
+	if capacity <= 0 {
+		return nil
+	}
+	return &changeLog{capacity: capacity, now: now}
+}
+
+func (log *changeLog) since(seq uint64) ([]Change, error) {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	if seq > log.lastSeq {
+		return nil, ErrChangesUnavailable
+	}
+	if len(log.changes) == 0 || seq >= log.lastSeq {
+		return []Change{}, nil
+	}
+
+	var first = sort.Search(len(log.changes), func(i int) bool { return log.changes[i].Seq > seq })
+	if first == 0 && log.changes[0].Seq > seq+1 {
+		return nil, ErrChangesUnavailable
+	}
+	return append([]Change(nil), log.changes[first:]...), nil
+}
+
+// record adds the changes of a successful operation; a nil log (disabled) is a no-op
+func (log *changeLog) record(entityId TypeId, operation ChangeOperation, ids ...uint64) {
+	if log == nil {
+		return
+	}
+
+	var timestamp = log.now()
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	for _, id := range ids {
+		var change = Change{EntityId: entityId, ObjectId: id, Operation: operation, Timestamp: timestamp}
+		if log.txDepth > 0 {
+			log.pending = append(log.pending, change)
+		} else {
+			log.append(change)
+		}
+	}
+}
+
+// append must be called with the mutex locked
+func (log *changeLog) append(change Change) {
+	log.lastSeq++
+	change.Seq = log.lastSeq
+	log.changes = append(log.changes, change)
+	if len(log.changes) > log.capacity {
+		log.changes = log.changes[len(log.changes)-log.capacity:]
+	}
+}
+
+// txStarted is called after a write transaction has been started
+func (log *changeLog) txStarted() {
+	if log == nil {
+		return
+	}
+	log.mutex.Lock()
+	log.txDepth++
+	log.mutex.Unlock()
+}
+
+// txFinished closes a write transaction using the given function, returning whether it has been committed, and
+// records the pending changes once the outermost transaction is committed. The mutex is held while closing so that
+// changes of writers waiting for the transaction to finish are recorded after the ones of the transaction.
+func (log *changeLog) txFinished(closeFn func() (committed bool)) {
+	if log == nil {
+		closeFn()
+		return
+	}
+
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	var committed = closeFn()
+	if log.txDepth--; log.txDepth > 0 {
+		return
+	}
+	if committed {
+		for _, change := range log.pending {
+			log.append(change)
+		}
+	}
+	log.pending = nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import "time"
+
+// Clock sets the function providing the current time to the store, used instead of time.Now() by the time-dependent
+// features: the background worker of an Expirer, query parameters relative to now (see Query.SetTimeParamsFromNow()),
+// change log timestamps, store event times and the model history. Inject a fixed or simulated clock to make tests
+// and simulations deterministic. Application code can read the same clock using ObjectBox.Now().
+func (builder *Builder) Clock(now func() time.Time) *Builder {
+	builder.clock = now
+	return builder
+}
+
+// Now returns the current time according to the clock configured by Builder.Clock(), time.Now() by default
+func (ob *ObjectBox) Now() time.Time {
+	return ob.options.now()
+}
+
+func (options *options) now() time.Time {
+	if options.clock != nil {
+		return options.clock()
+	}
+	return time.Now()
+}
+
+// SetTimeParamsFromNow changes query parameter values on the given date property to the current time of the store's
+// clock (see Builder.Clock()) shifted by the given offsets, e.g. to find objects created within the last hour:
+//
+//	query := box.Query(Task_.Created.GreaterThan(0))
+//	err := query.SetTimeParamsFromNow(Task_.Created, -time.Hour)
+//
+// Values are stored in milliseconds for "date" properties and in nanoseconds for "date-nano" properties; for aliases,
+// the property isn't known so milliseconds are used.
+func (query *Query) SetTimeParamsFromNow(identifier propertyOrAlias, offsets ...time.Duration) error {
+	var unit = time.Millisecond
+	if identifier.alias() == nil {
+		var info = query.objectBox.schemaProperty(identifier.entityId(), identifier.propertyId())
+		if info != nil && info.Type == C.OBXPropertyType_DateNano {
+			unit = time.Nanosecond
+		}
+	}
+
+	var now = query.objectBox.Now()
+	var values = make([]int64, len(offsets))
+	for i, offset := range offsets {
+		values[i] = now.Add(offset).UnixNano() / int64(unit)
+	}
+	return query.SetInt64Params(identifier, values...)
+}
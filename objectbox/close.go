@@ -0,0 +1,116 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// CloseTimeoutError is returned by CloseWithTimeout() if the store couldn't be drained in time.
+type CloseTimeoutError struct {
+	Timeout time.Duration
+
+	// AsyncPending is true if the async queue still contained operations; those are discarded
+	AsyncPending bool
+
+	// ActiveTransactions is the number of transactions still running; the store is left open (but not accepting new
+	// transactions) if non-zero, call Close() once they have finished
+	ActiveTransactions int
+}
+
+func (err *CloseTimeoutError) Error() string {
+	var msg = fmt.Sprintf("store couldn't be drained within %v", err.Timeout)
+	if err.AsyncPending {
+		msg += "; async operations were still pending and have been discarded"
+	}
+	if err.ActiveTransactions > 0 {
+		msg += fmt.Sprintf("; %d transactions still active, the store hasn't been closed", err.ActiveTransactions)
+	}
+	return msg
+}
+
+// closeDrainInterval is how often CloseWithTimeout() checks for remaining active transactions
+const closeDrainInterval = time.Millisecond
+
+// CloseWithTimeout closes the store gracefully: it stops accepting new transactions and async operations (failing
+// them with ErrStoreClosing), waits for the operations already submitted to the async queue to be processed and for
+// the active transactions to finish, and then closes the store like Close().
+//
+// If that doesn't happen within the given timeout, a *CloseTimeoutError reports what was still running: pending async
+// operations are discarded and the store is closed, unless transactions are still active - closing the native store
+// under running transactions isn't safe, so it's left in the closing state and Close() must be called later.
+// Returns nil if the store has already been closed. For stores obtained by GetOrOpen(), this only releases a single
+// reference the same way Close() does; the last one stays registered until the store is actually closed.
+func (ob *ObjectBox) CloseWithTimeout(timeout time.Duration) error {
+	// keeps the native store until the native calls below have returned, even if Close() is called meanwhile
+	store, err := ob.enterStore()
+	if err != nil {
+		return nil
+	}
+	defer ob.leave()
+	if !ob.startClosingRegistered() {
+		return nil
+	}
+
+	var deadline = time.Now().Add(timeout)
+
+	// the native call can't be interrupted (other than by obx_store_prepare_to_close()) so wait in a goroutine
+	var asyncDone = make(chan struct{})
+	go func() {
+		C.obx_store_await_async_submitted(store)
+		close(asyncDone)
+	}()
+
+	var timer = time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var asyncPending bool
+	select {
+	case <-asyncDone:
+	case <-timer.C:
+		asyncPending = true
+	}
+
+	var activeTx = int(atomic.LoadInt32(&ob.activeTxCount))
+	for activeTx > 0 && time.Now().Before(deadline) {
+		time.Sleep(closeDrainInterval)
+		activeTx = int(atomic.LoadInt32(&ob.activeTxCount))
+	}
+
+	if !asyncPending && activeTx == 0 {
+		ob.releaseRegistered()
+		ob.close()
+		return nil
+	}
+
+	// make the native store reject any remaining work, which also ends the await above
+	C.obx_store_prepare_to_close(store)
+	<-asyncDone
+
+	if activeTx == 0 {
+		ob.releaseRegistered()
+		ob.close()
+	}
+	return &CloseTimeoutError{Timeout: timeout, AsyncPending: asyncPending, ActiveTransactions: activeTx}
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"github.com/google/flatbuffers/go"
+)
+
+// PropertyCodec transforms the stored values of String and ByteVector properties, e.g. to encrypt personal data at
+// rest with keys managed by the application. See ObjectBox.SetPropertyCodec().
+type PropertyCodec interface {
+	// Encode is called with the value of the given property when an object is written; returns the value to store.
+	Encode(propertyId TypeId, value []byte) ([]byte, error)
+
+	// Decode is called with the stored value of the given property when an object is read; returns the original value.
+	Decode(propertyId TypeId, value []byte) ([]byte, error)
+}
+
+// propertyCodec applies a PropertyCodec to the serialized objects of an entity
+type propertyCodec struct {
+	codec      PropertyCodec
+	entity     *ModelEntityInfo
+	properties map[TypeId]bool // the properties to encode/decode
+}
+
+// SetPropertyCodec installs a codec transforming the values of the given properties of the entity, transparently
+// encoding them when objects are written and decoding them when objects are read. If no properties are given, all
+// String and ByteVector properties are transformed. Pass a nil codec to remove it.
+//
+// Set the codec right after opening the store, before using the entity; it's not synchronized with running
+// operations. Note that the database only sees the encoded values: queries and indexes on those properties work with
+// the encoded values, as do GetBytes() and Query.FindProjected(). Encoded strings are stored as String values so
+// they should remain valid UTF-8, e.g. by encoding them as base64; unique and indexed properties only make sense
+// with a deterministic encoding.
+func (ob *ObjectBox) SetPropertyCodec(entityId TypeId, codec PropertyCodec, propertyIds ...TypeId) error {
+	var entity = ob.entitiesById[entityId]
+	if entity == nil {
+		return fmt.Errorf("entity %d not found", entityId)
+	}
+
+	if codec == nil {
+		entity.codec = nil
+		return nil
+	}
+
+	var result = &propertyCodec{codec: codec, properties: make(map[TypeId]bool)}
+	for _, e := range ob.schema.Entities {
+		if e.Id == entityId {
+			result.entity = e
+		}
+	}
+
+	for _, p := range result.entity.Properties {
+		if len(propertyIds) == 0 && (p.Type == C.OBXPropertyType_String || p.Type == C.OBXPropertyType_ByteVector) {
+			result.properties[p.Id] = true
+		}
+	}
+	for _, id := range propertyIds {
+		var p = ob.schemaProperty(entityId, id)
+		if p == nil {
+			return fmt.Errorf("property %d not found in entity %s", id, entity.name)
+		}
+		if p.Type != C.OBXPropertyType_String && p.Type != C.OBXPropertyType_ByteVector {
+			return fmt.Errorf("property %s.%s of type %s can't be encoded, only String and ByteVector are supported",
+				entity.name, p.Name, propertyTypeName(p.Type))
+		}
+		result.properties[id] = true
+	}
+
+	entity.codec = result
+	return nil
+}
+
+// transcode rebuilds the given FlatBuffers object, passing the values of the codec properties through fn
+func (pc *propertyCodec) transcode(data []byte, fn func(TypeId, []byte) ([]byte, error)) ([]byte, error) {
+	var table = &flatbuffers.Table{
+		Bytes: data,
+		Pos:   flatbuffers.GetUOffsetT(data),
+	}
+	var fbb = flatbuffers.NewBuilder(len(data) + len(data)/4)
+
+	// offsets (strings and vectors) must be created before starting the table
+	var offsets = make(map[TypeId]flatbuffers.UOffsetT)
+	for _, p := range pc.entity.Properties {
+		var o = flatbuffers.UOffsetT(table.Offset(flatbuffers.VOffsetT(4 + 2*(p.Id-1))))
+		if o == 0 {
+			continue
+		}
+		switch p.Type {
+		case C.OBXPropertyType_String, C.OBXPropertyType_ByteVector, C.OBXPropertyType_Flex:
+			var value = table.ByteVector(o + table.Pos)
+			if pc.properties[p.Id] {
+				var err error
+				if value, err = fn(p.Id, value); err != nil {
+					return nil, err
+				}
+			}
+			if p.Type == C.OBXPropertyType_String {
+				offsets[p.Id] = fbb.CreateByteString(value)
+			} else {
+				offsets[p.Id] = fbb.CreateByteVector(value)
+			}
+		case C.OBXPropertyType_StringVector:
+			var vector = table.Vector(o)
+			var count = table.VectorLen(o)
+			var items = make([]flatbuffers.UOffsetT, count)
+			for i := 0; i < count; i++ {
+				var item = vector + flatbuffers.UOffsetT(i)*flatbuffers.SizeUOffsetT
+				items[i] = fbb.CreateByteString(table.ByteVector(item))
+			}
+			fbb.StartVector(flatbuffers.SizeUOffsetT, count, flatbuffers.SizeUOffsetT)
+			for i := count - 1; i >= 0; i-- {
+				fbb.PrependUOffsetT(items[i])
+			}
+			offsets[p.Id] = fbb.EndVector(count)
+		default:
+			if elementSize := vectorElementSize(p.Type); elementSize > 0 {
+				// copy the raw data of scalar vectors
+				var vector = int(table.Vector(o))
+				var count = table.VectorLen(o)
+				fbb.StartVector(elementSize, count, elementSize)
+				for i := count*elementSize - 1; i >= 0; i-- {
+					fbb.PrependByte(data[vector+i])
+				}
+				offsets[p.Id] = fbb.EndVector(count)
+			}
+		}
+	}
+
+	fbb.StartObject(int(pc.entity.LastPropertyId.Id))
+	for _, p := range pc.entity.Properties {
+		var slot = int(p.Id) - 1
+		if offset, found := offsets[p.Id]; found {
+			fbb.PrependUOffsetTSlot(slot, offset, 0)
+			continue
+		}
+		var o = flatbuffers.UOffsetT(table.Offset(flatbuffers.VOffsetT(4 + 2*(p.Id-1))))
+		if o == 0 {
+			continue
+		}
+		var pos = o + table.Pos
+		switch p.Type {
+		case C.OBXPropertyType_Bool, C.OBXPropertyType_Byte:
+			fbb.PrependUint8(table.GetUint8(pos))
+		case C.OBXPropertyType_Short, C.OBXPropertyType_Char:
+			fbb.PrependUint16(table.GetUint16(pos))
+		case C.OBXPropertyType_Int, C.OBXPropertyType_Float:
+			fbb.PrependUint32(table.GetUint32(pos))
+		case C.OBXPropertyType_Long, C.OBXPropertyType_Double, C.OBXPropertyType_Date, C.OBXPropertyType_DateNano,
+			C.OBXPropertyType_Relation:
+			fbb.PrependUint64(table.GetUint64(pos))
+		default:
+			continue
+		}
+		fbb.Slot(slot)
+	}
+	fbb.Finish(fbb.EndObject())
+	return fbb.FinishedBytes(), nil
+}
+
+// vectorElementSize returns the size of a single element of the given scalar vector type or 0 for other types
+func vectorElementSize(propertyType int) int {
+	switch propertyType {
+	case C.OBXPropertyType_BoolVector:
+		return 1
+	case C.OBXPropertyType_ShortVector, C.OBXPropertyType_CharVector:
+		return 2
+	case C.OBXPropertyType_IntVector, C.OBXPropertyType_FloatVector:
+		return 4
+	case C.OBXPropertyType_LongVector, C.OBXPropertyType_DoubleVector, C.OBXPropertyType_DateVector,
+		C.OBXPropertyType_DateNanoVector:
+		return 8
+	}
+	return 0
+}
+
+func (pc *propertyCodec) encode(data []byte) ([]byte, error) {
+	return pc.transcode(data, pc.codec.Encode)
+}
+
+func (pc *propertyCodec) decode(data []byte) ([]byte, error) {
+	return pc.transcode(data, pc.codec.Decode)
+}
+
+// plain rebuilds the given (not encoded) data the same way decode() does, i.e. objects with the same content result
+// in the same bytes regardless of the layout produced by the binding or of the codec being non-deterministic
+func (pc *propertyCodec) plain(data []byte) ([]byte, error) {
+	return pc.transcode(data, func(_ TypeId, value []byte) ([]byte, error) {
+		return value, nil
+	})
+}
+
+// encodeValue returns the value of the property as stored, e.g. to look it up by a query; values of properties
+// without a codec (or if pc is nil) are returned unchanged
+func (pc *propertyCodec) encodeValue(propertyId TypeId, value interface{}) (interface{}, error) {
+	if pc == nil || !pc.properties[propertyId] {
+		return value, nil
+	}
+	switch v := value.(type) {
+	case string:
+		encoded, err := pc.codec.Encode(propertyId, []byte(v))
+		return string(encoded), err
+	case []byte:
+		return pc.codec.Encode(propertyId, v)
+	}
+	return value, nil
+}
@@ -44,6 +44,11 @@ type conditionClosure struct {
 }
 
 func (condition *conditionClosure) applyTo(qb *QueryBuilder, isRoot bool) (ConditionId, error) {
+	// the opposite condition may consist of multiple conditions (e.g. for Between) so the alias wouldn't be reliable
+	if condition.alias != nil && qb.negated {
+		return 0, fmt.Errorf("using Alias/As(\"%s\") inside Not() is not supported", *condition.alias)
+	}
+
 	cid, err := condition.apply(qb)
 	if err != nil {
 		return 0, err
@@ -93,12 +98,21 @@ func (condition *conditionCombination) applyTo(qb *QueryBuilder, isRoot bool) (C
 		return 0, fmt.Errorf("using Alias/As(\"%s\") on a combination of conditions is not supported", *condition.alias)
 	}
 
+	for i, sub := range condition.conditions {
+		if sub == nil {
+			return 0, fmt.Errorf("condition %d of %s is nil", i, condition.name())
+		}
+	}
+
 	if len(condition.conditions) == 0 {
 		return 0, nil
 	} else if len(condition.conditions) == 1 {
 		return condition.conditions[0].applyTo(qb, isRoot)
 	}
 
+	// inside Not(), the operator is inverted as well: not (a or b) == (not a) and (not b)
+	var or = condition.or != qb.negated
+
 	ids := make([]ConditionId, 0, len(condition.conditions))
 	for _, sub := range condition.conditions {
 		cid, err := sub.applyTo(qb, false)
@@ -106,29 +120,42 @@ func (condition *conditionCombination) applyTo(qb *QueryBuilder, isRoot bool) (C
 			return 0, err
 		}
 
-		// Skip order pseudo conditions.
+		// Skip order pseudo conditions and empty combinations (they don't restrict the results).
 		// Note: conditionIdFakeLink is allowed here and is caught below if used in non-root or in an "ALL" combination.
-		if cid != conditionIdFakeOrder {
+		if cid != conditionIdFakeOrder && cid != 0 {
 			ids = append(ids, cid)
 		}
 	}
 
 	// root All (AND) is implicit so no need to actually combine the conditions
-	if isRoot && !condition.or {
+	if isRoot && !or {
 		return 0, nil
 	}
 
+	if len(ids) == 0 {
+		return 0, nil
+	} else if len(ids) == 1 {
+		return ids[0], nil
+	}
+
 	if err := condition.assertNoLinks(ids); err != nil {
 		return 0, err
 	}
 
-	if condition.or {
+	if or {
 		return qb.Any(ids)
 	}
 
 	return qb.All(ids)
 }
 
+func (condition *conditionCombination) name() string {
+	if condition.or {
+		return "Any()"
+	}
+	return "All()"
+}
+
 // Alias sets a string alias for the given condition. It can later be used in Query.Set*Params() methods.
 // This is an invalid call on a combination of conditions and will result in an error.
 func (condition *conditionCombination) Alias(alias string) Condition {
@@ -143,7 +170,9 @@ func (condition *conditionCombination) As(alias *alias) Condition {
 	return condition
 }
 
-// Any provides a way to combine multiple query conditions (equivalent to OR logical operator)
+// Any provides a way to combine multiple query conditions (equivalent to OR logical operator).
+// The conditions may be built dynamically, e.g. from user-supplied filters: `Any(conditions...)`.
+// A combination without any conditions doesn't restrict the results.
 func Any(conditions ...Condition) Condition {
 	return &conditionCombination{
 		or:         true,
@@ -151,13 +180,71 @@ func Any(conditions ...Condition) Condition {
 	}
 }
 
-// All provides a way to combine multiple query conditions (equivalent to AND logical operator)
+// All provides a way to combine multiple query conditions (equivalent to AND logical operator).
+// The conditions may be built dynamically, e.g. from user-supplied filters: `All(conditions...)`.
+// A combination without any conditions doesn't restrict the results.
 func All(conditions ...Condition) Condition {
 	return &conditionCombination{
 		conditions: conditions,
 	}
 }
 
+// Not negates the given condition, i.e. matches objects not matched by the condition.
+// There's no negation in the database queries so each condition is replaced by its opposite, e.g. Equals() by
+// NotEquals() or LessThan() by GreaterOrEqual(), and combinations are inverted, e.g. `Not(Any(a, b))` becomes
+// `All(Not(a), Not(b))`. Nested negations cancel each other out.
+//
+// Conditions without an opposite (e.g. string Contains(), HasPrefix() or In()), links and Alias()/As() are not
+// supported inside Not() and cause an error when building the query.
+// Note: like with the opposite condition, objects with a nil value of the property may not be matched.
+func Not(condition Condition) Condition {
+	return &conditionNot{condition: condition}
+}
+
+// conditionNot negates a condition, see Not()
+type conditionNot struct {
+	condition Condition
+	alias     *string // this is only used to report an error
+}
+
+func (condition *conditionNot) applyTo(qb *QueryBuilder, isRoot bool) (ConditionId, error) {
+	if condition.alias != nil {
+		return 0, fmt.Errorf("using Alias/As(\"%s\") on Not() is not supported", *condition.alias)
+	}
+	if condition.condition == nil {
+		return 0, errors.New("condition passed to Not() is nil")
+	}
+
+	qb.negated = !qb.negated
+	cid, err := condition.condition.applyTo(qb, isRoot)
+	qb.negated = !qb.negated
+	if err != nil {
+		return 0, err
+	}
+
+	switch cid {
+	case conditionIdFakeLink:
+		return 0, errors.New("using Link inside Not is not supported")
+	case conditionIdFakeOrder:
+		return 0, errors.New("using Order*() inside Not is not supported")
+	}
+	return cid, nil
+}
+
+// Alias sets a string alias for the given condition. It can later be used in Query.Set*Params() methods.
+// This is an invalid call on a negated condition and will result in an error.
+func (condition *conditionNot) Alias(alias string) Condition {
+	condition.alias = &alias
+	return condition
+}
+
+// As sets an alias for the given condition. It can later be used in Query.Set*Params() methods.
+// This is an invalid call on a negated condition and will result in an error.
+func (condition *conditionNot) As(alias *alias) Condition {
+	condition.alias = alias.alias()
+	return condition
+}
+
 // implements propertyOrAlias
 type alias struct {
 	string
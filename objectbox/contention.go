@@ -0,0 +1,218 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxContentionStacks limits the number of distinct blocker stacks tracked by a contention recorder
+const maxContentionStacks = 1000
+
+// maxContentionFrames limits the depth of the stacks identifying write transactions
+const maxContentionFrames = 32
+
+// ContentionReport summarizes how long write transactions waited for each other, see Builder.RecordContention().
+type ContentionReport struct {
+	// WriteTransactions is the number of write transactions started since recording began
+	WriteTransactions uint64
+
+	// TotalWait is the time spent waiting for the write lock, summed over all write transactions
+	TotalWait time.Duration
+
+	// MaxWait is the longest time a single write transaction waited for the write lock
+	MaxWait time.Duration
+
+	// Blockers are the write transactions (identified by the stack of the goroutine starting them) other write
+	// transactions waited for, sorted by the caused wait time (longest first). Waits for writers outside of this
+	// ObjectBox instance, e.g. the async queue, aren't attributed to a blocker. Neither are waits for single-object
+	// operations like Box.Put() called outside of a transaction: they run in implicit native transactions, which aren't
+	// recorded (and whose own waits aren't measured) - wrap them in RunInWriteTx() to include them.
+	Blockers []ContentionBlocker
+}
+
+// ContentionBlocker describes a write transaction call site other write transactions waited for
+type ContentionBlocker struct {
+	// Stack of the goroutine that started the blocking transaction: a function and its file:line per frame
+	Stack string
+
+	// Waits is the number of write transactions that waited for a transaction started at this stack
+	Waits uint64
+
+	// TotalWait is the wait time caused by this call site, summed over all waiting transactions
+	TotalWait time.Duration
+
+	// MaxWait is the longest wait time caused by this call site
+	MaxWait time.Duration
+}
+
+// String formats the report for logging, e.g. at the end of a benchmark.
+func (report ContentionReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d write transactions waited %v in total, %v at most\n", report.WriteTransactions,
+		report.TotalWait, report.MaxWait)
+	for i, blocker := range report.Blockers {
+		fmt.Fprintf(&sb, "#%d blocked %d transactions for %v in total, %v at most:\n%s\n", i+1, blocker.Waits,
+			blocker.TotalWait, blocker.MaxWait, blocker.Stack)
+	}
+	return sb.String()
+}
+
+// RecordContention makes the store measure how long write transactions wait for the write lock, which is held by a
+// single write transaction at a time, and remember the call sites of the longest blockers; see
+// ObjectBox.ContentionReport(). Use it to diagnose why throughput plateaus with concurrent writers.
+// This is an instrumentation mode: it captures the stack of each write transaction, so don't enable it in production.
+// The report contains up to maxBlockers call sites; zero disables the recording.
+func (builder *Builder) RecordContention(maxBlockers int) *Builder {
+	builder.contentionBlockers = maxBlockers
+	return builder
+}
+
+// ContentionReport returns the write contention recorded since the store was opened (or the last reset) and resets
+// the recording if reset is true, e.g. to measure phases of a benchmark separately.
+// Fails if the recording hasn't been enabled by Builder.RecordContention().
+func (ob *ObjectBox) ContentionReport(reset bool) (ContentionReport, error) {
+	if ob.contention == nil {
+		return ContentionReport{}, errors.New("contention recording is not enabled, use Builder.RecordContention()")
+	}
+	return ob.contention.report(reset), nil
+}
+
+// contentionRecorder tracks the current write transaction and the time others wait for it
+type contentionRecorder struct {
+	maxBlockers int
+
+	mutex             sync.Mutex
+	holder            *contentionHolder // the current write transaction, if started by this ObjectBox instance
+	writeTransactions uint64
+	totalWait         time.Duration
+	maxWait           time.Duration
+	blockers          map[string]*ContentionBlocker
+}
+
+// contentionHolder identifies a write transaction
+type contentionHolder struct {
+	stack string
+}
+
+func newContentionRecorder(maxBlockers int) *contentionRecorder {
+	if maxBlockers <= 0 {
+		return nil
+	}
+	return &contentionRecorder{maxBlockers: maxBlockers, blockers: make(map[string]*ContentionBlocker)}
+}
+
+// waiting is called before starting a write transaction and returns the transaction it's going to wait for, if any
+func (recorder *contentionRecorder) waiting() *contentionHolder {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return recorder.holder
+}
+
+// acquired is called after the write transaction has started, after waiting for the given blocker (may be nil)
+func (recorder *contentionRecorder) acquired(blocker *contentionHolder, wait time.Duration) *contentionHolder {
+	var holder = &contentionHolder{stack: contentionStack()}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.holder = holder
+	recorder.writeTransactions++
+	recorder.totalWait += wait
+	if wait > recorder.maxWait {
+		recorder.maxWait = wait
+	}
+
+	if blocker != nil {
+		var entry = recorder.blockers[blocker.stack]
+		if entry == nil {
+			if len(recorder.blockers) >= maxContentionStacks {
+				return holder
+			}
+			entry = &ContentionBlocker{Stack: blocker.stack}
+			recorder.blockers[blocker.stack] = entry
+		}
+		entry.Waits++
+		entry.TotalWait += wait
+		if wait > entry.MaxWait {
+			entry.MaxWait = wait
+		}
+	}
+	return holder
+}
+
+// contentionStack returns the stack of the goroutine starting a write transaction (from ObjectBox.beginTx() on), made of
+// function names and file:line only; unlike runtime.Stack(), which includes the goroutine ID and argument values,
+// it's the same for all transactions started at the same call site
+func contentionStack() string {
+	var pcs [maxContentionFrames]uintptr
+	var frames = runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])]) // skip Callers, contentionStack, acquired
+
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// released is called after the write transaction has finished
+func (recorder *contentionRecorder) released(holder *contentionHolder) {
+	recorder.mutex.Lock()
+	if recorder.holder == holder {
+		recorder.holder = nil
+	}
+	recorder.mutex.Unlock()
+}
+
+func (recorder *contentionRecorder) report(reset bool) ContentionReport {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	var report = ContentionReport{
+		WriteTransactions: recorder.writeTransactions,
+		TotalWait:         recorder.totalWait,
+		MaxWait:           recorder.maxWait,
+		Blockers:          make([]ContentionBlocker, 0, len(recorder.blockers)),
+	}
+	for _, blocker := range recorder.blockers {
+		report.Blockers = append(report.Blockers, *blocker)
+	}
+	sort.Slice(report.Blockers, func(i, j int) bool {
+		return report.Blockers[i].TotalWait > report.Blockers[j].TotalWait
+	})
+	if len(report.Blockers) > recorder.maxBlockers {
+		report.Blockers = report.Blockers[:recorder.maxBlockers]
+	}
+
+	if reset {
+		recorder.writeTransactions = 0
+		recorder.totalWait = 0
+		recorder.maxWait = 0
+		recorder.blockers = make(map[string]*ContentionBlocker)
+	}
+	return report
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// diagnosticsTxHistory is the number of recently finished transactions included in a diagnostic bundle
+const diagnosticsTxHistory = 32
+
+// DiagnosticBundle is the information written by ObjectBox.DiagnosticBundle(), meant to be attached to bug reports
+type DiagnosticBundle struct {
+	Created      time.Time
+	Reason       string // the error that triggered writing the bundle; empty if requested manually
+	Version      string // see VersionInfo()
+	Platform     string // GOOS/GOARCH and the Go version
+	Directory    string
+	Stats        *StoreStats `json:",omitempty"`
+	StatsError   string      `json:",omitempty"`
+	Model        *ModelVersion
+	ModelHistory int // number of recorded model versions, see ObjectBox.ModelHistory()
+
+	// Transactions lists the recently finished transactions, the latest last; only tracked with diagnostics enabled,
+	// see Builder.DiagnosticsOnCorruption()
+	Transactions []DiagnosticTransaction `json:",omitempty"`
+}
+
+// DiagnosticTransaction describes a recently finished transaction, see DiagnosticBundle
+type DiagnosticTransaction struct {
+	Finished  time.Time
+	Duration  time.Duration
+	ReadOnly  bool
+	Committed bool
+}
+
+// diagnostics holds the state of Builder.DiagnosticsOnCorruption()
+type diagnostics struct {
+	directory    string
+	mutex        sync.Mutex
+	transactions []DiagnosticTransaction // ring buffer of the recently finished transactions
+	next         int                     // position of the next transaction in the ring buffer
+	written      bool                    // a bundle is written only for the first corruption
+}
+
+// open stores with diagnostics enabled, notified by createError() about possible corruption
+var diagnosticStores = struct {
+	sync.Mutex
+	stores map[*ObjectBox]bool
+}{stores: make(map[*ObjectBox]bool)}
+
+// DiagnosticsOnCorruption makes the store write a diagnostic bundle (see ObjectBox.DiagnosticBundle()) to a new file
+// "objectbox-diagnostics-<timestamp>.json" in the given directory when a native error indicates possible database
+// corruption, e.g. of the database file or its pages. The bundle is written once per store, in the background.
+// With diagnostics enabled, the store also keeps track of the recently finished transactions to include them.
+func (builder *Builder) DiagnosticsOnCorruption(directory string) *Builder {
+	builder.diagnosticsDirectory = directory
+	return builder
+}
+
+// DiagnosticBundle writes information about the store to attach to bug reports as JSON: the store statistics, the
+// model, the platform and library versions and the recently finished transactions (with diagnostics enabled).
+func (ob *ObjectBox) DiagnosticBundle(w io.Writer) error {
+	return ob.writeDiagnosticBundle(w, "")
+}
+
+func (ob *ObjectBox) writeDiagnosticBundle(w io.Writer, reason string) error {
+	var bundle = DiagnosticBundle{
+		Created:   time.Now(),
+		Reason:    reason,
+		Version:   VersionInfo(),
+		Platform:  fmt.Sprintf("%s/%s %s", runtime.GOOS, runtime.GOARCH, runtime.Version()),
+		Directory: ob.directory,
+		Model:     ob.schema,
+	}
+
+	var err error
+	if bundle.Stats, err = ob.Stats(); err != nil {
+		bundle.StatsError = err.Error()
+	}
+	if history, err := ob.ModelHistory(); err == nil {
+		bundle.ModelHistory = len(history)
+	}
+	if ob.diagnostics != nil {
+		bundle.Transactions = ob.diagnostics.recentTransactions()
+	}
+
+	var encoder = json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundle)
+}
+
+// transactionDone records the finished transaction in the ring buffer
+func (diagnostics *diagnostics) transactionDone(readOnly, committed bool, duration time.Duration) {
+	var tx = DiagnosticTransaction{Finished: time.Now(), Duration: duration, ReadOnly: readOnly, Committed: committed}
+
+	diagnostics.mutex.Lock()
+	defer diagnostics.mutex.Unlock()
+	if len(diagnostics.transactions) < diagnosticsTxHistory {
+		diagnostics.transactions = append(diagnostics.transactions, tx)
+	} else {
+		diagnostics.transactions[diagnostics.next] = tx
+	}
+	diagnostics.next = (diagnostics.next + 1) % diagnosticsTxHistory
+}
+
+func (diagnostics *diagnostics) recentTransactions() []DiagnosticTransaction {
+	diagnostics.mutex.Lock()
+	defer diagnostics.mutex.Unlock()
+
+	var result = make([]DiagnosticTransaction, 0, len(diagnostics.transactions))
+	if len(diagnostics.transactions) == diagnosticsTxHistory {
+		result = append(result, diagnostics.transactions[diagnostics.next:]...)
+		return append(result, diagnostics.transactions[:diagnostics.next]...)
+	}
+	return append(result, diagnostics.transactions...)
+}
+
+// registerDiagnostics enables diagnostics for the newly opened store
+func (ob *ObjectBox) registerDiagnostics(directory string) {
+	ob.diagnostics = &diagnostics{directory: directory}
+	diagnosticStores.Lock()
+	diagnosticStores.stores[ob] = true
+	diagnosticStores.Unlock()
+}
+
+// unregisterDiagnostics is called when the store is closed
+func (ob *ObjectBox) unregisterDiagnostics() {
+	if ob.diagnostics != nil {
+		diagnosticStores.Lock()
+		delete(diagnosticStores.stores, ob)
+		diagnosticStores.Unlock()
+	}
+}
+
+// isCorruptionError returns true for native error codes indicating a possibly corrupted database
+func isCorruptionError(code C.obx_err) bool {
+	switch code {
+	case C.OBX_ERROR_FILE_CORRUPT, C.OBX_ERROR_FILE_PAGES_CORRUPT, C.OBX_ERROR_STORAGE_GENERAL,
+		C.OBX_ERROR_STORE_MUST_SHUTDOWN:
+		return true
+	}
+	return false
+}
+
+// reportCorruption writes a diagnostic bundle for each store with diagnostics enabled (that hasn't written one yet).
+// The native errors don't identify the store so all of them are reported, typically there's only one.
+// The bundles are written in the background as the calling goroutine may be in the middle of a native call.
+func reportCorruption(err error) {
+	diagnosticStores.Lock()
+	defer diagnosticStores.Unlock()
+
+	for ob := range diagnosticStores.stores {
+		ob.diagnostics.mutex.Lock()
+		var write = !ob.diagnostics.written
+		ob.diagnostics.written = true
+		ob.diagnostics.mutex.Unlock()
+
+		if write {
+			go func(ob *ObjectBox) {
+				if writeErr := ob.writeDiagnosticsFile(err.Error()); writeErr != nil {
+					logError("error writing the diagnostic bundle", writeErr)
+				}
+			}(ob)
+		}
+	}
+}
+
+// writeDiagnosticsFile writes the bundle to a temporary file first so that there's never a partially written bundle
+func (ob *ObjectBox) writeDiagnosticsFile(reason string) error {
+	var directory = ob.diagnostics.directory
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(directory, ".objectbox-diagnostics-")
+	if err != nil {
+		return err
+	}
+	if err = ob.writeDiagnosticBundle(file, reason); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		var name = fmt.Sprintf("objectbox-diagnostics-%s.json", time.Now().Format("20060102-150405.000"))
+		err = os.Rename(file.Name(), filepath.Join(directory, name))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"github.com/google/flatbuffers/go"
+	"time"
+)
+
+// distinctBytes is the key of a byte vector value in DistinctBy(); slices can't be used as map keys
+type distinctBytes string
+
+// DistinctBy returns only the first object for each distinct value of the given property among the objects matching
+// the query. "First" follows the order of the query, so the order controls which object is kept, e.g. the latest
+// event per device:
+//
+//	query := box.Query(Event_.Date.OrderDesc())
+//	latest, err := query.DistinctBy(Event_.Device)
+//
+// The results are in the order of the query too. Strings are compared case-sensitively. The property values and IDs
+// are collected and the objects read in a single read transaction. Note: Offset() and Limit() apply to the matching
+// objects before the deduplication.
+func (query *Query) DistinctBy(property Property) (objects interface{}, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.DistinctBy", time.Now(), &err)
+	}
+
+	projected, err := query.projectedProperty(property)
+	if err != nil {
+		return nil, err
+	}
+	if projected.propertyType == C.OBXPropertyType_StringVector {
+		return nil, fmt.Errorf("property %d is a string vector, which can't be used with DistinctBy()",
+			property.propertyId())
+	}
+
+	idProperty, err := query.idProjectedProperty()
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.objectBox.RunInReadTx(func() error {
+		var seen = make(map[interface{}]bool)
+		var ids []uint64
+		err := query.visitBytes(func(bytes []byte) bool {
+			var table = &flatbuffers.Table{
+				Bytes: bytes,
+				Pos:   flatbuffers.GetUOffsetT(bytes),
+			}
+			var key = projected.decode(table)
+			if value, isBytes := key.([]byte); isBytes {
+				key = distinctBytes(value)
+			}
+			if !seen[key] {
+				seen[key] = true
+				ids = append(ids, idProperty.decode(table).(uint64))
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		objects, err = query.box.GetManyExisting(ids...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// idProjectedProperty returns the ID property of the queried entity
+func (query *Query) idProjectedProperty() (projectedProperty, error) {
+	property, err := query.idProperty()
+	if err != nil {
+		return projectedProperty{}, err
+	}
+	return newProjectedProperty(property), nil
+}
+
+// idProperty returns the model information of the ID property of the queried entity
+func (query *Query) idProperty() (*ModelPropertyInfo, error) {
+	for _, e := range query.objectBox.schema.Entities {
+		if e.Id != query.entity.id {
+			continue
+		}
+		for _, p := range e.Properties {
+			if p.Flags&C.OBXPropertyFlags_ID != 0 {
+				return p, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("ID property of entity %s not found", query.entity.name)
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EagerRelation is a relation which can be loaded by Query.Eager(), i.e. a *RelationToMany or a *RelationToOne.
+type EagerRelation interface {
+	newEagerRelation(query *Query) (*eagerRelation, error)
+}
+
+// eagerRelation is a relation loaded by Query.Find() together with the source objects
+type eagerRelation struct {
+	toMany     *RelationToMany
+	toOne      *RelationToOne
+	targetBox  *Box
+	idIndex    []int // of the source struct field holding the target ID (to-one relations)
+	fieldIndex []int // of the source struct field receiving the target object(s)
+}
+
+// Eager makes Find() and FindWithContext() load the given relations together with the objects, in the same read
+// transaction. All targets of a relation are read at once instead of one read per source object; a target object
+// related to multiple source objects is loaded only once and shared by them.
+//
+// The objects are set on the field the relation is named after (the generator names relations after their fields):
+//   - a many-to-many relation, e.g. a slice field marked with the "lazy" tag, which would otherwise have to be
+//     fetched separately, object by object;
+//   - a to-one relation declared on an ID field, e.g. EventId with the "link:Event" tag, is loaded into the field
+//     with the same name without the "Id" suffix, e.g. Event of type *Event, excluded from the model by the "-" tag.
+//     To-one relations declared on an object field are always loaded by the generated binding, there's nothing left
+//     to do for them.
+func (query *Query) Eager(relations ...EagerRelation) *Query {
+	for _, relation := range relations {
+		if query.eagerErr != nil {
+			break
+		}
+		var eager *eagerRelation
+		if eager, query.eagerErr = relation.newEagerRelation(query); eager != nil {
+			query.eager = append(query.eager, eager)
+		}
+	}
+	return query
+}
+
+func (relation *RelationToMany) newEagerRelation(query *Query) (*eagerRelation, error) {
+	if relation.Source.Id != query.entity.id {
+		return nil, fmt.Errorf("relation from a different entity %d passed, expected %d", relation.Source.Id, query.entity.id)
+	}
+
+	if err := query.objectBox.checkRelation(relation); err != nil {
+		return nil, err
+	}
+
+	targetBox, err := query.objectBox.box(relation.Target.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the relation field is the one at the position of the relation among the entity's relations (in declaration order)
+	var position = -1
+	for _, e := range query.objectBox.schema.Entities {
+		if e.Id == relation.Source.Id {
+			for i, r := range e.Relations {
+				if r.Id == relation.Id {
+					position = i
+				}
+			}
+		}
+	}
+
+	var sourceType = query.box.objectType()
+	var fields = query.objectBox.relationFields(sourceType, nil)
+	if position < 0 || position >= len(fields) || elemType(fields[position].Type) != targetBox.objectType() {
+		return nil, fmt.Errorf("can't load relation %d eagerly: no matching field of type []%s found in entity %s",
+			relation.Id, targetBox.objectType().Name(), sourceType.Name())
+	}
+	return &eagerRelation{toMany: relation, targetBox: targetBox, fieldIndex: fields[position].Index}, nil
+}
+
+func (relation *RelationToOne) newEagerRelation(query *Query) (*eagerRelation, error) {
+	if relation.entityId() != query.entity.id {
+		return nil, fmt.Errorf("relation from a different entity %d passed, expected %d", relation.entityId(), query.entity.id)
+	}
+
+	var info = query.objectBox.schemaProperty(relation.entityId(), relation.propertyId())
+	if info == nil || info.RelationTarget == "" {
+		return nil, fmt.Errorf("property %d of entity %s is not a relation", relation.propertyId(), query.entity.name)
+	}
+
+	targetBox, err := query.objectBox.box(relation.Target.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceType = query.box.objectType()
+	var targetType = targetBox.objectType()
+	field, found := sourceType.FieldByName(info.Name)
+	if !found {
+		return nil, fmt.Errorf("can't load relation %s eagerly: no field %s found in entity %s",
+			info.Name, info.Name, sourceType.Name())
+	} else if elemType(field.Type) == targetType {
+		return nil, nil // an object field, loaded by the generated binding
+	} else if field.Type.Kind() != reflect.Uint64 {
+		return nil, fmt.Errorf("can't load relation %s eagerly: field %s is neither an ID nor a %s",
+			info.Name, info.Name, targetType.Name())
+	}
+
+	var name = strings.TrimSuffix(info.Name, "Id")
+	target, found := sourceType.FieldByName(name)
+	if !found || name == info.Name || elemType(target.Type) != targetType || target.Type.Kind() == reflect.Slice {
+		return nil, fmt.Errorf("can't load relation %s eagerly: entity %s has no field %s of type %s",
+			info.Name, sourceType.Name(), name, targetType.Name())
+	}
+	return &eagerRelation{toOne: relation, targetBox: targetBox, idIndex: field.Index, fieldIndex: target.Index}, nil
+}
+
+// relationFields returns the many-to-many relation fields of the given struct (including embedded structs) in the
+// order they're declared, i.e. the order the generator assigns relations in: slices of entity objects
+func (ob *ObjectBox) relationFields(structType reflect.Type, index []int) []reflect.StructField {
+	var result []reflect.StructField
+	for i := 0; i < structType.NumField(); i++ {
+		var field = structType.Field(i)
+		field.Index = append(append([]int{}, index...), i)
+
+		var tag = field.Tag.Get("objectbox")
+		if tag == "-" || strings.Contains(tag, "converter") {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			result = append(result, ob.relationFields(field.Type, field.Index)...)
+		} else if field.Type.Kind() == reflect.Slice && ob.isEntityType(elemType(field.Type)) {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// isEntityType reports whether objects of the given struct type are stored as an entity in this store
+func (ob *ObjectBox) isEntityType(objectType reflect.Type) bool {
+	for _, entity := range ob.entitiesById {
+		var entityType = reflect.TypeOf(entity.binding.MakeSlice(0)).Elem()
+		if entityType == objectType || (entityType.Kind() == reflect.Ptr && entityType.Elem() == objectType) {
+			return true
+		}
+	}
+	return false
+}
+
+// elemType returns the struct type of a (slice of) struct values or pointers
+func elemType(fieldType reflect.Type) reflect.Type {
+	if fieldType.Kind() == reflect.Slice {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType
+}
+
+// findEager executes the given find function and loads the eager relations in a single read transaction
+func (query *Query) findEager(find func() (interface{}, error)) (objects interface{}, err error) {
+	err = query.objectBox.RunInReadTx(func() error {
+		if objects, err = find(); err != nil {
+			return err
+		}
+		for _, eager := range query.eager {
+			if err = eager.load(query.box, reflect.ValueOf(objects)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// load reads the targets of all the given source objects and sets them on the source objects' relation field
+func (eager *eagerRelation) load(box *Box, sources reflect.Value) error {
+	if sources.Len() == 0 {
+		return nil
+	}
+
+	// collect the IDs of all targets first, then read them at once
+	var sourceIds = make([]uint64, sources.Len())
+	for i := range sourceIds {
+		var err error
+		if sourceIds[i], err = box.entity.binding.GetId(sources.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	var targetIds = make([][]uint64, sources.Len())
+	if eager.toOne != nil {
+		for i := range targetIds {
+			if id := structValue(sources.Index(i)).FieldByIndex(eager.idIndex).Uint(); id != 0 {
+				targetIds[i] = []uint64{id}
+			}
+		}
+	} else {
+		targetIdsBySource, err := box.RelationIdsMany(eager.toMany, sourceIds)
+		if err != nil {
+			return err
+		}
+		for i, sourceId := range sourceIds {
+			targetIds[i] = targetIdsBySource[sourceId]
+		}
+	}
+
+	var uniqueIds []uint64
+	var seen = make(map[uint64]bool)
+	for i := range sourceIds {
+		for _, id := range targetIds[i] {
+			if !seen[id] {
+				seen[id] = true
+				uniqueIds = append(uniqueIds, id)
+			}
+		}
+	}
+
+	targets, err := eager.targetBox.GetManyExisting(uniqueIds...)
+	if err != nil {
+		return err
+	}
+
+	var targetsSlice = reflect.ValueOf(targets)
+	var targetsById = make(map[uint64]reflect.Value, targetsSlice.Len())
+	for i := 0; i < targetsSlice.Len(); i++ {
+		var target = targetsSlice.Index(i)
+		id, err := eager.targetBox.entity.binding.GetId(target.Interface())
+		if err != nil {
+			return err
+		}
+		targetsById[id] = target
+	}
+
+	for i := 0; i < sources.Len(); i++ {
+		var field = structValue(sources.Index(i)).FieldByIndex(eager.fieldIndex)
+		if eager.toOne != nil {
+			field.Set(reflect.Zero(field.Type()))
+			if len(targetIds[i]) > 0 {
+				if target, found := targetsById[targetIds[i][0]]; found {
+					field.Set(adaptTargetValue(target, field.Type()))
+				}
+			}
+			continue
+		}
+
+		var slice = reflect.MakeSlice(field.Type(), 0, len(targetIds[i]))
+		for _, id := range targetIds[i] {
+			if target, found := targetsById[id]; found {
+				slice = reflect.Append(slice, adaptTargetValue(target, field.Type().Elem()))
+			}
+		}
+		field.Set(slice)
+	}
+	return nil
+}
+
+// structValue dereferences a source object given as a pointer
+func structValue(source reflect.Value) reflect.Value {
+	if source.Kind() == reflect.Ptr {
+		return source.Elem()
+	}
+	return source
+}
+
+// adaptTargetValue converts between a pointer and a value if the relation field and the target binding differ
+func adaptTargetValue(target reflect.Value, valueType reflect.Type) reflect.Value {
+	if target.Type() == valueType {
+		return target
+	} else if target.Kind() == reflect.Ptr {
+		return target.Elem()
+	}
+	var ptr = reflect.New(target.Type())
+	ptr.Elem().Set(target)
+	return ptr
+}
@@ -16,6 +16,12 @@
 
 package objectbox
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
 // Entity is used to specify model in the generated binding code
 type Entity struct {
 	Id TypeId
@@ -31,4 +37,48 @@ type entity struct {
 
 	// whether this entity has any relations (standalone or property-rels) - configured during model creation
 	hasRelations bool
+
+	// whether the ID is annotated `objectbox:"id(assignable)"`, allowing arbitrary IDs to be put
+	idSelfAssignable bool
+
+	// the unique string/bytes property holding the external ID looked up by Box.GetByUid(); see Builder.UidProperties()
+	uidPropertyId TypeId
+
+	// transforms the serialized objects if set by ObjectBox.SetPropertyCodec()
+	codec *propertyCodec
+
+	// checked and incremented on each put if set by ObjectBox.SetVersionProperty()
+	version *versionProperty
+
+	// removing objects only marks them as removed if set by ObjectBox.SetSoftDelete()
+	softDelete *softDelete
+
+	// checked on each put if set by Builder.BoxQuota()
+	quota *boxQuota
+}
+
+// field locates the struct field of the given property by its name, the same way the generator names the properties
+func (entity *entity) field(property *ModelPropertyInfo) (reflect.StructField, error) {
+	var objectType = reflect.TypeOf(entity.binding.MakeSlice(0)).Elem()
+	if objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+	field, found := objectType.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, property.Name) })
+	if !found {
+		return field, fmt.Errorf("field for the property %s.%s not found in %s", entity.name, property.Name, objectType)
+	}
+	return field, nil
+}
+
+// settableField returns the field with the given index of the object, which must be a pointer to a struct
+func settableField(object interface{}, index []int) (reflect.Value, error) {
+	var value = reflect.ValueOf(object)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	var field = value.FieldByIndex(index)
+	if !field.CanSet() {
+		return field, fmt.Errorf("field of %s can't be set, pass a pointer to the object", value.Type())
+	}
+	return field, nil
 }
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"sync"
+	"time"
+)
+
+// StoreEventKind identifies the type of a StoreEvent
+type StoreEventKind string
+
+const (
+	// StoreOpened - the store was opened; emitted to listeners registered by Builder.OnEvent()
+	StoreOpened StoreEventKind = "opened"
+
+	// StoreModelUpgraded - the store was opened with a model different from the previous one, see SchemaChanges
+	StoreModelUpgraded StoreEventKind = "modelUpgraded"
+
+	// StoreClosed - the store was closed, i.e. the last reference released; it can't be used anymore
+	StoreClosed StoreEventKind = "closed"
+
+	// StoreRetentionRun - an Expirer removed expired objects, see Count and Err
+	StoreRetentionRun StoreEventKind = "retentionRun"
+
+	// StoreSyncStateChanged - the sync client was started, stopped or closed, see SyncState
+	StoreSyncStateChanged StoreEventKind = "syncStateChanged"
+)
+
+// StoreEvent describes a lifecycle event of a store, see ObjectBox.SubscribeEvents()
+type StoreEvent struct {
+	Kind          StoreEventKind
+	Time          time.Time
+	SchemaChanges []SchemaChange  // StoreModelUpgraded
+	Count         uint64          // StoreRetentionRun: the number of removed objects
+	SyncState     SyncClientState // StoreSyncStateChanged: the state after the change
+	Err           error           // StoreRetentionRun: set if the run failed
+}
+
+// eventBus delivers store events to the subscribed listeners
+type eventBus struct {
+	now       func() time.Time // see Builder.Clock()
+	mutex     sync.Mutex
+	nextId    uint64
+	listeners []eventListener // in the order of subscription
+}
+
+type eventListener struct {
+	id uint64
+	fn func(event StoreEvent)
+}
+
+// OnEvent registers a listener for the lifecycle events of the store, including StoreOpened and StoreModelUpgraded
+// emitted when the store is opened (before Build() returns). Use ObjectBox.SubscribeEvents() to subscribe later.
+func (builder *Builder) OnEvent(listener func(event StoreEvent)) *Builder {
+	builder.eventListeners = append(builder.eventListeners, listener)
+	return builder
+}
+
+// SubscribeEvents registers a listener for the lifecycle events of the store, e.g. to centralize operational logging
+// or to show a status in a UI. Listeners are called synchronously by the goroutine causing the event so they should
+// return quickly and must not close the store. Call the returned function to unsubscribe.
+func (ob *ObjectBox) SubscribeEvents(listener func(event StoreEvent)) (unsubscribe func()) {
+	return ob.events.subscribe(listener)
+}
+
+func (bus *eventBus) subscribe(listener func(event StoreEvent)) (unsubscribe func()) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	bus.nextId++
+	var id = bus.nextId
+	bus.listeners = append(bus.listeners, eventListener{id: id, fn: listener})
+
+	return func() {
+		bus.mutex.Lock()
+		defer bus.mutex.Unlock()
+		for i, l := range bus.listeners {
+			if l.id == id {
+				// copy so that a concurrent emit() keeps iterating over the previous slice
+				bus.listeners = append(append([]eventListener{}, bus.listeners[:i]...), bus.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit calls all listeners subscribed at the time of the call, in the order of their subscription
+func (bus *eventBus) emit(event StoreEvent) {
+	bus.mutex.Lock()
+	var listeners = bus.listeners
+	bus.mutex.Unlock()
+
+	if len(listeners) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = bus.now()
+	}
+	for _, listener := range listeners {
+		listener.fn(event)
+	}
+}
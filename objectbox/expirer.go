@@ -0,0 +1,200 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultExpirerBatchSize is the number of objects removed in a single transaction by an Expirer
+const defaultExpirerBatchSize = 1000
+
+// Expirer periodically removes objects whose expiration date has passed, see NewExpirer().
+type Expirer struct {
+	objectBox *ObjectBox
+	interval  time.Duration
+
+	mutex        sync.Mutex
+	rules        []*expiryRule
+	batchSize    uint64
+	errorHandler func(error)
+
+	stop     chan struct{}
+	stopOnce sync.Once // Close() may be called concurrently
+	stopped  chan struct{}
+}
+
+type expiryRule struct {
+	box      *Box
+	property *PropertyInt64
+	unit     time.Duration // time.Millisecond for "date" properties, time.Nanosecond for "date-nano"
+}
+
+// NewExpirer starts a background worker removing expired objects every `interval`.
+// Configure which entities expire using Expire(), and stop the worker using Close() before closing the store.
+//
+// Example, given a Session entity with a property `Expires time.Time` annotated as a date:
+//
+//	var expirer = objectbox.NewExpirer(ob, time.Minute)
+//	defer expirer.Close()
+//	err := expirer.Expire(Session_.Expires)
+func NewExpirer(ob *ObjectBox, interval time.Duration) *Expirer {
+	var expirer = &Expirer{
+		objectBox: ob,
+		interval:  interval,
+		batchSize: defaultExpirerBatchSize,
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go expirer.run()
+	return expirer
+}
+
+// Expire registers a date property as the expiration date of its entity: objects are removed once the stored date
+// has passed. Objects with a zero value (i.e. 1970-01-01 or a zero time.Time) never expire.
+// The property must be a date, i.e. annotated with `objectbox:"date"` or `objectbox:"date-nano"`.
+func (expirer *Expirer) Expire(property *PropertyInt64) error {
+	var info = expirer.objectBox.schemaProperty(property.entityId(), property.propertyId())
+	if info == nil {
+		return fmt.Errorf("property %d of entity %d not found in the model", property.Id, property.Entity.Id)
+	}
+
+	var rule = &expiryRule{property: property}
+	switch info.Type {
+	case C.OBXPropertyType_Date:
+		rule.unit = time.Millisecond
+	case C.OBXPropertyType_DateNano:
+		rule.unit = time.Nanosecond
+	default:
+		return fmt.Errorf("property %s is not a date property; annotate it with `objectbox:\"date\"`", info.Name)
+	}
+
+	box, err := expirer.objectBox.BoxOrError(property.entityId())
+	if err != nil {
+		return err
+	}
+	rule.box = box
+
+	expirer.mutex.Lock()
+	expirer.rules = append(expirer.rules, rule)
+	expirer.mutex.Unlock()
+	return nil
+}
+
+// BatchSize sets the maximum number of objects removed in a single write transaction (default: 1000).
+// Smaller batches block other writers for a shorter time.
+func (expirer *Expirer) BatchSize(size uint64) *Expirer {
+	if size > 0 {
+		expirer.mutex.Lock()
+		expirer.batchSize = size
+		expirer.mutex.Unlock()
+	}
+	return expirer
+}
+
+// ErrorHandler sets a function receiving errors encountered by the background worker; by default they're ignored.
+func (expirer *Expirer) ErrorHandler(fn func(error)) *Expirer {
+	expirer.mutex.Lock()
+	expirer.errorHandler = fn
+	expirer.mutex.Unlock()
+	return expirer
+}
+
+// RemoveExpired removes all objects expired at the given time, regardless of the interval; returns their number.
+// The background worker calls this with the current time of the store, see Builder.Clock().
+func (expirer *Expirer) RemoveExpired(now time.Time) (removed uint64, err error) {
+	expirer.mutex.Lock()
+	var rules = expirer.rules
+	var batchSize = expirer.batchSize
+	expirer.mutex.Unlock()
+
+	defer func() {
+		expirer.objectBox.events.emit(StoreEvent{Kind: StoreRetentionRun, Count: removed, Err: err})
+	}()
+
+	for _, rule := range rules {
+		count, err := rule.removeExpired(now, batchSize)
+		removed += count
+		if err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// Close stops the background worker, waiting for a currently running removal to finish.
+func (expirer *Expirer) Close() {
+	expirer.stopOnce.Do(func() { close(expirer.stop) })
+	<-expirer.stopped
+}
+
+func (expirer *Expirer) run() {
+	defer close(expirer.stopped)
+
+	var ticker = time.NewTicker(expirer.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-expirer.stop:
+			return
+		case <-ticker.C:
+			if _, err := expirer.RemoveExpired(expirer.objectBox.Now()); err != nil {
+				expirer.mutex.Lock()
+				var handler = expirer.errorHandler
+				expirer.mutex.Unlock()
+				if handler != nil {
+					handler(err)
+				}
+			}
+		}
+	}
+}
+
+// removeExpired removes the expired objects in batches, each in its own write transaction
+func (rule *expiryRule) removeExpired(now time.Time, batchSize uint64) (removed uint64, err error) {
+	var threshold = now.UnixNano() / int64(rule.unit)
+	query, err := rule.box.QueryOrError(rule.property.Between(1, threshold))
+	if err != nil {
+		return 0, err
+	}
+	defer query.Close()
+	query.Limit(batchSize)
+
+	for {
+		var count uint64
+		err = rule.box.ObjectBox.RunInWriteTx(func() error {
+			ids, err := query.FindIds()
+			if err != nil {
+				return err
+			}
+			count, err = rule.box.RemoveIds(ids...)
+			return err
+		})
+		removed += count
+		if err != nil || count < batchSize {
+			return removed, err
+		}
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// importBatchSize is the number of objects written in a single transaction by Box.ImportJSON()
+const importBatchSize = 1000
+
+// ExportJSON writes all objects in the box as a JSON array, one object per line, using the standard encoding/json
+// marshalling of the entity struct (i.e. respecting `json` tags).
+// Objects are loaded and written one by one (all in a single read transaction), without reading all of them to memory.
+func (box *Box) ExportJSON(w io.Writer) error {
+	var writer = bufio.NewWriter(w)
+	var first = true
+
+	var writeErr error
+	var err = box.VisitAll(func(object interface{}) bool {
+		data, err := json.Marshal(object)
+		if err != nil {
+			writeErr = err
+			return false
+		}
+
+		var separator = ",\n"
+		if first {
+			separator = "[\n"
+			first = false
+		}
+		if _, err = writer.WriteString(separator); err == nil {
+			_, err = writer.Write(data)
+		}
+		if err != nil {
+			writeErr = err
+			return false
+		}
+		return true
+	})
+
+	if err == nil {
+		err = writeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if first {
+		_, err = writer.WriteString("[]\n")
+	} else {
+		_, err = writer.WriteString("\n]\n")
+	}
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// ImportJSON reads a JSON array of objects, e.g. as written by ExportJSON(), and writes them to the box using the given
+// mode. Objects are decoded one by one and written in batches, each batch in its own write transaction. If an error
+// occurs, objects written by previous batches stay in the database.
+func (box *Box) ImportJSON(r io.Reader, mode PutMode) error {
+	if mode != PutModePut && mode != PutModeInsert && mode != PutModeUpdate {
+		return fmt.Errorf("invalid put mode %d", mode)
+	}
+
+	var decoder = json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil {
+		return err
+	} else if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("invalid JSON - expected an array of objects, found %v", token)
+	}
+
+	var objectType = box.objectType()
+	var index = 0
+	for decoder.More() {
+		var err = box.ObjectBox.RunInWriteTx(func() error {
+			for i := 0; i < importBatchSize && decoder.More(); i++ {
+				var object = reflect.New(objectType).Interface()
+				if err := decoder.Decode(object); err != nil {
+					return fmt.Errorf("can't decode object at index %d: %s", index, err)
+				}
+				if _, err := box.put(object, true, C.OBXPutMode(mode)); err != nil {
+					return fmt.Errorf("can't write object at index %d: %s", index, err)
+				}
+				index++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// consume the closing bracket
+	_, err := decoder.Token()
+	return err
+}
+
+// objectType returns the struct type of the objects in this box (as opposed to a pointer to the struct)
+func (box *Box) objectType() reflect.Type {
+	var objectType = reflect.TypeOf(box.entity.binding.MakeSlice(0)).Elem()
+	if objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+	return objectType
+}
+
+// ExportCSV writes the given properties of all objects matching the query as CSV, starting with a header line
+// containing the property names. The values are read using property queries (see Query.Property()), i.e. without
+// loading whole objects. Only scalar and string properties are supported; nil values are written as zero/empty.
+// Note: the query offset and limit don't apply to property queries.
+func (query *Query) ExportCSV(w io.Writer, properties ...Property) error {
+	if len(properties) == 0 {
+		return errors.New("no properties given")
+	}
+
+	var header = make([]string, len(properties))
+	for i, property := range properties {
+		if property.entityId() != query.entity.id {
+			return fmt.Errorf("property from a different entity %d passed, expected %d",
+				property.entityId(), query.entity.id)
+		}
+		var info = query.objectBox.schemaProperty(property.entityId(), property.propertyId())
+		if info == nil {
+			return fmt.Errorf("property %d not found in the model", property.propertyId())
+		}
+		header[i] = info.Name
+	}
+
+	// read the columns in a single transaction so that the rows are consistent
+	var columns = make([][]string, len(properties))
+	var err = query.objectBox.RunInReadTx(func() error {
+		for i, property := range properties {
+			var err error
+			if columns[i], err = query.findPropertyStrings(property); err != nil {
+				return fmt.Errorf("can't read property %s: %s", header[i], err)
+			}
+			if len(columns[i]) != len(columns[0]) {
+				return fmt.Errorf("inconsistent number of values for property %s", header[i])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var writer = csv.NewWriter(w)
+	if err = writer.Write(header); err != nil {
+		return err
+	}
+
+	var record = make([]string, len(properties))
+	for row := range columns[0] {
+		for i := range columns {
+			record[i] = columns[i][row]
+		}
+		if err = writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// findPropertyStrings reads values of the given property of all objects matching the query, formatted as strings
+func (query *Query) findPropertyStrings(property Property) ([]string, error) {
+	var info = query.objectBox.schemaProperty(property.entityId(), property.propertyId())
+
+	pq, err := query.PropertyOrError(property)
+	if err != nil {
+		return nil, err
+	}
+	defer pq.Close()
+
+	var unsigned = info.Flags&C.OBXPropertyFlags_UNSIGNED != 0
+	var result []string
+	var add = func(count int, format func(i int) string) {
+		result = make([]string, count)
+		for i := range result {
+			result[i] = format(i)
+		}
+	}
+
+	switch info.Type {
+	case C.OBXPropertyType_Bool:
+		var values, err = pq.FindBools(new(bool))
+		add(len(values), func(i int) string { return strconv.FormatBool(values[i]) })
+		return result, err
+	case C.OBXPropertyType_Byte:
+		if unsigned {
+			var values, err = pq.FindUint8s(new(uint8))
+			add(len(values), func(i int) string { return strconv.FormatUint(uint64(values[i]), 10) })
+			return result, err
+		}
+		var values, err = pq.FindInt8s(new(int8))
+		add(len(values), func(i int) string { return strconv.FormatInt(int64(values[i]), 10) })
+		return result, err
+	case C.OBXPropertyType_Short, C.OBXPropertyType_Char:
+		if unsigned {
+			var values, err = pq.FindUint16s(new(uint16))
+			add(len(values), func(i int) string { return strconv.FormatUint(uint64(values[i]), 10) })
+			return result, err
+		}
+		var values, err = pq.FindInt16s(new(int16))
+		add(len(values), func(i int) string { return strconv.FormatInt(int64(values[i]), 10) })
+		return result, err
+	case C.OBXPropertyType_Int:
+		if unsigned {
+			var values, err = pq.FindUint32s(new(uint32))
+			add(len(values), func(i int) string { return strconv.FormatUint(uint64(values[i]), 10) })
+			return result, err
+		}
+		var values, err = pq.FindInt32s(new(int32))
+		add(len(values), func(i int) string { return strconv.FormatInt(int64(values[i]), 10) })
+		return result, err
+	case C.OBXPropertyType_Long, C.OBXPropertyType_Date, C.OBXPropertyType_DateNano, C.OBXPropertyType_Relation:
+		if unsigned {
+			var values, err = pq.FindUint64s(new(uint64))
+			add(len(values), func(i int) string { return strconv.FormatUint(values[i], 10) })
+			return result, err
+		}
+		var values, err = pq.FindInt64s(new(int64))
+		add(len(values), func(i int) string { return strconv.FormatInt(values[i], 10) })
+		return result, err
+	case C.OBXPropertyType_Float:
+		var values, err = pq.FindFloat32s(new(float32))
+		add(len(values), func(i int) string { return strconv.FormatFloat(float64(values[i]), 'g', -1, 32) })
+		return result, err
+	case C.OBXPropertyType_Double:
+		var values, err = pq.FindFloat64s(new(float64))
+		add(len(values), func(i int) string { return strconv.FormatFloat(values[i], 'g', -1, 64) })
+		return result, err
+	case C.OBXPropertyType_String:
+		return pq.FindStrings(new(string))
+	default:
+		return nil, fmt.Errorf("unsupported property type %d", info.Type)
+	}
+}
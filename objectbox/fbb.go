@@ -21,8 +21,32 @@ import (
 	"sync"
 )
 
-var fbbPool = sync.Pool{
-	New: func() interface{} {
-		return flatbuffers.NewBuilder(256)
-	},
+var fbbPool = sync.Pool{}
+
+// fbbFromPool returns a builder from the pool or a new one (allocated=true) if the pool is empty
+func fbbFromPool() (fbb *flatbuffers.Builder, allocated bool) {
+	if fbb, _ = fbbPool.Get().(*flatbuffers.Builder); fbb == nil {
+		fbb = flatbuffers.NewBuilder(256)
+		allocated = true
+	}
+	return fbb, allocated
+}
+
+// acquireFbb returns a builder from the pool, reporting the acquisition to the MetricsCollector (if configured).
+// Use releaseFbb() to return the builder to the pool once it's no longer used.
+func acquireFbb() *flatbuffers.Builder {
+	var fbb, allocated = fbbFromPool()
+	if collector := metricsCollector(); collector != nil {
+		collector.FlatBuffersBuilderAcquired(allocated)
+	}
+	return fbb
+}
+
+// releaseFbb puts the builder back to the pool for the others to use if it's reasonably small
+func releaseFbb(fbb *flatbuffers.Builder) {
+	// don't use defer in callers, it's slower
+	if cap(fbb.Bytes) < 1024*1024 {
+		fbb.Reset()
+		fbbPool.Put(fbb)
+	}
 }
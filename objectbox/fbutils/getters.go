@@ -90,6 +90,50 @@ func GetStringVectorPtrSlot(table *flatbuffers.Table, slot flatbuffers.VOffsetT)
 	return nil
 }
 
+// GetBoolVectorSlot provides access to the FlatBuffers table
+func GetBoolVectorSlot(table *flatbuffers.Table, slot flatbuffers.VOffsetT) []bool {
+	if vector := GetBoolVectorPtrSlot(table, slot); vector != nil {
+		return *vector
+	}
+	return nil
+}
+
+// GetBoolVectorPtrSlot provides access to the FlatBuffers table
+func GetBoolVectorPtrSlot(table *flatbuffers.Table, slot flatbuffers.VOffsetT) *[]bool {
+	if o := flatbuffers.UOffsetT(table.Offset(slot)); o != 0 {
+		var ln = table.VectorLen(o)
+		var start = table.Vector(o)
+		var values = make([]bool, ln)
+		for i := range values {
+			values[i] = table.GetBool(start + flatbuffers.UOffsetT(i)*flatbuffers.SizeBool)
+		}
+		return &values
+	}
+	return nil
+}
+
+// GetInt16VectorSlot provides access to the FlatBuffers table
+func GetInt16VectorSlot(table *flatbuffers.Table, slot flatbuffers.VOffsetT) []int16 {
+	if vector := GetInt16VectorPtrSlot(table, slot); vector != nil {
+		return *vector
+	}
+	return nil
+}
+
+// GetInt16VectorPtrSlot provides access to the FlatBuffers table
+func GetInt16VectorPtrSlot(table *flatbuffers.Table, slot flatbuffers.VOffsetT) *[]int16 {
+	if o := flatbuffers.UOffsetT(table.Offset(slot)); o != 0 {
+		var ln = table.VectorLen(o)
+		var start = table.Vector(o)
+		var values = make([]int16, ln)
+		for i := range values {
+			values[i] = table.GetInt16(start + flatbuffers.UOffsetT(i)*flatbuffers.SizeInt16)
+		}
+		return &values
+	}
+	return nil
+}
+
 // GetBoolSlot provides access to the FlatBuffers table
 func GetBoolSlot(table *flatbuffers.Table, slot flatbuffers.VOffsetT) bool {
 	return table.GetBoolSlot(slot, false)
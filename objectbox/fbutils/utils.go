@@ -47,6 +47,33 @@ func CreateStringVectorOffset(fbb *flatbuffers.Builder, values []string) flatbuf
 	return createOffsetVector(fbb, offsets)
 }
 
+// CreateBoolVectorOffset creates an offset in the FlatBuffers table.
+// Each value takes a single byte, matching the BoolVector property type of the database.
+func CreateBoolVectorOffset(fbb *flatbuffers.Builder, values []bool) flatbuffers.UOffsetT {
+	if values == nil {
+		return 0
+	}
+
+	fbb.StartVector(flatbuffers.SizeBool, len(values), flatbuffers.SizeBool)
+	for i := len(values) - 1; i >= 0; i-- {
+		fbb.PrependBool(values[i])
+	}
+	return fbb.EndVector(len(values))
+}
+
+// CreateInt16VectorOffset creates an offset in the FlatBuffers table
+func CreateInt16VectorOffset(fbb *flatbuffers.Builder, values []int16) flatbuffers.UOffsetT {
+	if values == nil {
+		return 0
+	}
+
+	fbb.StartVector(flatbuffers.SizeInt16, len(values), flatbuffers.SizeInt16)
+	for i := len(values) - 1; i >= 0; i-- {
+		fbb.PrependInt16(values[i])
+	}
+	return fbb.EndVector(len(values))
+}
+
 func createOffsetVector(fbb *flatbuffers.Builder, offsets []flatbuffers.UOffsetT) flatbuffers.UOffsetT {
 	fbb.StartVector(int(flatbuffers.SizeUOffsetT), len(offsets), int(flatbuffers.SizeUOffsetT))
 	for i := len(offsets) - 1; i >= 0; i-- {
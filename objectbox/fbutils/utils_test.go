@@ -68,6 +68,30 @@ func TestObjectRead(t *testing.T) {
 	fmt.Println(read)
 }
 
+func TestScalarVectors(t *testing.T) {
+	var bools = []bool{true, false, false, true, true}
+	var shorts = []int16{-32768, -1, 0, 1, 32767}
+
+	var fbb = flatbuffers.NewBuilder(64)
+	var offsetBools = CreateBoolVectorOffset(fbb, bools)
+	var offsetShorts = CreateInt16VectorOffset(fbb, shorts)
+	var offsetEmpty = CreateInt16VectorOffset(fbb, []int16{})
+	assert.Eq(t, flatbuffers.UOffsetT(0), CreateBoolVectorOffset(fbb, nil))
+	fbb.StartObject(4)
+	SetUOffsetTSlot(fbb, 0, offsetBools)
+	SetUOffsetTSlot(fbb, 1, offsetShorts)
+	SetUOffsetTSlot(fbb, 2, offsetEmpty)
+	fbb.Finish(fbb.EndObject())
+
+	var data = fbb.FinishedBytes()
+	var table = &flatbuffers.Table{Bytes: data, Pos: flatbuffers.GetUOffsetT(data)}
+	assert.Eq(t, bools, GetBoolVectorSlot(table, 4))
+	assert.Eq(t, shorts, GetInt16VectorSlot(table, 6))
+	assert.Eq(t, []int16{}, GetInt16VectorSlot(table, 8))
+	assert.True(t, GetBoolVectorPtrSlot(table, 10) == nil)
+	assert.True(t, GetInt16VectorSlot(table, 10) == nil)
+}
+
 // this simulates what cVoidPtrToByteSlice is doing, i.e. mapping an unmanaged pointer to a new []byte slice
 func getUnsafeBytes(source []byte) []byte {
 	var bytes []byte
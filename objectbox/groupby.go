@@ -0,0 +1,233 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"github.com/google/flatbuffers/go"
+	"time"
+)
+
+// GroupedQuery aggregates the objects matching a query per distinct value of a property, see Query.GroupBy().
+//
+// The aggregates are computed in a single pass over the stored data, decoding only the group and the aggregated
+// properties instead of loading whole objects. The results map each group value, as returned by Query.FindProjected()
+// (e.g. a string or an int64; byte vectors as a string), to its aggregate. Missing values (nil or not set) are read as
+// zero or an empty string, i.e. they form a group of their own and are included in aggregates.
+type GroupedQuery struct {
+	query *Query
+	group projectedProperty
+	err   error
+}
+
+// GroupBy creates a GroupedQuery computing aggregates per distinct value of the given property.
+func (query *Query) GroupBy(property Property) *GroupedQuery {
+	var grouped = &GroupedQuery{query: query}
+	grouped.group, grouped.err = query.projectedProperty(property)
+	if grouped.err == nil && grouped.group.propertyType == C.OBXPropertyType_StringVector {
+		grouped.err = fmt.Errorf("property %d is a string vector, which can't be used to group objects",
+			property.propertyId())
+	}
+	return grouped
+}
+
+// Count returns the number of objects in each group.
+func (grouped *GroupedQuery) Count() (counts map[interface{}]uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "GroupedQuery.Count", time.Now(), &err)
+	}
+
+	counts = make(map[interface{}]uint64)
+	err = grouped.visit(nil, func(key, _ interface{}) {
+		counts[key]++
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// Sum calculates the sum of the given integer property in each group.
+func (grouped *GroupedQuery) Sum(property Property) (sums map[interface{}]int64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "GroupedQuery.Sum", time.Now(), &err)
+	}
+
+	sums = make(map[interface{}]int64)
+	err = grouped.visitIntegers(property, func(key interface{}, value int64) {
+		sums[key] += value
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+// SumFloat64 calculates the sum of the given numeric property in each group.
+func (grouped *GroupedQuery) SumFloat64(property Property) (sums map[interface{}]float64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "GroupedQuery.SumFloat64", time.Now(), &err)
+	}
+
+	sums = make(map[interface{}]float64)
+	err = grouped.visitFloats(property, func(key interface{}, value float64) {
+		sums[key] += value
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+// Average calculates the average value of the given numeric property in each group.
+func (grouped *GroupedQuery) Average(property Property) (averages map[interface{}]float64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "GroupedQuery.Average", time.Now(), &err)
+	}
+
+	var sums = make(map[interface{}]float64)
+	var counts = make(map[interface{}]uint64)
+	err = grouped.visitFloats(property, func(key interface{}, value float64) {
+		sums[key] += value
+		counts[key]++
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	averages = make(map[interface{}]float64, len(sums))
+	for key, sum := range sums {
+		averages[key] = sum / float64(counts[key])
+	}
+	return averages, nil
+}
+
+// Min finds the minimum value of the given integer property in each group.
+func (grouped *GroupedQuery) Min(property Property) (minimums map[interface{}]int64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "GroupedQuery.Min", time.Now(), &err)
+	}
+
+	minimums = make(map[interface{}]int64)
+	err = grouped.visitIntegers(property, func(key interface{}, value int64) {
+		if current, found := minimums[key]; !found || value < current {
+			minimums[key] = value
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return minimums, nil
+}
+
+// Max finds the maximum value of the given integer property in each group.
+func (grouped *GroupedQuery) Max(property Property) (maximums map[interface{}]int64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "GroupedQuery.Max", time.Now(), &err)
+	}
+
+	maximums = make(map[interface{}]int64)
+	err = grouped.visitIntegers(property, func(key interface{}, value int64) {
+		if current, found := maximums[key]; !found || value > current {
+			maximums[key] = value
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return maximums, nil
+}
+
+func (grouped *GroupedQuery) visitIntegers(property Property, fn func(key interface{}, value int64)) error {
+	value, err := grouped.aggregated(property)
+	if err != nil {
+		return err
+	}
+	if value.propertyType == C.OBXPropertyType_Float || value.propertyType == C.OBXPropertyType_Double {
+		return fmt.Errorf("property %d is a floating-point property, use SumFloat64() or Average()",
+			property.propertyId())
+	}
+	return grouped.visit(&value, func(key, value interface{}) {
+		number, _ := integerValue(value)
+		fn(key, number)
+	})
+}
+
+func (grouped *GroupedQuery) visitFloats(property Property, fn func(key interface{}, value float64)) error {
+	value, err := grouped.aggregated(property)
+	if err != nil {
+		return err
+	}
+	return grouped.visit(&value, func(key, value interface{}) {
+		switch v := value.(type) {
+		case float32:
+			fn(key, float64(v))
+		case float64:
+			fn(key, v)
+		case uint64:
+			fn(key, float64(v))
+		default:
+			number, _ := integerValue(value)
+			fn(key, float64(number))
+		}
+	})
+}
+
+// aggregated checks the property can be aggregated, i.e. it's numeric
+func (grouped *GroupedQuery) aggregated(property Property) (projectedProperty, error) {
+	value, err := grouped.query.projectedProperty(property)
+	if err != nil {
+		return value, err
+	}
+	switch value.propertyType {
+	case C.OBXPropertyType_Byte, C.OBXPropertyType_Short, C.OBXPropertyType_Char, C.OBXPropertyType_Int,
+		C.OBXPropertyType_Long, C.OBXPropertyType_Date, C.OBXPropertyType_DateNano, C.OBXPropertyType_Float,
+		C.OBXPropertyType_Double:
+		return value, nil
+	}
+	return value, fmt.Errorf("property %d of type %s can't be aggregated, only numeric properties can",
+		property.propertyId(), propertyTypeName(value.propertyType))
+}
+
+// visit calls fn with the group key and the value of the aggregated property (if given) of each matching object
+func (grouped *GroupedQuery) visit(value *projectedProperty, fn func(key, value interface{})) error {
+	if grouped.err != nil {
+		return grouped.err
+	}
+
+	return grouped.query.visitBytes(func(bytes []byte) bool {
+		var table = &flatbuffers.Table{
+			Bytes: bytes,
+			Pos:   flatbuffers.GetUOffsetT(bytes),
+		}
+		var key = grouped.group.decode(table)
+		if data, isBytes := key.([]byte); isBytes {
+			key = string(data) // slices can't be used as map keys
+		}
+		if value != nil {
+			fn(key, value.decode(table))
+		} else {
+			fn(key, nil)
+		}
+		return true
+	})
+}
@@ -0,0 +1,271 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrStoreClosed is returned by operations on boxes, queries and transactions of a store that has been closed, e.g.
+// when a Box or a Query obtained before closing and reopening the store is still in use; get them from the new store.
+var ErrStoreClosed = errors.New("the store has been closed; boxes, queries and transactions of a closed store " +
+	"can't be used anymore")
+
+// ErrStoreClosing is returned when starting a transaction or submitting an async operation while the store is being
+// closed by CloseWithTimeout().
+var ErrStoreClosing = errors.New("the store is closing; new transactions and async operations aren't accepted")
+
+// checkOpen returns ErrStoreClosed if the store has been closed
+func (ob *ObjectBox) checkOpen() error {
+	ob.storeMutex.RLock()
+	defer ob.storeMutex.RUnlock()
+	return ob.checkOpenLocked()
+}
+
+// checkOpenLocked is like checkOpen() but must be called with the storeMutex (read-)locked
+func (ob *ObjectBox) checkOpenLocked() error {
+	if ob.store == nil {
+		return ErrStoreClosed
+	}
+	return nil
+}
+
+// enter is like checkOpen() but also counts the calling operation as active until leave() is called, so that close()
+// keeps the native store (and the native queries) meanwhile; used by box and query operations, which run in implicit
+// native transactions unless called inside of a Tx
+func (ob *ObjectBox) enter() error {
+	_, err := ob.enterStore()
+	return err
+}
+
+// enterStore is like enter() but also returns the native store, which stays valid until leave() is called
+func (ob *ObjectBox) enterStore() (*C.OBX_store, error) {
+	ob.storeMutex.RLock()
+	defer ob.storeMutex.RUnlock()
+	if err := ob.checkOpenLocked(); err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&ob.activeOpCount, 1)
+	return ob.store, nil
+}
+
+// enterAccepting is like enter() but also returns ErrStoreClosing while CloseWithTimeout() drains the store; used
+// when submitting new async operations
+func (ob *ObjectBox) enterAccepting() error {
+	ob.storeMutex.RLock()
+	defer ob.storeMutex.RUnlock()
+	if err := ob.checkAcceptingLocked(); err != nil {
+		return err
+	}
+	atomic.AddInt32(&ob.activeOpCount, 1)
+	return nil
+}
+
+// leave finishes an operation started by a successful enter()
+func (ob *ObjectBox) leave() {
+	if atomic.AddInt32(&ob.activeOpCount, -1) == 0 {
+		ob.closeIfIdle()
+	}
+}
+
+// closeIfIdle closes the native store if close() has been called meanwhile and neither transactions nor other
+// operations are active anymore
+func (ob *ObjectBox) closeIfIdle() {
+	ob.storeMutex.Lock()
+	defer ob.storeMutex.Unlock()
+	if ob.closedStore != nil && ob.idleLocked() {
+		ob.closeNativeLocked()
+	}
+}
+
+// idleLocked must be called with the storeMutex locked so that no transaction or operation can start meanwhile
+func (ob *ObjectBox) idleLocked() bool {
+	return atomic.LoadInt32(&ob.activeTxCount) == 0 && atomic.LoadInt32(&ob.activeOpCount) == 0
+}
+
+// closeNativeLocked closes the native queries, async queues and the native store released by close() and clears the
+// native handles of the boxes; must be called with the storeMutex locked
+func (ob *ObjectBox) closeNativeLocked() {
+	for _, handles := range ob.closedBoxHandles {
+		if ob.options.asyncTimeout > 0 {
+			// the shared async queues were created with a custom timeout and aren't owned by the store
+			C.obx_async_close(handles.cAsync)
+		}
+		handles.cBox = nil
+		handles.cAsync = nil
+	}
+	ob.closedBoxHandles = nil
+	for _, async := range ob.closedAsyncBoxes {
+		// not closed by the user; the native async instance must be closed before the store
+		C.obx_async_close(async.cAsync)
+		async.cAsync = nil
+	}
+	ob.closedAsyncBoxes = nil
+	ob.queries.closeAll()
+	C.obx_store_close(ob.closedStore)
+	ob.closedStore = nil
+}
+
+// checkAccepting is like checkOpen() but also returns ErrStoreClosing while CloseWithTimeout() drains the store;
+// used when starting new work, while work already in progress may continue
+func (ob *ObjectBox) checkAccepting() error {
+	ob.storeMutex.RLock()
+	defer ob.storeMutex.RUnlock()
+	return ob.checkAcceptingLocked()
+}
+
+// checkAcceptingLocked is like checkAccepting() but must be called with the storeMutex (read-)locked
+func (ob *ObjectBox) checkAcceptingLocked() error {
+	if err := ob.checkOpenLocked(); err != nil {
+		return err
+	}
+	if atomic.LoadInt32(&ob.closing) != 0 {
+		return ErrStoreClosing
+	}
+	return nil
+}
+
+// nativeQueries tracks the native (property) queries of a store which haven't been closed yet; closing the store
+// closes them first, so closing a Query or a PropertyQuery afterwards (e.g. by its finalizer) doesn't access the
+// freed native store. Native pointers are tracked instead of the Go objects, which would never be finalized otherwise.
+type nativeQueries struct {
+	mutex       sync.Mutex
+	closed      bool
+	queries     map[*C.OBX_query]bool
+	propQueries map[*C.OBX_query_prop]bool
+}
+
+// addQuery starts tracking the given native query; if the store has been closed meanwhile, it's closed right away
+func (nq *nativeQueries) addQuery(cQuery *C.OBX_query) error {
+	nq.mutex.Lock()
+	defer nq.mutex.Unlock()
+	if nq.closed {
+		C.obx_query_close(cQuery)
+		return ErrStoreClosed
+	}
+	if nq.queries == nil {
+		nq.queries = make(map[*C.OBX_query]bool)
+	}
+	nq.queries[cQuery] = true
+	return nil
+}
+
+// closeQuery closes the native query unless it has already been closed together with the store
+func (nq *nativeQueries) closeQuery(cQuery *C.OBX_query) error {
+	nq.mutex.Lock()
+	defer nq.mutex.Unlock()
+	if !nq.queries[cQuery] {
+		return nil
+	}
+	delete(nq.queries, cQuery)
+	return cCall(func() C.obx_err { return C.obx_query_close(cQuery) })
+}
+
+// addPropQuery is like addQuery() for property queries
+func (nq *nativeQueries) addPropQuery(cPropQuery *C.OBX_query_prop) error {
+	nq.mutex.Lock()
+	defer nq.mutex.Unlock()
+	if nq.closed {
+		C.obx_query_prop_close(cPropQuery)
+		return ErrStoreClosed
+	}
+	if nq.propQueries == nil {
+		nq.propQueries = make(map[*C.OBX_query_prop]bool)
+	}
+	nq.propQueries[cPropQuery] = true
+	return nil
+}
+
+// closePropQuery is like closeQuery() for property queries
+func (nq *nativeQueries) closePropQuery(cPropQuery *C.OBX_query_prop) error {
+	nq.mutex.Lock()
+	defer nq.mutex.Unlock()
+	if !nq.propQueries[cPropQuery] {
+		return nil
+	}
+	delete(nq.propQueries, cPropQuery)
+	return cCall(func() C.obx_err { return C.obx_query_prop_close(cPropQuery) })
+}
+
+// closeAll closes all tracked queries, property queries first as they're based on the queries; called before closing
+// the native store, see ObjectBox.closeNativeLocked()
+func (nq *nativeQueries) closeAll() {
+	nq.mutex.Lock()
+	defer nq.mutex.Unlock()
+	nq.closed = true
+	for cPropQuery := range nq.propQueries {
+		C.obx_query_prop_close(cPropQuery)
+	}
+	for cQuery := range nq.queries {
+		C.obx_query_close(cQuery)
+	}
+	nq.propQueries = nil
+	nq.queries = nil
+}
+
+// checkOpen returns ErrStoreClosed if the store this box belongs to has been closed
+func (box *Box) checkOpen() error {
+	return box.ObjectBox.checkOpen()
+}
+
+// checkRelation verifies the relation is part of the model of this store, i.e. it wasn't defined by a different model
+func (ob *ObjectBox) checkRelation(relation *RelationToMany) error {
+	if relation == nil || relation.Source == nil || relation.Target == nil {
+		return errors.New("relation must not be nil")
+	}
+	for _, e := range ob.schema.Entities {
+		if e.Id != relation.Source.Id {
+			continue
+		}
+		for _, r := range e.Relations {
+			if r.Id == relation.Id {
+				if r.TargetId != relation.Target.Id {
+					return fmt.Errorf("relation %d of entity %s targets entity %d in the model of this store, not %d",
+						relation.Id, e.Name, r.TargetId, relation.Target.Id)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("relation %d isn't part of entity %s in the model of this store", relation.Id, e.Name)
+	}
+	return fmt.Errorf("relation %d: source entity %d isn't part of the model of this store", relation.Id,
+		relation.Source.Id)
+}
+
+// checkRelation verifies the store is open and the relation is part of its model
+func (box *Box) checkRelation(relation *RelationToMany) error {
+	if err := box.checkOpen(); err != nil {
+		return err
+	}
+	return box.ObjectBox.checkRelation(relation)
+}
+
+// enterRelation is like ObjectBox.enter() but also verifies the relation is part of the model of this store
+func (box *Box) enterRelation(relation *RelationToMany) error {
+	if err := box.ObjectBox.checkRelation(relation); err != nil {
+		return err
+	}
+	return box.ObjectBox.enter()
+}
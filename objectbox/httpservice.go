@@ -0,0 +1,245 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HTTPService exposes the objects of all registered entities over a REST-like JSON API, e.g. so that an edge device can
+// make its local data available to a controller without hand-writing the handlers:
+//
+//	service := objectbox.NewHTTPService(ob)
+//	service.ReadOnly = true
+//	http.Handle("/data/", http.StripPrefix("/data", service))
+//
+// Endpoints (relative to the handler root, entities are addressed by their name as in the model):
+//
+//	GET    /                       - list of the entity names
+//	GET    /{Entity}               - all objects, optionally filtered by ?q={query string}, see Box.QueryString();
+//	                                 supports ?offset= and ?limit=
+//	GET    /{Entity}/count         - the number of objects, optionally filtered by ?q=
+//	GET    /{Entity}/{id}          - a single object
+//	POST   /{Entity}               - inserts or updates (put) the object from the request body; responds with its ID
+//	PUT    /{Entity}/{id}          - updates an existing object from the request body
+//	DELETE /{Entity}/{id}          - removes the object
+//
+// Objects are encoded using the standard encoding/json marshalling of the entity struct (i.e. respecting `json` tags),
+// same as Box.ExportJSON(). Errors are reported with an appropriate status code and {"error": "..."} body.
+// Note: the service doesn't implement any authentication; wrap it in a handler doing that when exposing it.
+// The service works on any model at runtime; neither per-entity services nor gRPC are generated as the generator is a
+// separate module.
+type HTTPService struct {
+	// ReadOnly rejects all requests that would change the data (POST, PUT and DELETE)
+	ReadOnly bool
+
+	ob *ObjectBox
+}
+
+// NewHTTPService creates a service exposing all entities of the given store; see HTTPService
+func NewHTTPService(ob *ObjectBox) *HTTPService {
+	return &HTTPService{ob: ob}
+}
+
+type httpError struct {
+	status int
+	err    error
+}
+
+func (err *httpError) Error() string {
+	return err.err.Error()
+}
+
+func httpErrorf(status int, format string, args ...interface{}) error {
+	return &httpError{status, fmt.Errorf(format, args...)}
+}
+
+// ServeHTTP implements http.Handler
+func (service *HTTPService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result, err := service.serve(r)
+	if err != nil {
+		var status = http.StatusInternalServerError
+		if httpErr, ok := err.(*httpError); ok {
+			status = httpErr.status
+		}
+		service.write(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	service.write(w, http.StatusOK, result)
+}
+
+func (service *HTTPService) write(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func (service *HTTPService) serve(r *http.Request) (interface{}, error) {
+	var path = strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) > 2 {
+		return nil, httpErrorf(http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+
+	if path[0] == "" {
+		if r.Method != http.MethodGet {
+			return nil, httpErrorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		var names = make([]string, 0, len(service.ob.entitiesByName))
+		for name := range service.ob.entitiesByName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	var entity = service.ob.entitiesByName[path[0]]
+	if entity == nil {
+		return nil, httpErrorf(http.StatusNotFound, "unknown entity %s", path[0])
+	}
+	box, err := service.ob.box(entity.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Method != http.MethodGet && service.ReadOnly {
+		return nil, httpErrorf(http.StatusMethodNotAllowed, "the service is read-only")
+	}
+
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return service.find(box, r)
+		case http.MethodPost:
+			return service.put(box, r, 0)
+		}
+		return nil, httpErrorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+
+	if path[1] == "count" && r.Method == http.MethodGet {
+		query, err := service.query(box, r)
+		if err != nil {
+			return nil, err
+		}
+		defer query.Close()
+		return query.Count()
+	}
+
+	id, err := strconv.ParseUint(path[1], 10, 64)
+	if err != nil || id == 0 {
+		return nil, httpErrorf(http.StatusNotFound, "invalid object ID %s", path[1])
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		object, err := box.Get(id)
+		if err == nil && isNilObject(object) {
+			err = httpErrorf(http.StatusNotFound, "object with ID %d not found", id)
+		}
+		return object, err
+	case http.MethodPut:
+		return service.put(box, r, id)
+	case http.MethodDelete:
+		if err := service.checkExists(box, id); err != nil {
+			return nil, err
+		}
+		return map[string]uint64{"id": id}, box.RemoveId(id)
+	}
+	return nil, httpErrorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+}
+
+// isNilObject checks whether the object returned by Box.Get() is nil, which may be a typed nil pointer
+func isNilObject(object interface{}) bool {
+	if object == nil {
+		return true
+	}
+	var value = reflect.ValueOf(object)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+func (service *HTTPService) checkExists(box *Box, id uint64) error {
+	if exists, err := box.Contains(id); err != nil {
+		return err
+	} else if !exists {
+		return httpErrorf(http.StatusNotFound, "object with ID %d not found", id)
+	}
+	return nil
+}
+
+// query creates a query from the optional "q" URL parameter
+func (service *HTTPService) query(box *Box, r *http.Request) (*Query, error) {
+	var expression = r.URL.Query().Get("q")
+	if expression == "" {
+		return box.QueryOrError()
+	}
+	query, err := box.QueryString(expression)
+	if err != nil {
+		return nil, &httpError{http.StatusBadRequest, err}
+	}
+	return query, nil
+}
+
+func (service *HTTPService) find(box *Box, r *http.Request) (interface{}, error) {
+	query, err := service.query(box, r)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	for _, param := range []string{"offset", "limit"} {
+		var text = r.URL.Query().Get(param)
+		if text == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return nil, httpErrorf(http.StatusBadRequest, "invalid %s %s", param, text)
+		}
+		if param == "offset" {
+			query.Offset(value)
+		} else {
+			query.Limit(value)
+		}
+	}
+	return query.Find()
+}
+
+// put decodes the object from the request body and writes it; if id is given, the object must already exist
+func (service *HTTPService) put(box *Box, r *http.Request, id uint64) (interface{}, error) {
+	var object = reflect.New(box.objectType()).Interface()
+	if err := json.NewDecoder(r.Body).Decode(object); err != nil {
+		return nil, httpErrorf(http.StatusBadRequest, "can't decode the object: %s", err)
+	}
+
+	if id == 0 {
+		id, err := box.Put(object)
+		return map[string]uint64{"id": id}, err
+	}
+
+	if err := service.checkExists(box, id); err != nil {
+		return nil, err
+	}
+	if err := box.entity.binding.SetId(object, id); err != nil {
+		return nil, err
+	}
+	return map[string]uint64{"id": id}, box.Update(object)
+}
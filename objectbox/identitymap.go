@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdint.h>
+#ifdef _WIN32
+#include <windows.h>
+static uint64_t obx_go_thread_id() { return (uint64_t) GetCurrentThreadId(); }
+#else
+#include <pthread.h>
+static uint64_t obx_go_thread_id() { return (uint64_t) (uintptr_t) pthread_self(); }
+#endif
+*/
+import "C"
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// identityMap holds the objects read by Box.Get() inside ObjectBox.WithIdentityMap()
+type identityMap struct {
+	objects map[TypeId]map[uint64]interface{}
+}
+
+// identityMaps holds the active identity maps by the OS thread of the transaction they're bound to; transactions lock
+// their goroutine to the thread so it identifies the goroutine for the duration of WithIdentityMap()
+type identityMaps struct {
+	active   int32 // number of entries, accessed atomically to skip the lookup when no identity map is in use
+	mutex    sync.Mutex
+	byThread map[uint64]*identityMap
+}
+
+// WithIdentityMap executes fn inside a read transaction in which repeated Box.Get() calls of the same ID return the same
+// object instance, which is decoded only once. This includes the relation targets loaded while reading objects, so
+// it avoids decoding the same targets many times when reading a relation-heavy graph.
+//
+// The objects are shared by all reads inside fn and must not be modified there. Only Get() goes through the identity
+// map; queries and other reads always decode their results. Nested calls use the outermost identity map.
+// Note: as with RunInReadTx(), fn must run sequentially on the calling goroutine.
+func (ob *ObjectBox) WithIdentityMap(fn func() error) error {
+	return ob.RunInReadTx(func() error {
+		var thread = uint64(C.obx_go_thread_id())
+		if ob.identityMaps.current() != nil {
+			return fn()
+		}
+
+		ob.identityMaps.mutex.Lock()
+		if ob.identityMaps.byThread == nil {
+			ob.identityMaps.byThread = make(map[uint64]*identityMap)
+		}
+		ob.identityMaps.byThread[thread] = &identityMap{objects: make(map[TypeId]map[uint64]interface{})}
+		atomic.AddInt32(&ob.identityMaps.active, 1)
+		ob.identityMaps.mutex.Unlock()
+
+		defer func() {
+			ob.identityMaps.mutex.Lock()
+			delete(ob.identityMaps.byThread, thread)
+			atomic.AddInt32(&ob.identityMaps.active, -1)
+			ob.identityMaps.mutex.Unlock()
+		}()
+		return fn()
+	})
+}
+
+// current returns the identity map of the calling goroutine or nil if it's not inside WithIdentityMap()
+func (maps *identityMaps) current() *identityMap {
+	if atomic.LoadInt32(&maps.active) == 0 {
+		return nil
+	}
+	var thread = uint64(C.obx_go_thread_id())
+	maps.mutex.Lock()
+	defer maps.mutex.Unlock()
+	return maps.byThread[thread]
+}
+
+func (im *identityMap) get(entityId TypeId, id uint64) (interface{}, bool) {
+	object, found := im.objects[entityId][id]
+	return object, found
+}
+
+func (im *identityMap) put(entityId TypeId, id uint64, object interface{}) {
+	var objects = im.objects[entityId]
+	if objects == nil {
+		objects = make(map[uint64]interface{})
+		im.objects[entityId] = objects
+	}
+	objects[id] = object
+}
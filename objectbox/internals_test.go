@@ -3,6 +3,8 @@ package objectbox
 import (
 	"runtime"
 	"testing"
+
+	flatbuffers "github.com/google/flatbuffers/go"
 )
 
 func TestLargeArraySupport(t *testing.T) {
@@ -18,3 +20,54 @@ func TestLargeArraySupport(t *testing.T) {
 			"in the ObjectBox core library", runtime.GOARCH)
 	}
 }
+
+func TestRedactQueryDescription(t *testing.T) {
+	var redactString = func(property string) bool { return property == "String" || property == "ByteVector" }
+	var cases = map[string]string{
+		`TRUE`:                         `TRUE`,
+		`Int == 47`:                    `Int == 47`,
+		`String == "Val-1"`:            `String == ?`,
+		`String == "a \" AND b"`:       `String == ?`,
+		`(String != "x" AND Int < -1)`: `(String != ? AND Int < -1)`,
+		`(Int64 between 47 and 94 OR (String starts with "Va" AND Float64 > 4.7e+01))`:   `(Int64 between 47 and 94 OR (String starts with ? AND Float64 > 4.7e+01))`,
+		`(ByteVector < byte[5]{0x0102030508} OR String == "" OR Int64 between -1 and 1)`: `(ByteVector < ? OR String == ? OR Int64 between -1 and 1)`,
+	}
+	for description, expected := range cases {
+		if actual := redactQueryDescription(description, redactString); actual != expected {
+			t.Errorf("%s: expected %s, got %s", description, expected, actual)
+		}
+	}
+
+	var redactAll = func(string) bool { return true }
+	var expected = `(Int64 between ? and ? OR Float64 > ?)`
+	if actual := redactQueryDescription(`(Int64 between -47 and 94 OR Float64 > 4.7e-01)`, redactAll); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestSafeLoad(t *testing.T) {
+	var fbb = flatbuffers.NewBuilder(0)
+	fbb.StartObject(2)
+	fbb.PrependUint64Slot(0, 47, 0)
+	fbb.Finish(fbb.EndObject())
+	var bytes = fbb.FinishedBytes()
+
+	if err := checkTable(bytes); err != nil {
+		t.Errorf("valid table rejected: %s", err)
+	}
+	for _, size := range []int{0, 3, 6, len(bytes) - 1} {
+		if err := checkTable(bytes[:size]); err == nil {
+			t.Errorf("truncated table (%d of %d bytes) accepted", size, len(bytes))
+		}
+	}
+
+	var e = &entity{name: "Test"}
+	var err = e.safeLoad(bytes, func(bytes []byte) error {
+		var slice []byte
+		_ = slice[len(bytes)] // like generated code reading a malformed vector
+		return nil
+	})
+	if loadErr, ok := err.(*LoadError); !ok || loadErr.Entity != "Test" {
+		t.Errorf("expected a LoadError, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// maxInternedStrings limits the number of distinct values cached per property; once reached, further values are
+// returned as loaded, i.e. not interned. This prevents unbounded growth if a property isn't as low-cardinality as expected.
+const maxInternedStrings = 10000
+
+// InternStrings enables string interning for the given (low-cardinality) properties, e.g. device names or enum-like
+// values: all loaded objects share a single instance of each distinct value instead of each object holding its own copy.
+// This can considerably reduce memory usage when reading many objects repeating the same few strings.
+// At most 10000 distinct values are cached per property.
+//
+// Note: the generated binding code may implement faster bulk-loading extensions (see ObjectBindingAppendMany); these
+// are not used for entities with interned properties.
+func (builder *Builder) InternStrings(properties ...*PropertyString) *Builder {
+	builder.internedProperties = append(builder.internedProperties, properties...)
+	return builder
+}
+
+// internedBinding wraps the entity binding, interning string fields of the objects returned by Load()
+type internedBinding struct {
+	ObjectBinding
+	fields []*internedField
+}
+
+type internedField struct {
+	index []int // reflect field index in the entity struct
+	mutex sync.RWMutex
+	cache map[string]string
+}
+
+// Load implements ObjectBinding
+func (binding *internedBinding) Load(ob *ObjectBox, bytes []byte) (interface{}, error) {
+	object, err := binding.ObjectBinding.Load(ob, bytes)
+	if err != nil || object == nil {
+		return object, err
+	}
+
+	var value = reflect.ValueOf(object)
+	if value.Kind() != reflect.Ptr {
+		return object, nil // by-value bindings; can't be modified in place
+	}
+	value = value.Elem()
+	for _, field := range binding.fields {
+		var fieldValue = value.FieldByIndex(field.index)
+		fieldValue.SetString(field.intern(fieldValue.String()))
+	}
+	return object, nil
+}
+
+func (field *internedField) intern(value string) string {
+	field.mutex.RLock()
+	interned, found := field.cache[value]
+	field.mutex.RUnlock()
+	if found {
+		return interned
+	}
+
+	field.mutex.Lock()
+	defer field.mutex.Unlock()
+	if interned, found = field.cache[value]; found {
+		return interned
+	} else if len(field.cache) < maxInternedStrings {
+		field.cache[value] = value
+	}
+	return value
+}
+
+// applyStringInterning wraps the bindings of entities with interned properties; called when the store is being built.
+func (model *Model) applyStringInterning(properties []*PropertyString) error {
+	var bindings = make(map[TypeId]*internedBinding)
+	for _, property := range properties {
+		var entity = model.entitiesById[property.entityId()]
+		if entity == nil {
+			return fmt.Errorf("can't intern property %d - entity %d not found", property.Id, property.entityId())
+		}
+
+		var info *ModelPropertyInfo
+		for _, e := range model.schema.Entities {
+			if e.Id == entity.id {
+				for _, p := range e.Properties {
+					if p.Id == property.propertyId() {
+						info = p
+					}
+				}
+			}
+		}
+		if info == nil {
+			return fmt.Errorf("can't intern property %d of entity %s - not found", property.Id, entity.name)
+		}
+
+		var binding = bindings[entity.id]
+		if binding == nil {
+			binding = &internedBinding{ObjectBinding: entity.binding}
+			bindings[entity.id] = binding
+		}
+
+		// the generated code uses the field name as the property name (unless overridden using a tag)
+		var objectType = reflect.TypeOf(entity.binding.MakeSlice(0)).Elem()
+		if objectType.Kind() == reflect.Ptr {
+			objectType = objectType.Elem()
+		}
+		field, found := objectType.FieldByName(info.Name)
+		if !found || field.Type.Kind() != reflect.String {
+			return fmt.Errorf("can't intern property %s.%s - no string field with the same name found in %s",
+				entity.name, info.Name, objectType)
+		}
+		binding.fields = append(binding.fields, &internedField{index: field.Index, cache: make(map[string]string)})
+	}
+
+	for id, binding := range bindings {
+		model.entitiesById[id].binding = binding
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build go1.23
+
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import "iter"
+
+// All returns an iterator over the IDs and objects matching the query, streaming them in a single read transaction
+// instead of collecting them in a slice. The objects are of the entity's pointer type; generated queries don't offer
+// a typed variant yet, so cast them, e.g.
+//
+//	var err error
+//	for id, object := range query.All(&err) {
+//		task := object.(*Task)
+//		...
+//	}
+//	if err != nil {
+//		return err
+//	}
+//
+// Iteration stops at the first error, which is stored in *err (err must not be nil; it is reset when the iteration
+// starts).
+// The loop body runs inside the read transaction on a locked OS thread: don't write to the store or start goroutines
+// expecting to be part of the transaction in it. Eager relations (see Eager()) aren't loaded.
+func (query *Query) All(err *error) iter.Seq2[uint64, interface{}] {
+	return func(yield func(uint64, interface{}) bool) {
+		*err = nil
+		if visitErr := query.visit(idYielder(query.box, err, yield)); visitErr != nil {
+			*err = visitErr
+		}
+	}
+}
+
+// All returns an iterator over the IDs and objects of all objects in the box, streaming them in a single read
+// transaction; see Query.All() for the usage and the restrictions of the loop body.
+func (box *Box) All(err *error) iter.Seq2[uint64, interface{}] {
+	return func(yield func(uint64, interface{}) bool) {
+		*err = nil
+		if visitErr := box.VisitAll(idYielder(box, err, yield)); visitErr != nil {
+			*err = visitErr
+		}
+	}
+}
+
+// idYielder adapts yield to a visitor function, reading the ID of each object; stores an error reading it in *err
+func idYielder(box *Box, err *error, yield func(uint64, interface{}) bool) func(object interface{}) bool {
+	return func(object interface{}) bool {
+		id, idErr := box.entity.binding.GetId(object)
+		if idErr != nil {
+			*err = idErr
+			return false
+		}
+		return yield(id, object)
+	}
+}
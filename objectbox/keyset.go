@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FindAfter implements keyset (seek) pagination by ID: it returns at most limit objects matching the query with an ID
+// greater than lastId, ordered by ID. Pass zero to get the first page and the ID of the last object of the previous
+// page to get the next one. As opposed to Offset(), which skips the preceding objects by reading them, this seeks
+// directly to the start of the page so the cost of a page doesn't grow with its position.
+//
+// The query is executed using the conditions given to Box.Query(); it must not contain orders as the results are
+// ordered by ID. Params set on the query, as well as its offset and limit, don't apply.
+func (query *Query) FindAfter(lastId uint64, limit uint64) (objects interface{}, err error) {
+	id, err := query.keysetIdProperty()
+	if err != nil {
+		return nil, err
+	}
+	return query.findKeyset(limit, id.GreaterThan(lastId), id.OrderAsc())
+}
+
+// FindAfterBy is like FindAfter but orders the results by the given property (ideally indexed, e.g. a creation date),
+// using the ID to order objects with equal values. Pass the value and the ID of the last object of the previous page,
+// or math.MinInt64 and zero to get the first page:
+//
+//	page, err := query.FindAfterBy(Event_.Date, math.MinInt64, 0, 100)
+//	...
+//	last := page[len(page)-1]
+//	next, err := query.FindAfterBy(Event_.Date, last.Date, last.Id, 100)
+func (query *Query) FindAfterBy(property *PropertyInt64, lastValue int64, lastId uint64,
+	limit uint64) (objects interface{}, err error) {
+	if property.entityId() != query.entity.id {
+		return nil, fmt.Errorf("property from a different entity %d passed, expected %d", property.entityId(),
+			query.entity.id)
+	}
+
+	id, err := query.keysetIdProperty()
+	if err != nil {
+		return nil, err
+	}
+	var after = Any(property.GreaterThan(lastValue), All(property.Equals(lastValue), id.GreaterThan(lastId)))
+	return query.findKeyset(limit, after, property.OrderAsc(), id.OrderAsc())
+}
+
+func (query *Query) keysetIdProperty() (*PropertyUint64, error) {
+	property, err := query.idProperty()
+	if err != nil {
+		return nil, err
+	}
+	return &PropertyUint64{&BaseProperty{Id: property.Id, Entity: &Entity{Id: query.entity.id}}}, nil
+}
+
+// findKeyset builds a query with the conditions of this one and the given ones and returns at most limit objects
+func (query *Query) findKeyset(limit uint64, conditions ...Condition) (interface{}, error) {
+	if err := query.enter(); err != nil {
+		return nil, err
+	}
+	defer query.objectBox.leave()
+
+	for _, condition := range query.conditions {
+		if _, isOrder := condition.(*orderClosure); isOrder {
+			return nil, errors.New("keyset pagination defines the order itself, the query must not contain orders")
+		}
+	}
+
+	// query.conditions already contain the soft-delete ones (if applicable), therefore use the unscoped builder
+	var all = make([]Condition, 0, len(query.conditions)+len(conditions))
+	all = append(all, query.conditions...)
+	page, err := query.box.query(append(all, conditions...))
+	if err != nil {
+		return nil, err
+	}
+	defer page.Close()
+
+	page.eager = query.eager
+	page.resultCountHint = limit
+	return page.Limit(limit).Find()
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-go/objectbox/fbutils"
+)
+
+// KV is a simple key-value store for data which doesn't warrant an entity of its own, e.g. application settings.
+// It's enabled by Builder.KV() and obtained by ObjectBox.KV().
+//
+// The data is kept in the store itself, as objects of an internal entity added to the model. Therefore, KV operations
+// run inside a transaction (RunInWriteTx() etc.) are committed or rolled back together with the other changes.
+type KV struct {
+	objectBox *ObjectBox
+	box       *Box
+}
+
+// The internal entity uses IDs reserved at the top of the ID range to stay clear of those assigned by the generator.
+const (
+	kvEntryId       TypeId = 65535
+	kvEntryUid             = 7393061574291049001
+	kvEntryIndexId  TypeId = 65535
+	kvEntryIndexUid        = 7393061574291049201
+)
+
+// kvEntry is the internal entity backing KV
+type kvEntry struct {
+	Id    uint64
+	Key   string
+	Value []byte
+}
+
+type kvEntry_EntityInfo struct {
+	Entity
+}
+
+var kvEntryBinding = kvEntry_EntityInfo{Entity: Entity{Id: kvEntryId}}
+
+var kvEntryKey = &PropertyString{BaseProperty: &BaseProperty{Id: 2, Entity: &kvEntryBinding.Entity}}
+
+func (kvEntry_EntityInfo) GeneratorVersion() int {
+	return 6
+}
+
+func (kvEntry_EntityInfo) AddToModel(model *Model) {
+	model.Entity("ObjectBoxKVEntry", kvEntryId, kvEntryUid)
+	model.Property("Id", 6, 1, 7393061574291049101)
+	model.PropertyFlags(1)
+	model.Property("Key", 9, 2, 7393061574291049102)
+	model.PropertyFlags(2080) // unique & indexed by hash
+	model.PropertyIndex(kvEntryIndexId, kvEntryIndexUid)
+	model.Property("Value", 23, 3, 7393061574291049103)
+	model.EntityLastPropertyId(3, 7393061574291049103)
+}
+
+func (kvEntry_EntityInfo) GetId(object interface{}) (uint64, error) {
+	return object.(*kvEntry).Id, nil
+}
+
+func (kvEntry_EntityInfo) SetId(object interface{}, id uint64) error {
+	object.(*kvEntry).Id = id
+	return nil
+}
+
+func (kvEntry_EntityInfo) PutRelated(ob *ObjectBox, object interface{}, id uint64) error {
+	return nil
+}
+
+func (kvEntry_EntityInfo) Flatten(object interface{}, fbb *flatbuffers.Builder, id uint64) error {
+	obj := object.(*kvEntry)
+	var offsetKey = fbutils.CreateStringOffset(fbb, obj.Key)
+	var offsetValue = fbutils.CreateByteVectorOffset(fbb, obj.Value)
+
+	fbb.StartObject(3)
+	fbutils.SetUint64Slot(fbb, 0, id)
+	fbutils.SetUOffsetTSlot(fbb, 1, offsetKey)
+	fbutils.SetUOffsetTSlot(fbb, 2, offsetValue)
+	return nil
+}
+
+func (kvEntry_EntityInfo) Load(ob *ObjectBox, bytes []byte) (interface{}, error) {
+	if len(bytes) == 0 {
+		return nil, errors.New("can't deserialize a key-value entry - no data received")
+	}
+
+	var table = &flatbuffers.Table{
+		Bytes: bytes,
+		Pos:   flatbuffers.GetUOffsetT(bytes),
+	}
+
+	return &kvEntry{
+		Id:    table.GetUint64Slot(4, 0),
+		Key:   fbutils.GetStringSlot(table, 6),
+		Value: fbutils.GetByteVectorSlot(table, 8),
+	}, nil
+}
+
+func (kvEntry_EntityInfo) MakeSlice(capacity int) interface{} {
+	return make([]*kvEntry, 0, capacity)
+}
+
+func (kvEntry_EntityInfo) AppendToSlice(slice interface{}, object interface{}) interface{} {
+	if object == nil {
+		return append(slice.([]*kvEntry), nil)
+	}
+	return append(slice.([]*kvEntry), object.(*kvEntry))
+}
+
+// KV adds the internal entity backing ObjectBox.KV() to the model, see KV.
+// The entity uses IDs reserved above those assigned by the generator, i.e. the model can't use entity or index IDs
+// as high as 65535.
+func (builder *Builder) KV() *Builder {
+	builder.kv = true
+	return builder
+}
+
+// addKVEntity registers the internal entity backing KV, unless already present (e.g. when building again)
+func (model *Model) addKVEntity() error {
+	if model.entitiesById[kvEntryId] != nil {
+		return nil
+	}
+
+	if model.lastEntityId >= kvEntryId || model.lastIndexId >= kvEntryIndexId {
+		return fmt.Errorf("can't add the key-value store entity - the model uses IDs reserved for it (%d)", kvEntryId)
+	}
+
+	model.RegisterBinding(kvEntryBinding)
+	model.LastEntityId(kvEntryId, kvEntryUid)
+	model.LastIndexId(kvEntryIndexId, kvEntryIndexUid)
+	if model.Error != nil {
+		return model.Error
+	}
+	return model.validate()
+}
+
+// KV returns the key-value store kept in this store; it must have been enabled by Builder.KV().
+func (ob *ObjectBox) KV() (*KV, error) {
+	if err := ob.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	box, err := ob.BoxOrError(kvEntryId)
+	if err != nil {
+		return nil, errors.New("the key-value store is not enabled, see Builder.KV()")
+	}
+	return &KV{objectBox: ob, box: box}, nil
+}
+
+// find returns the entry stored under the given key or nil if there's none
+func (kv *KV) find(key string) (*kvEntry, error) {
+	query, err := kv.box.QueryOrError(kvEntryKey.Equals(key, true))
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	entries, err := query.Find()
+	if err != nil {
+		return nil, err
+	}
+	if slice := entries.([]*kvEntry); len(slice) > 0 {
+		return slice[0], nil
+	}
+	return nil, nil
+}
+
+// Get reads the value stored under the given key. Returns a nil slice and found=false if the key doesn't exist.
+func (kv *KV) Get(key string) (value []byte, found bool, err error) {
+	entry, err := kv.find(key)
+	if err != nil || entry == nil {
+		return nil, false, err
+	}
+	if entry.Value == nil {
+		return []byte{}, true, nil
+	}
+	return entry.Value, true, nil
+}
+
+// GetString reads the value stored under the given key as a string, see Get().
+func (kv *KV) GetString(key string) (value string, found bool, err error) {
+	bytes, found, err := kv.Get(key)
+	return string(bytes), found, err
+}
+
+// Put stores the value under the given key, replacing the previous value, if any.
+func (kv *KV) Put(key string, value []byte) error {
+	return kv.objectBox.RunInWriteTx(func() error {
+		entry, err := kv.find(key)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			entry = &kvEntry{Key: key}
+		}
+		entry.Value = value
+		_, err = kv.box.Put(entry)
+		return err
+	})
+}
+
+// PutString stores the string value under the given key, see Put().
+func (kv *KV) PutString(key string, value string) error {
+	return kv.Put(key, []byte(value))
+}
+
+// Delete removes the value stored under the given key. Returns false if the key didn't exist.
+func (kv *KV) Delete(key string) (removed bool, err error) {
+	query, err := kv.box.QueryOrError(kvEntryKey.Equals(key, true))
+	if err != nil {
+		return false, err
+	}
+	defer query.Close()
+
+	count, err := query.Remove()
+	return count > 0, err
+}
+
+// Iterate calls fn for each key starting with the given prefix (all keys if empty), in ascending key order.
+// The iteration stops at the first error returned by fn, which is passed through.
+func (kv *KV) Iterate(prefix string, fn func(key string, value []byte) error) error {
+	var conditions = []Condition{kvEntryKey.OrderAsc(true)}
+	if prefix != "" {
+		conditions = append(conditions, kvEntryKey.HasPrefix(prefix, true))
+	}
+	query, err := kv.box.QueryOrError(conditions...)
+	if err != nil {
+		return err
+	}
+	defer query.Close()
+
+	entries, err := query.Find()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries.([]*kvEntry) {
+		if err = fn(entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
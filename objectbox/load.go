@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"fmt"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// LoadError is returned by read operations if a stored object can't be loaded by the generated binding, e.g. because
+// the data is corrupted or was written by an incompatible version of the model. Instead of panicking while decoding
+// such objects, the read operation fails with this error.
+type LoadError struct {
+	Entity string // name of the entity of the object
+	Err    error  // describes the problem
+}
+
+func (err *LoadError) Error() string {
+	return fmt.Sprintf("can't load %s object: %s", err.Entity, err.Err)
+}
+
+// Unwrap returns the underlying error
+func (err *LoadError) Unwrap() error {
+	return err.Err
+}
+
+// load constructs the object from the serialized bytes, see safeLoad()
+func (entity *entity) load(ob *ObjectBox, bytes []byte) (object interface{}, err error) {
+	err = entity.safeLoad(bytes, func(bytes []byte) error {
+		object, err = entity.binding.Load(ob, bytes)
+		return err
+	})
+	return object, err
+}
+
+// safeLoad checks the bounds of the FlatBuffers table and calls the given (generated) load function, turning a panic
+// (e.g. an out-of-range slice access on malformed data) into a LoadError. If a PropertyCodec is set, the data passed
+// to the load function is decoded first.
+func (entity *entity) safeLoad(bytes []byte, load func(bytes []byte) error) (err error) {
+	if err := checkTable(bytes); err != nil {
+		return &LoadError{Entity: entity.name, Err: err}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = &LoadError{Entity: entity.name, Err: fmt.Errorf("%v", r)}
+		}
+	}()
+
+	if codec := entity.codec; codec != nil {
+		if bytes, err = codec.decode(bytes); err != nil {
+			return &LoadError{Entity: entity.name, Err: err}
+		}
+	}
+	return load(bytes)
+}
+
+// checkTable verifies the root table and its vtable lie within the given bytes. Missing fields (e.g. written by an
+// older model version) are fine, the generated code reads them as zero values.
+func checkTable(bytes []byte) error {
+	var size = len(bytes)
+	if size < flatbuffers.SizeUOffsetT {
+		return fmt.Errorf("data too short: %d bytes", size)
+	}
+
+	var tablePos = int(flatbuffers.GetUOffsetT(bytes))
+	if tablePos < flatbuffers.SizeUOffsetT || tablePos > size-flatbuffers.SizeSOffsetT {
+		return fmt.Errorf("table offset %d out of range (data size %d)", tablePos, size)
+	}
+
+	var vtablePos = tablePos - int(flatbuffers.GetSOffsetT(bytes[tablePos:]))
+	if vtablePos < 0 || vtablePos > size-2*flatbuffers.SizeVOffsetT {
+		return fmt.Errorf("vtable offset %d out of range (data size %d)", vtablePos, size)
+	}
+
+	var vtableSize = int(flatbuffers.GetVOffsetT(bytes[vtablePos:]))
+	if vtableSize < 2*flatbuffers.SizeVOffsetT || vtableSize%flatbuffers.SizeVOffsetT != 0 || vtablePos+vtableSize > size {
+		return fmt.Errorf("invalid vtable size %d at offset %d (data size %d)", vtableSize, vtablePos, size)
+	}
+
+	var tableSize = int(flatbuffers.GetVOffsetT(bytes[vtablePos+flatbuffers.SizeVOffsetT:]))
+	if tablePos+tableSize > size {
+		return fmt.Errorf("table size %d at offset %d out of range (data size %d)", tableSize, tablePos, size)
+	}
+	return nil
+}
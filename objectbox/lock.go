@@ -0,0 +1,244 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/objectbox/objectbox-go/objectbox/obxerr"
+)
+
+// lockInfoFile is stored next to the database files by the process that has the store open for writing.
+// The native library doesn't tell which process holds its lock, so the binding keeps this record itself.
+const lockInfoFile = "objectbox-lock.json"
+
+// lockRetryInterval is the pause between open attempts while waiting for a lock, see WaitForWritableLock()
+const lockRetryInterval = 50 * time.Millisecond
+
+// LockInfo describes the process that has opened a store for writing, see ObjectBox.LockInfo() and StoreLockedError.
+// It's recorded when the store is opened and removed when it's closed; if the process crashed, the record may be stale.
+type LockInfo struct {
+	PID        int       `json:"pid"`
+	Host       string    `json:"host"`
+	Executable string    `json:"executable,omitempty"`
+	Since      time.Time `json:"since"`
+}
+
+func (info *LockInfo) String() string {
+	var process = fmt.Sprintf("PID %d on %s", info.PID, info.Host)
+	if info.Executable != "" {
+		process += " (" + info.Executable + ")"
+	}
+	return process + " since " + info.Since.Format(time.RFC3339)
+}
+
+// isStale reports whether the record was left behind by a process that isn't running anymore; only processes on this
+// host can be checked
+func (info *LockInfo) isStale() bool {
+	var host, _ = os.Hostname()
+	return info.Host == host && !processAlive(info.PID)
+}
+
+// processAlive reports whether a process with the given PID is running on this host
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	defer process.Release()
+	if runtime.GOOS == "windows" {
+		return true // FindProcess() opens the process on Windows so it fails if it doesn't exist
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || err == syscall.EPERM
+}
+
+// isLockError reports whether the native library failed to open the store in the given directory because it's in use;
+// other errors, e.g. of an incompatible model, aren't worth waiting for. The native library doesn't report a dedicated
+// error code, so a storage error counts if a running process has recorded holding the store or if the message
+// matches the one of a failed file lock.
+func isLockError(err error, directory string) bool {
+	switch obxerr.Code(err) {
+	case obxerr.IllegalState, obxerr.DbGeneral, obxerr.StorageGeneral:
+	default:
+		return false
+	}
+
+	if holder := readLockHolder(directory); holder != nil {
+		return true
+	}
+
+	var msg = strings.ToLower(err.Error())
+	for _, hint := range []string{"lock", "still open", "in use", "already open", strings.ToLower(syscall.EAGAIN.Error()),
+		strings.ToLower(syscall.EWOULDBLOCK.Error())} {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// StoreLockedError is returned by Builder.BuildOrError() if the store couldn't be opened because another process holds
+// its lock, see Builder.WaitForWritableLock() and Builder.OpenIfLockedReadOnly().
+type StoreLockedError struct {
+	Directory string
+	Holder    *LockInfo // the process holding the lock as recorded in the store directory; nil if there's no record
+	Err       error     // the error reported by the native library
+}
+
+func (err *StoreLockedError) Error() string {
+	if err.Holder == nil {
+		return fmt.Sprintf("can't open store in %s - it's locked by another process: %s", err.Directory, err.Err)
+	}
+	return fmt.Sprintf("can't open store in %s - it's locked by %s: %s", err.Directory, err.Holder, err.Err)
+}
+
+// Unwrap returns the underlying error
+func (err *StoreLockedError) Unwrap() error {
+	return err.Err
+}
+
+// WaitForWritableLock makes BuildOrError() wait up to the given timeout for another process to release the store
+// before giving up (or falling back to read-only, see OpenIfLockedReadOnly()). By default, it fails immediately.
+func (builder *Builder) WaitForWritableLock(timeout time.Duration) *Builder {
+	builder.lockWait = timeout
+	return builder
+}
+
+// OpenIfLockedReadOnly makes BuildOrError() open the store read-only if another process holds it open for writing,
+// e.g. for tools inspecting the database of a running service. Write transactions on such a store fail.
+func (builder *Builder) OpenIfLockedReadOnly() *Builder {
+	builder.openIfLockedReadOnly = true
+	return builder
+}
+
+// LockInfo returns the process holding the store open for writing as recorded in the store directory; this process
+// unless the store was opened using Builder.OpenIfLockedReadOnly(). Returns nil if there's no record, e.g. for
+// in-memory stores.
+func (ob *ObjectBox) LockInfo() (*LockInfo, error) {
+	if err := ob.checkOpen(); err != nil {
+		return nil, err
+	}
+	if isInMemoryDirectory(ob.directory) {
+		return nil, nil
+	}
+	return readLockInfo(ob.directory)
+}
+
+// openLocked handles the failure to open the store: if it's locked, waits for the lock to be released, then tries to
+// open read-only, depending on the builder options. The lock info file only serves to report the lock holder.
+func (builder *Builder) openLocked(err error) (*ObjectBox, error) {
+	var directory = builder.getDirectory()
+	if isInMemoryDirectory(directory) || !isLockError(err, directory) {
+		return nil, err
+	}
+
+	for deadline := time.Now().Add(builder.lockWait); time.Now().Before(deadline); {
+		var pause = time.Until(deadline)
+		if pause > lockRetryInterval {
+			pause = lockRetryInterval
+		}
+		time.Sleep(pause)
+
+		ob, retryErr := builder.openRetry(false)
+		if retryErr == nil {
+			return ob, nil
+		}
+		err = retryErr
+
+		// the lock was released but opening failed for a different reason
+		if !isLockError(err, directory) {
+			return nil, err
+		}
+	}
+
+	if builder.openIfLockedReadOnly {
+		if ob, readOnlyErr := builder.openRetry(true); readOnlyErr == nil {
+			return ob, nil
+		}
+	}
+	return nil, &StoreLockedError{Directory: directory, Holder: readLockHolder(directory), Err: err}
+}
+
+// openRetry opens the store with a new native model because the previous attempt has consumed the builder's one.
+func (builder *Builder) openRetry(readOnly bool) (*ObjectBox, error) {
+	var model = builder.model
+	if builder.modelVersion == nil {
+		model = model.snapshot().toModel()
+		if model.Error != nil {
+			return nil, model.Error
+		}
+	}
+	return builder.open(model, readOnly)
+}
+
+// readLockHolder returns the running process recorded as holding the store, if any
+func readLockHolder(directory string) *LockInfo {
+	if holder, _ := readLockInfo(directory); holder != nil && !holder.isStale() {
+		return holder
+	}
+	return nil
+}
+
+func readLockInfo(directory string) (*LockInfo, error) {
+	data, err := ioutil.ReadFile(filepath.Join(directory, lockInfoFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var info LockInfo
+	if err = json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("can't read %s: %s", lockInfoFile, err)
+	}
+	return &info, nil
+}
+
+// writeLockInfo records this process as the lock holder; returns false if that failed (the store is usable anyway).
+func writeLockInfo(directory string, now time.Time) bool {
+	var info = LockInfo{PID: os.Getpid(), Since: now.UTC()}
+	info.Host, _ = os.Hostname()
+	info.Executable, _ = os.Executable()
+
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(directory, lockInfoFile), data, 0644)
+	}
+	if err != nil {
+		logError("can't record the store lock holder", err)
+		return false
+	}
+	return true
+}
+
+func removeLockInfo(directory string) {
+	if err := os.Remove(filepath.Join(directory, lockInfoFile)); err != nil && !os.IsNotExist(err) {
+		logError("can't remove the store lock info", err)
+	}
+}
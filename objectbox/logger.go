@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+
+// forwards the native log output to the Go Logger, see SetLogger()
+extern void cLogCallbackDispatch(int level, char* message, size_t size, void* userData);
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Logger receives the log output of ObjectBox, see SetLogger(). The methods take a message and alternating key/value
+// pairs (attributes), the same as the methods of *slog.Logger from the standard library, which implements this
+// interface; other structured loggers can be adapted easily.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// loggerHolder allows storing a nil logger in the atomic.Value
+type loggerHolder struct {
+	logger Logger
+}
+
+var currentLogger atomic.Value
+
+var cLogCallbackDispatchPtr = (*C.obx_log_callback)(unsafe.Pointer(C.cLogCallbackDispatch))
+
+// SetLogger routes the log output of all stores to the given logger instead of stdout/stderr; pass nil to restore
+// the default output. This covers:
+//   - the log events of the native library (e.g. with debug flags set by ObjectBox.SetDebugFlags()), with the level
+//     mapped to Debug, Info, Warn or Error; note that the native library still writes its default log output as well,
+//   - slow queries and transactions reported by Builder.SlowLog() without a custom function (as Warn),
+//   - errors of asynchronous operations of AsyncBoxes without an error listener (see AsyncBox.SetErrorListener()),
+//   - model changes detected when opening a store (as Info, see ObjectBox.SchemaDiff()),
+//   - errors of finalizers and other failures which can't be returned to the caller.
+//
+// Messages start with "objectbox: " and details are passed as attributes, e.g. "entity", "duration" or "error".
+// The logger is called synchronously, possibly from threads of the native library, so it must be safe for concurrent
+// use. Native log events are only forwarded for stores opened after the first call.
+func SetLogger(logger Logger) {
+	currentLogger.Store(loggerHolder{logger})
+}
+
+func getLogger() Logger {
+	holder, _ := currentLogger.Load().(loggerHolder)
+	return holder.logger
+}
+
+// coreLogged is called by cLogCallbackDispatch for each log event of the native library
+func coreLogged(level int, message string) {
+	var logger = getLogger()
+	if logger == nil {
+		return
+	}
+	switch {
+	case level >= C.OBXLogLevel_Error:
+		logger.Error("objectbox: " + message)
+	case level >= C.OBXLogLevel_Warn:
+		logger.Warn("objectbox: " + message)
+	case level >= C.OBXLogLevel_Info:
+		logger.Info("objectbox: " + message)
+	default:
+		logger.Debug("objectbox: " + message)
+	}
+}
+
+// logError reports an error which can't be returned to the caller to the Logger or, if none is set, prints it
+func logError(message string, err error) {
+	if logger := getLogger(); logger != nil {
+		logger.Error("objectbox: "+message, "error", err)
+	} else {
+		fmt.Printf("objectbox: %s: %s\n", message, err)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import "errors"
+
+// ErrResultBudgetExceeded is returned by reads exceeding the result budget while under memory pressure,
+// see ObjectBox.SetMemoryPressureHook().
+var ErrResultBudgetExceeded = errors.New("result set exceeds the budget configured for memory pressure")
+
+// MemoryPressureFunc reports whether the application is currently under memory pressure.
+// It's called before each large read so it must be fast, e.g. just read a flag set by a platform specific listener.
+type MemoryPressureFunc func() bool
+
+type memoryPressureHook struct {
+	check      MemoryPressureFunc
+	maxObjects uint64
+}
+
+// SetMemoryPressureHook registers a function consulted before large reads, i.e. Box.GetAll() and Query.Find().
+// While it reports memory pressure, load shedding is active:
+//   - objects are read one by one using a streaming visitor instead of collecting all the (native) data at once,
+//   - reads returning more than maxObjects objects fail with ErrResultBudgetExceeded; 0 means no budget.
+//
+// Pass nil to remove the hook.
+func (ob *ObjectBox) SetMemoryPressureHook(fn MemoryPressureFunc, maxObjects uint64) {
+	if fn == nil {
+		ob.memoryPressureHook.Store((*memoryPressureHook)(nil))
+	} else {
+		ob.memoryPressureHook.Store(&memoryPressureHook{check: fn, maxObjects: maxObjects})
+	}
+}
+
+// memoryPressure returns whether load shedding is currently active and if so, the result budget to apply
+func (ob *ObjectBox) memoryPressure() (underPressure bool, maxObjects uint64) {
+	hook, _ := ob.memoryPressureHook.Load().(*memoryPressureHook)
+	if hook == nil || !hook.check() {
+		return false, 0
+	}
+	return true, hook.maxObjects
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"expvar"
+	"sync/atomic"
+	"time"
+)
+
+// MetricsCollector receives instrumentation data from the hot paths of the binding, see SetMetricsCollector().
+// The methods are called synchronously, on the goroutine executing the operation, so they must be fast and
+// safe for concurrent use.
+type MetricsCollector interface {
+	// OperationDone is called after each instrumented operation, e.g. "Box.Put" or "Query.Find".
+	// The err is the result of the operation, i.e. nil on success.
+	OperationDone(operation string, duration time.Duration, err error)
+
+	// TransactionDone is called after each transaction has finished, i.e. both for the ones started by
+	// RunInWriteTx()/RunInReadTx() and the explicit ones (see ObjectBox.BeginTx()).
+	// Committed is false for aborted write transactions and always true for successfully finished read transactions.
+	TransactionDone(readOnly bool, committed bool, duration time.Duration)
+
+	// FlatBuffersBuilderAcquired is called each time a FlatBuffers builder is taken from the pool to serialize an object.
+	// The allocated flag is true if the pool was empty and a new builder had to be created.
+	FlatBuffersBuilderAcquired(allocated bool)
+}
+
+// collectorHolder allows storing a nil collector in the atomic.Value
+type collectorHolder struct {
+	collector MetricsCollector
+}
+
+var currentMetricsCollector atomic.Value
+
+// SetMetricsCollector configures the MetricsCollector used by all stores; pass nil to disable the collection.
+// There's no overhead (apart from a single atomic load per operation) when no collector is configured.
+func SetMetricsCollector(collector MetricsCollector) {
+	currentMetricsCollector.Store(collectorHolder{collector})
+}
+
+func metricsCollector() MetricsCollector {
+	holder, _ := currentMetricsCollector.Load().(collectorHolder)
+	return holder.collector
+}
+
+// observeOperation is meant to be deferred, with the start time evaluated at the time of the defer statement
+func observeOperation(collector MetricsCollector, operation string, start time.Time, err *error) {
+	collector.OperationDone(operation, time.Since(start), *err)
+}
+
+// ExpvarMetricsCollector is a MetricsCollector publishing the collected metrics as an expvar.Map, i.e. available at
+// the "/debug/vars" HTTP endpoint if the expvar handler is installed. The map contains the following counters:
+//   - "<operation>.count", "<operation>.errors" and "<operation>.nanos" (total duration) for each operation,
+//   - "tx.read.count", "tx.write.count", "tx.write.aborted" and "tx.nanos" for transactions,
+//   - "fbb.acquired" and "fbb.allocated" for the FlatBuffers builder pool.
+//
+// To export to a different monitoring system, e.g. Prometheus, implement the MetricsCollector interface instead.
+type ExpvarMetricsCollector struct {
+	vars *expvar.Map
+}
+
+// NewExpvarMetricsCollector creates a collector publishing an expvar.Map with the given name.
+// Note: expvar names must be unique - calling this twice with the same name panics.
+func NewExpvarMetricsCollector(name string) *ExpvarMetricsCollector {
+	return &ExpvarMetricsCollector{vars: expvar.NewMap(name)}
+}
+
+// Vars returns the published map, e.g. to read the values in tests.
+func (c *ExpvarMetricsCollector) Vars() *expvar.Map {
+	return c.vars
+}
+
+// OperationDone implements MetricsCollector
+func (c *ExpvarMetricsCollector) OperationDone(operation string, duration time.Duration, err error) {
+	c.vars.Add(operation+".count", 1)
+	c.vars.Add(operation+".nanos", duration.Nanoseconds())
+	if err != nil {
+		c.vars.Add(operation+".errors", 1)
+	}
+}
+
+// TransactionDone implements MetricsCollector
+func (c *ExpvarMetricsCollector) TransactionDone(readOnly bool, committed bool, duration time.Duration) {
+	if readOnly {
+		c.vars.Add("tx.read.count", 1)
+	} else {
+		c.vars.Add("tx.write.count", 1)
+		if !committed {
+			c.vars.Add("tx.write.aborted", 1)
+		}
+	}
+	c.vars.Add("tx.nanos", duration.Nanoseconds())
+}
+
+// FlatBuffersBuilderAcquired implements MetricsCollector
+func (c *ExpvarMetricsCollector) FlatBuffersBuilderAcquired(allocated bool) {
+	c.vars.Add("fbb.acquired", 1)
+	if allocated {
+		c.vars.Add("fbb.allocated", 1)
+	}
+}
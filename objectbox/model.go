@@ -60,6 +60,14 @@ type ObjectBinding interface {
 	GeneratorVersion() int
 }
 
+// ObjectBindingAppendMany is an optional extension of ObjectBinding. If implemented, bulk reads (e.g. GetAll, Find)
+// append the loaded objects to the result in batches instead of calling AppendToSlice() for each object.
+type ObjectBindingAppendMany interface {
+	// AppendManyToSlice adds the objects (some may be nil) at the end of the slice created by MakeSlice().
+	// Returns the new slice.
+	AppendManyToSlice(slice interface{}, objects []interface{}) (sliceNew interface{})
+}
+
 // Model is used by the generated code to represent information about the ObjectBox database schema
 type Model struct {
 	cModel *C.OBX_model
@@ -79,6 +87,11 @@ type Model struct {
 	lastRelationUid uint64
 
 	generatorVersion int
+
+	// the schema as declared by the generated code; used to report all inconsistencies at once (see validate())
+	// and persisted in the model history (see ObjectBox.ModelHistory())
+	schema   ModelVersion
+	problems []ModelProblem
 }
 
 // NewModel creates a model
@@ -157,6 +170,29 @@ func (model *Model) Entity(name string, id TypeId, uid uint64) {
 		name: name,
 		id:   id,
 	}
+
+	model.schema.Entities = append(model.schema.Entities, &ModelEntityInfo{
+		Name: name,
+		Id:   id,
+		Uid:  uid,
+	})
+}
+
+// currentSchemaEntity returns the entity last declared by Entity()
+func (model *Model) currentSchemaEntity() *ModelEntityInfo {
+	if len(model.schema.Entities) == 0 {
+		return &ModelEntityInfo{} // Entity() not called, RegisterBinding() reports that
+	}
+	return model.schema.Entities[len(model.schema.Entities)-1]
+}
+
+// currentSchemaProperty returns the property last declared by Property()
+func (model *Model) currentSchemaProperty() *ModelPropertyInfo {
+	var e = model.currentSchemaEntity()
+	if len(e.Properties) == 0 {
+		return &ModelPropertyInfo{} // Property() not called, the C-api reports that
+	}
+	return e.Properties[len(e.Properties)-1]
 }
 
 // EntityFlags configures behavior of entities
@@ -167,6 +203,8 @@ func (model *Model) EntityFlags(entityFlags int) {
 	model.Error = cCall(func() C.obx_err {
 		return C.obx_model_entity_flags(model.cModel, C.uint32_t(entityFlags))
 	})
+
+	model.currentSchemaEntity().Flags = entityFlags
 }
 
 // TODO each Entity-related method (e.g. Property, Relation,...) should check whether currentEntity is not nil
@@ -182,6 +220,14 @@ func (model *Model) Relation(relationId TypeId, relationUid uint64, targetEntity
 			C.obx_schema_id(targetEntityId), C.obx_uid(targetEntityUid))
 	})
 
+	var e = model.currentSchemaEntity()
+	e.Relations = append(e.Relations, &ModelRelationInfo{
+		Id:        relationId,
+		Uid:       relationUid,
+		TargetId:  targetEntityId,
+		TargetUid: targetEntityUid,
+	})
+
 	model.currentEntity.hasRelations = true
 }
 
@@ -195,6 +241,8 @@ func (model *Model) EntityLastPropertyId(id TypeId, uid uint64) {
 	model.Error = cCall(func() C.obx_err {
 		return C.obx_model_entity_last_property_id(model.cModel, C.obx_schema_id(id), C.obx_uid(uid))
 	})
+
+	model.currentSchemaEntity().LastPropertyId = ModelIdUid{Id: id, Uid: uid}
 }
 
 // Property creates a property in an Entity
@@ -208,6 +256,14 @@ func (model *Model) Property(name string, propertyType int, id TypeId, uid uint6
 	model.Error = cCall(func() C.obx_err {
 		return C.obx_model_property(model.cModel, cname, C.OBXPropertyType(propertyType), C.obx_schema_id(id), C.obx_uid(uid))
 	})
+
+	var e = model.currentSchemaEntity()
+	e.Properties = append(e.Properties, &ModelPropertyInfo{
+		Name: name,
+		Type: propertyType,
+		Id:   id,
+		Uid:  uid,
+	})
 }
 
 // PropertyFlags configures type and other information about the property
@@ -215,9 +271,30 @@ func (model *Model) PropertyFlags(propertyFlags int) {
 	if model.Error != nil {
 		return
 	}
+
+	// on-conflict replace strategy - Put replaces the existing object instead of failing; only valid on unique props.
+	// Note: the generator (a separate module) doesn't emit it, i.e. there's no `unique:replace` annotation.
+	if propertyFlags&C.OBXPropertyFlags_UNIQUE_ON_CONFLICT_REPLACE != 0 && propertyFlags&C.OBXPropertyFlags_UNIQUE == 0 {
+		model.Error = fmt.Errorf("invalid property flags %d - the unique conflict strategy 'replace' can only be "+
+			"used on a unique property", propertyFlags)
+		return
+	}
+
+	// "id(assignable)" - Put accepts any ID given by the caller, not just those reserved from the ID sequence
+	if propertyFlags&C.OBXPropertyFlags_ID_SELF_ASSIGNABLE != 0 {
+		if propertyFlags&C.OBXPropertyFlags_ID == 0 {
+			model.Error = fmt.Errorf("invalid property flags %d - only the ID property can be self-assignable",
+				propertyFlags)
+			return
+		}
+		model.currentEntity.idSelfAssignable = true
+	}
+
 	model.Error = cCall(func() C.obx_err {
 		return C.obx_model_property_flags(model.cModel, C.uint32_t(propertyFlags))
 	})
+
+	model.currentSchemaProperty().Flags = propertyFlags
 }
 
 // PropertyIndex creates a new index on the property
@@ -228,6 +305,8 @@ func (model *Model) PropertyIndex(id TypeId, uid uint64) {
 	model.Error = cCall(func() C.obx_err {
 		return C.obx_model_property_index_id(model.cModel, C.obx_schema_id(id), C.obx_uid(uid))
 	})
+
+	model.currentSchemaProperty().Index = ModelIdUid{Id: id, Uid: uid}
 }
 
 // PropertyRelation adds a property-based (i.e. to-one) relation
@@ -243,6 +322,10 @@ func (model *Model) PropertyRelation(targetEntityName string, indexId TypeId, in
 		return C.obx_model_property_relation(model.cModel, cname, C.obx_schema_id(indexId), C.obx_uid(indexUid))
 	})
 
+	var p = model.currentSchemaProperty()
+	p.Index = ModelIdUid{Id: indexId, Uid: indexUid}
+	p.RelationTarget = targetEntityName
+
 	model.currentEntity.hasRelations = true
 }
 
@@ -275,20 +358,27 @@ func (model *Model) RegisterBinding(binding ObjectBinding) {
 		return
 	}
 
-	if model.entitiesById[id] != nil {
-		model.Error = fmt.Errorf("duplicate binding - entity id %d is already registered", id)
-		return
+	// the following problems don't prevent further model construction; they're collected and reported by validate()
+	var valid = true
+	if existing := model.entitiesById[id]; existing != nil {
+		model.addProblem(name, "", "duplicate binding - entity ID %d is already registered by %s", id, existing.name)
+		valid = false
 	}
 
 	if model.entitiesByName[name] != nil {
-		model.Error = fmt.Errorf("duplicate binding - entity name %s is already registered", name)
-		return
+		model.addProblem(name, "", "duplicate binding - entity name %s is already registered", name)
+		valid = false
 	}
 
 	var version = binding.GeneratorVersion()
 	if version != gogen.VersionId {
-		model.Error = fmt.Errorf("incompatible generator version %d used to generate the binding %s code "+
-			"- please follow the upgrade procedure described in the README.md", version, name)
+		model.addProblem(name, "", "incompatible generator version used to generate the binding code - "+
+			"expected %d, found %d; please follow the upgrade procedure described in the README.md", gogen.VersionId, version)
+		valid = false
+	}
+
+	if !valid {
+		model.currentEntity = nil
 		return
 	}
 
@@ -305,12 +395,18 @@ func (model *Model) validate() error {
 	}
 
 	if model.generatorVersion != gogen.VersionId {
-		return fmt.Errorf("incompatible generator version %d used to generate the model code "+
-			"- please follow the upgrade procedure described in the README.md", model.generatorVersion)
+		model.addProblem("", "", "incompatible generator version used to generate the model code - expected %d, "+
+			"found %d; please follow the upgrade procedure described in the README.md", gogen.VersionId, model.generatorVersion)
 	}
 
 	if model.lastEntityId == 0 || model.lastEntityUid == 0 {
-		return fmt.Errorf("last entity ID/UID is missing")
+		model.addProblem("", "", "last entity ID/UID is missing")
+	}
+
+	model.checkSchema()
+
+	if len(model.problems) > 0 {
+		return &ModelValidationError{Problems: model.problems}
 	}
 
 	return nil
@@ -0,0 +1,257 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// modelHistoryFile is stored next to the database files in the store directory
+const modelHistoryFile = "model-history.json"
+
+// defaultDirectory is used by the core if Builder.Directory() isn't set
+const defaultDirectory = "objectbox"
+
+// inMemoryPrefix identifies in-memory databases, e.g. "memory:test-db"
+const inMemoryPrefix = "memory:"
+
+// ModelVersion is a snapshot of the model the store was opened with, see ObjectBox.ModelHistory().
+// A new version is recorded each time the store is opened with a model that differs from the previous one.
+type ModelVersion struct {
+	Version        int                `json:"version"`
+	Created        time.Time          `json:"created"`
+	Entities       []*ModelEntityInfo `json:"entities"`
+	LastEntityId   ModelIdUid         `json:"lastEntityId"`
+	LastIndexId    ModelIdUid         `json:"lastIndexId"`
+	LastRelationId ModelIdUid         `json:"lastRelationId"`
+}
+
+// ModelIdUid is an ID/UID pair identifying an element of the model
+type ModelIdUid struct {
+	Id  TypeId `json:"id"`
+	Uid uint64 `json:"uid"`
+}
+
+// ModelEntityInfo describes an entity in a ModelVersion
+type ModelEntityInfo struct {
+	Name           string               `json:"name"`
+	Id             TypeId               `json:"id"`
+	Uid            uint64               `json:"uid"`
+	Flags          int                  `json:"flags,omitempty"`
+	LastPropertyId ModelIdUid           `json:"lastPropertyId"`
+	Properties     []*ModelPropertyInfo `json:"properties"`
+	Relations      []*ModelRelationInfo `json:"relations,omitempty"`
+}
+
+// ModelPropertyInfo describes a property in a ModelVersion
+type ModelPropertyInfo struct {
+	Name           string     `json:"name"`
+	Type           int        `json:"type"`
+	Flags          int        `json:"flags,omitempty"`
+	Id             TypeId     `json:"id"`
+	Uid            uint64     `json:"uid"`
+	Index          ModelIdUid `json:"index"`
+	RelationTarget string     `json:"relationTarget,omitempty"`
+}
+
+// ModelRelationInfo describes a standalone (many-to-many) relation in a ModelVersion
+type ModelRelationInfo struct {
+	Id        TypeId `json:"id"`
+	Uid       uint64 `json:"uid"`
+	TargetId  TypeId `json:"targetId"`
+	TargetUid uint64 `json:"targetUid"`
+}
+
+// ModelHistory returns all model versions this store has been opened with, the oldest first.
+// In-memory databases don't keep a model history so the result is always empty for them.
+func (ob *ObjectBox) ModelHistory() ([]*ModelVersion, error) {
+	if isInMemoryDirectory(ob.directory) {
+		return nil, nil
+	}
+	return readModelHistory(ob.directory)
+}
+
+// CurrentModel describes the model this store has been opened with, e.g. to inspect entities and properties at
+// runtime. The result is shared and must not be modified.
+func (ob *ObjectBox) CurrentModel() *ModelVersion {
+	return ob.schema
+}
+
+// OpenWithModelVersion opens the store read-only using the given version of the model history instead of the model
+// passed to Model(). The bindings of the current model are still used to read the objects.
+//
+// This allows an older binary to safely read the data after a deployment that has upgraded the schema was rolled
+// back: the store keeps the newer model version, which the old model (lower "last" IDs) would otherwise fail to open.
+// See ObjectBox.ModelHistory() for the available versions.
+func (builder *Builder) OpenWithModelVersion(version int) *Builder {
+	builder.modelVersion = &version
+	return builder
+}
+
+func isInMemoryDirectory(directory string) bool {
+	return strings.HasPrefix(directory, inMemoryPrefix)
+}
+
+func readModelHistory(directory string) ([]*ModelVersion, error) {
+	data, err := ioutil.ReadFile(filepath.Join(directory, modelHistoryFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("can't read the model history: %s", err)
+	}
+
+	var history []*ModelVersion
+	if err = json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("can't parse the model history: %s", err)
+	}
+	return history, nil
+}
+
+// recordModelVersion appends the model to the history in the given store directory, unless it's the same as the last one.
+func recordModelVersion(directory string, model *Model, now time.Time) error {
+	history, err := readModelHistory(directory)
+	if err != nil {
+		return err
+	}
+
+	var current = model.snapshot()
+	if len(history) > 0 {
+		var last = history[len(history)-1]
+		current.Version = last.Version
+		current.Created = last.Created
+		if sameJSON, err := jsonEquals(current, last); err != nil {
+			return err
+		} else if sameJSON {
+			return nil
+		}
+		current.Version = last.Version + 1
+	} else {
+		current.Version = 1
+	}
+	current.Created = now.UTC()
+	history = append(history, current)
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a crash doesn't leave a corrupted history behind
+	var path = filepath.Join(directory, modelHistoryFile)
+	if err = ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("can't write the model history: %s", err)
+	}
+	if err = os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("can't write the model history: %s", err)
+	}
+	return nil
+}
+
+func jsonEquals(a, b interface{}) (bool, error) {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aJSON, bJSON), nil
+}
+
+// findModelVersion loads the given version from the model history in the store directory.
+func findModelVersion(directory string, version int) (*ModelVersion, error) {
+	if isInMemoryDirectory(directory) {
+		return nil, fmt.Errorf("in-memory databases don't keep a model history")
+	}
+
+	history, err := readModelHistory(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range history {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("model version %d not found in the model history of %s", version, directory)
+}
+
+// snapshot returns a copy of the schema information collected during the model construction.
+func (model *Model) snapshot() *ModelVersion {
+	var result = model.schema
+	result.LastEntityId = ModelIdUid{Id: model.lastEntityId, Uid: model.lastEntityUid}
+	result.LastIndexId = ModelIdUid{Id: model.lastIndexId, Uid: model.lastIndexUid}
+	result.LastRelationId = ModelIdUid{Id: model.lastRelationId, Uid: model.lastRelationUid}
+	return &result
+}
+
+// schemaProperty finds the given property in the model the store was opened with; nil if not found.
+func (ob *ObjectBox) schemaProperty(entityId, propertyId TypeId) *ModelPropertyInfo {
+	for _, e := range ob.schema.Entities {
+		if e.Id == entityId {
+			for _, p := range e.Properties {
+				if p.Id == propertyId {
+					return p
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// toModel creates a native model (without any bindings) from the persisted model version.
+func (version *ModelVersion) toModel() *Model {
+	var model = NewModel()
+	for _, e := range version.Entities {
+		model.Entity(e.Name, e.Id, e.Uid)
+		if e.Flags != 0 {
+			model.EntityFlags(e.Flags)
+		}
+		for _, p := range e.Properties {
+			model.Property(p.Name, p.Type, p.Id, p.Uid)
+			if p.Flags != 0 {
+				model.PropertyFlags(p.Flags)
+			}
+			if p.RelationTarget != "" {
+				model.PropertyRelation(p.RelationTarget, p.Index.Id, p.Index.Uid)
+			} else if p.Index.Id != 0 {
+				model.PropertyIndex(p.Index.Id, p.Index.Uid)
+			}
+		}
+		model.EntityLastPropertyId(e.LastPropertyId.Id, e.LastPropertyId.Uid)
+		for _, r := range e.Relations {
+			model.Relation(r.Id, r.Uid, r.TargetId, r.TargetUid)
+		}
+	}
+	model.LastEntityId(version.LastEntityId.Id, version.LastEntityId.Uid)
+	if version.LastIndexId.Id != 0 {
+		model.LastIndexId(version.LastIndexId.Id, version.LastIndexId.Uid)
+	}
+	if version.LastRelationId.Id != 0 {
+		model.LastRelationId(version.LastRelationId.Id, version.LastRelationId.Uid)
+	}
+	return model
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MergeModels specifies the schema for the database combined from the models generated in several packages, e.g. of
+// plugins each contributing their own entities: MergeModels(users.ObjectBoxModel(), billing.ObjectBoxModel()).
+// If a model has already been set by Model(), the given models are merged into it.
+//
+// The packages must allocate distinct IDs and UIDs for their entities, indexes and relations, e.g. each using its own
+// ID range (see the -idBase flag of objectbox-gogen), and entity names must be unique; conflicts are reported as a
+// *ModelValidationError. The combined model doesn't depend on the order of the given models. Relations may target
+// entities of other packages. On success, the given models are consumed, i.e. can't be used with another builder.
+func (builder *Builder) MergeModels(models ...*Model) *Builder {
+	if builder.Error != nil {
+		return builder
+	}
+
+	if builder.model != nil {
+		models = append([]*Model{builder.model}, models...)
+	}
+
+	merged, err := mergeModels(models)
+	if err != nil {
+		builder.Error = err
+		builder.model = nil
+		return builder
+	}
+	return builder.Model(merged)
+}
+
+func mergeModels(models []*Model) (*Model, error) {
+	if len(models) == 0 {
+		return nil, errors.New("no models to merge")
+	}
+
+	var conflicts []ModelProblem
+	var conflict = func(entity, property string, format string, args ...interface{}) {
+		conflicts = append(conflicts, ModelProblem{Entity: entity, Property: property, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var version ModelVersion
+	var entityNames = make(map[string]bool)
+	var entityIds = make(map[TypeId]string)
+	var indexIds = make(map[TypeId]string)
+	var relationIds = make(map[TypeId]string)
+	for _, model := range models {
+		if err := model.validate(); err != nil {
+			return nil, err
+		}
+
+		for _, e := range model.schema.Entities {
+			if existing, found := entityIds[e.Id]; found {
+				conflict(e.Name, "", "entity ID %d is already used by %s", e.Id, existing)
+				continue
+			} else if entityNames[e.Name] {
+				conflict(e.Name, "", "entity name %s is already used by another model", e.Name)
+				continue
+			}
+			entityIds[e.Id] = e.Name
+			entityNames[e.Name] = true
+
+			for _, p := range e.Properties {
+				if p.Index.Id == 0 {
+					continue
+				} else if existing, found := indexIds[p.Index.Id]; found {
+					conflict(e.Name, p.Name, "index ID %d is already used by %s", p.Index.Id, existing)
+				} else {
+					indexIds[p.Index.Id] = e.Name + "." + p.Name
+				}
+			}
+
+			for _, r := range e.Relations {
+				if existing, found := relationIds[r.Id]; found {
+					conflict(e.Name, "", "relation ID %d is already used by %s", r.Id, existing)
+				} else {
+					relationIds[r.Id] = e.Name
+				}
+			}
+			version.Entities = append(version.Entities, e)
+		}
+
+		version.LastEntityId = maxIdUid(version.LastEntityId, model.lastEntityId, model.lastEntityUid)
+		version.LastIndexId = maxIdUid(version.LastIndexId, model.lastIndexId, model.lastIndexUid)
+		version.LastRelationId = maxIdUid(version.LastRelationId, model.lastRelationId, model.lastRelationUid)
+	}
+
+	if len(conflicts) > 0 {
+		return nil, &ModelValidationError{Problems: conflicts}
+	}
+
+	sort.Slice(version.Entities, func(i, j int) bool { return version.Entities[i].Id < version.Entities[j].Id })
+
+	var merged = version.toModel()
+	merged.GeneratorVersion(models[0].generatorVersion)
+	for _, model := range models {
+		for id, entity := range model.entitiesById {
+			merged.entitiesById[id] = entity
+			merged.entitiesByName[entity.name] = entity
+		}
+	}
+
+	// checks UIDs are unique across the merged models; the given models are left intact on failure
+	if err := merged.validate(); err != nil {
+		C.obx_model_free(merged.cModel)
+		merged.cModel = nil
+		return nil, err
+	}
+
+	for _, model := range models {
+		// the native model is only consumed when opening a store; the merged one is used instead
+		C.obx_model_free(model.cModel)
+		model.cModel = nil
+		model.Error = errors.New("the model has been merged into another one by MergeModels()")
+	}
+	return merged, nil
+}
+
+// maxIdUid returns the ID/UID pair with the higher ID
+func maxIdUid(current ModelIdUid, id TypeId, uid uint64) ModelIdUid {
+	if id > current.Id {
+		return ModelIdUid{Id: id, Uid: uid}
+	}
+	return current
+}
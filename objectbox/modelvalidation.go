@@ -0,0 +1,135 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelProblem describes a single inconsistency found while validating the model
+type ModelProblem struct {
+	// Entity is the name of the affected entity; empty for model-wide problems
+	Entity string
+
+	// Property is the name of the affected property; empty for entity-level problems
+	Property string
+
+	// Message describes the problem, including the expected and the found IDs/UIDs where applicable
+	Message string
+}
+
+func (problem ModelProblem) String() string {
+	if problem.Property != "" {
+		return fmt.Sprintf("%s.%s: %s", problem.Entity, problem.Property, problem.Message)
+	} else if problem.Entity != "" {
+		return fmt.Sprintf("%s: %s", problem.Entity, problem.Message)
+	}
+	return problem.Message
+}
+
+// ModelValidationError is returned by Builder.Model() and Builder.BuildOrError() if the model is inconsistent.
+// It lists all problems found in the model at once instead of just the first one.
+type ModelValidationError struct {
+	Problems []ModelProblem
+}
+
+func (err *ModelValidationError) Error() string {
+	var lines = make([]string, len(err.Problems))
+	for i, problem := range err.Problems {
+		lines[i] = problem.String()
+	}
+	return fmt.Sprintf("invalid model - %d problem(s) found:\n  %s", len(err.Problems), strings.Join(lines, "\n  "))
+}
+
+func (model *Model) addProblem(entity, property string, format string, args ...interface{}) {
+	model.problems = append(model.problems, ModelProblem{
+		Entity:   entity,
+		Property: property,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkSchema verifies declared IDs/UIDs against the "last ID" declarations and checks UIDs are unique.
+func (model *Model) checkSchema() {
+	// each UID must be unique within the whole model, remember the first element using it
+	var uids = make(map[uint64]string)
+	var checkUid = func(entity, property string, uid uint64, what string) {
+		if uid == 0 {
+			model.addProblem(entity, property, "%s UID is missing", what)
+			return
+		}
+		var owner = entity
+		if property != "" {
+			owner = entity + "." + property
+		}
+		if existing, found := uids[uid]; found {
+			model.addProblem(entity, property, "%s UID %d is already used by %s", what, uid, existing)
+		} else {
+			uids[uid] = owner
+		}
+	}
+
+	var checkLast = func(entity, property string, id TypeId, uid uint64, lastId TypeId, lastUid uint64, what string) {
+		if id > lastId {
+			model.addProblem(entity, property, "%s ID %d is higher than the last %s ID - expected at most %d, found %d",
+				what, id, what, lastId, id)
+		} else if id == lastId && uid != lastUid {
+			model.addProblem(entity, property, "%s ID %d is declared as the last %s ID but with a different UID - "+
+				"expected %d, found %d", what, id, what, lastUid, uid)
+		}
+	}
+
+	for _, e := range model.schema.Entities {
+		checkUid(e.Name, "", e.Uid, "entity")
+		checkLast(e.Name, "", e.Id, e.Uid, model.lastEntityId, model.lastEntityUid, "entity")
+
+		if e.LastPropertyId.Id == 0 {
+			model.addProblem(e.Name, "", "last property ID/UID is missing")
+		}
+
+		var propertyIds = make(map[TypeId]string)
+		for _, p := range e.Properties {
+			if existing, found := propertyIds[p.Id]; found {
+				model.addProblem(e.Name, p.Name, "property ID %d is already used by %s", p.Id, existing)
+			} else {
+				propertyIds[p.Id] = p.Name
+			}
+			checkUid(e.Name, p.Name, p.Uid, "property")
+			if e.LastPropertyId.Id != 0 {
+				checkLast(e.Name, p.Name, p.Id, p.Uid, e.LastPropertyId.Id, e.LastPropertyId.Uid, "property")
+			}
+		}
+	}
+
+	// indexes and relations are checked after all entities so the problems are grouped by kind
+	for _, e := range model.schema.Entities {
+		for _, p := range e.Properties {
+			if p.Index.Id != 0 {
+				checkUid(e.Name, p.Name, p.Index.Uid, "index")
+				checkLast(e.Name, p.Name, p.Index.Id, p.Index.Uid, model.lastIndexId, model.lastIndexUid, "index")
+			}
+		}
+	}
+
+	for _, e := range model.schema.Entities {
+		for _, r := range e.Relations {
+			checkUid(e.Name, "", r.Uid, "relation")
+			checkLast(e.Name, "", r.Id, r.Uid, model.lastRelationId, model.lastRelationUid, "relation")
+		}
+	}
+}
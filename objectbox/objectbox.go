@@ -26,9 +26,10 @@ import "C"
 import (
 	"errors"
 	"fmt"
-	"runtime"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -64,6 +65,20 @@ const (
 	cPutModePutIdGuaranteedToBeNew = 4
 )
 
+// PutMode defines how objects are written by operations accepting it, e.g. Box.ImportJSON()
+type PutMode int
+
+const (
+	// PutModePut inserts new objects and updates existing ones, like Box.Put()
+	PutModePut PutMode = cPutModePut
+
+	// PutModeInsert fails if an object already exists, like Box.Insert()
+	PutModeInsert PutMode = cPutModeInsert
+
+	// PutModeUpdate fails if an object doesn't exist, like Box.Update()
+	PutModeUpdate PutMode = cPutModeUpdate
+)
+
 // atomic boolean true & false
 const aTrue = 1
 const aFalse = 0
@@ -73,31 +88,158 @@ type TypeId uint32
 
 // ObjectBox provides super-fast object storage
 type ObjectBox struct {
-	store          *C.OBX_store
+	store          *C.OBX_store // nil once closed, protected by storeMutex
+	storeMutex     sync.RWMutex
+	directory      string
 	entitiesById   map[TypeId]*entity
 	entitiesByName map[string]*entity
 	boxes          map[TypeId]*Box
+	boxHandles     map[TypeId]*boxHandles // native handles of the boxes by entity, protected by boxesMutex
 	boxesMutex     sync.Mutex
+	asyncBoxes     map[*AsyncBox]bool // created by NewAsyncBox() and not closed yet, protected by boxesMutex
+	boxCaches      map[*boxCache]bool // enabled by Box.WithCache(), protected by boxesMutex
 	options        options
 	syncClient     *SyncClient
+
+	// the model (as passed to the builder) describing the entities and properties
+	schema *ModelVersion
+
+	// differences to the model the store was previously opened with, see SchemaDiff()
+	schemaChanges []SchemaChange
+
+	// effective size limits the store was opened with, see Stats()
+	maxSizeInKb     uint64
+	maxDataSizeInKb uint64
+
+	// *memoryPressureHook, see SetMemoryPressureHook()
+	memoryPressureHook atomic.Value
+
+	// only set if enabled by Builder.DiagnosticsOnCorruption()
+	diagnostics *diagnostics
+
+	// only set if enabled by Builder.ChangeLog()
+	changeLog *changeLog
+
+	// only set if enabled by Builder.RecordContention()
+	contention *contentionRecorder
+
+	// see Box.CachedQuery()
+	queryCache *queryCache
+
+	// native queries closed before the store
+	queries nativeQueries
+
+	// the native store if close() was called while transactions or other operations were still active; it's only
+	// closed once they've finished (by their own threads, see Tx.finished() and leave()), protected by storeMutex
+	closedStore *C.OBX_store
+
+	// native handles released by close() and cleared together with the native store, protected by storeMutex
+	closedBoxHandles []*boxHandles
+	closedAsyncBoxes []*AsyncBox
+
+	// see WithIdentityMap()
+	identityMaps identityMaps
+
+	// quotas set up by Builder.BoxQuota()
+	quotas []*boxQuota
+
+	// whether this store has written the lock info file in its directory, see LockInfo()
+	lockOwner bool
+
+	// see SubscribeEvents()
+	events eventBus
+
+	// number of transactions currently active, accessed atomically; reads aren't batched while non-zero
+	activeTxCount int32
+
+	// number of operations outside of transactions currently active (see enter()), accessed atomically
+	activeOpCount int32
+
+	// set by CloseWithTimeout(), accessed atomically; no new transactions are started while non-zero
+	closing int32
+
+	// set when opened using GetOrOpen(), protected by the registry mutex
+	registryKey string
+	refCount    int
 }
 
 type options struct {
-	asyncTimeout uint
+	asyncTimeout time.Duration // zero to use the native default
+	batchWindow  time.Duration
+	queryTracer  *queryTracer // see Builder.TraceQueries()
+	slowLog      *slowLog     // see Builder.SlowLog()
+
+	diagnosticsDirectory string // see Builder.DiagnosticsOnCorruption()
+	changeLogCapacity    int    // see Builder.ChangeLog()
+	contentionBlockers   int    // see Builder.RecordContention()
+	queryCacheSize       int    // see Builder.QueryCacheSize()
+
+	clock func() time.Time // see Builder.Clock()
 }
 
 // constant during runtime so no need to call this each time it's necessary
 var supportsResultArray = bool(C.obx_has_feature(C.OBXFeature_ResultArray))
 
-// Close fully closes the database and frees resources
+// Close fully closes the database and frees resources.
+// For stores obtained by GetOrOpen(), this only releases a single reference; the database is closed once the last
+// reference is released.
+//
+// Boxes, AsyncBoxes (including those created by NewAsyncBox() and not closed yet), queries and transactions of a
+// closed store are invalidated: using them afterwards fails with ErrStoreClosed; queries are closed together with the
+// store. Transactions still running keep the native store open until they're finished, committing them fails with
+// ErrStoreClosed. The same goes for box and query operations running concurrently with Close(), which either finish or
+// fail with an error.
 func (ob *ObjectBox) Close() {
+	if !ob.releaseRegistered() {
+		return
+	}
+	ob.close()
+}
+
+// close releases the store regardless of the references registered by GetOrOpen()
+func (ob *ObjectBox) close() {
+	ob.unregisterDiagnostics()
+
+	ob.storeMutex.Lock()
 	storeToClose := ob.store
 	ob.store = nil
+	ob.storeMutex.Unlock()
 	if ob.syncClient != nil {
 		_ = ob.syncClient.Close()
 	}
+	ob.boxesMutex.Lock()
+	// the handles are cleared together with the native store, once operations still running have finished
+	var closedBoxHandles = make([]*boxHandles, 0, len(ob.boxHandles))
+	for _, handles := range ob.boxHandles {
+		closedBoxHandles = append(closedBoxHandles, handles)
+	}
+	ob.boxHandles = nil
+	var closedAsyncBoxes = make([]*AsyncBox, 0, len(ob.asyncBoxes))
+	for async := range ob.asyncBoxes {
+		closedAsyncBoxes = append(closedAsyncBoxes, async)
+	}
+	ob.asyncBoxes = nil
+	for cache := range ob.boxCaches {
+		// observers must be closed before the store
+		_ = cache.close()
+		cache.box.cache.Store((*boxCache)(nil))
+	}
+	ob.boxCaches = nil
+	ob.boxesMutex.Unlock()
+	ob.queryCache.close()
 	if storeToClose != nil {
-		C.obx_store_close(storeToClose)
+		ob.storeMutex.Lock()
+		ob.closedStore = storeToClose
+		ob.closedBoxHandles = closedBoxHandles
+		ob.closedAsyncBoxes = closedAsyncBoxes
+		if ob.idleLocked() {
+			ob.closeNativeLocked()
+		}
+		ob.storeMutex.Unlock()
+		if ob.lockOwner {
+			removeLockInfo(ob.directory)
+		}
+		ob.events.emit(StoreEvent{Kind: StoreClosed})
 	}
 }
 
@@ -120,46 +262,31 @@ func (ob *ObjectBox) RunInWriteTx(fn func() error) error {
 	return ob.runInTxn(false, fn)
 }
 
-func (ob *ObjectBox) runInTxn(readOnly bool, fn func() error) (err error) {
-	// NOTE if runtime.LockOSThread() is about to be removed, evaluate use of createError() inside transactions
-	runtime.LockOSThread()
-
-	var cTxn *C.OBX_txn
-	if readOnly {
-		cTxn = C.obx_txn_read(ob.store)
-	} else {
-		cTxn = C.obx_txn_write(ob.store)
-	}
+func (ob *ObjectBox) runInTxn(readOnly bool, fn func() error) error {
+	return ob.runInTx(readOnly, func(*Tx) error { return fn() })
+}
 
-	if cTxn == nil {
-		err = createError()
-		runtime.UnlockOSThread()
+func (ob *ObjectBox) runInTx(readOnly bool, fn func(tx *Tx) error) (err error) {
+	tx, err := ob.beginTx(readOnly)
+	if err != nil {
 		return err
 	}
 
 	// Defer to ensure a TX is ALWAYS closed, even in a panic
 	defer func() {
-		if cTxn != nil {
-			if rc := C.obx_txn_close(cTxn); rc != 0 {
-				if err == nil {
-					err = createError()
-				} else {
-					err = fmt.Errorf("%s; %s", err, createError())
-				}
+		if closeErr := tx.Abort(); closeErr != nil {
+			if err == nil {
+				err = closeErr
+			} else {
+				err = fmt.Errorf("%s; %s", err, closeErr)
 			}
 		}
-
-		runtime.UnlockOSThread()
 	}()
 
-	err = fn()
+	err = fn(tx)
 
 	if !readOnly && err == nil {
-		var ptr = cTxn
-		cTxn = nil
-		if rc := C.obx_txn_success(ptr); rc != 0 {
-			err = createError()
-		}
+		err = tx.Commit()
 	}
 
 	return err
@@ -186,8 +313,13 @@ func (ob *ObjectBox) getEntityByName(name string) *entity {
 // SetDebugFlags configures debug logging of the ObjectBox core.
 // See DebugFlags* constants
 func (ob *ObjectBox) SetDebugFlags(flags uint) error {
+	cStore, err := ob.enterStore()
+	if err != nil {
+		return err
+	}
+	defer ob.leave()
 	return cCall(func() C.obx_err {
-		return C.obx_store_debug_flags(ob.store, C.uint32_t(flags))
+		return C.obx_store_debug_flags(cStore, C.uint32_t(flags))
 	})
 }
 
@@ -200,15 +332,30 @@ func (ob *ObjectBox) InternalBox(entityId TypeId) *Box {
 	return box
 }
 
+// BoxOrError is like InternalBox() but returns an error instead of panicking, e.g. if the entity is not registered.
+// Useful for frameworks resolving entity IDs dynamically.
+func (ob *ObjectBox) BoxOrError(entityId TypeId) (*Box, error) {
+	return ob.box(entityId)
+}
+
 // Gets an Entity Box which provides CRUD access to objects of the given type
 func (ob *ObjectBox) box(entityId TypeId) (*Box, error) {
 	ob.boxesMutex.Lock()
 	defer ob.boxesMutex.Unlock()
 
+	return ob.boxLocked(entityId)
+}
+
+// boxLocked returns a cached box or creates a new one; requires boxesMutex to be held
+func (ob *ObjectBox) boxLocked(entityId TypeId) (*Box, error) {
 	if box := ob.boxes[entityId]; box != nil {
 		return box, nil
 	}
 
+	if ob.entitiesById[entityId] == nil {
+		return nil, fmt.Errorf("no entity registered for entity ID %d", entityId)
+	}
+
 	box, err := newBox(ob, entityId)
 	if err != nil {
 		return nil, err
@@ -218,10 +365,46 @@ func (ob *ObjectBox) box(entityId TypeId) (*Box, error) {
 	return box, nil
 }
 
+// PreallocateBoxes creates and caches boxes for the given entities ahead of time; all registered entities if no IDs
+// are given. This moves the (small) cost of the first box access to the initialization of your app.
+func (ob *ObjectBox) PreallocateBoxes(entityIds ...TypeId) error {
+	ob.boxesMutex.Lock()
+	defer ob.boxesMutex.Unlock()
+
+	if len(entityIds) == 0 {
+		for id := range ob.entitiesById {
+			entityIds = append(entityIds, id)
+		}
+	}
+
+	for _, id := range entityIds {
+		if _, err := ob.boxLocked(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearBoxCache drops all cached boxes; they are recreated on their next access.
+// Boxes obtained before this call stay valid until the store is closed. Note: this does not affect any data stored in
+// the database.
+func (ob *ObjectBox) ClearBoxCache() {
+	ob.boxesMutex.Lock()
+	defer ob.boxesMutex.Unlock()
+
+	// the dropped boxes may still be in use; they share the native handles with the recreated ones, see boxHandles
+	ob.boxes = make(map[TypeId]*Box, len(ob.entitiesById))
+}
+
 // AwaitAsyncCompletion blocks until all PutAsync insert have been processed
 func (ob *ObjectBox) AwaitAsyncCompletion() error {
+	cStore, err := ob.enterStore()
+	if err != nil {
+		return err
+	}
+	defer ob.leave()
 	return cCallBool(func() bool {
-		return bool(C.obx_store_await_async_completion(ob.store))
+		return bool(C.obx_store_await_async_completion(cStore))
 	})
 }
 
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package obxerr provides access to the native error codes of errors returned by ObjectBox operations, e.g. to react
+// to a specific error like a unique constraint violation or to correlate an error with the native library logs.
+package obxerr
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Error codes as defined by the native library (OBX_NOT_FOUND, OBX_ERROR_* in objectbox.h)
+const (
+	NotFound  = 404
+	NoSuccess = 1001
+	Timeout   = 1002
+
+	IllegalState          = 10001
+	IllegalArgument       = 10002
+	Allocation            = 10003
+	NumericOverflow       = 10004
+	FeatureNotAvailable   = 10005
+	ShuttingDown          = 10006
+	IO                    = 10007
+	BackupFileInvalid     = 10008
+	NoErrorInfo           = 10097
+	General               = 10098
+	Unknown               = 10099
+	DbFull                = 10101
+	MaxReadersExceeded    = 10102
+	StoreMustShutdown     = 10103
+	MaxDataSizeExceeded   = 10104
+	DbGeneral             = 10198
+	StorageGeneral        = 10199
+	UniqueViolated        = 10201
+	NonUniqueResult       = 10202
+	PropertyTypeMismatch  = 10203
+	IdAlreadyExists       = 10210
+	IdNotFound            = 10211
+	TimeSeries            = 10212
+	ConstraintViolated    = 10299
+	StdIllegalArgument    = 10301
+	StdOutOfRange         = 10302
+	StdLength             = 10303
+	StdBadAlloc           = 10304
+	StdRange              = 10305
+	StdOverflow           = 10306
+	StdOther              = 10399
+	Schema                = 10501
+	FileCorrupt           = 10502
+	FilePagesCorrupt      = 10503
+	SchemaObjectNotFound  = 10504
+	TreeModelInvalid      = 10601
+	TreeValueTypeMismatch = 10602
+	TreePathNonUnique     = 10603
+	TreePathIllegal       = 10604
+	TreeOther             = 10699
+)
+
+// Error is returned by ObjectBox operations failing in the native library
+type Error struct {
+	Code      int
+	Secondary int // the underlying error code if the native library provides one, e.g. of the OS; otherwise 0
+	Message   string
+}
+
+func (err *Error) Error() string {
+	if Verbosity(atomic.LoadInt32(&verbosity)) == VerbosityCodes {
+		if err.Secondary != 0 {
+			return fmt.Sprintf("%s (error code %d, secondary %d)", err.Message, err.Code, err.Secondary)
+		}
+		return fmt.Sprintf("%s (error code %d)", err.Message, err.Code)
+	}
+	return err.Message
+}
+
+// Verbosity defines what Error.Error() includes, see SetVerbosity()
+type Verbosity int32
+
+const (
+	// VerbosityMessage - only the native error message (the default)
+	VerbosityMessage Verbosity = iota
+
+	// VerbosityCodes - the native error message followed by the error code and the secondary error code (if any)
+	VerbosityCodes
+)
+
+var verbosity int32
+
+// SetVerbosity changes the error messages of all native errors, including those already returned; e.g. to include
+// the error codes in logs.
+func SetVerbosity(v Verbosity) {
+	atomic.StoreInt32(&verbosity, int32(v))
+}
+
+// Code returns the native error code of the given error, or 0 if it isn't (and doesn't wrap) a native error
+func Code(err error) int {
+	if native := find(err); native != nil {
+		return native.Code
+	}
+	return 0
+}
+
+// Secondary returns the secondary native error code of the given error, or 0 if there's none
+func Secondary(err error) int {
+	if native := find(err); native != nil {
+		return native.Secondary
+	}
+	return 0
+}
+
+// find returns the first *Error in the chain of wrapped errors, see errors.Unwrap() in Go 1.13+
+func find(err error) *Error {
+	for err != nil {
+		if native, ok := err.(*Error); ok {
+			return native
+		}
+		wrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return nil
+		}
+		err = wrapper.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package obxerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/test/assert"
+)
+
+type wrapper struct {
+	err error
+}
+
+func (w wrapper) Error() string { return fmt.Sprintf("wrapped: %s", w.err) }
+func (w wrapper) Unwrap() error { return w.err }
+
+func TestCode(t *testing.T) {
+	var err = &Error{Code: UniqueViolated, Message: "Unique constraint violated"}
+	assert.Eq(t, UniqueViolated, Code(err))
+	assert.Eq(t, 0, Secondary(err))
+	assert.Eq(t, UniqueViolated, Code(wrapper{err}))
+	assert.Eq(t, 0, Code(errors.New("not a native error")))
+	assert.Eq(t, 0, Code(nil))
+
+	err = &Error{Code: IO, Secondary: 2, Message: "Could not open file"}
+	assert.Eq(t, 2, Secondary(wrapper{err}))
+}
+
+func TestVerbosity(t *testing.T) {
+	defer SetVerbosity(VerbosityMessage)
+
+	var err = &Error{Code: IO, Secondary: 2, Message: "Could not open file"}
+	assert.Eq(t, "Could not open file", err.Error())
+
+	SetVerbosity(VerbosityCodes)
+	assert.Eq(t, "Could not open file (error code 10007, secondary 2)", err.Error())
+	err.Secondary = 0
+	assert.Eq(t, "Could not open file (error code 10007)", err.Error())
+}
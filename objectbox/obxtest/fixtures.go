@@ -0,0 +1,165 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package obxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/objectbox/objectbox-go/objectbox"
+	"io"
+	"os"
+	"reflect"
+	"sync/atomic"
+)
+
+// TestingT is the subset of testing.TB used by the helpers in this package, so that it doesn't depend on the testing
+// package; pass the *testing.T or *testing.B of your test.
+type TestingT interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+}
+
+// storeCounter makes the names of the in-memory stores unique within the process
+var storeCounter uint64
+
+// NewStore opens a throwaway in-memory store with the given model, e.g. `obxtest.NewStore(t, ObjectBoxModel())`.
+// Each call creates a new, empty database; its data is gone once the store is closed. Fails the test on error.
+func NewStore(t TestingT, model *objectbox.Model) *objectbox.ObjectBox {
+	t.Helper()
+	var directory = fmt.Sprintf("memory:obxtest-%d", atomic.AddUint64(&storeCounter, 1))
+	ob, err := objectbox.NewBuilder().Directory(directory).Model(model).BuildOrError()
+	if err != nil {
+		t.Fatalf("can't open the test store: %s", err)
+		return nil
+	}
+	return ob
+}
+
+// entityBox returns the box of the given entity, failing the test if it isn't registered
+func entityBox(t TestingT, ob *objectbox.ObjectBox, entityId objectbox.TypeId) *objectbox.Box {
+	t.Helper()
+	box, err := ob.BoxOrError(entityId)
+	if err != nil {
+		t.Fatalf("%s", err)
+		return nil
+	}
+	return box
+}
+
+// Put puts the given objects, a slice of the entity type (e.g. []*Event{...}), and returns their IDs.
+// Fails the test on error.
+func Put(t TestingT, ob *objectbox.ObjectBox, entityId objectbox.TypeId, objects interface{}) []uint64 {
+	t.Helper()
+	var box = entityBox(t, ob, entityId)
+	if box == nil {
+		return nil
+	}
+	ids, err := box.PutMany(objects)
+	if err != nil {
+		t.Fatalf("can't put the fixture objects: %s", err)
+	}
+	return ids
+}
+
+// PutJSON puts the objects read from a JSON array, decoded using the standard encoding/json unmarshalling of the
+// entity struct, i.e. the same format as written by Box.ExportJSON(). Fails the test on error.
+func PutJSON(t TestingT, ob *objectbox.ObjectBox, entityId objectbox.TypeId, r io.Reader) {
+	t.Helper()
+	if box := entityBox(t, ob, entityId); box == nil {
+		return
+	} else if err := box.ImportJSON(r, objectbox.PutModePut); err != nil {
+		t.Fatalf("can't put the JSON fixture: %s", err)
+	}
+}
+
+// PutJSONFile is like PutJSON, reading the objects from the given file, e.g. "testdata/events.json".
+func PutJSONFile(t TestingT, ob *objectbox.ObjectBox, entityId objectbox.TypeId, path string) {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("can't open the JSON fixture: %s", err)
+		return
+	}
+	defer file.Close()
+	PutJSON(t, ob, entityId, file)
+}
+
+// AssertCount fails the test unless the number of objects matching the conditions (all objects if none are given)
+// equals the expected count.
+func AssertCount(t TestingT, ob *objectbox.ObjectBox, entityId objectbox.TypeId, expected uint64,
+	conditions ...objectbox.Condition) {
+	t.Helper()
+	var box = entityBox(t, ob, entityId)
+	if box == nil {
+		return
+	}
+	query, err := box.QueryOrError(conditions...)
+	if err != nil {
+		t.Fatalf("can't create the query: %s", err)
+		return
+	}
+	defer query.Close()
+
+	count, err := query.Count()
+	if err != nil {
+		t.Fatalf("can't count the objects: %s", err)
+	} else if count != expected {
+		t.Fatalf("expected %d objects, found %d", expected, count)
+	}
+}
+
+// AssertObjects fails the test unless the objects stored in the box, ordered by their ID, deeply equal the expected
+// ones, a slice of the same type as returned by GetAll() (e.g. []*Event). IDs are compared too, so set them on the
+// expected objects, e.g. to the ones returned by Put(). The differing objects are reported as JSON.
+func AssertObjects(t TestingT, ob *objectbox.ObjectBox, entityId objectbox.TypeId, expected interface{}) {
+	t.Helper()
+	var box = entityBox(t, ob, entityId)
+	if box == nil {
+		return
+	}
+	actual, err := box.GetAll()
+	if err != nil {
+		t.Fatalf("can't read the objects: %s", err)
+		return
+	}
+
+	var expectedValue = reflect.ValueOf(expected)
+	var actualValue = reflect.ValueOf(actual)
+	if expectedValue.Kind() != reflect.Slice || expectedValue.Type() != actualValue.Type() {
+		t.Fatalf("expected objects must be of type %s, got %T", actualValue.Type(), expected)
+		return
+	}
+	if expectedValue.Len() != actualValue.Len() {
+		t.Fatalf("expected %d objects, found %d", expectedValue.Len(), actualValue.Len())
+		return
+	}
+	for i := 0; i < actualValue.Len(); i++ {
+		var e, a = expectedValue.Index(i).Interface(), actualValue.Index(i).Interface()
+		if !reflect.DeepEqual(e, a) {
+			t.Fatalf("object %d differs:\nexpected: %s\nactual:   %s", i, asJSON(e), asJSON(a))
+			return
+		}
+	}
+}
+
+func asJSON(object interface{}) string {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return fmt.Sprintf("%+v", object)
+	}
+	return string(data)
+}
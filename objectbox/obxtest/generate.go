@@ -0,0 +1,416 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package obxtest provides utilities to set up ObjectBox databases for tests, benchmarks and demos.
+package obxtest
+
+import (
+	"fmt"
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/objectbox/fbutils"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// property types and flags as defined in objectbox.h
+const (
+	typeBool         = 1
+	typeByte         = 2
+	typeShort        = 3
+	typeChar         = 4
+	typeInt          = 5
+	typeLong         = 6
+	typeFloat        = 7
+	typeDouble       = 8
+	typeString       = 9
+	typeDate         = 10
+	typeRelation     = 11
+	typeDateNano     = 12
+	typeByteVector   = 23
+	typeStringVector = 30
+
+	flagId       = 1
+	flagUnique   = 32
+	flagVirtual  = 1024
+	flagUnsigned = 8192
+)
+
+// generateBatchSize is the number of objects put in a single transaction
+const generateBatchSize = 10000
+
+// Rule customizes the values generated for a single property. The zero value generates random values suitable for
+// the property type.
+type Rule struct {
+	// Min and Max limit numeric values, i.e. Min <= value < Max, in the stored unit (e.g. milliseconds for dates).
+	// Ignored if both are zero.
+	Min, Max float64
+
+	// Length of generated strings (words) and byte vectors; defaults to 8 and 16, respectively.
+	Length int
+
+	// Values, if not empty, are picked from at random instead of generating values.
+	Values []interface{}
+
+	// Func, if set, returns the value of the object with the given index (0 to n-1), overriding all other settings.
+	Func func(random *rand.Rand, index int) interface{}
+}
+
+// Rules configure Generate()
+type Rules struct {
+	// Seed of the random generator, making the generated data reproducible; a time-based seed is used if zero.
+	Seed int64
+
+	// Properties maps property names to the rule generating their values.
+	Properties map[string]Rule
+}
+
+// Generate fabricates n random objects of the given entity, based on the model the store has been opened with, and
+// puts them, returning their IDs. This allows to quickly set up performance tests and demos against any schema.
+//
+// The values respect the model: unique strings get a unique suffix, unique integers are sequential (starting after
+// the number of objects already present), relations point to random existing objects of the target entity (zero if
+// there are none). Dates fall into the last year unless limited by a Rule. Vectors other than strings and bytes
+// are left empty. Values supplied by Rule.Values or Rule.Func must be convertible to the stored type, e.g. int64 for
+// dates, and are the caller's responsibility regarding uniqueness.
+func Generate(ob *objectbox.ObjectBox, entityId objectbox.TypeId, n int, rules Rules) ([]uint64, error) {
+	box, err := ob.BoxOrError(entityId)
+	if err != nil {
+		return nil, err
+	}
+
+	var g = &generator{ob: ob, rules: rules}
+	if err = g.init(entityId); err != nil {
+		return nil, err
+	}
+
+	if g.existing, err = box.Count(); err != nil {
+		return nil, err
+	}
+
+	var ids = make([]uint64, 0, n)
+	for start := 0; start < n; start += generateBatchSize {
+		var end = start + generateBatchSize
+		if end > n {
+			end = n
+		}
+		err = ob.RunInWriteTx(func() error {
+			for i := start; i < end; i++ {
+				data, err := g.object(i)
+				if err != nil {
+					return err
+				}
+				object, err := box.Load(data)
+				if err != nil {
+					return err
+				}
+				id, err := box.Put(object)
+				if err != nil {
+					return err
+				}
+				ids = append(ids, id)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+type generator struct {
+	ob       *objectbox.ObjectBox
+	rules    Rules
+	random   *rand.Rand
+	entity   *objectbox.ModelEntityInfo
+	existing uint64
+	now      time.Time
+
+	// relation property ID => IDs of the target objects
+	targetIds map[objectbox.TypeId][]uint64
+}
+
+func (g *generator) init(entityId objectbox.TypeId) error {
+	var model = g.ob.CurrentModel()
+	for _, entity := range model.Entities {
+		if entity.Id == entityId {
+			g.entity = entity
+		}
+	}
+	if g.entity == nil {
+		return fmt.Errorf("entity %d not found in the model", entityId)
+	}
+
+	for name := range g.rules.Properties {
+		if findProperty(g.entity, name) == nil {
+			return fmt.Errorf("rule for an unknown property %s.%s", g.entity.Name, name)
+		}
+	}
+
+	var seed = g.rules.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g.random = rand.New(rand.NewSource(seed))
+	g.now = g.ob.Now()
+
+	g.targetIds = make(map[objectbox.TypeId][]uint64)
+	for _, property := range g.entity.Properties {
+		if property.Type != typeRelation {
+			continue
+		}
+		for _, target := range model.Entities {
+			if target.Name != property.RelationTarget {
+				continue
+			}
+			targetBox, err := g.ob.BoxOrError(target.Id)
+			if err != nil {
+				return err
+			}
+			if g.targetIds[property.Id], err = targetBox.Query().FindIds(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func findProperty(entity *objectbox.ModelEntityInfo, name string) *objectbox.ModelPropertyInfo {
+	for _, property := range entity.Properties {
+		if property.Name == name {
+			return property
+		}
+	}
+	return nil
+}
+
+// object builds the FlatBuffers data of the object with the given index
+func (g *generator) object(index int) ([]byte, error) {
+	var fbb = flatbuffers.NewBuilder(256)
+
+	// FlatBuffers requires strings and vectors to be created before starting the table
+	var values = make(map[objectbox.TypeId]interface{}, len(g.entity.Properties))
+	var offsets = make(map[objectbox.TypeId]flatbuffers.UOffsetT)
+	for _, property := range g.entity.Properties {
+		if property.Flags&(flagId|flagVirtual) != 0 {
+			continue
+		}
+		value, err := g.value(property, index)
+		if err != nil {
+			return nil, fmt.Errorf("can't generate %s.%s: %s", g.entity.Name, property.Name, err)
+		}
+		switch v := value.(type) {
+		case nil:
+		case string:
+			offsets[property.Id] = fbutils.CreateStringOffset(fbb, v)
+		case []byte:
+			offsets[property.Id] = fbutils.CreateByteVectorOffset(fbb, v)
+		case []string:
+			offsets[property.Id] = fbutils.CreateStringVectorOffset(fbb, v)
+		default:
+			values[property.Id] = v
+		}
+	}
+
+	fbb.StartObject(int(g.entity.LastPropertyId.Id))
+	for _, property := range g.entity.Properties {
+		var slot = int(property.Id) - 1
+		if offset, found := offsets[property.Id]; found {
+			fbutils.SetUOffsetTSlot(fbb, slot, offset)
+			continue
+		}
+		value, found := values[property.Id]
+		if !found {
+			continue
+		}
+		var unsigned = property.Flags&flagUnsigned != 0
+		switch property.Type {
+		case typeBool:
+			fbutils.SetBoolSlot(fbb, slot, value.(bool))
+		case typeByte:
+			if unsigned {
+				fbutils.SetUint8Slot(fbb, slot, uint8(value.(int64)))
+			} else {
+				fbutils.SetInt8Slot(fbb, slot, int8(value.(int64)))
+			}
+		case typeShort, typeChar:
+			if unsigned {
+				fbutils.SetUint16Slot(fbb, slot, uint16(value.(int64)))
+			} else {
+				fbutils.SetInt16Slot(fbb, slot, int16(value.(int64)))
+			}
+		case typeInt:
+			if unsigned {
+				fbutils.SetUint32Slot(fbb, slot, uint32(value.(int64)))
+			} else {
+				fbutils.SetInt32Slot(fbb, slot, int32(value.(int64)))
+			}
+		case typeLong, typeDate, typeDateNano, typeRelation:
+			fbutils.SetInt64Slot(fbb, slot, value.(int64))
+		case typeFloat:
+			fbutils.SetFloat32Slot(fbb, slot, float32(value.(float64)))
+		case typeDouble:
+			fbutils.SetFloat64Slot(fbb, slot, value.(float64))
+		}
+	}
+	fbb.Finish(fbb.EndObject())
+	return fbb.FinishedBytes(), nil
+}
+
+// value returns the value of the given property as bool, int64, float64, string, []byte, []string or nil (not set)
+func (g *generator) value(property *objectbox.ModelPropertyInfo, index int) (interface{}, error) {
+	var rule = g.rules.Properties[property.Name]
+	if rule.Func != nil {
+		return normalize(property, rule.Func(g.random, index))
+	}
+	if len(rule.Values) > 0 {
+		return normalize(property, rule.Values[g.random.Intn(len(rule.Values))])
+	}
+
+	var unique = property.Flags&flagUnique != 0
+	switch property.Type {
+	case typeBool:
+		return g.random.Intn(2) == 1, nil
+	case typeByte, typeShort, typeChar, typeInt, typeLong:
+		var min, max = rule.Min, rule.Max
+		if min == 0 && max == 0 {
+			min, max = 0, 1000
+		}
+		if unique {
+			return int64(min) + int64(g.existing) + int64(index), nil
+		}
+		return randomInt64(g.random, min, max), nil
+	case typeFloat, typeDouble:
+		var min, max = rule.Min, rule.Max
+		if min == 0 && max == 0 {
+			min, max = 0, 1000
+		}
+		return min + g.random.Float64()*(max-min), nil
+	case typeDate, typeDateNano:
+		var unit = time.Millisecond
+		if property.Type == typeDateNano {
+			unit = time.Nanosecond
+		}
+		var min, max = rule.Min, rule.Max
+		if min == 0 && max == 0 {
+			max = float64(g.now.UnixNano() / int64(unit))
+			min = max - float64(365*24*time.Hour/unit)
+		}
+		return randomInt64(g.random, min, max), nil
+	case typeRelation:
+		var ids = g.targetIds[property.Id]
+		if len(ids) == 0 {
+			return int64(0), nil
+		}
+		return int64(ids[g.random.Intn(len(ids))]), nil
+	case typeString:
+		var text = g.word(rule.Length)
+		if strings.Contains(strings.ToLower(property.Name), "email") {
+			text += "@example.com"
+		}
+		if unique {
+			text = strconv.FormatUint(g.random.Uint64(), 36) + "-" + strconv.Itoa(index) + "-" + text
+		}
+		return text, nil
+	case typeByteVector:
+		var length = rule.Length
+		if length == 0 {
+			length = 16
+		}
+		var data = make([]byte, length)
+		g.random.Read(data)
+		return data, nil
+	case typeStringVector:
+		return []string{g.word(rule.Length), g.word(rule.Length), g.word(rule.Length)}, nil
+	}
+	return nil, nil
+}
+
+// randomInt64 returns a random value in [min, max), or min if the range is empty
+func randomInt64(random *rand.Rand, min, max float64) int64 {
+	if max <= min {
+		return int64(min)
+	}
+	return int64(min) + random.Int63n(int64(max-min))
+}
+
+var syllables = []string{"ka", "lo", "mi", "ne", "ru", "ta", "sen", "vo", "dar", "el", "is", "on", "ba", "ti", "quo"}
+
+// word returns a pronounceable random word of about the given length
+func (g *generator) word(length int) string {
+	if length <= 0 {
+		length = 8
+	}
+	var builder strings.Builder
+	for builder.Len() < length {
+		builder.WriteString(syllables[g.random.Intn(len(syllables))])
+	}
+	return builder.String()[:length]
+}
+
+// normalize converts a value given by a Rule to the type used by generator.object() for the property
+func normalize(property *objectbox.ModelPropertyInfo, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	var v = reflect.ValueOf(value)
+	switch property.Type {
+	case typeBool:
+		if v.Kind() == reflect.Bool {
+			return v.Bool(), nil
+		}
+	case typeByte, typeShort, typeChar, typeInt, typeLong, typeDate, typeDateNano, typeRelation:
+		if t, isTime := value.(time.Time); isTime {
+			if property.Type == typeDateNano {
+				return t.UnixNano(), nil
+			}
+			return t.UnixNano() / int64(time.Millisecond), nil
+		}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return v.Int(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return int64(v.Uint()), nil
+		}
+	case typeFloat, typeDouble:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return v.Float(), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(v.Int()), nil
+		}
+	case typeString:
+		if v.Kind() == reflect.String {
+			return v.String(), nil
+		}
+	case typeByteVector:
+		if bytes, ok := value.([]byte); ok {
+			return bytes, nil
+		}
+	case typeStringVector:
+		if strs, ok := value.([]string); ok {
+			return strs, nil
+		}
+	default:
+		return nil, fmt.Errorf("values of this property type aren't supported")
+	}
+	return nil, fmt.Errorf("value %v of type %T doesn't match the property type", value, value)
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/flatbuffers/go"
+	"reflect"
+)
+
+// ErrConcurrentModification is returned by Put() and Update() of an entity with a version property (see
+// ObjectBox.SetVersionProperty()) if the object has been changed in the database since it was read.
+var ErrConcurrentModification = errors.New("the object has been modified concurrently; read it again and retry")
+
+// versionProperty holds the optimistic locking configuration of an entity, see ObjectBox.SetVersionProperty()
+type versionProperty struct {
+	property projectedProperty
+	field    []int // index of the struct field, see reflect.Value.FieldByIndex()
+}
+
+// SetVersionProperty enables optimistic locking for the entity of the given integer property: Put() and Update()
+// compare the version of the object with the stored one inside the write transaction, fail with
+// ErrConcurrentModification if they differ, and increment the version on success (also on the given object), e.g. new
+// objects are stored with version 1. For example, given
+//
+//	type Order struct {
+//		Id      uint64
+//		Version uint64
+//	}
+//
+// enable it right after opening the store, before using the entity: ob.SetVersionProperty(Order_.Version).
+// Note: optimistic locking is only available at runtime, enabled by this call; the generator (a separate module)
+// doesn't recognize an `objectbox:"version"` annotation.
+// Asynchronous puts (AsyncBox.Put(), PutMany(), ...) of such an entity aren't supported as they can't be checked.
+// If a PutMany() fails, the versions of the objects processed before the failure are restored as the transaction is
+// rolled back, i.e. the same objects can be put again.
+func (ob *ObjectBox) SetVersionProperty(property Property) error {
+	var entity = ob.entitiesById[property.entityId()]
+	if entity == nil {
+		return fmt.Errorf("entity %d not found", property.entityId())
+	}
+
+	var info = ob.schemaProperty(entity.id, property.propertyId())
+	if info == nil {
+		return fmt.Errorf("property %d not found in entity %s", property.propertyId(), entity.name)
+	}
+	switch info.Type {
+	case C.OBXPropertyType_Byte, C.OBXPropertyType_Short, C.OBXPropertyType_Int, C.OBXPropertyType_Long:
+	default:
+		return fmt.Errorf("property %s.%s of type %s can't hold a version, only integer properties can",
+			entity.name, info.Name, propertyTypeName(info.Type))
+	}
+
+	field, err := entity.field(info)
+	if err != nil {
+		return err
+	}
+	switch field.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return fmt.Errorf("field %s of %s must be an integer to hold a version, is %s", field.Name, entity.name,
+			field.Type)
+	}
+
+	entity.version = &versionProperty{property: newProjectedProperty(info), field: field.Index}
+	return nil
+}
+
+// checkVersion verifies the version of the object matches the stored one and increments it on the object.
+// Must be called inside a write transaction; returns a function restoring the original version on failure.
+func (box *Box) checkVersion(object interface{}, id uint64, isNew bool) (restore func(), err error) {
+	var version = box.entity.version
+	field, err := settableField(object, version.field)
+	if err != nil {
+		return nil, err
+	}
+
+	var current uint64
+	if field.Kind() >= reflect.Uint && field.Kind() <= reflect.Uint64 {
+		current = field.Uint()
+	} else {
+		current = uint64(field.Int())
+	}
+
+	if !isNew {
+		var stored uint64
+		found, err := box.GetBytes(id, func(bytes []byte) error {
+			var table = &flatbuffers.Table{
+				Bytes: bytes,
+				Pos:   flatbuffers.GetUOffsetT(bytes),
+			}
+			number, _ := integerValue(version.property.decode(table))
+			stored = uint64(number)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if found && stored != current {
+			return nil, ErrConcurrentModification
+		}
+	}
+
+	var setVersion = func(v uint64) {
+		if field.Kind() >= reflect.Uint && field.Kind() <= reflect.Uint64 {
+			field.SetUint(v)
+		} else {
+			field.SetInt(int64(v))
+		}
+	}
+	setVersion(current + 1)
+	return func() { setVersion(current) }, nil
+}
+
+// restoreVersions calls the functions returned by checkVersion() for objects of a rolled back transaction
+func restoreVersions(restores []func()) {
+	for i := len(restores) - 1; i >= 0; i-- {
+		restores[i]()
+	}
+}
@@ -0,0 +1,244 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+	"unsafe"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-generator/cmd/objectbox-gogen"
+)
+
+// InMemory configures an in-memory database identified by the given name instead of a directory; this is the same as
+// calling Directory() with the "memory:" prefix. The data is never written to disk and is lost once the store is
+// closed, which makes in-memory databases a good fit for tests. Use ObjectBox.PersistTo() to keep a snapshot.
+func (builder *Builder) InMemory(name string) *Builder {
+	return builder.Directory(inMemoryPrefix + name)
+}
+
+// IsInMemory returns true if this store is an in-memory database, see Builder.InMemory()
+func (ob *ObjectBox) IsInMemory() bool {
+	return isInMemoryDirectory(ob.directory)
+}
+
+// PersistTo writes a snapshot of all objects and relations of this store to a new database in the given directory,
+// e.g. to keep the state of an in-memory database used by tests as a fixture. The snapshot is consistent, it's read
+// in a single read transaction, and it can be opened using the same model as this store.
+//
+// The database is written to a temporary directory next to path first and moved into place once complete, so path
+// never contains a partially written snapshot. An existing database in path is replaced; to prevent accidental data
+// loss, any other existing directory that isn't empty is rejected.
+func (ob *ObjectBox) PersistTo(path string) (err error) {
+	if path == "" || isInMemoryDirectory(path) {
+		return fmt.Errorf("invalid snapshot directory %q", path)
+	}
+	if path == ob.directory {
+		return errors.New("can't persist the store to its own directory")
+	}
+	if err = checkReplaceableDirectory(path); err != nil {
+		return err
+	}
+
+	var parent = filepath.Dir(path)
+	if err = os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	tmpDir, err := ioutil.TempDir(parent, filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+
+	// the snapshot is opened with the schema of this store; bindings aren't necessary to copy the raw data
+	var model = ob.schema.withAssignableIds().toModel()
+	model.GeneratorVersion(gogen.VersionId)
+	snapshot, err := NewBuilder().Directory(tmpDir).Model(model).BuildOrError()
+	if err != nil {
+		return err
+	}
+
+	err = ob.RunInReadTx(func() error {
+		return snapshot.RunInWriteTx(func() error {
+			return ob.copyTo(snapshot)
+		})
+	})
+	snapshot.Close()
+	if err != nil {
+		return err
+	}
+
+	return replaceDirectory(tmpDir, path)
+}
+
+// withAssignableIds returns a copy of the schema with all ID properties marked as self-assignable, allowing to put
+// objects under their original IDs to a new store. The flag isn't persisted with the data: a store written this way
+// can be opened with the original model; the ID sequence continues after the highest stored ID.
+func (version *ModelVersion) withAssignableIds() *ModelVersion {
+	var result = *version
+	result.Entities = make([]*ModelEntityInfo, len(version.Entities))
+	for i, e := range version.Entities {
+		var entity = *e
+		entity.Properties = make([]*ModelPropertyInfo, len(e.Properties))
+		for j, p := range e.Properties {
+			var property = *p
+			if property.Flags&C.OBXPropertyFlags_ID != 0 {
+				property.Flags |= C.OBXPropertyFlags_ID_SELF_ASSIGNABLE
+			}
+			entity.Properties[j] = &property
+		}
+		result.Entities[i] = &entity
+	}
+	return &result
+}
+
+// copyTo copies all objects and standalone relations to the given store with the same schema; must be called inside
+// a read transaction of this store and a write transaction of the target store (on the same thread).
+// Objects keep their IDs so the target schema must have self-assignable IDs, see withAssignableIds().
+func (ob *ObjectBox) copyTo(target *ObjectBox) error {
+	for _, entity := range ob.schema.Entities {
+		source, err := ob.box(entity.Id)
+		if err != nil {
+			return err
+		}
+		destination, err := target.box(entity.Id)
+		if err != nil {
+			return err
+		}
+
+		var idProperty *ModelPropertyInfo
+		for _, property := range entity.Properties {
+			if property.Flags&C.OBXPropertyFlags_ID != 0 {
+				idProperty = property
+			}
+		}
+		if idProperty == nil {
+			return fmt.Errorf("entity %s has no ID property", entity.Name)
+		}
+
+		ids, err := source.copyRawObjectsTo(destination, idProperty.Id)
+		if err != nil {
+			return fmt.Errorf("can't copy objects of entity %s: %s", entity.Name, err)
+		}
+
+		for _, rel := range entity.Relations {
+			var relation = &RelationToMany{Id: rel.Id, Source: &Entity{Id: entity.Id}, Target: &Entity{Id: rel.TargetId}}
+			if err := source.copyRelationsTo(destination, relation, ids); err != nil {
+				return fmt.Errorf("can't copy relation %d of entity %s: %s", rel.Id, entity.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// copyRawObjectsTo puts the stored data of all objects to the target box without decoding them; returns their IDs
+func (box *Box) copyRawObjectsTo(target *Box, idPropertyId TypeId) (ids []uint64, err error) {
+	// the ID is read directly from the FlatBuffers table, see the generated Load() functions for the slot layout
+	var idSlot = flatbuffers.VOffsetT(4 + 2*(idPropertyId-1))
+
+	var putErr error
+	visitor, err := dataVisitorRegister(func(bytes []byte) bool {
+		var table = &flatbuffers.Table{Bytes: bytes, Pos: flatbuffers.GetUOffsetT(bytes)}
+		id, err := target.idForPut(table.GetUint64Slot(idSlot, 0))
+		if err == nil {
+			ids = append(ids, id)
+			err = cCall(func() C.obx_err {
+				return C.obx_box_put(target.cBox, C.obx_id(id), cBytesPtr(bytes), C.size_t(len(bytes)))
+			})
+		}
+		putErr = err
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer dataVisitorUnregister(visitor)
+
+	if err := cCall(func() C.obx_err {
+		return C.obx_box_visit_all(box.cBox, dataVisitor, unsafe.Pointer(&visitor))
+	}); err != nil {
+		return nil, err
+	}
+	return ids, putErr
+}
+
+// copyRelationsTo creates the standalone relations of the given source objects in the target box
+func (box *Box) copyRelationsTo(target *Box, relation *RelationToMany, sourceIds []uint64) error {
+	for _, sourceId := range sourceIds {
+		targetIds, err := box.RelationIds(relation, sourceId)
+		if err != nil {
+			return err
+		}
+		for _, targetId := range targetIds {
+			if err := target.RelationPut(relation, sourceId, targetId); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkReplaceableDirectory returns an error if the path exists and is neither an empty directory nor a database
+func checkReplaceableDirectory(path string) error {
+	files, err := ioutil.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(path, "data.mdb")); err != nil {
+		return fmt.Errorf("directory %s already exists and doesn't contain a database", path)
+	}
+	return nil
+}
+
+// replaceDirectory moves src to dst, replacing dst if it exists
+func replaceDirectory(src, dst string) error {
+	if _, err := os.Stat(dst); os.IsNotExist(err) {
+		return os.Rename(src, dst)
+	} else if err != nil {
+		return err
+	}
+
+	var previous = dst + ".old-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := os.Rename(dst, previous); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		_ = os.Rename(previous, dst)
+		return err
+	}
+	return os.RemoveAll(previous)
+}
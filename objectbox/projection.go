@@ -0,0 +1,224 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-go/objectbox/fbutils"
+	"reflect"
+	"runtime"
+	"time"
+	"unsafe"
+)
+
+// Projection holds the values of the properties selected by Query.FindProjected(), in the same order.
+type Projection []interface{}
+
+// projectedProperty describes how to decode a single property directly from FlatBuffers
+type projectedProperty struct {
+	slot         flatbuffers.VOffsetT
+	propertyType int
+	unsigned     bool
+}
+
+func (p projectedProperty) decode(table *flatbuffers.Table) interface{} {
+	switch p.propertyType {
+	case C.OBXPropertyType_Bool:
+		return fbutils.GetBoolSlot(table, p.slot)
+	case C.OBXPropertyType_Byte:
+		if p.unsigned {
+			return fbutils.GetUint8Slot(table, p.slot)
+		}
+		return fbutils.GetInt8Slot(table, p.slot)
+	case C.OBXPropertyType_Short, C.OBXPropertyType_Char:
+		if p.unsigned {
+			return fbutils.GetUint16Slot(table, p.slot)
+		}
+		return fbutils.GetInt16Slot(table, p.slot)
+	case C.OBXPropertyType_Int:
+		if p.unsigned {
+			return fbutils.GetUint32Slot(table, p.slot)
+		}
+		return fbutils.GetInt32Slot(table, p.slot)
+	case C.OBXPropertyType_Long, C.OBXPropertyType_Date, C.OBXPropertyType_DateNano:
+		if p.unsigned {
+			return fbutils.GetUint64Slot(table, p.slot)
+		}
+		return fbutils.GetInt64Slot(table, p.slot)
+	case C.OBXPropertyType_Relation:
+		return fbutils.GetUint64Slot(table, p.slot)
+	case C.OBXPropertyType_Float:
+		return fbutils.GetFloat32Slot(table, p.slot)
+	case C.OBXPropertyType_Double:
+		return fbutils.GetFloat64Slot(table, p.slot)
+	case C.OBXPropertyType_String:
+		return fbutils.GetStringSlot(table, p.slot)
+	case C.OBXPropertyType_ByteVector:
+		return fbutils.GetByteVectorSlot(table, p.slot)
+	case C.OBXPropertyType_StringVector:
+		return fbutils.GetStringVectorSlot(table, p.slot)
+	}
+	return nil // unreachable, checked by Query.projectedProperty()
+}
+
+// FindProjected returns only the values of the given properties of all objects matching the query, decoded directly
+// from the stored data without loading whole objects. This saves CPU and allocations if only a few fields of a large
+// entity are needed.
+//
+// Values are returned as stored, i.e. before applying any converters of the binding: dates as int64 (milliseconds
+// or nanoseconds), integers by their stored size (e.g. int32, or uint32 for unsigned properties), IDs and relations
+// as uint64. Strings and byte vectors are copied, other vectors aren't supported.
+func (query *Query) FindProjected(properties ...Property) (projections []Projection, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.FindProjected", time.Now(), &err)
+	}
+
+	if len(properties) == 0 {
+		return nil, fmt.Errorf("no properties given")
+	}
+
+	var projected = make([]projectedProperty, len(properties))
+	for i, property := range properties {
+		if projected[i], err = query.projectedProperty(property); err != nil {
+			return nil, err
+		}
+	}
+
+	err = query.visitBytes(func(bytes []byte) bool {
+		var table = &flatbuffers.Table{
+			Bytes: bytes,
+			Pos:   flatbuffers.GetUOffsetT(bytes),
+		}
+		var projection = make(Projection, len(projected))
+		for i, p := range projected {
+			projection[i] = p.decode(table)
+		}
+		projections = append(projections, projection)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return projections, nil
+}
+
+func (query *Query) projectedProperty(property Property) (projectedProperty, error) {
+	var entityId = property.entityId()
+	if entityId != query.entity.id {
+		return projectedProperty{}, fmt.Errorf("property from a different entity %d passed, expected %d",
+			entityId, query.entity.id)
+	}
+
+	var info = query.objectBox.schemaProperty(entityId, property.propertyId())
+	if info == nil {
+		return projectedProperty{}, fmt.Errorf("property %d not found in entity %d", property.propertyId(), entityId)
+	}
+
+	switch info.Type {
+	case C.OBXPropertyType_Bool, C.OBXPropertyType_Byte, C.OBXPropertyType_Short, C.OBXPropertyType_Char,
+		C.OBXPropertyType_Int, C.OBXPropertyType_Long, C.OBXPropertyType_Date, C.OBXPropertyType_DateNano,
+		C.OBXPropertyType_Relation, C.OBXPropertyType_Float, C.OBXPropertyType_Double, C.OBXPropertyType_String,
+		C.OBXPropertyType_ByteVector, C.OBXPropertyType_StringVector:
+	default:
+		return projectedProperty{}, fmt.Errorf("property %s of type %s can't be projected",
+			info.Name, propertyTypeName(info.Type))
+	}
+
+	return newProjectedProperty(info), nil
+}
+
+func newProjectedProperty(info *ModelPropertyInfo) projectedProperty {
+	// FlatBuffers fields are indexed by the property ID, starting at the vtable offset 4
+	return projectedProperty{
+		slot:         flatbuffers.VOffsetT(4 + 2*(info.Id-1)),
+		propertyType: info.Type,
+		unsigned:     info.Flags&(C.OBXPropertyFlags_UNSIGNED|C.OBXPropertyFlags_ID) != 0,
+	}
+}
+
+// integerValue converts any integer to int64, keeping the bits of unsigned values (as stored by the database)
+func integerValue(value interface{}) (int64, bool) {
+	var v = reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint()), true
+	}
+	return 0, false
+}
+
+// objectProperty serializes the object and decodes the value of the given property as seen by the application, i.e.
+// before a property codec is applied (see propertyCodec.encodeValue() for the stored value); works with any binding,
+// e.g. to read a property without knowing the object's type
+func (box *Box) objectProperty(object interface{}, id uint64, property projectedProperty) (interface{}, error) {
+	var fbb = acquireFbb()
+	defer releaseFbb(fbb)
+
+	if err := box.entity.binding.Flatten(object, fbb, id); err != nil {
+		return nil, err
+	}
+	fbb.Finish(fbb.EndObject())
+	var bytes = fbb.FinishedBytes()
+	var table = &flatbuffers.Table{
+		Bytes: bytes,
+		Pos:   flatbuffers.GetUOffsetT(bytes),
+	}
+	return property.decode(table), nil
+}
+
+// visitBytes calls fn with the data of each object matching the query (decoded by the PropertyCodec, if any), until it
+// returns false. The data is only valid during the call. Like when loading objects, the FlatBuffers table is checked
+// first and a panic of fn on malformed data is recovered; both make visitBytes fail with a LoadError.
+func (query *Query) visitBytes(fn func(bytes []byte) bool) error {
+	defer runtime.KeepAlive(query)
+
+	if err := query.enter(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
+	var loadErr error
+	visitor, err := dataVisitorRegister(func(bytes []byte) bool {
+		var next bool
+		loadErr = query.entity.safeLoad(bytes, func(bytes []byte) error {
+			next = fn(bytes)
+			return nil
+		})
+		return loadErr == nil && next
+	})
+	if err != nil {
+		return err
+	}
+	defer dataVisitorUnregister(visitor)
+
+	err = query.objectBox.RunInReadTx(func() error {
+		return cCall(func() C.obx_err {
+			return C.obx_query_visit(query.cQuery, dataVisitor, unsafe.Pointer(&visitor))
+		})
+	})
+	if err == nil {
+		err = loadErr
+	}
+	return err
+}
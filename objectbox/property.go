@@ -16,6 +16,11 @@
 
 package objectbox
 
+import (
+	"fmt"
+	"time"
+)
+
 // BaseProperty serves as a common base for all the property types
 type BaseProperty struct {
 	Id     TypeId
@@ -92,6 +97,27 @@ func (property BaseProperty) orderNilAsZero() Condition {
 	}
 }
 
+// Asc orders the results ascending by this property, adjusted by the given flags.
+// Orders are applied by their priority, i.e. the first one given to Box.Query() or Query.Order() is the primary one:
+//
+//	box.Query().Order(Event_.Date.Desc(), Event_.Device.Asc(objectbox.OrderCaseSensitive))
+func (property BaseProperty) Asc(flags ...OrderFlag) Condition {
+	return &orderClosure{
+		apply: func(qb *QueryBuilder) error {
+			return qb.orderWith(&property, false, flags)
+		},
+	}
+}
+
+// Desc orders the results descending by this property, adjusted by the given flags, see Asc()
+func (property BaseProperty) Desc(flags ...OrderFlag) Condition {
+	return &orderClosure{
+		apply: func(qb *QueryBuilder) error {
+			return qb.orderWith(&property, true, flags)
+		},
+	}
+}
+
 // PropertyString holds information about a property and provides query building methods
 type PropertyString struct {
 	*BaseProperty
@@ -231,6 +257,32 @@ func (property PropertyStringVector) Contains(text string, caseSensitive bool) C
 	}
 }
 
+// ContainsAnyElement finds entities with the stored string vector containing at least one of the given values
+func (property PropertyStringVector) ContainsAnyElement(caseSensitive bool, values ...string) Condition {
+	return property.elementsCombination(Any, "ContainsAnyElement", caseSensitive, values)
+}
+
+// ContainsAllElements finds entities with the stored string vector containing all of the given values
+func (property PropertyStringVector) ContainsAllElements(caseSensitive bool, values ...string) Condition {
+	return property.elementsCombination(All, "ContainsAllElements", caseSensitive, values)
+}
+
+func (property PropertyStringVector) elementsCombination(combine func(...Condition) Condition, name string,
+	caseSensitive bool, values []string) Condition {
+	if len(values) == 0 {
+		return &conditionClosure{
+			apply: func(qb *QueryBuilder) (ConditionId, error) {
+				return 0, fmt.Errorf("%s requires at least one value", name)
+			},
+		}
+	}
+	var conditions = make([]Condition, len(values))
+	for i, value := range values {
+		conditions[i] = property.Contains(value, caseSensitive)
+	}
+	return combine(conditions...)
+}
+
 // PropertyInt64 holds information about a property and provides query building methods
 type PropertyInt64 struct {
 	*BaseProperty
@@ -337,6 +389,85 @@ func (property PropertyInt64) OrderNilAsZero() Condition {
 	return property.orderNilAsZero()
 }
 
+// Duration provides query building methods accepting time.Duration values, for properties storing a time.Duration
+// (as int64 nanoseconds), e.g. `Task_.Timeout.Duration().GreaterThan(time.Minute)`.
+func (property PropertyInt64) Duration() PropertyDuration {
+	return PropertyDuration{property.BaseProperty}
+}
+
+// PropertyDuration holds information about a time.Duration property, stored as int64 nanoseconds,
+// and provides query building methods
+type PropertyDuration struct {
+	*BaseProperty
+}
+
+func (property PropertyDuration) int64() PropertyInt64 {
+	return PropertyInt64{property.BaseProperty}
+}
+
+// Equals finds entities with the stored property value equal to the given value
+func (property PropertyDuration) Equals(value time.Duration) Condition {
+	return property.int64().Equals(int64(value))
+}
+
+// NotEquals finds entities with the stored property value different than the given value
+func (property PropertyDuration) NotEquals(value time.Duration) Condition {
+	return property.int64().NotEquals(int64(value))
+}
+
+// GreaterThan finds entities with the stored property value greater than the given value
+func (property PropertyDuration) GreaterThan(value time.Duration) Condition {
+	return property.int64().GreaterThan(int64(value))
+}
+
+// GreaterOrEqual finds entities with the stored property value greater than the given value or they're equal
+func (property PropertyDuration) GreaterOrEqual(value time.Duration) Condition {
+	return property.int64().GreaterOrEqual(int64(value))
+}
+
+// LessThan finds entities with the stored property value less than the given value
+func (property PropertyDuration) LessThan(value time.Duration) Condition {
+	return property.int64().LessThan(int64(value))
+}
+
+// LessOrEqual finds entities with the stored property value less than the given value or they're equal
+func (property PropertyDuration) LessOrEqual(value time.Duration) Condition {
+	return property.int64().LessOrEqual(int64(value))
+}
+
+// Between finds entities with the stored property value between a and b (including a and b)
+func (property PropertyDuration) Between(a, b time.Duration) Condition {
+	return property.int64().Between(int64(a), int64(b))
+}
+
+// In finds entities with the stored property value equal to any of the given values
+func (property PropertyDuration) In(values ...time.Duration) Condition {
+	return property.int64().In(durationsToInt64(values)...)
+}
+
+// NotIn finds entities with the stored property value not equal to any of the given values
+func (property PropertyDuration) NotIn(values ...time.Duration) Condition {
+	return property.int64().NotIn(durationsToInt64(values)...)
+}
+
+// OrderAsc sets ascending order based on this property
+func (property PropertyDuration) OrderAsc() Condition {
+	return property.orderAsc()
+}
+
+// OrderDesc sets descending order based on this property
+func (property PropertyDuration) OrderDesc() Condition {
+	return property.orderDesc()
+}
+
+func durationsToInt64(values []time.Duration) []int64 {
+	var result = make([]int64, len(values))
+	for i, v := range values {
+		result[i] = int64(v)
+	}
+	return result
+}
+
 // PropertyInt holds information about a property and provides query building methods
 type PropertyInt struct {
 	*BaseProperty
@@ -462,7 +593,7 @@ type PropertyUint64 struct {
 func (property PropertyUint64) Equals(value uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntEqual(property.BaseProperty, int64(value))
+			return qb.Uint64Equal(property.BaseProperty, value)
 		},
 	}
 }
@@ -471,7 +602,7 @@ func (property PropertyUint64) Equals(value uint64) Condition {
 func (property PropertyUint64) NotEquals(value uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntNotEqual(property.BaseProperty, int64(value))
+			return qb.Uint64NotEqual(property.BaseProperty, value)
 		},
 	}
 }
@@ -480,7 +611,7 @@ func (property PropertyUint64) NotEquals(value uint64) Condition {
 func (property PropertyUint64) GreaterThan(value uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntGreater(property.BaseProperty, int64(value), false)
+			return qb.Uint64Greater(property.BaseProperty, value, false)
 		},
 	}
 }
@@ -489,7 +620,7 @@ func (property PropertyUint64) GreaterThan(value uint64) Condition {
 func (property PropertyUint64) GreaterOrEqual(value uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntGreater(property.BaseProperty, int64(value), true)
+			return qb.Uint64Greater(property.BaseProperty, value, true)
 		},
 	}
 }
@@ -498,7 +629,7 @@ func (property PropertyUint64) GreaterOrEqual(value uint64) Condition {
 func (property PropertyUint64) LessThan(value uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntLess(property.BaseProperty, int64(value), false)
+			return qb.Uint64Less(property.BaseProperty, value, false)
 		},
 	}
 }
@@ -507,7 +638,7 @@ func (property PropertyUint64) LessThan(value uint64) Condition {
 func (property PropertyUint64) LessOrEqual(value uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntLess(property.BaseProperty, int64(value), true)
+			return qb.Uint64Less(property.BaseProperty, value, true)
 		},
 	}
 }
@@ -516,26 +647,16 @@ func (property PropertyUint64) LessOrEqual(value uint64) Condition {
 func (property PropertyUint64) Between(a, b uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntBetween(property.BaseProperty, int64(a), int64(b))
+			return qb.Uint64Between(property.BaseProperty, a, b)
 		},
 	}
 }
 
-func (property PropertyUint64) int64Slice(values []uint64) []int64 {
-	result := make([]int64, len(values))
-
-	for i, v := range values {
-		result[i] = int64(v)
-	}
-
-	return result
-}
-
 // In finds entities with the stored property value equal to any of the given values
 func (property PropertyUint64) In(values ...uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.Int64In(property.BaseProperty, property.int64Slice(values))
+			return qb.Uint64In(property.BaseProperty, values)
 		},
 	}
 }
@@ -544,7 +665,7 @@ func (property PropertyUint64) In(values ...uint64) Condition {
 func (property PropertyUint64) NotIn(values ...uint64) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.Int64NotIn(property.BaseProperty, property.int64Slice(values))
+			return qb.Uint64NotIn(property.BaseProperty, values)
 		},
 	}
 }
@@ -578,7 +699,7 @@ type PropertyUint struct {
 func (property PropertyUint) Equals(value uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntEqual(property.BaseProperty, int64(value))
+			return qb.Uint64Equal(property.BaseProperty, uint64(value))
 		},
 	}
 }
@@ -587,7 +708,7 @@ func (property PropertyUint) Equals(value uint) Condition {
 func (property PropertyUint) NotEquals(value uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntNotEqual(property.BaseProperty, int64(value))
+			return qb.Uint64NotEqual(property.BaseProperty, uint64(value))
 		},
 	}
 }
@@ -596,7 +717,7 @@ func (property PropertyUint) NotEquals(value uint) Condition {
 func (property PropertyUint) GreaterThan(value uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntGreater(property.BaseProperty, int64(value), false)
+			return qb.Uint64Greater(property.BaseProperty, uint64(value), false)
 		},
 	}
 }
@@ -605,7 +726,7 @@ func (property PropertyUint) GreaterThan(value uint) Condition {
 func (property PropertyUint) GreaterOrEqual(value uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntGreater(property.BaseProperty, int64(value), true)
+			return qb.Uint64Greater(property.BaseProperty, uint64(value), true)
 		},
 	}
 }
@@ -614,7 +735,7 @@ func (property PropertyUint) GreaterOrEqual(value uint) Condition {
 func (property PropertyUint) LessThan(value uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntLess(property.BaseProperty, int64(value), false)
+			return qb.Uint64Less(property.BaseProperty, uint64(value), false)
 		},
 	}
 }
@@ -623,7 +744,7 @@ func (property PropertyUint) LessThan(value uint) Condition {
 func (property PropertyUint) LessOrEqual(value uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntLess(property.BaseProperty, int64(value), true)
+			return qb.Uint64Less(property.BaseProperty, uint64(value), true)
 		},
 	}
 }
@@ -632,16 +753,16 @@ func (property PropertyUint) LessOrEqual(value uint) Condition {
 func (property PropertyUint) Between(a, b uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.IntBetween(property.BaseProperty, int64(a), int64(b))
+			return qb.Uint64Between(property.BaseProperty, uint64(a), uint64(b))
 		},
 	}
 }
 
-func (property PropertyUint) int64Slice(values []uint) []int64 {
-	result := make([]int64, len(values))
+func (property PropertyUint) uint64Slice(values []uint) []uint64 {
+	result := make([]uint64, len(values))
 
 	for i, v := range values {
-		result[i] = int64(v)
+		result[i] = uint64(v)
 	}
 
 	return result
@@ -651,7 +772,7 @@ func (property PropertyUint) int64Slice(values []uint) []int64 {
 func (property PropertyUint) In(values ...uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.Int64In(property.BaseProperty, property.int64Slice(values))
+			return qb.Uint64In(property.BaseProperty, property.uint64Slice(values))
 		},
 	}
 }
@@ -660,7 +781,7 @@ func (property PropertyUint) In(values ...uint) Condition {
 func (property PropertyUint) NotIn(values ...uint) Condition {
 	return &conditionClosure{
 		apply: func(qb *QueryBuilder) (ConditionId, error) {
-			return qb.Int64NotIn(property.BaseProperty, property.int64Slice(values))
+			return qb.Uint64NotIn(property.BaseProperty, property.uint64Slice(values))
 		},
 	}
 }
@@ -1653,6 +1774,18 @@ func (property PropertyByteVector) LessOrEqual(value []byte) Condition {
 	}
 }
 
+// PropertyBoolVector holds information about a property and provides query building methods.
+// Only IsNil() and IsNotNil() conditions are available; the core doesn't support element conditions for this type.
+type PropertyBoolVector struct {
+	*BaseProperty
+}
+
+// PropertyInt16Vector holds information about a property and provides query building methods.
+// Only IsNil() and IsNotNil() conditions are available; the core doesn't support element conditions for this type.
+type PropertyInt16Vector struct {
+	*BaseProperty
+}
+
 // PropertyBool holds information about a property and provides query building methods
 type PropertyBool struct {
 	*BaseProperty
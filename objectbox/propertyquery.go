@@ -22,7 +22,7 @@ package objectbox
 */
 import "C"
 import (
-	"fmt"
+	"errors"
 	"runtime"
 	"sync"
 	"unsafe"
@@ -45,6 +45,10 @@ func newPropertyQuery(query *Query, propertyId TypeId) (*PropertyQuery, error) {
 		return nil, err
 	}
 
+	if err := query.objectBox.queries.addPropQuery(pq.cPropQuery); err != nil {
+		return nil, err
+	}
+
 	runtime.SetFinalizer(pq, propQueryFinalizer)
 	return pq, nil
 }
@@ -57,27 +61,39 @@ func (pq *PropertyQuery) Close() error {
 	defer pq.closeMutex.Unlock()
 
 	if pq.cPropQuery != nil {
-		return cCall(func() C.obx_err {
-			var err = C.obx_query_prop_close(pq.cPropQuery)
-			pq.cPropQuery = nil
-			runtime.SetFinalizer(pq, nil) // remove
-			return err
-		})
+		var err = pq.query.objectBox.queries.closePropQuery(pq.cPropQuery)
+		pq.cPropQuery = nil
+		runtime.SetFinalizer(pq, nil) // remove
+		return err
 	}
 
 	return nil
 }
 
+// enter returns an error if the property query can't be executed anymore; otherwise, the execution is counted as an
+// active operation of the store until pq.query.objectBox.leave() is called, see ObjectBox.enter()
+func (pq *PropertyQuery) enter() error {
+	if pq.cPropQuery == nil {
+		return errors.New("illegal state; property query was closed")
+	}
+	return pq.query.objectBox.enter()
+}
+
 func propQueryFinalizer(pq *PropertyQuery) {
 	err := pq.Close()
 	if err != nil {
-		fmt.Printf("Error in PropertyQuery finalizer: %s", err)
+		logError("error in PropertyQuery finalizer", err)
 	}
 }
 
 // Distinct configures the property query to work only on distinct values.
 // Note: not all methods support distinct, those that don't will return an error.
 func (pq *PropertyQuery) Distinct(value bool) error {
+	if err := pq.enter(); err != nil {
+		return err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cCall(func() C.obx_err {
 		return C.obx_query_prop_distinct(pq.cPropQuery, C.bool(value))
 	})
@@ -86,6 +102,11 @@ func (pq *PropertyQuery) Distinct(value bool) error {
 // DistinctString configures the property query to work only on distinct values.
 // Note: not all methods support distinct, those that don't will return an error.
 func (pq *PropertyQuery) DistinctString(value, caseSensitive bool) error {
+	if err := pq.enter(); err != nil {
+		return err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cCall(func() C.obx_err {
 		return C.obx_query_prop_distinct_case(pq.cPropQuery, C.bool(value), C.bool(caseSensitive))
 	})
@@ -93,6 +114,11 @@ func (pq *PropertyQuery) DistinctString(value, caseSensitive bool) error {
 
 // Count returns a number of non-NULL values of the given property across all objects matching the query.
 func (pq *PropertyQuery) Count() (uint64, error) {
+	if err := pq.enter(); err != nil {
+		return 0, err
+	}
+	defer pq.query.objectBox.leave()
+
 	var cResult C.uint64_t
 	if err := cCall(func() C.obx_err { return C.obx_query_prop_count(pq.cPropQuery, &cResult) }); err != nil {
 		return 0, err
@@ -102,6 +128,11 @@ func (pq *PropertyQuery) Count() (uint64, error) {
 
 // Average returns an average value for the given numeric property across all objects matching the query.
 func (pq *PropertyQuery) Average() (float64, error) {
+	if err := pq.enter(); err != nil {
+		return 0, err
+	}
+	defer pq.query.objectBox.leave()
+
 	var cResult C.double
 	var cCount C.int64_t
 	if err := cCall(func() C.obx_err { return C.obx_query_prop_avg(pq.cPropQuery, &cResult, &cCount) }); err != nil {
@@ -112,6 +143,11 @@ func (pq *PropertyQuery) Average() (float64, error) {
 
 // MinFloat64 finds the minimum value of the given floating-point property across all objects matching the query.
 func (pq *PropertyQuery) MinFloat64() (float64, error) {
+	if err := pq.enter(); err != nil {
+		return 0, err
+	}
+	defer pq.query.objectBox.leave()
+
 	var cResult C.double
 	if err := cCall(func() C.obx_err { return C.obx_query_prop_min(pq.cPropQuery, &cResult, nil) }); err != nil {
 		return 0, err
@@ -121,6 +157,11 @@ func (pq *PropertyQuery) MinFloat64() (float64, error) {
 
 // MaxFloat64 finds the maximum value of the given floating-point property across all objects matching the query.
 func (pq *PropertyQuery) MaxFloat64() (float64, error) {
+	if err := pq.enter(); err != nil {
+		return 0, err
+	}
+	defer pq.query.objectBox.leave()
+
 	var cResult C.double
 	if err := cCall(func() C.obx_err { return C.obx_query_prop_max(pq.cPropQuery, &cResult, nil) }); err != nil {
 		return 0, err
@@ -130,6 +171,11 @@ func (pq *PropertyQuery) MaxFloat64() (float64, error) {
 
 // SumFloat64 calculates the sum of the given floating-point property across all objects matching the query.
 func (pq *PropertyQuery) SumFloat64() (float64, error) {
+	if err := pq.enter(); err != nil {
+		return 0, err
+	}
+	defer pq.query.objectBox.leave()
+
 	var cResult C.double
 	if err := cCall(func() C.obx_err { return C.obx_query_prop_sum(pq.cPropQuery, &cResult, nil) }); err != nil {
 		return 0, err
@@ -139,6 +185,11 @@ func (pq *PropertyQuery) SumFloat64() (float64, error) {
 
 // Min finds the minimum value of the given property across all objects matching the query.
 func (pq *PropertyQuery) Min() (int64, error) {
+	if err := pq.enter(); err != nil {
+		return 0, err
+	}
+	defer pq.query.objectBox.leave()
+
 	var cResult C.int64_t
 	if err := cCall(func() C.obx_err { return C.obx_query_prop_min_int(pq.cPropQuery, &cResult, nil) }); err != nil {
 		return 0, err
@@ -148,6 +199,11 @@ func (pq *PropertyQuery) Min() (int64, error) {
 
 // Max finds the maximum value of the given property across all objects matching the query.
 func (pq *PropertyQuery) Max() (int64, error) {
+	if err := pq.enter(); err != nil {
+		return 0, err
+	}
+	defer pq.query.objectBox.leave()
+
 	var cResult C.int64_t
 	if err := cCall(func() C.obx_err { return C.obx_query_prop_max_int(pq.cPropQuery, &cResult, nil) }); err != nil {
 		return 0, err
@@ -157,6 +213,11 @@ func (pq *PropertyQuery) Max() (int64, error) {
 
 // Sum calculates the sum of the given property across all objects matching the query.
 func (pq *PropertyQuery) Sum() (int64, error) {
+	if err := pq.enter(); err != nil {
+		return 0, err
+	}
+	defer pq.query.objectBox.leave()
+
 	var cResult C.int64_t
 	if err := cCall(func() C.obx_err { return C.obx_query_prop_sum_int(pq.cPropQuery, &cResult, nil) }); err != nil {
 		return 0, err
@@ -168,6 +229,11 @@ func (pq *PropertyQuery) Sum() (int64, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindInts(valueIfNil *int) ([]int, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetInts(func() *C.OBX_int64_array {
 		if valueIfNil == nil {
 			return C.obx_query_prop_find_int64s(pq.cPropQuery, nil)
@@ -182,6 +248,11 @@ func (pq *PropertyQuery) FindInts(valueIfNil *int) ([]int, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindUints(valueIfNil *uint) ([]uint, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetUints(func() *C.OBX_int64_array {
 		if valueIfNil == nil {
 			return C.obx_query_prop_find_int64s(pq.cPropQuery, nil)
@@ -196,6 +267,11 @@ func (pq *PropertyQuery) FindUints(valueIfNil *uint) ([]uint, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindInt64s(valueIfNil *int64) ([]int64, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetInt64s(func() *C.OBX_int64_array {
 		return C.obx_query_prop_find_int64s(pq.cPropQuery, (*C.int64_t)(valueIfNil))
 	})
@@ -205,6 +281,11 @@ func (pq *PropertyQuery) FindInt64s(valueIfNil *int64) ([]int64, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindUint64s(valueIfNil *uint64) ([]uint64, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetUint64s(func() *C.OBX_int64_array {
 		if valueIfNil == nil {
 			return C.obx_query_prop_find_int64s(pq.cPropQuery, nil)
@@ -219,6 +300,11 @@ func (pq *PropertyQuery) FindUint64s(valueIfNil *uint64) ([]uint64, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindInt32s(valueIfNil *int32) ([]int32, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetInt32s(func() *C.OBX_int32_array {
 		return C.obx_query_prop_find_int32s(pq.cPropQuery, (*C.int32_t)(valueIfNil))
 	})
@@ -228,6 +314,11 @@ func (pq *PropertyQuery) FindInt32s(valueIfNil *int32) ([]int32, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindUint32s(valueIfNil *uint32) ([]uint32, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetUint32s(func() *C.OBX_int32_array {
 		if valueIfNil == nil {
 			return C.obx_query_prop_find_int32s(pq.cPropQuery, nil)
@@ -242,6 +333,11 @@ func (pq *PropertyQuery) FindUint32s(valueIfNil *uint32) ([]uint32, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindInt16s(valueIfNil *int16) ([]int16, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetInt16s(func() *C.OBX_int16_array {
 		return C.obx_query_prop_find_int16s(pq.cPropQuery, (*C.int16_t)(valueIfNil))
 	})
@@ -251,6 +347,11 @@ func (pq *PropertyQuery) FindInt16s(valueIfNil *int16) ([]int16, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindUint16s(valueIfNil *uint16) ([]uint16, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetUint16s(func() *C.OBX_int16_array {
 		if valueIfNil == nil {
 			return C.obx_query_prop_find_int16s(pq.cPropQuery, nil)
@@ -265,6 +366,11 @@ func (pq *PropertyQuery) FindUint16s(valueIfNil *uint16) ([]uint16, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindInt8s(valueIfNil *int8) ([]int8, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetInt8s(func() *C.OBX_int8_array {
 		return C.obx_query_prop_find_int8s(pq.cPropQuery, (*C.int8_t)(valueIfNil))
 	})
@@ -274,6 +380,11 @@ func (pq *PropertyQuery) FindInt8s(valueIfNil *int8) ([]int8, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindUint8s(valueIfNil *uint8) ([]uint8, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetUint8s(func() *C.OBX_int8_array {
 		if valueIfNil == nil {
 			return C.obx_query_prop_find_int8s(pq.cPropQuery, nil)
@@ -288,6 +399,11 @@ func (pq *PropertyQuery) FindUint8s(valueIfNil *uint8) ([]uint8, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindFloat64s(valueIfNil *float64) ([]float64, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetFloat64s(func() *C.OBX_double_array {
 		return C.obx_query_prop_find_doubles(pq.cPropQuery, (*C.double)(valueIfNil))
 	})
@@ -297,6 +413,11 @@ func (pq *PropertyQuery) FindFloat64s(valueIfNil *float64) ([]float64, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindFloat32s(valueIfNil *float32) ([]float32, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetFloat32s(func() *C.OBX_float_array {
 		return C.obx_query_prop_find_floats(pq.cPropQuery, (*C.float)(valueIfNil))
 	})
@@ -306,6 +427,11 @@ func (pq *PropertyQuery) FindFloat32s(valueIfNil *float32) ([]float32, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindBools(valueIfNil *bool) ([]bool, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetBools(func() *C.OBX_int8_array {
 		if valueIfNil == nil {
 			return C.obx_query_prop_find_int8s(pq.cPropQuery, nil)
@@ -323,6 +449,11 @@ func (pq *PropertyQuery) FindBools(valueIfNil *bool) ([]bool, error) {
 // Parameter valueIfNil - value that should be returned instead of NULL values on object fields.
 // If `valueIfNil = nil` is given, objects with NULL values of the specified field are skipped.
 func (pq *PropertyQuery) FindStrings(valueIfNil *string) ([]string, error) {
+	if err := pq.enter(); err != nil {
+		return nil, err
+	}
+	defer pq.query.objectBox.leave()
+
 	return cGetStrings(func() *C.OBX_string_array {
 		if valueIfNil == nil {
 			return C.obx_query_prop_find_strings(pq.cPropQuery, nil)
@@ -22,10 +22,13 @@ package objectbox
 */
 import "C"
 import (
+	"context"
 	"errors"
 	"fmt"
 	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -35,6 +38,7 @@ import (
 // 		box.Query(Person_.LastName.HasPrefix("N", false)).Find()
 // Note that Person_ is a struct generated by ObjectBox allowing to conveniently reference properties.
 type Query struct {
+	bytesDecoded    uint64 // accessed atomically, must be 64-bit aligned (first field) on 32-bit platforms
 	entity          *entity
 	objectBox       *ObjectBox
 	box             *Box
@@ -42,7 +46,14 @@ type Query struct {
 	closeMutex      sync.Mutex
 	offsetErr       error
 	limitErr        error
+	offset          uint64 // as set by Offset(), applied by Count() and Remove() on the Go side
+	limit           uint64 // as set by Limit()
 	linkedEntityIds []TypeId
+	resultCountHint uint64
+	eager           []*eagerRelation
+	eagerErr        error
+	conditions      []Condition // as given to Box.Query(), used to rebuild the native query by Order()
+	orderErr        error
 }
 
 // Close frees (native) resources held by this Query.
@@ -52,20 +63,75 @@ func (query *Query) Close() error {
 	defer query.closeMutex.Unlock()
 
 	if query.cQuery != nil {
-		return cCall(func() C.obx_err {
-			var err = C.obx_query_close(query.cQuery)
-			query.cQuery = nil
-			runtime.SetFinalizer(query, nil) // remove the finalizer
-			return err
-		})
+		var err = query.objectBox.queries.closeQuery(query.cQuery)
+		query.cQuery = nil
+		runtime.SetFinalizer(query, nil) // remove the finalizer
+		return err
 	}
 	return nil
 }
 
+// Clone creates an independent copy of the query, including its params, offset and limit, sharing the prepared native
+// query plan; this is much cheaper than building the query again. Use it to serve many goroutines from a single
+// built query, e.g. in an HTTP server: each request clones it, sets its params and closes the clone when done:
+//
+//	var eventsOfDevice = box.Query(Event_.Device.Equals("", true).Alias("device"))
+//
+//	query, err := eventsOfDevice.Clone()
+//	...
+//	defer query.Close()
+//	err = query.SetStringParams(objectbox.Alias("device"), device)
+//
+// Clone may be called concurrently from multiple goroutines, as long as the original query isn't changed meanwhile.
+func (query *Query) Clone() (*Query, error) {
+	query.closeMutex.Lock()
+	defer query.closeMutex.Unlock()
+
+	if err := query.enter(); err != nil {
+		return nil, err
+	}
+	defer query.objectBox.leave()
+
+	var clone = &Query{
+		entity:          query.entity,
+		objectBox:       query.objectBox,
+		box:             query.box,
+		offset:          query.offset,
+		limit:           query.limit,
+		linkedEntityIds: query.linkedEntityIds,
+		resultCountHint: query.resultCountHint,
+		eager:           query.eager,
+		conditions:      query.conditions,
+	}
+	if err := cCallBool(func() bool {
+		clone.cQuery = C.obx_query_clone(query.cQuery)
+		return clone.cQuery != nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := query.objectBox.queries.addQuery(clone.cQuery); err != nil {
+		return nil, err
+	}
+	clone.installFinalizer()
+
+	// make sure the offset and limit apply, regardless of whether the native clone copies them
+	if clone.offset != 0 {
+		clone.Offset(clone.offset)
+	}
+	if clone.limit != 0 {
+		clone.Limit(clone.limit)
+	}
+	if err := clone.check(); err != nil {
+		_ = clone.Close()
+		return nil, err
+	}
+	return clone, nil
+}
+
 func queryFinalizer(query *Query) {
 	err := query.Close()
 	if err != nil {
-		fmt.Printf("Error in Query finalizer: %s", err)
+		logError("error in Query finalizer", err)
 	}
 }
 
@@ -76,13 +142,37 @@ func (query *Query) installFinalizer() {
 	runtime.SetFinalizer(query, queryFinalizer)
 }
 
+// enter is like check() but also counts the execution as an active operation of the store until
+// query.objectBox.leave() is called, see ObjectBox.enter()
+func (query *Query) enter() error {
+	if err := query.check(); err != nil {
+		return err
+	}
+	return query.objectBox.enter()
+}
+
+// enterNative is like enter() but doesn't report errors of previous Offset() and Limit() calls; used by the calls
+// changing the native query
+func (query *Query) enterNative() error {
+	if query.cQuery == nil {
+		return errors.New("illegal state; query was closed")
+	}
+	return query.objectBox.enter()
+}
+
 func (query *Query) check() error {
 	if query.cQuery == nil {
 		return errors.New("illegal state; query was closed")
+	} else if err := query.objectBox.checkOpen(); err != nil {
+		return err
 	} else if query.limitErr != nil {
 		return query.limitErr
 	} else if query.offsetErr != nil {
 		return query.offsetErr
+	} else if query.eagerErr != nil {
+		return query.eagerErr
+	} else if query.orderErr != nil {
+		return query.orderErr
 	}
 
 	return nil
@@ -110,58 +200,315 @@ func (query *Query) PropertyOrError(prop Property) (*PropertyQuery, error) {
 
 // Find returns all objects matching the query
 func (query *Query) Find() (objects interface{}, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.Find", time.Now(), &err)
+	}
+	if query.objectBox.options.observesQueries() {
+		defer func(start time.Time) { query.observed("Query.Find", start, resultLen(objects), err) }(time.Now())
+	}
+
 	defer runtime.KeepAlive(query)
 
-	if err := query.check(); err != nil {
+	if err := query.enter(); err != nil {
 		return nil, err
 	}
+	defer query.objectBox.leave()
 
+	if len(query.eager) > 0 {
+		return query.findEager(query.find)
+	}
+	return query.find()
+}
+
+func (query *Query) find() (objects interface{}, err error) {
 	const existingOnly = true
-	if supportsResultArray {
+	var underPressure, maxObjects = query.objectBox.memoryPressure()
+	if supportsResultArray && !underPressure {
 		var cFn = func() *C.OBX_bytes_array {
 			return C.obx_query_find(query.cQuery)
 		}
-		return query.box.readManyObjects(existingOnly, cFn)
+		return query.box.readManyObjectsWith(existingOnly, cFn, readOptions{decoded: &query.bytesDecoded})
 	}
 
 	var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
 		return C.obx_query_visit(query.cQuery, dataVisitor, visitorArg)
 	}
-	return query.box.readUsingVisitor(existingOnly, cFn)
+	return query.box.readUsingVisitor(existingOnly, cFn, query.readOptionsFor(maxObjects, nil))
+}
+
+// FindWithContext is like Find but stops reading objects once the given context is done, e.g. when the deadline
+// passes or an HTTP request is cancelled, returning ctx.Err(). The context is checked periodically while the objects
+// are being visited so a large scan doesn't need to run to completion.
+func (query *Query) FindWithContext(ctx context.Context) (objects interface{}, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.Find", time.Now(), &err)
+	}
+	if query.objectBox.options.observesQueries() {
+		defer func(start time.Time) { query.observed("Query.Find", start, resultLen(objects), err) }(time.Now())
+	}
+
+	defer runtime.KeepAlive(query)
+
+	if err := query.enter(); err != nil {
+		return nil, err
+	}
+	defer query.objectBox.leave()
+
+	if len(query.eager) > 0 {
+		return query.findEager(func() (interface{}, error) { return query.findWithContext(ctx) })
+	}
+	return query.findWithContext(ctx)
+}
+
+func (query *Query) findWithContext(ctx context.Context) (objects interface{}, err error) {
+	const existingOnly = true
+	var _, maxObjects = query.objectBox.memoryPressure()
+	var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_query_visit(query.cQuery, dataVisitor, visitorArg)
+	}
+	return query.box.readUsingVisitor(existingOnly, cFn, query.readOptionsFor(maxObjects, ctx))
+}
+
+// QueryResult is delivered by Query.FindAsync()
+type QueryResult struct {
+	Objects interface{} // the slice of objects, as returned by Find()
+	Err     error
+}
+
+// FindAsync runs Find on a background goroutine and delivers the result through the returned channel, which receives
+// exactly one QueryResult and is closed afterwards; e.g. to keep a UI responsive while a large query is running.
+// Call cancel to stop reading the objects early (the result then contains context.Canceled) or to release the
+// resources if the result isn't needed anymore; it's safe to call cancel multiple times and after the result arrived.
+//
+// The query must not be used or changed (e.g. by setting params) until the result has been delivered.
+func (query *Query) FindAsync() (result <-chan QueryResult, cancel context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var channel = make(chan QueryResult, 1)
+	go func() {
+		defer cancel()
+		objects, err := query.FindWithContext(ctx)
+		channel <- QueryResult{Objects: objects, Err: err}
+		close(channel)
+	}()
+	return channel, cancel
+}
+
+// visit calls fn for each object matching the query, in a single read transaction, until it returns false
+func (query *Query) visit(fn func(object interface{}) bool) error {
+	defer runtime.KeepAlive(query)
+
+	if err := query.enter(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
+	return query.box.visit(func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_query_visit(query.cQuery, dataVisitor, visitorArg)
+	}, fn)
+}
+
+func (query *Query) readOptionsFor(maxObjects uint64, ctx context.Context) readOptions {
+	var capacity = query.resultCountHint
+	if maxObjects != 0 && maxObjects < capacity {
+		capacity = maxObjects
+	}
+	return readOptions{maxObjects: maxObjects, ctx: ctx, capacity: capacity, decoded: &query.bytesDecoded}
+}
+
+// HintResultCount sets the expected number of objects returned by Find() and FindWithContext(), used to preallocate
+// the resulting slice when the objects are read one by one (e.g. under memory pressure or with a context).
+// This avoids repeated reallocation and copying of large results; there's no need to be exact.
+func (query *Query) HintResultCount(count uint64) *Query {
+	query.resultCountHint = count
+	return query
+}
+
+// BytesDecoded returns the total size of the object data (FlatBuffers) loaded by Find() and FindWithContext() of this
+// query so far. Combined with EstimateSize(), it helps to understand the memory footprint of query results.
+func (query *Query) BytesDecoded() uint64 {
+	return atomic.LoadUint64(&query.bytesDecoded)
 }
 
 // Offset defines the index of the first object to process (how many objects to skip)
 func (query *Query) Offset(offset uint64) *Query {
+	if err := query.enterNative(); err != nil {
+		query.offsetErr = err
+		return query
+	}
+	defer query.objectBox.leave()
+
 	query.offsetErr = cCall(func() C.obx_err { return C.obx_query_offset(query.cQuery, C.size_t(offset)) })
+	if query.offsetErr == nil {
+		query.offset = offset
+	}
 	return query
 }
 
 // Limit sets the number of elements to process by the query
 func (query *Query) Limit(limit uint64) *Query {
+	if err := query.enterNative(); err != nil {
+		query.limitErr = err
+		return query
+	}
+	defer query.objectBox.leave()
+
 	query.limitErr = cCall(func() C.obx_err { return C.obx_query_limit(query.cQuery, C.size_t(limit)) })
+	if query.limitErr == nil {
+		query.limit = limit
+	}
 	return query
 }
 
+// Order sets the order of the results by the given sort keys, created by Asc() and Desc() on properties, replacing the
+// orders given to previous Order() calls. The first one is the primary sort key; the following ones only order objects
+// equal by all preceding keys:
+//
+//	box.Query(Event_.Device.HasPrefix("sensor", true)).Order(Event_.Date.Desc(), Event_.Device.Asc())
+//
+// Orders given to Box.Query() precede the ones given here.
+// Note: the native query is rebuilt so call Order() before setting parameters; the offset and limit are kept.
+func (query *Query) Order(orders ...Condition) *Query {
+	query.orderErr = nil
+	for _, order := range orders {
+		if _, isOrder := order.(*orderClosure); !isOrder {
+			query.orderErr = errors.New("only orders created by Asc(), Desc() or Order*() can be passed to Order()")
+			return query
+		}
+	}
+
+	if err := query.enter(); err != nil {
+		query.orderErr = err
+		return query
+	}
+	defer query.objectBox.leave()
+
+	var conditions = make([]Condition, 0, len(query.conditions)+len(orders))
+	conditions = append(conditions, query.conditions...)
+	// query.conditions already contain the soft-delete condition (if any) so don't use QueryOrError()
+	rebuilt, err := query.box.query(append(conditions, orders...))
+	if err != nil {
+		query.orderErr = err
+		return query
+	}
+
+	// take over the native query of the rebuilt one, keeping the conditions given to Box.Query() for another Order()
+	query.closeMutex.Lock()
+	defer query.closeMutex.Unlock()
+	rebuilt.closeMutex.Lock()
+	defer rebuilt.closeMutex.Unlock()
+
+	var previous = query.cQuery
+	query.cQuery, rebuilt.cQuery = rebuilt.cQuery, nil
+	runtime.SetFinalizer(rebuilt, nil)
+	query.orderErr = query.objectBox.queries.closeQuery(previous)
+
+	// the rebuilt native query has neither an offset nor a limit, apply the ones set on this query
+	query.offsetErr, query.limitErr = nil, nil
+	if query.offset != 0 || query.limit != 0 {
+		query.offsetErr = cCall(func() C.obx_err {
+			return C.obx_query_offset_limit(query.cQuery, C.size_t(query.offset), C.size_t(query.limit))
+		})
+	}
+	return query
+}
+
+// Page returns at most `limit` objects matching the query (0 means no limit), skipping the first `offset` of them,
+// together with the total number of objects matching the query.
+// Both are read in a single read transaction so the total is consistent with the returned page even if the data is
+// being changed concurrently. For a stable ordering across pages, use an order condition, e.g. Entity_.Name.OrderAsc().
+//
+// Note: the offset and limit previously set on the query are ignored by Page and restored when it returns.
+func (query *Query) Page(offset, limit uint64) (objects interface{}, total uint64, err error) {
+	defer runtime.KeepAlive(query)
+
+	if query.cQuery == nil {
+		return nil, 0, errors.New("illegal state; query was closed")
+	}
+
+	defer query.restoreOffsetLimit(query.offset, query.limit)
+
+	err = query.objectBox.RunInReadTx(func() error {
+		// Count() takes the offset and limit into account but the total must not - reset both before counting
+		if total, err = query.Offset(0).Limit(0).Count(); err != nil {
+			return err
+		}
+
+		objects, err = query.Offset(offset).Limit(limit).Find()
+		return err
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+	return objects, total, nil
+}
+
 // FindIds returns IDs of all objects matching the query
-func (query *Query) FindIds() ([]uint64, error) {
+func (query *Query) FindIds() (ids []uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.FindIds", time.Now(), &err)
+	}
+	if query.objectBox.options.observesQueries() {
+		defer func(start time.Time) { query.observed("Query.FindIds", start, uint64(len(ids)), err) }(time.Now())
+	}
+
 	defer runtime.KeepAlive(query)
 
-	if err := query.check(); err != nil {
+	if err := query.enter(); err != nil {
 		return nil, err
 	}
+	defer query.objectBox.leave()
 
 	return cGetIds(func() *C.OBX_id_array {
 		return C.obx_query_find_ids(query.cQuery)
 	})
 }
 
-// Count returns the number of objects matching the query.
-// Currently can't be used in combination with Offset().
-func (query *Query) Count() (uint64, error) {
-	if err := query.check(); err != nil {
+// Count returns the number of objects matching the query, taking the offset and limit into account, e.g. with
+// Offset(10).Limit(5), it's at most 5, and 0 if there are no more than 10 objects matching the query.
+func (query *Query) Count() (count uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.Count", time.Now(), &err)
+	}
+	if query.objectBox.options.observesQueries() {
+		defer func(start time.Time) { query.observed("Query.Count", start, count, err) }(time.Now())
+	}
+
+	if err := query.enter(); err != nil {
 		return 0, err
 	}
+	defer query.objectBox.leave()
 
+	// the native count takes the limit into account
+	if query.offset == 0 {
+		return query.count()
+	}
+
+	// the native count doesn't support an offset, count all and apply the offset and limit to the result
+	if err := cCall(func() C.obx_err { return C.obx_query_offset_limit(query.cQuery, 0, 0) }); err != nil {
+		return 0, err
+	}
+	count, err = query.count()
+	if restoreErr := cCall(func() C.obx_err {
+		return C.obx_query_offset_limit(query.cQuery, C.size_t(query.offset), C.size_t(query.limit))
+	}); err == nil {
+		err = restoreErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if count <= query.offset {
+		return 0, nil
+	}
+	count -= query.offset
+	if query.limit != 0 && count > query.limit {
+		count = query.limit
+	}
+	return count, nil
+}
+
+func (query *Query) count() (uint64, error) {
 	var cResult C.uint64_t
 	if err := cCall(func() C.obx_err { return C.obx_query_count(query.cQuery, &cResult) }); err != nil {
 		return 0, err
@@ -170,15 +517,60 @@ func (query *Query) Count() (uint64, error) {
 	return uint64(cResult), nil
 }
 
-// Remove permanently deletes all objects matching the query from the database.
-// Currently can't be used in combination with Offset() or Limit().
+// Remove permanently deletes all objects matching the query from the database, taking the order, offset and limit
+// into account. E.g. to remove the oldest 100 entries, order by the creation date and set Limit(100).
+// In the soft-delete mode (see ObjectBox.SetSoftDelete()), the objects are only marked as removed, like Box.Remove()
+// does; Box.Purge() deletes them permanently.
 func (query *Query) Remove() (count uint64, err error) {
-	if err := query.check(); err != nil {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.Remove", time.Now(), &err)
+	}
+	if query.objectBox.options.observesQueries() {
+		defer func(start time.Time) { query.observed("Query.Remove", start, count, err) }(time.Now())
+	}
+
+	if err := query.enter(); err != nil {
 		return 0, err
 	}
+	defer query.objectBox.leave()
+
+	if query.entity.softDelete != nil {
+		err = query.objectBox.RunInWriteTx(func() error {
+			ids, err := query.FindIds()
+			if err == nil {
+				count, err = query.box.softRemove(ids)
+			}
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		return count, nil
+	}
+	return query.remove()
+}
+
+// remove deletes the matching objects permanently, regardless of the soft-delete mode; requires enter() to be called
+func (query *Query) remove() (count uint64, err error) {
+	// the native remove doesn't support an offset and a limit and doesn't report the IDs of the removed objects,
+	// which are also necessary to update the size tracked by a quota
+	if query.objectBox.changeLog != nil || query.offset != 0 || query.limit != 0 || query.entity.quota.tracksBytes() {
+		err = query.objectBox.RunInWriteTx(func() error {
+			ids, err := query.FindIds()
+			if err == nil {
+				count, err = query.box.removeIds(ids)
+			}
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		return count, nil
+	}
 
 	var cResult C.uint64_t
-	if err := cCall(func() C.obx_err { return C.obx_query_remove(query.cQuery, &cResult) }); err != nil {
+	err = cCall(func() C.obx_err { return C.obx_query_remove(query.cQuery, &cResult) })
+	if err != nil {
 		return 0, err
 	}
 
@@ -186,11 +578,80 @@ func (query *Query) Remove() (count uint64, err error) {
 	return uint64(cResult), nil
 }
 
+// RemoveChunked removes all objects matching the query in chunks of at most chunkSize objects, each chunk in its own
+// write transaction, and returns the number of removed objects. Use it instead of Remove() when deleting a large
+// number of objects: bounded transactions keep the database size in check and don't block other writers for long.
+// After each chunk, progress (if not nil) is called with the total number of objects removed so far; returning false
+// stops the removal. If an error occurs, chunks removed before stay removed.
+//
+// Note: the offset and limit previously set on the query are ignored by RemoveChunked and restored when it returns.
+func (query *Query) RemoveChunked(chunkSize uint64, progress func(removed uint64) bool) (removed uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.RemoveChunked", time.Now(), &err)
+	}
+
+	defer runtime.KeepAlive(query)
+
+	if chunkSize == 0 {
+		return 0, errors.New("chunk size must be greater than zero")
+	}
+
+	if err := query.enter(); err != nil {
+		return 0, err
+	}
+	defer query.objectBox.leave()
+
+	defer query.restoreOffsetLimit(query.offset, query.limit)
+	query.Offset(0).Limit(chunkSize)
+
+	for {
+		var count uint64
+		err = query.objectBox.RunInWriteTx(func() error {
+			ids, err := query.FindIds()
+			if err == nil {
+				count, err = query.box.RemoveIds(ids...)
+			}
+			return err
+		})
+		removed += count
+		if err != nil {
+			return removed, err
+		}
+
+		if count > 0 && progress != nil && !progress(removed) {
+			return removed, nil
+		}
+		if count < chunkSize {
+			return removed, nil
+		}
+	}
+}
+
+// restoreOffsetLimit sets the offset and limit back to the values saved before Page() or RemoveChunked() changed them
+func (query *Query) restoreOffsetLimit(offset, limit uint64) {
+	query.Offset(offset).Limit(limit)
+}
+
+// Describe returns a human-readable description of the query, without the conditions' parameter values
+func (query *Query) Describe() (string, error) {
+	if err := query.enter(); err != nil {
+		return "", err
+	}
+	defer query.objectBox.leave()
+
+	// no need to free, it's handled by the cQuery internally
+	cResult := C.obx_query_describe(query.cQuery)
+
+	runtime.KeepAlive(query)
+	return C.GoString(cResult), nil
+}
+
 // DescribeParams returns a string representation of the query conditions
 func (query *Query) DescribeParams() (string, error) {
-	if err := query.check(); err != nil {
+	if err := query.enter(); err != nil {
 		return "", err
 	}
+	defer query.objectBox.leave()
 
 	// no need to free, it's handled by the cQuery internally
 	cResult := C.obx_query_describe_params(query.cQuery)
@@ -247,6 +708,11 @@ func (query *Query) SetStringParams(identifier propertyOrAlias, values ...string
 		return err
 	}
 
+	if err := query.enterNative(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
 	if len(values) == 0 {
 		return fmt.Errorf("no values given")
 	}
@@ -280,6 +746,11 @@ func (query *Query) SetStringParamsIn(identifier propertyOrAlias, values ...stri
 		return err
 	}
 
+	if err := query.enterNative(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
 	if len(values) == 0 {
 		return fmt.Errorf("no values given")
 	}
@@ -309,6 +780,11 @@ func (query *Query) SetInt64Params(identifier propertyOrAlias, values ...int64)
 		return err
 	}
 
+	if err := query.enterNative(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
 	if len(values) == 0 {
 		return fmt.Errorf("no values given")
 	}
@@ -347,6 +823,11 @@ func (query *Query) SetInt64ParamsIn(identifier propertyOrAlias, values ...int64
 		return err
 	}
 
+	if err := query.enterNative(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
 	if len(values) == 0 {
 		return fmt.Errorf("no values given")
 	}
@@ -365,6 +846,32 @@ func (query *Query) SetInt64ParamsIn(identifier propertyOrAlias, values ...int64
 	})
 }
 
+// SetUint64Params changes query parameter values on the given property, accepting the full uint64 range if the
+// property is stored as unsigned (e.g. IDs)
+func (query *Query) SetUint64Params(identifier propertyOrAlias, values ...uint64) error {
+	if err := query.checkUint64Params(identifier, values); err != nil {
+		return err
+	}
+	return query.SetInt64Params(identifier, uint64sToInt64s(values)...)
+}
+
+// SetUint64ParamsIn changes query parameter values on the given property, accepting the full uint64 range if the
+// property is stored as unsigned (e.g. IDs)
+func (query *Query) SetUint64ParamsIn(identifier propertyOrAlias, values ...uint64) error {
+	if err := query.checkUint64Params(identifier, values); err != nil {
+		return err
+	}
+	return query.SetInt64ParamsIn(identifier, uint64sToInt64s(values)...)
+}
+
+// checkUint64Params verifies the value range; for aliases, the property isn't known so the native library decides
+func (query *Query) checkUint64Params(identifier propertyOrAlias, values []uint64) error {
+	if identifier.alias() != nil {
+		return nil
+	}
+	return query.objectBox.checkUint64Range(identifier.entityId(), identifier.propertyId(), values)
+}
+
 // SetInt32ParamsIn changes query parameter values on the given property
 func (query *Query) SetInt32ParamsIn(identifier propertyOrAlias, values ...int32) error {
 	defer runtime.KeepAlive(query)
@@ -373,6 +880,11 @@ func (query *Query) SetInt32ParamsIn(identifier propertyOrAlias, values ...int32
 		return err
 	}
 
+	if err := query.enterNative(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
 	if len(values) == 0 {
 		return fmt.Errorf("no values given")
 	}
@@ -399,6 +911,11 @@ func (query *Query) SetFloat64Params(identifier propertyOrAlias, values ...float
 		return err
 	}
 
+	if err := query.enterNative(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
 	if len(values) == 0 {
 		return fmt.Errorf("no values given")
 	}
@@ -438,6 +955,11 @@ func (query *Query) SetBytesParams(identifier propertyOrAlias, values ...[]byte)
 		return err
 	}
 
+	if err := query.enterNative(); err != nil {
+		return err
+	}
+	defer query.objectBox.leave()
+
 	if len(values) == 0 {
 		return fmt.Errorf("no values given")
 
@@ -24,6 +24,7 @@ import "C"
 import (
 	"errors"
 	"fmt"
+	"math"
 	"runtime"
 	"strings"
 	"unsafe"
@@ -37,6 +38,10 @@ type QueryBuilder struct {
 	typeId        TypeId
 	innerBuilders []*QueryBuilder
 	orderFlags    map[TypeId]C.OBXOrderFlags
+	orderSequence []TypeId // properties in the order they were first used to order by, i.e. by their priority
+
+	// whether the conditions are currently created inside Not(), i.e. should be replaced by their opposites
+	negated bool
 
 	// The first error that occurred during a any of the calls on the query builder
 	Err error
@@ -49,8 +54,15 @@ func newQueryBuilder(ob *ObjectBox, typeId TypeId) *QueryBuilder {
 		orderFlags: make(map[TypeId]C.OBXOrderFlags),
 	}
 
+	cStore, err := ob.enterStore()
+	if err != nil {
+		qb.Err = err
+		return qb
+	}
+	defer ob.leave()
+
 	qb.Err = cCallBool(func() bool {
-		qb.cqb = C.obx_query_builder(ob.store, C.obx_schema_id(typeId))
+		qb.cqb = C.obx_query_builder(cStore, C.obx_schema_id(typeId))
 		return qb.cqb != nil
 	})
 
@@ -106,8 +118,8 @@ func (qb *QueryBuilder) Close() error {
 
 // Build is called internally
 func (qb *QueryBuilder) Build(box *Box) (*Query, error) {
-	for propertyId, orderFlags := range qb.orderFlags {
-		qb.order(C.obx_schema_id(propertyId), orderFlags)
+	for _, propertyId := range qb.orderSequence {
+		qb.order(C.obx_schema_id(propertyId), qb.orderFlags[propertyId])
 	}
 
 	if qb.Err != nil {
@@ -128,6 +140,10 @@ func (qb *QueryBuilder) Build(box *Box) (*Query, error) {
 		return nil, err
 	}
 
+	if err := qb.objectBox.queries.addQuery(query.cQuery); err != nil {
+		return nil, err
+	}
+
 	query.installFinalizer()
 
 	// search all inner builders recursively and collect linked entity IDs
@@ -148,7 +164,7 @@ func (qb *QueryBuilder) applyConditions(conditions []Condition) error {
 		return qb.Err
 	}
 
-	if len(conditions) == 1 {
+	if len(conditions) == 1 && conditions[0] != nil {
 		_, qb.Err = conditions[0].applyTo(qb, true)
 	} else if len(conditions) > 1 {
 		_, qb.Err = (&conditionCombination{conditions: conditions}).applyTo(qb, true)
@@ -234,6 +250,9 @@ func (qb *QueryBuilder) order(propertyId C.obx_schema_id, flags C.OBXOrderFlags)
 // if value is true, the flag is set, otherwise the flag is cleared (unset)
 func (qb *QueryBuilder) setOrderFlag(property *BaseProperty, flag C.OBXOrderFlags, value bool) error {
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+		if _, found := qb.orderFlags[property.Id]; !found {
+			qb.orderSequence = append(qb.orderSequence, property.Id)
+		}
 		if value {
 			// set the flag
 			qb.orderFlags[property.Id] = qb.orderFlags[property.Id] | flag
@@ -246,13 +265,22 @@ func (qb *QueryBuilder) setOrderFlag(property *BaseProperty, flag C.OBXOrderFlag
 }
 
 func (qb *QueryBuilder) orderAsc(property *BaseProperty) error {
+	qb.orderUnsigned(property)
 	return qb.setOrderFlag(property, C.OBXOrderFlags_DESCENDING, false)
 }
 
 func (qb *QueryBuilder) orderDesc(property *BaseProperty) error {
+	qb.orderUnsigned(property)
 	return qb.setOrderFlag(property, C.OBXOrderFlags_DESCENDING, true)
 }
 
+// orderUnsigned makes the order of unsigned properties compare unsigned values; by default, scalars compare signed
+func (qb *QueryBuilder) orderUnsigned(property *BaseProperty) {
+	if qb.objectBox.isUnsignedProperty(property.Entity.Id, property.Id) {
+		qb.setOrderFlag(property, C.OBXOrderFlags_UNSIGNED, true)
+	}
+}
+
 func (qb *QueryBuilder) orderCaseSensitive(property *BaseProperty, value bool) error {
 	return qb.setOrderFlag(property, C.OBXOrderFlags_CASE_SENSITIVE, value)
 }
@@ -265,6 +293,36 @@ func (qb *QueryBuilder) orderNilAsZero(property *BaseProperty) error {
 	return qb.setOrderFlag(property, C.OBXOrderFlags_NULLS_ZERO, true)
 }
 
+// OrderFlag adjusts the order by a property, see BaseProperty.Asc() and BaseProperty.Desc()
+type OrderFlag uint32
+
+const (
+	// OrderCaseSensitive compares strings case sensitively; by default, ASCII characters are compared case insensitive
+	OrderCaseSensitive OrderFlag = C.OBXOrderFlags_CASE_SENSITIVE
+
+	// OrderUnsigned compares scalars as unsigned values; set automatically for unsigned properties
+	OrderUnsigned OrderFlag = C.OBXOrderFlags_UNSIGNED
+
+	// OrderNilLast puts objects with a nil value of the property at the end; by default, they come first
+	OrderNilLast OrderFlag = C.OBXOrderFlags_NULLS_LAST
+
+	// OrderNilAsZero treats a nil value of the property the same as 0 (scalars only)
+	OrderNilAsZero OrderFlag = C.OBXOrderFlags_NULLS_ZERO
+)
+
+// orderWith sets the order direction and the given additional flags
+func (qb *QueryBuilder) orderWith(property *BaseProperty, descending bool, flags []OrderFlag) error {
+	if descending {
+		qb.orderDesc(property)
+	} else {
+		qb.orderAsc(property)
+	}
+	for _, flag := range flags {
+		qb.setOrderFlag(property, C.OBXOrderFlags(flag), true)
+	}
+	return qb.Err
+}
+
 func (qb *QueryBuilder) checkForCError() {
 	// if there's already an error logged, don't overwrite it
 	if qb.Err != nil {
@@ -344,6 +402,11 @@ func (qb *QueryBuilder) All(ids []ConditionId) (ConditionId, error) {
 func (qb *QueryBuilder) IsNil(property *BaseProperty) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.IsNotNil(property)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_null(qb.cqb, C.obx_schema_id(property.Id)))
 	}
@@ -355,6 +418,11 @@ func (qb *QueryBuilder) IsNil(property *BaseProperty) (ConditionId, error) {
 func (qb *QueryBuilder) IsNotNil(property *BaseProperty) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.IsNil(property)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_not_null(qb.cqb, C.obx_schema_id(property.Id)))
 	}
@@ -366,6 +434,11 @@ func (qb *QueryBuilder) IsNotNil(property *BaseProperty) (ConditionId, error) {
 func (qb *QueryBuilder) StringEquals(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.StringNotEquals(property, value, caseSensitive)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
@@ -379,6 +452,10 @@ func (qb *QueryBuilder) StringEquals(property *BaseProperty, value string, caseS
 func (qb *QueryBuilder) StringIn(property *BaseProperty, values []string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		return cid, qb.notNegatable("StringIn")
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		if len(values) > 0 {
 			cStringArray := goStringArrayToC(values)
@@ -396,6 +473,10 @@ func (qb *QueryBuilder) StringIn(property *BaseProperty, values []string, caseSe
 func (qb *QueryBuilder) StringContains(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		return cid, qb.notNegatable("StringContains")
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
@@ -409,6 +490,10 @@ func (qb *QueryBuilder) StringContains(property *BaseProperty, value string, cas
 func (qb *QueryBuilder) StringHasPrefix(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		return cid, qb.notNegatable("StringHasPrefix")
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
@@ -422,6 +507,10 @@ func (qb *QueryBuilder) StringHasPrefix(property *BaseProperty, value string, ca
 func (qb *QueryBuilder) StringHasSuffix(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		return cid, qb.notNegatable("StringHasSuffix")
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
@@ -435,6 +524,11 @@ func (qb *QueryBuilder) StringHasSuffix(property *BaseProperty, value string, ca
 func (qb *QueryBuilder) StringNotEquals(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.StringEquals(property, value, caseSensitive)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
@@ -448,6 +542,11 @@ func (qb *QueryBuilder) StringNotEquals(property *BaseProperty, value string, ca
 func (qb *QueryBuilder) StringGreater(property *BaseProperty, value string, caseSensitive bool, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.StringLess(property, value, caseSensitive, !withEqual)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
@@ -465,6 +564,11 @@ func (qb *QueryBuilder) StringGreater(property *BaseProperty, value string, case
 func (qb *QueryBuilder) StringLess(property *BaseProperty, value string, caseSensitive bool, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.StringGreater(property, value, caseSensitive, !withEqual)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
@@ -482,6 +586,10 @@ func (qb *QueryBuilder) StringLess(property *BaseProperty, value string, caseSen
 func (qb *QueryBuilder) StringVectorContains(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		return cid, qb.notNegatable("StringVectorContains")
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
@@ -495,6 +603,11 @@ func (qb *QueryBuilder) StringVectorContains(property *BaseProperty, value strin
 func (qb *QueryBuilder) IntBetween(property *BaseProperty, value1 int64, value2 int64) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.anyOf(qb.IntLess(property, value1, false))(qb.IntGreater(property, value2, false))
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_between_2ints(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value1), C.int64_t(value2)))
 	}
@@ -506,6 +619,11 @@ func (qb *QueryBuilder) IntBetween(property *BaseProperty, value1 int64, value2
 func (qb *QueryBuilder) IntEqual(property *BaseProperty, value int64) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.IntNotEqual(property, value)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_equals_int(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value)))
 	}
@@ -517,6 +635,11 @@ func (qb *QueryBuilder) IntEqual(property *BaseProperty, value int64) (Condition
 func (qb *QueryBuilder) IntNotEqual(property *BaseProperty, value int64) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.IntEqual(property, value)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_not_equals_int(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value)))
 	}
@@ -528,6 +651,11 @@ func (qb *QueryBuilder) IntNotEqual(property *BaseProperty, value int64) (Condit
 func (qb *QueryBuilder) IntGreater(property *BaseProperty, value int64, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.IntLess(property, value, !withEqual)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_greater_or_equal_int(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value)))
@@ -543,6 +671,11 @@ func (qb *QueryBuilder) IntGreater(property *BaseProperty, value int64, withEqua
 func (qb *QueryBuilder) IntLess(property *BaseProperty, value int64, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.IntGreater(property, value, !withEqual)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_less_or_equal_int(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value)))
@@ -558,6 +691,11 @@ func (qb *QueryBuilder) IntLess(property *BaseProperty, value int64, withEqual b
 func (qb *QueryBuilder) Int64In(property *BaseProperty, values []int64) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.Int64NotIn(property, values)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_in_int64s(qb.cqb, C.obx_schema_id(property.Id), goInt64ArrayToC(values), C.size_t(len(values))))
 	}
@@ -569,6 +707,11 @@ func (qb *QueryBuilder) Int64In(property *BaseProperty, values []int64) (Conditi
 func (qb *QueryBuilder) Int64NotIn(property *BaseProperty, values []int64) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.Int64In(property, values)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_not_in_int64s(qb.cqb, C.obx_schema_id(property.Id), goInt64ArrayToC(values), C.size_t(len(values))))
 	}
@@ -576,10 +719,80 @@ func (qb *QueryBuilder) Int64NotIn(property *BaseProperty, values []int64) (Cond
 	return cid, qb.Err
 }
 
+// Uint64Equal is called internally
+func (qb *QueryBuilder) Uint64Equal(property *BaseProperty, value uint64) (ConditionId, error) {
+	if err := qb.checkUint64(property, value); err != nil {
+		return 0, err
+	}
+	return qb.IntEqual(property, int64(value))
+}
+
+// Uint64NotEqual is called internally
+func (qb *QueryBuilder) Uint64NotEqual(property *BaseProperty, value uint64) (ConditionId, error) {
+	if err := qb.checkUint64(property, value); err != nil {
+		return 0, err
+	}
+	return qb.IntNotEqual(property, int64(value))
+}
+
+// Uint64Greater is called internally
+func (qb *QueryBuilder) Uint64Greater(property *BaseProperty, value uint64, withEqual bool) (ConditionId, error) {
+	if err := qb.checkUint64(property, value); err != nil {
+		return 0, err
+	}
+	return qb.IntGreater(property, int64(value), withEqual)
+}
+
+// Uint64Less is called internally
+func (qb *QueryBuilder) Uint64Less(property *BaseProperty, value uint64, withEqual bool) (ConditionId, error) {
+	if err := qb.checkUint64(property, value); err != nil {
+		return 0, err
+	}
+	return qb.IntLess(property, int64(value), withEqual)
+}
+
+// Uint64Between is called internally
+func (qb *QueryBuilder) Uint64Between(property *BaseProperty, value1 uint64, value2 uint64) (ConditionId, error) {
+	if err := qb.checkUint64(property, value1, value2); err != nil {
+		return 0, err
+	}
+	return qb.IntBetween(property, int64(value1), int64(value2))
+}
+
+// Uint64In is called internally
+func (qb *QueryBuilder) Uint64In(property *BaseProperty, values []uint64) (ConditionId, error) {
+	if err := qb.checkUint64(property, values...); err != nil {
+		return 0, err
+	}
+	return qb.Int64In(property, uint64sToInt64s(values))
+}
+
+// Uint64NotIn is called internally
+func (qb *QueryBuilder) Uint64NotIn(property *BaseProperty, values []uint64) (ConditionId, error) {
+	if err := qb.checkUint64(property, values...); err != nil {
+		return 0, err
+	}
+	return qb.Int64NotIn(property, uint64sToInt64s(values))
+}
+
+// checkUint64 sets qb.Err if any of the values is above math.MaxInt64 but the property isn't stored as unsigned:
+// the native library would compare the value as a (negative) signed number.
+func (qb *QueryBuilder) checkUint64(property *BaseProperty, values ...uint64) error {
+	if qb.Err == nil {
+		qb.Err = qb.objectBox.checkUint64Range(property.Entity.Id, property.Id, values)
+	}
+	return qb.Err
+}
+
 // Int32In is called internally
 func (qb *QueryBuilder) Int32In(property *BaseProperty, values []int32) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.Int32NotIn(property, values)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_in_int32s(qb.cqb, C.obx_schema_id(property.Id), goInt32ArrayToC(values), C.size_t(len(values))))
 	}
@@ -591,6 +804,11 @@ func (qb *QueryBuilder) Int32In(property *BaseProperty, values []int32) (Conditi
 func (qb *QueryBuilder) Int32NotIn(property *BaseProperty, values []int32) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.Int32In(property, values)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_not_in_int32s(qb.cqb, C.obx_schema_id(property.Id), goInt32ArrayToC(values), C.size_t(len(values))))
 	}
@@ -602,6 +820,11 @@ func (qb *QueryBuilder) Int32NotIn(property *BaseProperty, values []int32) (Cond
 func (qb *QueryBuilder) DoubleGreater(property *BaseProperty, value float64, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.DoubleLess(property, value, !withEqual)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_greater_or_equal_double(qb.cqb, C.obx_schema_id(property.Id), C.double(value)))
@@ -617,6 +840,11 @@ func (qb *QueryBuilder) DoubleGreater(property *BaseProperty, value float64, wit
 func (qb *QueryBuilder) DoubleLess(property *BaseProperty, value float64, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.DoubleGreater(property, value, !withEqual)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_less_or_equal_double(qb.cqb, C.obx_schema_id(property.Id), C.double(value)))
@@ -632,6 +860,11 @@ func (qb *QueryBuilder) DoubleLess(property *BaseProperty, value float64, withEq
 func (qb *QueryBuilder) DoubleBetween(property *BaseProperty, valueA float64, valueB float64) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.anyOf(qb.DoubleLess(property, valueA, false))(qb.DoubleGreater(property, valueB, false))
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_between_2doubles(qb.cqb, C.obx_schema_id(property.Id), C.double(valueA), C.double(valueB)))
 	}
@@ -643,6 +876,11 @@ func (qb *QueryBuilder) DoubleBetween(property *BaseProperty, valueA float64, va
 func (qb *QueryBuilder) BytesEqual(property *BaseProperty, value []byte) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.anyOf(qb.BytesLess(property, value, false))(qb.BytesGreater(property, value, false))
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		cid = qb.getConditionId(C.obx_qb_equals_bytes(qb.cqb, C.obx_schema_id(property.Id), cBytesPtr(value), C.size_t(len(value))))
 	}
@@ -654,6 +892,11 @@ func (qb *QueryBuilder) BytesEqual(property *BaseProperty, value []byte) (Condit
 func (qb *QueryBuilder) BytesGreater(property *BaseProperty, value []byte, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.BytesLess(property, value, !withEqual)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_greater_or_equal_bytes(qb.cqb, C.obx_schema_id(property.Id), cBytesPtr(value), C.size_t(len(value))))
@@ -669,6 +912,11 @@ func (qb *QueryBuilder) BytesGreater(property *BaseProperty, value []byte, withE
 func (qb *QueryBuilder) BytesLess(property *BaseProperty, value []byte, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
+	if qb.negated {
+		defer qb.restoreNegated()()
+		return qb.BytesGreater(property, value, !withEqual)
+	}
+
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_less_or_equal_bytes(qb.cqb, C.obx_schema_id(property.Id), cBytesPtr(value), C.size_t(len(value))))
@@ -679,3 +927,57 @@ func (qb *QueryBuilder) BytesLess(property *BaseProperty, value []byte, withEqua
 
 	return cid, qb.Err
 }
+
+// restoreNegated temporarily disables negation so that the opposite condition can be created; call the returned
+// function afterwards to enable it again
+func (qb *QueryBuilder) restoreNegated() func() {
+	qb.negated = false
+	return func() {
+		qb.negated = true
+	}
+}
+
+// anyOf combines the results of two condition calls using Any(), e.g. to negate a "between" condition
+func (qb *QueryBuilder) anyOf(cid1 ConditionId, err error) func(cid2 ConditionId, err error) (ConditionId, error) {
+	return func(cid2 ConditionId, err2 error) (ConditionId, error) {
+		if err != nil {
+			return 0, err
+		} else if err2 != nil {
+			return 0, err2
+		}
+		return qb.Any([]ConditionId{cid1, cid2})
+	}
+}
+
+// notNegatable records an error for conditions without an opposite condition
+func (qb *QueryBuilder) notNegatable(condition string) error {
+	if qb.Err == nil {
+		qb.Err = fmt.Errorf("%s can't be used inside Not() - there's no opposite condition", condition)
+	}
+	return qb.Err
+}
+
+// isUnsignedProperty returns true for properties stored as unsigned integers, including IDs
+func (ob *ObjectBox) isUnsignedProperty(entityId, propertyId TypeId) bool {
+	var info = ob.schemaProperty(entityId, propertyId)
+	return info != nil && info.Flags&(C.OBXPropertyFlags_UNSIGNED|C.OBXPropertyFlags_ID) != 0
+}
+
+// checkUint64Range fails if any of the values doesn't fit into int64 unless the property is stored as unsigned
+func (ob *ObjectBox) checkUint64Range(entityId, propertyId TypeId, values []uint64) error {
+	for _, value := range values {
+		if value > math.MaxInt64 && !ob.isUnsignedProperty(entityId, propertyId) {
+			return fmt.Errorf("value %d is out of range of property %d, which is stored as a signed integer; "+
+				"regenerate the binding to store it as unsigned", value, propertyId)
+		}
+	}
+	return nil
+}
+
+func uint64sToInt64s(values []uint64) []int64 {
+	result := make([]int64, len(values))
+	for i, v := range values {
+		result[i] = int64(v) // same bits, the native library interprets them according to the property flags
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultQueryCacheSize is the number of queries kept by Box.CachedQuery() unless set by Builder.QueryCacheSize()
+const defaultQueryCacheSize = 64
+
+// QueryCacheStats describes the usage of the store's prepared query cache, see Box.CachedQuery()
+type QueryCacheStats struct {
+	Hits      uint64 // number of CachedQuery() calls served from the cache
+	Misses    uint64 // number of CachedQuery() calls that had to build the query
+	Evictions uint64 // number of queries removed from the cache to make room for new ones
+	Size      int    // number of queries currently in the cache
+	Capacity  int    // maximum number of queries in the cache; zero if the cache is disabled
+}
+
+// queryCache is an LRU cache of built queries, keyed by the entity and the identity of the conditions
+type queryCache struct {
+	capacity int
+	mutex    sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List // of *queryCacheEntry, the most recently used at the front
+	stats    QueryCacheStats
+}
+
+type queryCacheEntry struct {
+	key   string
+	query *Query // the prepared query, never used directly, only cloned
+}
+
+func newQueryCache(size int) *queryCache {
+	if size == 0 {
+		size = defaultQueryCacheSize
+	} else if size < 0 {
+		size = 0
+	}
+	return &queryCache{
+		capacity: size,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		stats:    QueryCacheStats{Capacity: size},
+	}
+}
+
+// QueryCacheSize sets the maximum number of queries kept by Box.CachedQuery(), evicting the least recently used ones
+// when full; the default is 64. A negative size disables the cache, i.e. CachedQuery() builds each query anew.
+func (builder *Builder) QueryCacheSize(size int) *Builder {
+	builder.queryCacheSize = size
+	return builder
+}
+
+// CachedQuery is like Query() but reuses the query built by a previous call with the same conditions, avoiding the
+// cost of building the native query on hot code paths, e.g. for each HTTP request. The returned query is a clone of the
+// cached one, so it's independent of other callers (safe to use concurrently with them) and can be configured by
+// setting params, offset and limit; close it when done.
+//
+// The conditions are identified by their instances (not their values), so define them once with aliases and set the
+// values on the returned query, the same way as with prepared statements in database/sql:
+//
+//	var eventsOfDevice = []objectbox.Condition{Event_.Device.Equals("", true).Alias("device")}
+//
+//	query, err := box.CachedQuery(eventsOfDevice...)
+//	...
+//	defer query.Close()
+//	err = query.SetStringParams(objectbox.Alias("device"), device)
+//
+// Conditions created anew for each call never hit the cache; watch ObjectBox.QueryCacheStats() to catch that.
+func (box *Box) CachedQuery(conditions ...Condition) (*Query, error) {
+	if err := box.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	var cache = box.ObjectBox.queryCache
+	var key = queryCacheKey(box.entity.id, conditions)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, found := cache.entries[key]; found {
+		cache.stats.Hits++
+		cache.lru.MoveToFront(element)
+		return element.Value.(*queryCacheEntry).query.Clone()
+	}
+
+	cache.stats.Misses++
+	query, err := box.QueryOrError(conditions...)
+	if err != nil || cache.capacity == 0 {
+		return query, err
+	}
+
+	// the entry keeps the conditions referenced (query.conditions) so their addresses, used in the key, can't be reused
+	cache.entries[key] = cache.lru.PushFront(&queryCacheEntry{key: key, query: query})
+	for cache.lru.Len() > cache.capacity {
+		var oldest = cache.lru.Remove(cache.lru.Back()).(*queryCacheEntry)
+		delete(cache.entries, oldest.key)
+		_ = oldest.query.Close()
+		cache.stats.Evictions++
+	}
+	return query.Clone()
+}
+
+func queryCacheKey(entityId TypeId, conditions []Condition) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d", entityId)
+	for _, condition := range conditions {
+		fmt.Fprintf(&sb, ":%p", condition)
+	}
+	return sb.String()
+}
+
+// QueryCacheStats returns the hit and miss counts of Box.CachedQuery() for all boxes of this store
+func (ob *ObjectBox) QueryCacheStats() QueryCacheStats {
+	ob.queryCache.mutex.Lock()
+	defer ob.queryCache.mutex.Unlock()
+
+	var stats = ob.queryCache.stats
+	stats.Size = ob.queryCache.lru.Len()
+	return stats
+}
+
+// close closes all cached queries
+func (cache *queryCache) close() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for element := cache.lru.Front(); element != nil; element = element.Next() {
+		_ = element.Value.(*queryCacheEntry).query.Close()
+	}
+	cache.entries = make(map[string]*list.Element)
+	cache.lru.Init()
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QueryPlan explains how a query is likely to be executed, see Query.Explain()
+type QueryPlan struct {
+	// Description lists the query conditions including the parameter values, see Query.DescribeParams()
+	Description string
+
+	// Properties used by the conditions of the query, in the order of their first appearance
+	Properties []QueryPlanProperty
+
+	// FullScan is true if none of the conditions can use an index (or the ID), i.e. all objects are visited.
+	// Note: conditions combined using OR (Any()) may require visiting all objects even if FullScan is false.
+	FullScan bool
+}
+
+// QueryPlanProperty describes a property used by a query condition, see QueryPlan
+type QueryPlanProperty struct {
+	Name    string
+	Id      bool // whether this is the ID property; conditions on the ID are resolved without visiting other objects
+	Indexed bool // whether the property has an index (including to-one relations)
+	Known   bool // false if the property isn't part of the queried entity, e.g. in a condition of a linked entity
+}
+
+// Explain returns the properties used by the query conditions and whether they're indexed, to help finding out why a
+// query is slow. A query on properties without an index has to visit all objects; consider adding an index to the
+// property (see the `objectbox:"index"` tag) if the query is executed often on a large number of objects.
+func (query *Query) Explain() (*QueryPlan, error) {
+	description, err := query.DescribeParams()
+	if err != nil {
+		return nil, err
+	}
+
+	var plan = &QueryPlan{Description: description, FullScan: true}
+	var seen = make(map[string]bool)
+
+	// the scanner used for redacting reports each property starting a condition
+	redactQueryDescription(description, func(name string) bool {
+		if !seen[name] {
+			seen[name] = true
+			var property = query.schemaPropertyByName(name)
+			if property.Id || property.Indexed {
+				plan.FullScan = false
+			}
+			plan.Properties = append(plan.Properties, property)
+		}
+		return false
+	})
+	return plan, nil
+}
+
+func (query *Query) schemaPropertyByName(name string) QueryPlanProperty {
+	var result = QueryPlanProperty{Name: name}
+	for _, e := range query.objectBox.schema.Entities {
+		if e.Id != query.entity.id {
+			continue
+		}
+		for _, p := range e.Properties {
+			if p.Name == name {
+				result.Known = true
+				result.Id = p.Flags&C.OBXPropertyFlags_ID != 0
+				result.Indexed = p.Index.Id != 0
+			}
+		}
+	}
+	return result
+}
+
+func (plan *QueryPlan) String() string {
+	var lines = []string{plan.Description}
+	if plan.FullScan {
+		lines = append(lines, "full scan: none of the conditions can use an index")
+	}
+	for _, property := range plan.Properties {
+		switch {
+		case !property.Known:
+			lines = append(lines, fmt.Sprintf("%s: not a property of the queried entity", property.Name))
+		case property.Id:
+			lines = append(lines, fmt.Sprintf("%s: ID", property.Name))
+		case property.Indexed:
+			lines = append(lines, fmt.Sprintf("%s: indexed", property.Name))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: not indexed", property.Name))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
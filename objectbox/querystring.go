@@ -0,0 +1,506 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QueryString creates a query from a textual filter expression, resolving property names using the model, e.g.
+//
+//	query, err := box.QueryString(`Device = ? AND (Date > ? OR Picture IS NULL)`, "device-1", since)
+//
+// The expression language supports:
+//   - comparisons: =, !=, <>, <, <=, >, >= (floating point properties don't support equality)
+//   - IN (value, ...) and NOT IN (value, ...) for integer and string properties
+//   - IS NULL and IS NOT NULL
+//   - AND, OR and parentheses; AND binds stronger than OR; keywords are case insensitive
+//   - values: `?` placeholders taking params in order, 'quoted strings', numbers, TRUE and FALSE
+//
+// Property names are matched case insensitively. String comparisons are case sensitive. Params of date properties may
+// be given as time.Time. Intended for tools and admin consoles receiving filters as text; prefer the generated
+// properties (e.g. Entity_.Name.Equals()) in code, which are checked by the compiler.
+func (box *Box) QueryString(expression string, params ...interface{}) (*Query, error) {
+	var entity *ModelEntityInfo
+	for _, e := range box.ObjectBox.schema.Entities {
+		if e.Id == box.entity.id {
+			entity = e
+		}
+	}
+	if entity == nil {
+		return nil, fmt.Errorf("entity %s not found in the model", box.entity.name)
+	}
+
+	tokens, err := tokenizeQueryString(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var parser = &queryStringParser{entity: entity, tokens: tokens, params: params}
+	condition, err := parser.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid query string %q: %v", expression, err)
+	}
+	return box.QueryOrError(condition)
+}
+
+type queryStringTokenKind int
+
+const (
+	queryStringIdentifier queryStringTokenKind = iota
+	queryStringOperator
+	queryStringString
+	queryStringNumber
+	queryStringParam
+	queryStringOpen
+	queryStringClose
+	queryStringComma
+)
+
+type queryStringToken struct {
+	kind queryStringTokenKind
+	text string
+	pos  int
+}
+
+func tokenizeQueryString(expression string) ([]queryStringToken, error) {
+	var tokens []queryStringToken
+	var runes = []rune(expression)
+	for i := 0; i < len(runes); {
+		var r = runes[i]
+		var start = i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, queryStringToken{queryStringOpen, "(", start})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryStringToken{queryStringClose, ")", start})
+			i++
+		case r == ',':
+			tokens = append(tokens, queryStringToken{queryStringComma, ",", start})
+			i++
+		case r == '?':
+			tokens = append(tokens, queryStringToken{queryStringParam, "?", start})
+			i++
+		case r == '\'':
+			// a quote inside a string is escaped by doubling it: 'it''s'
+			var text []rune
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string starting at position %d", start)
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i++
+					} else {
+						i++
+						break
+					}
+				}
+				text = append(text, runes[i])
+			}
+			tokens = append(tokens, queryStringToken{queryStringString, string(text), start})
+		case strings.ContainsRune("=!<>", r):
+			i++
+			if i < len(runes) && (runes[i] == '=' || (r == '<' && runes[i] == '>')) {
+				i++
+			}
+			var text = string(runes[start:i])
+			if text == "!" {
+				return nil, fmt.Errorf("unexpected '!' at position %d", start)
+			}
+			tokens = append(tokens, queryStringToken{queryStringOperator, text, start})
+		case unicode.IsDigit(r) || r == '-' || r == '.':
+			// a sign is only part of the number at its start or in the exponent, e.g. 5-3 isn't a single token
+			for i++; i < len(runes); i++ {
+				if runes[i] == '+' || runes[i] == '-' {
+					if runes[i-1] != 'e' && runes[i-1] != 'E' {
+						break
+					}
+				} else if !unicode.IsDigit(runes[i]) && !strings.ContainsRune(".eE", runes[i]) {
+					break
+				}
+			}
+			tokens = append(tokens, queryStringToken{queryStringNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			for i++; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_'); i++ {
+			}
+			tokens = append(tokens, queryStringToken{queryStringIdentifier, string(runes[start:i]), start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, start)
+		}
+	}
+	return tokens, nil
+}
+
+// queryStringParser is a recursive descent parser of the QueryString expression language
+type queryStringParser struct {
+	entity *ModelEntityInfo
+	tokens []queryStringToken
+	next   int
+	params []interface{}
+	param  int
+}
+
+func (parser *queryStringParser) parse() (Condition, error) {
+	condition, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token != nil {
+		return nil, fmt.Errorf("unexpected %q at position %d", token.text, token.pos)
+	}
+	if parser.param != len(parser.params) {
+		return nil, fmt.Errorf("%d params given but only %d placeholders used", len(parser.params), parser.param)
+	}
+	return condition, nil
+}
+
+func (parser *queryStringParser) peek() *queryStringToken {
+	if parser.next < len(parser.tokens) {
+		return &parser.tokens[parser.next]
+	}
+	return nil
+}
+
+func (parser *queryStringParser) take() (*queryStringToken, error) {
+	var token = parser.peek()
+	if token == nil {
+		return nil, fmt.Errorf("unexpected end of the expression")
+	}
+	parser.next++
+	return token, nil
+}
+
+// keyword consumes the next token if it's the given (case insensitive) keyword
+func (parser *queryStringParser) keyword(keyword string) bool {
+	var token = parser.peek()
+	if token != nil && token.kind == queryStringIdentifier && strings.EqualFold(token.text, keyword) {
+		parser.next++
+		return true
+	}
+	return false
+}
+
+func (parser *queryStringParser) expect(kind queryStringTokenKind, text string) error {
+	token, err := parser.take()
+	if err != nil {
+		return fmt.Errorf("expected %q: %v", text, err)
+	}
+	if token.kind != kind {
+		return fmt.Errorf("expected %q at position %d, found %q", text, token.pos, token.text)
+	}
+	return nil
+}
+
+func (parser *queryStringParser) parseOr() (Condition, error) {
+	return parser.parseCombination("OR", Any, parser.parseAnd)
+}
+
+func (parser *queryStringParser) parseAnd() (Condition, error) {
+	return parser.parseCombination("AND", All, parser.parseTerm)
+}
+
+func (parser *queryStringParser) parseCombination(keyword string, combine func(...Condition) Condition,
+	parseOperand func() (Condition, error)) (Condition, error) {
+	var conditions []Condition
+	for {
+		condition, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+		if !parser.keyword(keyword) {
+			break
+		}
+	}
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return combine(conditions...), nil
+}
+
+func (parser *queryStringParser) parseTerm() (Condition, error) {
+	token, err := parser.take()
+	if err != nil {
+		return nil, err
+	}
+
+	if token.kind == queryStringOpen {
+		condition, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return condition, parser.expect(queryStringClose, ")")
+	}
+
+	if token.kind != queryStringIdentifier {
+		return nil, fmt.Errorf("expected a property name at position %d, found %q", token.pos, token.text)
+	}
+	property, err := parser.property(token.text)
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.keyword("IS") {
+		var negate = parser.keyword("NOT")
+		if !parser.keyword("NULL") {
+			return nil, fmt.Errorf("expected NULL after IS near position %d", token.pos)
+		}
+		var base = BaseProperty{Id: property.Id, Entity: &Entity{Id: parser.entity.Id}}
+		if negate {
+			return base.IsNotNil(), nil
+		}
+		return base.IsNil(), nil
+	}
+
+	if parser.keyword("NOT") {
+		if !parser.keyword("IN") {
+			return nil, fmt.Errorf("expected IN after NOT near position %d", token.pos)
+		}
+		return parser.parseIn(property, true)
+	}
+	if parser.keyword("IN") {
+		return parser.parseIn(property, false)
+	}
+
+	operator, err := parser.take()
+	if err != nil {
+		return nil, err
+	}
+	if operator.kind != queryStringOperator {
+		return nil, fmt.Errorf("expected an operator at position %d, found %q", operator.pos, operator.text)
+	}
+	value, err := parser.parseValue(property)
+	if err != nil {
+		return nil, err
+	}
+	return parser.comparison(property, operator.text, value)
+}
+
+func (parser *queryStringParser) parseIn(property *ModelPropertyInfo, negate bool) (Condition, error) {
+	if err := parser.expect(queryStringOpen, "("); err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for {
+		value, err := parser.parseValue(property)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if token := parser.peek(); token == nil || token.kind != queryStringComma {
+			break
+		}
+		parser.next++
+	}
+	if err := parser.expect(queryStringClose, ")"); err != nil {
+		return nil, err
+	}
+
+	var base = &BaseProperty{Id: property.Id, Entity: &Entity{Id: parser.entity.Id}}
+	switch value := values[0].(type) {
+	case int64:
+		var ints = make([]int64, len(values))
+		for i := range values {
+			ints[i] = values[i].(int64)
+		}
+		if negate {
+			return PropertyInt64{base}.NotIn(ints...), nil
+		}
+		return PropertyInt64{base}.In(ints...), nil
+	case string:
+		if negate {
+			return nil, fmt.Errorf("NOT IN isn't supported for string property %s", property.Name)
+		}
+		var texts = make([]string, len(values))
+		for i := range values {
+			texts[i] = values[i].(string)
+		}
+		return PropertyString{base}.In(true, texts...), nil
+	default:
+		return nil, fmt.Errorf("IN isn't supported for property %s of type %s with value %v", property.Name,
+			propertyTypeName(property.Type), value)
+	}
+}
+
+// property finds the property by name, case insensitive
+func (parser *queryStringParser) property(name string) (*ModelPropertyInfo, error) {
+	for _, p := range parser.entity.Properties {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("entity %s has no property %s", parser.entity.Name, name)
+}
+
+// parseValue reads a literal or a param and converts it to the type used by conditions on the given property:
+// int64, float64, string or bool
+func (parser *queryStringParser) parseValue(property *ModelPropertyInfo) (interface{}, error) {
+	token, err := parser.take()
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch token.kind {
+	case queryStringParam:
+		if parser.param >= len(parser.params) {
+			return nil, fmt.Errorf("missing param for placeholder %d at position %d", parser.param+1, token.pos)
+		}
+		value = parser.params[parser.param]
+		parser.param++
+	case queryStringString:
+		value = token.text
+	case queryStringNumber:
+		if i, err := strconv.ParseInt(token.text, 10, 64); err == nil {
+			value = i
+		} else if f, err := strconv.ParseFloat(token.text, 64); err == nil {
+			value = f
+		} else {
+			return nil, fmt.Errorf("invalid number %q at position %d", token.text, token.pos)
+		}
+	case queryStringIdentifier:
+		if strings.EqualFold(token.text, "TRUE") || strings.EqualFold(token.text, "FALSE") {
+			value = strings.EqualFold(token.text, "TRUE")
+		} else {
+			return nil, fmt.Errorf("unexpected %q at position %d, expected a value", token.text, token.pos)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d, expected a value", token.text, token.pos)
+	}
+
+	converted, err := queryStringValue(property, value)
+	if err != nil {
+		return nil, fmt.Errorf("position %d: %v", token.pos, err)
+	}
+	return converted, nil
+}
+
+func queryStringValue(property *ModelPropertyInfo, value interface{}) (interface{}, error) {
+	switch property.Type {
+	case C.OBXPropertyType_String:
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+	case C.OBXPropertyType_Bool:
+		if flag, ok := value.(bool); ok {
+			return flag, nil
+		}
+	case C.OBXPropertyType_Float, C.OBXPropertyType_Double:
+		if i, ok := integerValue(value); ok {
+			return float64(i), nil
+		}
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case float32:
+			return float64(v), nil
+		}
+	case C.OBXPropertyType_Date, C.OBXPropertyType_DateNano:
+		if t, ok := value.(time.Time); ok {
+			if property.Type == C.OBXPropertyType_DateNano {
+				return t.UnixNano(), nil
+			}
+			return t.UnixNano() / int64(time.Millisecond), nil
+		}
+		fallthrough
+	case C.OBXPropertyType_Byte, C.OBXPropertyType_Short, C.OBXPropertyType_Char, C.OBXPropertyType_Int,
+		C.OBXPropertyType_Long, C.OBXPropertyType_Relation:
+		if i, ok := integerValue(value); ok {
+			return i, nil
+		}
+	default:
+		return nil, fmt.Errorf("property %s of type %s can't be used in a query string", property.Name,
+			propertyTypeName(property.Type))
+	}
+	return nil, fmt.Errorf("value %v (%T) can't be used with property %s of type %s", value, value, property.Name,
+		propertyTypeName(property.Type))
+}
+
+func (parser *queryStringParser) comparison(property *ModelPropertyInfo, operator string,
+	value interface{}) (Condition, error) {
+	var base = &BaseProperty{Id: property.Id, Entity: &Entity{Id: parser.entity.Id}}
+	switch v := value.(type) {
+	case int64:
+		var p = PropertyInt64{base}
+		switch operator {
+		case "=":
+			return p.Equals(v), nil
+		case "!=", "<>":
+			return p.NotEquals(v), nil
+		case "<":
+			return p.LessThan(v), nil
+		case "<=":
+			return p.LessOrEqual(v), nil
+		case ">":
+			return p.GreaterThan(v), nil
+		case ">=":
+			return p.GreaterOrEqual(v), nil
+		}
+	case string:
+		var p = PropertyString{base}
+		switch operator {
+		case "=":
+			return p.Equals(v, true), nil
+		case "!=", "<>":
+			return p.NotEquals(v, true), nil
+		case "<":
+			return p.LessThan(v, true), nil
+		case "<=":
+			return p.LessOrEqual(v, true), nil
+		case ">":
+			return p.GreaterThan(v, true), nil
+		case ">=":
+			return p.GreaterOrEqual(v, true), nil
+		}
+	case float64:
+		var p = PropertyFloat64{base}
+		switch operator {
+		case "<":
+			return p.LessThan(v), nil
+		case "<=":
+			return p.LessOrEqual(v), nil
+		case ">":
+			return p.GreaterThan(v), nil
+		case ">=":
+			return p.GreaterOrEqual(v), nil
+		}
+	case bool:
+		var p = PropertyBool{base}
+		switch operator {
+		case "=":
+			return p.Equals(v), nil
+		case "!=", "<>":
+			return p.Equals(!v), nil
+		}
+	}
+	return nil, fmt.Errorf("operator %s isn't supported for property %s of type %s", operator, property.Name,
+		propertyTypeName(property.Type))
+}
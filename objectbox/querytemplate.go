@@ -0,0 +1,142 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import "fmt"
+
+// QueryTemplate defines query conditions once, by property names, for all entities sharing these properties, e.g. a
+// common set of fields like CreatedAt and TenantId embedded in multiple entities:
+//
+//	var recentOfTenant = objectbox.NewQueryTemplate(func(fields *objectbox.TemplateFields) []objectbox.Condition {
+//		return []objectbox.Condition{
+//			fields.String("TenantId").Equals("", true).Alias("tenant"),
+//			fields.Int64("CreatedAt").GreaterThan(0).Alias("since"),
+//			fields.Int64("CreatedAt").Desc(),
+//		}
+//	})
+//
+//	query, err := recentOfTenant.Query(orderBox.Box)
+//	err = query.SetStringParams(objectbox.Alias("tenant"), tenant)
+//
+// Use aliases to set the values of an instantiated query, as its properties differ between entities.
+type QueryTemplate struct {
+	define func(fields *TemplateFields) []Condition
+}
+
+// NewQueryTemplate creates a template; define is called for each instantiation to create the conditions
+func NewQueryTemplate(define func(fields *TemplateFields) []Condition) *QueryTemplate {
+	return &QueryTemplate{define: define}
+}
+
+// Query instantiates the template for the entity of the given box, combining its conditions with the given ones.
+// Fails if the entity doesn't have a property used by the template or if it has a different type.
+func (template *QueryTemplate) Query(box *Box, conditions ...Condition) (*Query, error) {
+	var fields = &TemplateFields{entityName: box.entity.name}
+	for _, e := range box.ObjectBox.schema.Entities {
+		if e.Id == box.entity.id {
+			fields.entity = e
+		}
+	}
+	if fields.entity == nil {
+		return nil, fmt.Errorf("entity %s not found in the model", box.entity.name)
+	}
+
+	var templateConditions = template.define(fields)
+	if fields.err != nil {
+		return nil, fields.err
+	}
+	return box.QueryOrError(append(templateConditions, conditions...)...)
+}
+
+// TemplateFields resolves the properties used by a QueryTemplate by their name when it's instantiated for an entity
+type TemplateFields struct {
+	entityName string
+	entity     *ModelEntityInfo
+	err        error // the first property not found
+}
+
+// property finds the property by name, checking its type; records an error if that fails
+func (fields *TemplateFields) property(name string, types ...int) *BaseProperty {
+	var property = &BaseProperty{Entity: &Entity{Id: fields.entity.Id}}
+	for _, p := range fields.entity.Properties {
+		if p.Name != name {
+			continue
+		}
+		for _, propertyType := range types {
+			if p.Type == propertyType {
+				property.Id = p.Id
+				return property
+			}
+		}
+		if fields.err == nil {
+			fields.err = fmt.Errorf("property %s.%s has type %s, which can't be used as %s by the query template",
+				fields.entityName, name, propertyTypeName(p.Type), propertyTypeName(types[0]))
+		}
+		return property
+	}
+
+	if fields.err == nil {
+		fields.err = fmt.Errorf("entity %s has no property %s used by the query template", fields.entityName, name)
+	}
+	return property
+}
+
+// String returns the string property of the given name
+func (fields *TemplateFields) String(name string) *PropertyString {
+	return &PropertyString{fields.property(name, C.OBXPropertyType_String)}
+}
+
+// Int64 returns the int64 property of the given name, including dates (time.Time) stored as int64
+func (fields *TemplateFields) Int64(name string) *PropertyInt64 {
+	return &PropertyInt64{fields.property(name, C.OBXPropertyType_Long, C.OBXPropertyType_Date,
+		C.OBXPropertyType_DateNano)}
+}
+
+// Uint64 returns the uint64 property of the given name, including to-one relations
+func (fields *TemplateFields) Uint64(name string) *PropertyUint64 {
+	return &PropertyUint64{fields.property(name, C.OBXPropertyType_Long, C.OBXPropertyType_Relation)}
+}
+
+// Int32 returns the int32 property of the given name
+func (fields *TemplateFields) Int32(name string) *PropertyInt32 {
+	return &PropertyInt32{fields.property(name, C.OBXPropertyType_Int)}
+}
+
+// Uint32 returns the uint32 property of the given name
+func (fields *TemplateFields) Uint32(name string) *PropertyUint32 {
+	return &PropertyUint32{fields.property(name, C.OBXPropertyType_Int)}
+}
+
+// Bool returns the bool property of the given name
+func (fields *TemplateFields) Bool(name string) *PropertyBool {
+	return &PropertyBool{fields.property(name, C.OBXPropertyType_Bool)}
+}
+
+// Float64 returns the float64 property of the given name
+func (fields *TemplateFields) Float64(name string) *PropertyFloat64 {
+	return &PropertyFloat64{fields.property(name, C.OBXPropertyType_Double)}
+}
+
+// ByteVector returns the []byte property of the given name
+func (fields *TemplateFields) ByteVector(name string) *PropertyByteVector {
+	return &PropertyByteVector{fields.property(name, C.OBXPropertyType_ByteVector)}
+}
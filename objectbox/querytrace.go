@@ -0,0 +1,193 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// QueryTrace describes a single executed query, see Builder.TraceQueries()
+type QueryTrace struct {
+	// Operation is the executed query method, e.g. "Query.Find" or "Query.Count"
+	Operation string
+
+	// Entity is the name of the queried entity
+	Entity string
+
+	// Description lists the query conditions with parameter values (redacted as configured), see Query.DescribeParams()
+	Description string
+
+	// Duration of the operation
+	Duration time.Duration
+
+	// Count is the number of objects found, counted or removed
+	Count uint64
+
+	// Err is the result of the operation, i.e. nil on success
+	Err error
+}
+
+// queryTracer holds the configuration of Builder.TraceQueries()
+type queryTracer struct {
+	fn     func(trace QueryTrace)
+	redact func(entity, property string) bool
+}
+
+// TraceQueries makes the store report each executed query (Find, FindIds, Count and Remove) to the given function,
+// e.g. to log slow or unexpectedly large queries while debugging in production. The function is called synchronously,
+// on the goroutine executing the query, after the query has finished.
+//
+// To keep sensitive data (e.g. PII) out of the logs, the parameter values of all properties for which redact returns
+// true are replaced by "?" in the description, e.g. `(Name == "John" AND Age > 30)` becomes `(Name == ? AND Age > 30)`.
+// If redact is nil, all values are redacted.
+func (builder *Builder) TraceQueries(fn func(trace QueryTrace), redact func(entity, property string) bool) *Builder {
+	if fn == nil {
+		builder.queryTracer = nil
+	} else {
+		builder.queryTracer = &queryTracer{fn: fn, redact: redact}
+	}
+	return builder
+}
+
+// trace reports the finished query operation
+func (tracer *queryTracer) trace(query *Query, operation string, start time.Time, count uint64, err error) {
+	var trace = QueryTrace{
+		Operation: operation,
+		Entity:    query.entity.name,
+		Duration:  time.Since(start),
+		Count:     count,
+		Err:       err,
+	}
+	if query.cQuery != nil {
+		// no need to free, it's handled by the cQuery internally
+		trace.Description = C.GoString(C.obx_query_describe_params(query.cQuery))
+		trace.Description = redactQueryDescription(trace.Description, func(property string) bool {
+			return tracer.redact == nil || tracer.redact(query.entity.name, property)
+		})
+	}
+	tracer.fn(trace)
+}
+
+// resultLen returns the number of objects in a result slice returned by Find()
+func resultLen(objects interface{}) uint64 {
+	if objects == nil {
+		return 0
+	}
+	return uint64(reflect.ValueOf(objects).Len())
+}
+
+// redactQueryDescription replaces the values of the conditions on properties for which redact returns true by "?".
+// The description format is the one produced by the core, e.g. `(String == "Val" OR Int64 between 47 and 94)`.
+func redactQueryDescription(description string, redact func(property string) bool) string {
+	var result strings.Builder
+	result.Grow(len(description))
+
+	var conditionStart = true // whether a property name (starting a new condition) may follow
+	var redacting = false     // whether the values of the current condition are redacted
+	for i := 0; i < len(description); {
+		var c = description[i]
+		switch {
+		case c == '"':
+			// a string literal, possibly containing escaped quotes
+			var end = i + 1
+			for end < len(description) && description[end] != '"' {
+				if description[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(description) {
+				end++ // the closing quote
+			}
+			if redacting {
+				result.WriteByte('?')
+			} else {
+				result.WriteString(description[i:end])
+			}
+			i = end
+
+		case conditionStart && isIdentifierChar(c):
+			var end = i
+			for end < len(description) && isIdentifierChar(description[end]) {
+				end++
+			}
+			var property = description[i:end]
+			redacting = end < len(description) && description[end] == ' ' && redact(property)
+			conditionStart = false
+			result.WriteString(property)
+			i = end
+
+		case c == '(':
+			conditionStart = true
+			result.WriteByte(c)
+			i++
+
+		case c == ')':
+			redacting = false
+			result.WriteByte(c)
+			i++
+
+		case strings.HasPrefix(description[i:], " AND ") || strings.HasPrefix(description[i:], " OR "):
+			var end = i + strings.IndexByte(description[i+1:], ' ') + 2
+			conditionStart = true
+			redacting = false
+			result.WriteString(description[i:end])
+			i = end
+
+		case redacting && strings.HasPrefix(description[i:], "byte["):
+			// a byte vector, e.g. byte[5]{0x0102030508}
+			var end = strings.IndexByte(description[i:], '}')
+			if end < 0 {
+				end = len(description)
+			} else {
+				end += i + 1
+			}
+			result.WriteByte('?')
+			i = end
+
+		case redacting && (isDigit(c) || (c == '-' && i+1 < len(description) && isDigit(description[i+1]))):
+			// a number, e.g. -47 or 4.7e+01
+			var end = i + 1
+			for end < len(description) && (isIdentifierChar(description[end]) || description[end] == '.' ||
+				((description[end] == '+' || description[end] == '-') && (description[end-1] == 'e' || description[end-1] == 'E'))) {
+				end++
+			}
+			result.WriteByte('?')
+			i = end
+
+		default:
+			result.WriteByte(c)
+			i++
+		}
+	}
+	return result.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierChar(c byte) bool {
+	return isDigit(c) || c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
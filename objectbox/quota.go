@@ -0,0 +1,280 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// BoxQuota limits the objects stored for an entity, see Builder.BoxQuota()
+type BoxQuota struct {
+	MaxCount uint64 // maximum number of objects; 0 for no limit
+	MaxBytes uint64 // maximum total size of the objects as stored (serialized); 0 for no limit
+}
+
+// QuotaExceededError is returned by Put(), Insert(), Update() and PutMany() if the put would exceed the BoxQuota of the
+// entity; nothing is written by the failing put.
+type QuotaExceededError struct {
+	Entity string
+	Quota  BoxQuota
+	Count  uint64 // number of objects including the put one; 0 if Quota.MaxCount isn't set
+	Bytes  uint64 // total size of the objects including the put one; 0 if Quota.MaxBytes isn't set
+}
+
+func (err *QuotaExceededError) Error() string {
+	if err.Quota.MaxCount > 0 && err.Count > err.Quota.MaxCount {
+		return fmt.Sprintf("quota of entity %s exceeded: %d objects, at most %d allowed",
+			err.Entity, err.Count, err.Quota.MaxCount)
+	}
+	return fmt.Sprintf("quota of entity %s exceeded: %d bytes, at most %d allowed",
+		err.Entity, err.Bytes, err.Quota.MaxBytes)
+}
+
+// BoxQuota limits the number of objects and/or their total size for the given entity, so that a single chatty entity
+// can't fill the whole store and starve the others. Puts exceeding the quota fail with a QuotaExceededError, unless
+// the OnQuotaExceeded() callback makes room.
+//
+// Note: puts of the entity are executed in a write transaction (like for entities with relations) and PutMany() puts
+// the objects one by one; objects put by an AsyncBox aren't checked.
+func (builder *Builder) BoxQuota(entityId TypeId, quota BoxQuota) *Builder {
+	if builder.boxQuotas == nil {
+		builder.boxQuotas = make(map[TypeId]BoxQuota)
+	}
+	builder.boxQuotas[entityId] = quota
+	return builder
+}
+
+// OnQuotaExceeded sets a function called when a put would exceed the BoxQuota of an entity, e.g. to remove the oldest
+// objects using the given box. It's called inside the write transaction of the put; if it returns nil, the quota is
+// checked again and the put fails with a QuotaExceededError only if there's still not enough room. An error returned
+// by fn makes the put fail with that error instead.
+func (builder *Builder) OnQuotaExceeded(fn func(box *Box, err *QuotaExceededError) error) *Builder {
+	builder.onQuotaExceeded = fn
+	return builder
+}
+
+// checkBoxQuotas validates the quotas configured by BoxQuota()
+func (builder *Builder) checkBoxQuotas() error {
+	for entityId := range builder.boxQuotas {
+		if builder.model.entitiesById[entityId] == nil {
+			return fmt.Errorf("can't set a quota for entity %d - not found in the model", entityId)
+		}
+	}
+	return nil
+}
+
+// applyBoxQuotas sets up the quotas on the model entities of the store being opened
+func (builder *Builder) applyBoxQuotas() []*boxQuota {
+	var quotas []*boxQuota
+	for id, entity := range builder.model.entitiesById {
+		entity.quota = nil
+		if quota, found := builder.boxQuotas[id]; found && (quota.MaxCount > 0 || quota.MaxBytes > 0) {
+			entity.quota = &boxQuota{BoxQuota: quota, onExceeded: builder.onQuotaExceeded}
+			quotas = append(quotas, entity.quota)
+		}
+	}
+	return quotas
+}
+
+// boxQuota enforces the BoxQuota of an entity
+type boxQuota struct {
+	BoxQuota
+	onExceeded func(box *Box, err *QuotaExceededError) error
+
+	// total size of the stored objects, read on demand and then kept up-to-date by puts and removals; it's read again
+	// after a write transaction has been aborted
+	mutex      sync.Mutex
+	bytes      uint64
+	bytesKnown bool
+}
+
+// beforePut checks the quota for putting an object of the given size; must be called inside the write transaction.
+// Returns the total size of the objects after the put, see afterPut().
+func (quota *boxQuota) beforePut(box *Box, id uint64, size int) (uint64, error) {
+	bytes, err := quota.check(box, id, size)
+	if exceeded, isExceeded := err.(*QuotaExceededError); isExceeded && quota.onExceeded != nil {
+		if err = quota.onExceeded(box, exceeded); err == nil {
+			bytes, err = quota.check(box, id, size)
+		}
+	}
+	return bytes, err
+}
+
+// afterPut updates the total size once the object has been written
+func (quota *boxQuota) afterPut(bytes uint64) {
+	if quota.MaxBytes == 0 {
+		return
+	}
+	quota.mutex.Lock()
+	quota.bytes = bytes
+	quota.mutex.Unlock()
+}
+
+func (quota *boxQuota) check(box *Box, id uint64, size int) (uint64, error) {
+	// the object may replace a stored one (update)
+	var data unsafe.Pointer
+	var dataSize C.size_t
+	var exists bool
+	if rc := C.obx_box_get(box.cBox, C.obx_id(id), &data, &dataSize); rc == 0 {
+		exists = true
+	} else if rc == C.OBX_NOT_FOUND {
+		dataSize = 0
+	} else {
+		return 0, createError()
+	}
+
+	var result = &QuotaExceededError{Entity: box.entity.name, Quota: quota.BoxQuota}
+	if quota.MaxCount > 0 {
+		var cCount C.uint64_t
+		if err := cCall(func() C.obx_err { return C.obx_box_count(box.cBox, 0, &cCount) }); err != nil {
+			return 0, err
+		}
+		result.Count = uint64(cCount)
+		if !exists {
+			result.Count++
+		}
+	}
+
+	if quota.MaxBytes > 0 {
+		stored, err := quota.storedBytes(box)
+		if err != nil {
+			return 0, err
+		}
+		result.Bytes = stored - uint64(dataSize) + uint64(size)
+	}
+
+	if (quota.MaxCount > 0 && result.Count > quota.MaxCount) || (quota.MaxBytes > 0 && result.Bytes > quota.MaxBytes) {
+		return 0, result
+	}
+	return result.Bytes, nil
+}
+
+// storedBytes returns the total size of the stored objects, reading all of them if it isn't known
+func (quota *boxQuota) storedBytes(box *Box) (uint64, error) {
+	quota.mutex.Lock()
+	defer quota.mutex.Unlock()
+	if quota.bytesKnown {
+		return quota.bytes, nil
+	}
+
+	var total uint64
+	visitor, err := dataVisitorRegister(func(bytes []byte) bool {
+		total += uint64(len(bytes))
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer dataVisitorUnregister(visitor)
+
+	if err = cCall(func() C.obx_err {
+		return C.obx_box_visit_all(box.cBox, dataVisitor, unsafe.Pointer(&visitor))
+	}); err != nil {
+		return 0, err
+	}
+	quota.bytes = total
+	quota.bytesKnown = true
+	return total, nil
+}
+
+// tracksBytes reports whether the quota keeps the total size of the objects, which removals must update; false for a
+// nil quota
+func (quota *boxQuota) tracksBytes() bool {
+	return quota != nil && quota.MaxBytes > 0
+}
+
+// remove calls removeFn to remove the objects with the given IDs and subtracts their size from the total; if the size
+// is tracked, it's read in the same write transaction as the objects are removed
+func (quota *boxQuota) remove(box *Box, ids []uint64, removeFn func() error) error {
+	if !quota.tracksBytes() {
+		return removeFn()
+	}
+
+	return box.ObjectBox.RunInWriteTx(func() error {
+		quota.mutex.Lock()
+		var known = quota.bytesKnown
+		quota.mutex.Unlock()
+
+		var size uint64
+		if known {
+			var seen = make(map[uint64]bool, len(ids))
+			for _, id := range ids {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+
+				var data unsafe.Pointer
+				var dataSize C.size_t
+				if rc := C.obx_box_get(box.cBox, C.obx_id(id), &data, &dataSize); rc == 0 {
+					size += uint64(dataSize)
+				} else if rc != C.OBX_NOT_FOUND {
+					return createError()
+				}
+			}
+		}
+
+		if err := removeFn(); err != nil {
+			return err
+		}
+
+		quota.mutex.Lock()
+		if quota.bytesKnown && quota.bytes >= size {
+			quota.bytes -= size
+		} else {
+			quota.bytesKnown = false
+		}
+		quota.mutex.Unlock()
+		return nil
+	})
+}
+
+// removedAll resets the total size once all objects have been removed; a nil quota is a no-op
+func (quota *boxQuota) removedAll() {
+	if !quota.tracksBytes() {
+		return
+	}
+	quota.mutex.Lock()
+	quota.bytes = 0
+	quota.bytesKnown = true
+	quota.mutex.Unlock()
+}
+
+// invalidate makes the total size to be read again, e.g. after an aborted transaction; a nil quota is a no-op
+func (quota *boxQuota) invalidate() {
+	if quota == nil {
+		return
+	}
+	quota.mutex.Lock()
+	quota.bytesKnown = false
+	quota.mutex.Unlock()
+}
+
+// invalidateQuotas is called when a write transaction has been aborted, discarding the puts tracked by the quotas
+func (ob *ObjectBox) invalidateQuotas() {
+	for _, quota := range ob.quotas {
+		quota.invalidate()
+	}
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// process-wide registry of stores opened using GetOrOpen(), keyed by their (absolute) directory
+var openStores = struct {
+	sync.Mutex
+	byDirectory map[string]*ObjectBox
+}{byDirectory: make(map[string]*ObjectBox)}
+
+// GetOrOpen returns an ObjectBox already opened by GetOrOpen() for the same directory within this process,
+// or builds a new one using the builder returned by builderFn (the directory is set on the builder automatically).
+//
+// Stores returned by this function are reference counted: each call must be matched by a call to ObjectBox.Close()
+// and the underlying store is only closed after the last reference has been released.
+// This allows independent modules of an application to share a store without coordinating its initialization.
+// Returns ErrStoreClosing while the last reference is being released by ObjectBox.CloseWithTimeout().
+func GetOrOpen(directory string, builderFn func() *Builder) (*ObjectBox, error) {
+	if builderFn == nil {
+		return nil, errors.New("builderFn must not be nil")
+	}
+
+	key, err := storeRegistryKey(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	openStores.Lock()
+	defer openStores.Unlock()
+
+	if ob := openStores.byDirectory[key]; ob != nil {
+		// the last reference is being released by CloseWithTimeout()
+		if atomic.LoadInt32(&ob.closing) != 0 {
+			return nil, ErrStoreClosing
+		}
+		ob.refCount++
+		return ob, nil
+	}
+
+	var builder = builderFn()
+	if builder == nil {
+		return nil, errors.New("builderFn returned nil")
+	}
+
+	ob, err := builder.Directory(directory).BuildOrError()
+	if err != nil {
+		return nil, err
+	}
+
+	ob.registryKey = key
+	ob.refCount = 1
+	openStores.byDirectory[key] = ob
+	return ob, nil
+}
+
+// storeRegistryKey normalizes the directory so that different spellings of the same path share a store
+func storeRegistryKey(directory string) (string, error) {
+	// in-memory databases are identified by name, not by a file system path
+	if isInMemoryDirectory(directory) {
+		return directory, nil
+	}
+	return filepath.Abs(directory)
+}
+
+// releaseRegistered decrements the reference count of a store opened by GetOrOpen().
+// Returns true if the store should actually be closed (i.e. this was the last reference).
+func (ob *ObjectBox) releaseRegistered() bool {
+	openStores.Lock()
+	defer openStores.Unlock()
+
+	if ob.registryKey == "" {
+		return true
+	}
+
+	ob.refCount--
+	if ob.refCount > 0 {
+		return false
+	}
+
+	delete(openStores.byDirectory, ob.registryKey)
+	ob.registryKey = ""
+	return true
+}
+
+// startClosingRegistered is like releaseRegistered() but keeps the last reference, marking the store as closing
+// instead; the reference is released by releaseRegistered() once the store is actually closed. This way, a store left
+// open by CloseWithTimeout() stays registered and GetOrOpen() doesn't open the same directory again meanwhile.
+func (ob *ObjectBox) startClosingRegistered() bool {
+	openStores.Lock()
+	defer openStores.Unlock()
+
+	if ob.registryKey != "" && ob.refCount > 1 {
+		ob.refCount--
+		return false
+	}
+
+	atomic.StoreInt32(&ob.closing, 1)
+	return true
+}
+
+// StoreRegistry manages multiple independent stores by name, e.g. a store per tenant, see NewStoreRegistry().
+// Each store is opened once, on first use, and can be looked up by other parts of the application afterwards.
+type StoreRegistry struct {
+	mutex  sync.Mutex
+	stores map[string]*ObjectBox
+}
+
+// NewStoreRegistry creates an empty registry. Close the stores using CloseAll() on shutdown.
+func NewStoreRegistry() *StoreRegistry {
+	return &StoreRegistry{stores: make(map[string]*ObjectBox)}
+}
+
+// Open returns the store registered under the given name, or builds it using the builder returned by builderFn and
+// registers it. Concurrent calls for the same name open the store only once.
+func (registry *StoreRegistry) Open(name string, builderFn func() *Builder) (*ObjectBox, error) {
+	if builderFn == nil {
+		return nil, errors.New("builderFn must not be nil")
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if ob := registry.lookup(name); ob != nil {
+		return ob, nil
+	}
+
+	var builder = builderFn()
+	if builder == nil {
+		return nil, errors.New("builderFn returned nil")
+	}
+
+	ob, err := builder.BuildOrError()
+	if err != nil {
+		return nil, err
+	}
+	registry.stores[name] = ob
+	return ob, nil
+}
+
+// Get returns the store registered under the given name or nil if there's none (or it has been closed).
+func (registry *StoreRegistry) Get(name string) *ObjectBox {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	return registry.lookup(name)
+}
+
+// lookup returns the registered store, dropping it if it has been closed directly; must be called with the mutex
+func (registry *StoreRegistry) lookup(name string) *ObjectBox {
+	var ob = registry.stores[name]
+	if ob != nil && ob.checkOpen() != nil {
+		delete(registry.stores, name)
+		return nil
+	}
+	return ob
+}
+
+// Names returns the names of the registered stores in alphabetical order.
+func (registry *StoreRegistry) Names() []string {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	var names = make([]string, 0, len(registry.stores))
+	for name := range registry.stores {
+		if registry.lookup(name) != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes the store registered under the given name and removes it from the registry.
+// Returns false if there was no such store.
+func (registry *StoreRegistry) Close(name string) bool {
+	registry.mutex.Lock()
+	var ob = registry.lookup(name)
+	delete(registry.stores, name)
+	registry.mutex.Unlock()
+
+	if ob == nil {
+		return false
+	}
+	ob.Close()
+	return true
+}
+
+// CloseAll closes all registered stores and empties the registry.
+func (registry *StoreRegistry) CloseAll() {
+	registry.mutex.Lock()
+	var stores = registry.stores
+	registry.stores = make(map[string]*ObjectBox)
+	registry.mutex.Unlock()
+
+	for _, ob := range stores {
+		ob.Close()
+	}
+}
+
+// Clone attaches a new, independently closable ObjectBox instance to this already open store, e.g. for a part of the
+// application that manages its own lifecycle. Both instances share the same database and model; the underlying store
+// is only closed after all instances have been closed. Boxes and event subscriptions are not shared, i.e. they must
+// be obtained from the returned instance. KV() is only available on the original instance.
+func (ob *ObjectBox) Clone() (*ObjectBox, error) {
+	cOriginal, err := ob.enterStore()
+	if err != nil {
+		return nil, err
+	}
+	defer ob.leave()
+
+	cStore := C.obx_store_clone(cOriginal)
+	if cStore == nil {
+		return nil, createError()
+	}
+
+	clone := &ObjectBox{
+		store:           cStore,
+		directory:       ob.directory,
+		schema:          ob.schema,
+		schemaChanges:   ob.schemaChanges,
+		maxSizeInKb:     ob.maxSizeInKb,
+		maxDataSizeInKb: ob.maxDataSizeInKb,
+		entitiesById:    ob.entitiesById,
+		entitiesByName:  ob.entitiesByName,
+		boxes:           make(map[TypeId]*Box, len(ob.entitiesById)),
+		options:         ob.options,
+		changeLog:       ob.changeLog,
+		contention:      ob.contention,
+		quotas:          ob.quotas,
+		queryCache:      newQueryCache(ob.options.queryCacheSize),
+	}
+	clone.events.now = clone.Now
+	return clone, nil
+}
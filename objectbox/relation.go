@@ -68,7 +68,13 @@ func (relation RelationToOne) alias() *string {
 	return nil
 }
 
-// Link creates a connection and takes inner conditions to evaluate on the linked entity.
+// Link creates a connection and takes inner conditions to evaluate on the linked entity, e.g. orders of a customer:
+//
+//	box.Query(Order_.Customer.Link(Customer_.Name.Equals("ACME", true)))
+//
+// When used in a query of the target entity, it's a backlink, e.g. customers with at least one order above 1000:
+//
+//	customerBox.Query(Order_.Customer.Link(Order_.Total.GreaterThan(1000)))
 func (relation *RelationToOne) Link(conditions ...Condition) Condition {
 	return &conditionRelationOneToMany{relation: relation, conditions: conditions}
 }
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Rows provides query results row by row, with an interface similar to database/sql.Rows. It eases migrating code
+// written against database/sql and allows generic consumers, e.g. report writers, to process ObjectBox queries.
+// See Query.Rows().
+type Rows struct {
+	columns []string
+	types   []int
+	rows    []Projection
+	current int // index of the current row; -1 before the first call to Next()
+	closed  bool
+}
+
+// Rows runs the query and returns the values of the given properties (columns) as Rows, decoded directly from the
+// stored data as with FindProjected(). If no properties are given, all properties supported by FindProjected() are
+// returned, in the order of the model.
+func (query *Query) Rows(properties ...Property) (*Rows, error) {
+	if len(properties) == 0 {
+		for _, p := range query.objectBox.schema.Entities {
+			if p.Id != query.entity.id {
+				continue
+			}
+			for _, info := range p.Properties {
+				var property = &BaseProperty{Id: info.Id, Entity: &Entity{Id: query.entity.id}}
+				if _, err := query.projectedProperty(property); err == nil {
+					properties = append(properties, property)
+				}
+			}
+		}
+	}
+
+	projections, err := query.FindProjected(properties...)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows = &Rows{rows: projections, current: -1}
+	for _, property := range properties {
+		var info = query.objectBox.schemaProperty(property.entityId(), property.propertyId())
+		rows.columns = append(rows.columns, info.Name)
+		rows.types = append(rows.types, info.Type)
+	}
+	return rows, nil
+}
+
+// Columns returns the names of the properties in the order they are scanned.
+func (rows *Rows) Columns() []string {
+	return rows.columns
+}
+
+// Next advances to the next row; returns false if there are no more rows or the Rows have been closed.
+func (rows *Rows) Next() bool {
+	if rows.closed || rows.current+1 >= len(rows.rows) {
+		return false
+	}
+	rows.current++
+	return true
+}
+
+// Err returns the error encountered during the iteration, if any. The results are read by Query.Rows() at once so
+// there are no errors, this is only provided for compatibility with database/sql.Rows.
+func (rows *Rows) Err() error {
+	return nil
+}
+
+// Close releases the results. Next() returns false afterwards.
+func (rows *Rows) Close() error {
+	rows.closed = true
+	rows.rows = nil
+	return nil
+}
+
+// Scan copies the values of the current row into the values pointed at by dest, one per column.
+// Besides pointers to the stored types (see Query.FindProjected()), it supports conversions to other numeric types
+// if the value fits, date properties to *time.Time, strings to *[]byte, *interface{} and sql.Scanner implementations.
+func (rows *Rows) Scan(dest ...interface{}) error {
+	if rows.closed {
+		return errors.New("rows are closed")
+	}
+	if rows.current < 0 || rows.current >= len(rows.rows) {
+		return errors.New("scan called without calling Next")
+	}
+	if len(dest) != len(rows.columns) {
+		return fmt.Errorf("expected %d destination arguments in Scan, not %d", len(rows.columns), len(dest))
+	}
+
+	var row = rows.rows[rows.current]
+	for i, value := range row {
+		if err := scanValue(dest[i], value, rows.types[i]); err != nil {
+			return fmt.Errorf("can't scan column %d (%s): %s", i, rows.columns[i], err)
+		}
+	}
+	return nil
+}
+
+func scanValue(dest interface{}, value interface{}, propertyType int) error {
+	switch d := dest.(type) {
+	case sql.Scanner:
+		return d.Scan(value)
+	case *interface{}:
+		*d = value
+		return nil
+	case *time.Time:
+		var v = reflect.ValueOf(value)
+		if v.Kind() != reflect.Int64 && v.Kind() != reflect.Uint64 {
+			break
+		}
+		var unix = v.Convert(reflect.TypeOf(int64(0))).Int()
+		if propertyType == C.OBXPropertyType_DateNano {
+			*d = time.Unix(0, unix)
+		} else if propertyType == C.OBXPropertyType_Date {
+			*d = time.Unix(0, unix*int64(time.Millisecond))
+		} else {
+			break
+		}
+		return nil
+	case *[]byte:
+		switch v := value.(type) {
+		case []byte:
+			*d = v
+			return nil
+		case string:
+			*d = []byte(v)
+			return nil
+		}
+	}
+
+	var target = reflect.ValueOf(dest)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dest)
+	}
+	target = target.Elem()
+
+	var source = reflect.ValueOf(value)
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var number int64
+		switch source.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number = source.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if source.Uint() > 1<<63-1 {
+				return fmt.Errorf("value %d overflows %s", source.Uint(), target.Type())
+			}
+			number = int64(source.Uint())
+		default:
+			return fmt.Errorf("can't convert %T to %s", value, target.Type())
+		}
+		if target.OverflowInt(number) {
+			return fmt.Errorf("value %d overflows %s", number, target.Type())
+		}
+		target.SetInt(number)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var number uint64
+		switch source.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if source.Int() < 0 {
+				return fmt.Errorf("value %d overflows %s", source.Int(), target.Type())
+			}
+			number = uint64(source.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number = source.Uint()
+		default:
+			return fmt.Errorf("can't convert %T to %s", value, target.Type())
+		}
+		if target.OverflowUint(number) {
+			return fmt.Errorf("value %d overflows %s", number, target.Type())
+		}
+		target.SetUint(number)
+	case reflect.Float32, reflect.Float64:
+		switch source.Kind() {
+		case reflect.Float32, reflect.Float64:
+			target.SetFloat(source.Float())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			target.SetFloat(float64(source.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			target.SetFloat(float64(source.Uint()))
+		default:
+			return fmt.Errorf("can't convert %T to %s", value, target.Type())
+		}
+	default:
+		if !source.Type().AssignableTo(target.Type()) {
+			return fmt.Errorf("can't convert %T to %s", value, target.Type())
+		}
+		target.Set(source)
+	}
+	return nil
+}
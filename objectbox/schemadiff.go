@@ -0,0 +1,248 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SchemaChangeKind identifies the type of a SchemaChange
+type SchemaChangeKind string
+
+const (
+	// SchemaEntityAdded - a new entity (there's no data yet)
+	SchemaEntityAdded SchemaChangeKind = "entityAdded"
+
+	// SchemaEntityRemoved - an entity is not part of the model anymore; its data will be removed
+	SchemaEntityRemoved SchemaChangeKind = "entityRemoved"
+
+	// SchemaEntityRenamed - an entity has a new name but the same UID, the data is kept
+	SchemaEntityRenamed SchemaChangeKind = "entityRenamed"
+
+	// SchemaPropertyAdded - a new property, existing objects will read its zero value
+	SchemaPropertyAdded SchemaChangeKind = "propertyAdded"
+
+	// SchemaPropertyRemoved - a property is not part of the model anymore; its data will be lost
+	SchemaPropertyRemoved SchemaChangeKind = "propertyRemoved"
+
+	// SchemaPropertyRenamed - a property has a new name but the same UID, the data is kept
+	SchemaPropertyRenamed SchemaChangeKind = "propertyRenamed"
+
+	// SchemaPropertyReplaced - a property was removed and another one of the same type was added to the same entity.
+	// This is what an accidental rename looks like: the data of the previous property is lost. To rename a property
+	// instead, annotate the field with `objectbox:"uid"`, run the generator and use the UID it reports.
+	SchemaPropertyReplaced SchemaChangeKind = "propertyReplaced"
+
+	// SchemaPropertyTypeChanged - a property has a different type than before
+	SchemaPropertyTypeChanged SchemaChangeKind = "propertyTypeChanged"
+)
+
+// SchemaChange describes a difference between the model a store was last opened with and the current model,
+// see Builder.SchemaDiff() and ObjectBox.SchemaDiff(). Entities and properties are matched by their UIDs.
+type SchemaChange struct {
+	Kind     SchemaChangeKind
+	Entity   string // the current entity name; the previous one if the entity was removed
+	Property string // the current property name; the previous one if the property was removed; empty for entities
+	Previous string // the previous name (renamed/replaced) or type (type changed)
+	Current  string // the current type (type changed)
+}
+
+func (change SchemaChange) String() string {
+	var element = change.Entity
+	if change.Property != "" {
+		element += "." + change.Property
+	}
+	switch change.Kind {
+	case SchemaEntityRenamed, SchemaPropertyRenamed:
+		return fmt.Sprintf("%s: %s (previously %s)", change.Kind, element, change.Previous)
+	case SchemaPropertyReplaced:
+		return fmt.Sprintf("%s: %s replaces %s, whose data will be lost", change.Kind, element, change.Previous)
+	case SchemaPropertyTypeChanged:
+		return fmt.Sprintf("%s: %s from %s to %s", change.Kind, element, change.Previous, change.Current)
+	}
+	return fmt.Sprintf("%s: %s", change.Kind, element)
+}
+
+// SchemaDiff compares the model configured in this builder with the model the store in the configured directory was
+// last opened with (see ObjectBox.ModelHistory()), without opening the store. Returns nil for new stores and
+// in-memory databases. Use it to review schema changes before applying them, e.g. to catch accidental renames,
+// or configure OnSchemaChange() to check them automatically.
+func (builder *Builder) SchemaDiff() ([]SchemaChange, error) {
+	if builder.Error != nil {
+		return nil, builder.Error
+	}
+	if builder.model == nil {
+		return nil, fmt.Errorf("model is not defined")
+	}
+	return schemaDiff(builder.getDirectory(), builder.model)
+}
+
+// OnSchemaChange sets a function called before opening the store if the model differs from the model the store was
+// last opened with; returning an error aborts opening the store (the error is returned by Build()).
+// This allows to reject unexpected changes, e.g. data loss by removed properties, or to prepare a migration.
+func (builder *Builder) OnSchemaChange(fn func(changes []SchemaChange) error) *Builder {
+	builder.onSchemaChange = fn
+	return builder
+}
+
+// SchemaDiff returns the schema changes detected when this store was opened, i.e. the differences between the model
+// the store was previously opened with and the current one. See Builder.SchemaDiff() for details.
+func (ob *ObjectBox) SchemaDiff() []SchemaChange {
+	return ob.schemaChanges
+}
+
+func schemaDiff(directory string, model *Model) ([]SchemaChange, error) {
+	if isInMemoryDirectory(directory) {
+		return nil, nil
+	}
+
+	history, err := readModelHistory(directory)
+	if err != nil || len(history) == 0 {
+		return nil, err
+	}
+	return diffModelVersions(history[len(history)-1], model.snapshot()), nil
+}
+
+// diffModelVersions lists the differences between the two model versions, matching the elements by UID
+func diffModelVersions(previous, current *ModelVersion) []SchemaChange {
+	var changes []SchemaChange
+
+	var previousEntities = make(map[uint64]*ModelEntityInfo, len(previous.Entities))
+	for _, e := range previous.Entities {
+		previousEntities[e.Uid] = e
+	}
+
+	for _, e := range current.Entities {
+		var old = previousEntities[e.Uid]
+		if old == nil {
+			changes = append(changes, SchemaChange{Kind: SchemaEntityAdded, Entity: e.Name})
+			continue
+		}
+		delete(previousEntities, e.Uid)
+
+		if old.Name != e.Name {
+			changes = append(changes, SchemaChange{Kind: SchemaEntityRenamed, Entity: e.Name, Previous: old.Name})
+		}
+		changes = append(changes, diffEntityProperties(old, e)...)
+	}
+
+	// keep the order of the previous model
+	for _, e := range previous.Entities {
+		if previousEntities[e.Uid] != nil {
+			changes = append(changes, SchemaChange{Kind: SchemaEntityRemoved, Entity: e.Name})
+		}
+	}
+	return changes
+}
+
+func diffEntityProperties(previous, current *ModelEntityInfo) []SchemaChange {
+	var changes []SchemaChange
+
+	var previousProperties = make(map[uint64]*ModelPropertyInfo, len(previous.Properties))
+	for _, p := range previous.Properties {
+		previousProperties[p.Uid] = p
+	}
+
+	var added []*ModelPropertyInfo
+	for _, p := range current.Properties {
+		var old = previousProperties[p.Uid]
+		if old == nil {
+			added = append(added, p)
+			continue
+		}
+		delete(previousProperties, p.Uid)
+
+		if old.Name != p.Name {
+			changes = append(changes, SchemaChange{Kind: SchemaPropertyRenamed, Entity: current.Name, Property: p.Name,
+				Previous: old.Name})
+		}
+		if old.Type != p.Type {
+			changes = append(changes, SchemaChange{Kind: SchemaPropertyTypeChanged, Entity: current.Name,
+				Property: p.Name, Previous: propertyTypeName(old.Type), Current: propertyTypeName(p.Type)})
+		}
+	}
+
+	var removed []*ModelPropertyInfo
+	for _, p := range previous.Properties {
+		if previousProperties[p.Uid] != nil {
+			removed = append(removed, p)
+		}
+	}
+
+	// a removed and an added property of the same type look like an accidental rename
+	for _, p := range added {
+		var replaced = -1
+		for i, old := range removed {
+			if old.Type == p.Type {
+				replaced = i
+				break
+			}
+		}
+		if replaced < 0 {
+			changes = append(changes, SchemaChange{Kind: SchemaPropertyAdded, Entity: current.Name, Property: p.Name})
+		} else {
+			changes = append(changes, SchemaChange{Kind: SchemaPropertyReplaced, Entity: current.Name,
+				Property: p.Name, Previous: removed[replaced].Name})
+			removed = append(removed[:replaced], removed[replaced+1:]...)
+		}
+	}
+
+	for _, p := range removed {
+		changes = append(changes, SchemaChange{Kind: SchemaPropertyRemoved, Entity: current.Name, Property: p.Name})
+	}
+	return changes
+}
+
+var propertyTypeNames = map[int]string{
+	C.OBXPropertyType_Bool:           "Bool",
+	C.OBXPropertyType_Byte:           "Byte",
+	C.OBXPropertyType_Short:          "Short",
+	C.OBXPropertyType_Char:           "Char",
+	C.OBXPropertyType_Int:            "Int",
+	C.OBXPropertyType_Long:           "Long",
+	C.OBXPropertyType_Float:          "Float",
+	C.OBXPropertyType_Double:         "Double",
+	C.OBXPropertyType_String:         "String",
+	C.OBXPropertyType_Date:           "Date",
+	C.OBXPropertyType_Relation:       "Relation",
+	C.OBXPropertyType_DateNano:       "DateNano",
+	C.OBXPropertyType_Flex:           "Flex",
+	C.OBXPropertyType_BoolVector:     "BoolVector",
+	C.OBXPropertyType_ByteVector:     "ByteVector",
+	C.OBXPropertyType_ShortVector:    "ShortVector",
+	C.OBXPropertyType_CharVector:     "CharVector",
+	C.OBXPropertyType_IntVector:      "IntVector",
+	C.OBXPropertyType_LongVector:     "LongVector",
+	C.OBXPropertyType_FloatVector:    "FloatVector",
+	C.OBXPropertyType_DoubleVector:   "DoubleVector",
+	C.OBXPropertyType_StringVector:   "StringVector",
+	C.OBXPropertyType_DateVector:     "DateVector",
+	C.OBXPropertyType_DateNanoVector: "DateNanoVector",
+}
+
+func propertyTypeName(propertyType int) string {
+	if name, found := propertyTypeNames[propertyType]; found {
+		return name
+	}
+	return strconv.Itoa(propertyType)
+}
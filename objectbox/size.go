@@ -0,0 +1,154 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// EstimateSize approximates the heap memory (in bytes) held by the given value, typically a slice of objects
+// returned by Box.GetAll() or Query.Find(). It accounts for the slice backing array, the objects pointed to,
+// strings, byte vectors and other nested data, including objects of eagerly loaded relations.
+// Data referenced multiple times (e.g. interned strings or shared relation targets) is counted only once.
+// The result is an estimate: allocator overhead and memory shared with other values are not considered.
+func EstimateSize(value interface{}) uint64 {
+	if value == nil {
+		return 0
+	}
+	var estimator = sizeEstimator{seen: make(map[uintptr]bool)}
+	var v = reflect.ValueOf(value)
+
+	// the value itself (e.g. the slice header) lives on the heap only if passed by pointer
+	if v.Kind() == reflect.Ptr {
+		return estimator.pointee(v)
+	}
+	return uint64(v.Type().Size()) + estimator.indirect(v)
+}
+
+type sizeEstimator struct {
+	seen map[uintptr]bool // addresses already counted
+}
+
+// firstVisit returns true if the memory at the given address hasn't been counted yet
+func (estimator *sizeEstimator) firstVisit(address uintptr) bool {
+	if address == 0 || estimator.seen[address] {
+		return false
+	}
+	estimator.seen[address] = true
+	return true
+}
+
+// pointee returns the size of the value a non-nil pointer points to, including its indirect data
+func (estimator *sizeEstimator) pointee(v reflect.Value) uint64 {
+	if v.IsNil() || !estimator.firstVisit(v.Pointer()) {
+		return 0
+	}
+	var elem = v.Elem()
+	return uint64(elem.Type().Size()) + estimator.indirect(elem)
+}
+
+// indirect returns the size of the data referenced by the value, excluding the value itself
+func (estimator *sizeEstimator) indirect(v reflect.Value) uint64 {
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() == 0 {
+			return 0
+		}
+		var str = v.String()
+		if !estimator.firstVisit((*reflect.StringHeader)(unsafe.Pointer(&str)).Data) {
+			return 0
+		}
+		return uint64(v.Len())
+
+	case reflect.Slice:
+		if v.IsNil() || v.Cap() == 0 || !estimator.firstVisit(v.Pointer()) {
+			return 0
+		}
+		var size = uint64(v.Cap()) * uint64(v.Type().Elem().Size())
+		if hasIndirectData(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += estimator.indirect(v.Index(i))
+			}
+		}
+		return size
+
+	case reflect.Array:
+		var size uint64
+		if hasIndirectData(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += estimator.indirect(v.Index(i))
+			}
+		}
+		return size
+
+	case reflect.Struct:
+		var size uint64
+		for i := 0; i < v.NumField(); i++ {
+			size += estimator.indirect(v.Field(i))
+		}
+		return size
+
+	case reflect.Ptr:
+		return estimator.pointee(v)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		var elem = v.Elem()
+		if elem.Kind() == reflect.Ptr {
+			return estimator.pointee(elem)
+		}
+		// non-pointer values stored in an interface are boxed on the heap
+		return uint64(elem.Type().Size()) + estimator.indirect(elem)
+
+	case reflect.Map:
+		if v.IsNil() || !estimator.firstVisit(v.Pointer()) {
+			return 0
+		}
+		// a rough approximation of the buckets; the exact layout is a runtime implementation detail
+		var size = uint64(v.Len()) * uint64(v.Type().Key().Size()+v.Type().Elem().Size()+1)
+		for _, key := range v.MapKeys() {
+			size += estimator.indirect(key) + estimator.indirect(v.MapIndex(key))
+		}
+		return size
+	}
+
+	// scalars, functions, channels, ...
+	return 0
+}
+
+// hasIndirectData returns false for types that never reference other memory, e.g. numbers; used to skip element loops
+func hasIndirectData(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return hasIndirectData(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasIndirectData(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
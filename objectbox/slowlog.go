@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// SlowOperation describes a query or a transaction that took longer than the threshold configured by Builder.SlowLog()
+type SlowOperation struct {
+	// Operation is the query method (e.g. "Query.Find" or "Query.Count") or "ReadTx"/"WriteTx" for transactions
+	Operation string
+
+	// Entity is the name of the queried entity; empty for transactions
+	Entity string
+
+	// Description of the query conditions without parameter values, e.g. `(Name == ? AND Age > ?)`,
+	// so a full scan shows up as an empty description or conditions on non-indexed properties; empty for transactions
+	Description string
+
+	// Duration of the operation
+	Duration time.Duration
+
+	// Stack of the goroutine that executed the operation, identifying the caller
+	Stack string
+}
+
+// slowLog holds the configuration of Builder.SlowLog()
+type slowLog struct {
+	threshold time.Duration
+	fn        func(operation SlowOperation)
+}
+
+// SlowLog makes the store report queries (Find, FindIds, Count and Remove) and transactions (including those started
+// by RunInReadTx() and RunInWriteTx()) taking longer than the given threshold, e.g. to surface accidental full scans in
+// production. Each entry contains the query description (without parameter values) and the stack of the caller.
+// The function is called synchronously, on the goroutine executing the operation, after the operation has finished;
+// if it's nil, the entries are written to the Logger set by SetLogger() or, if there's none, using the standard library
+// "log" package. A zero threshold disables the log.
+func (builder *Builder) SlowLog(threshold time.Duration, fn func(operation SlowOperation)) *Builder {
+	if threshold <= 0 {
+		builder.slowLog = nil
+	} else {
+		builder.slowLog = &slowLog{threshold: threshold, fn: fn}
+	}
+	return builder
+}
+
+// observesQueries returns true if a query tracer or a slow log is configured, see Query.observed()
+func (options *options) observesQueries() bool {
+	return options.queryTracer != nil || options.slowLog != nil
+}
+
+// observed reports the finished query operation to the query tracer and/or the slow log
+func (query *Query) observed(operation string, start time.Time, count uint64, err error) {
+	var options = &query.objectBox.options
+	if options.queryTracer != nil {
+		options.queryTracer.trace(query, operation, start, count, err)
+	}
+	if options.slowLog != nil {
+		if duration := time.Since(start); duration >= options.slowLog.threshold {
+			var entry = SlowOperation{Operation: operation, Entity: query.entity.name, Duration: duration}
+			if query.cQuery != nil {
+				// no need to free, it's handled by the cQuery internally
+				entry.Description = redactQueryDescription(C.GoString(C.obx_query_describe_params(query.cQuery)),
+					func(string) bool { return true })
+			}
+			options.slowLog.report(entry)
+		}
+	}
+}
+
+// transactionDone reports the transaction if it took longer than the threshold
+func (slowLog *slowLog) transactionDone(readOnly bool, duration time.Duration) {
+	if duration < slowLog.threshold {
+		return
+	}
+	var entry = SlowOperation{Operation: "WriteTx", Duration: duration}
+	if readOnly {
+		entry.Operation = "ReadTx"
+	}
+	slowLog.report(entry)
+}
+
+func (slowLog *slowLog) report(entry SlowOperation) {
+	entry.Stack = string(debug.Stack())
+	if slowLog.fn != nil {
+		slowLog.fn(entry)
+	} else if logger := getLogger(); logger != nil {
+		logger.Warn("objectbox: slow "+entry.Operation, "operation", entry.Operation, "entity", entry.Entity,
+			"description", entry.Description, "duration", entry.Duration, "stack", entry.Stack)
+	} else if entry.Description != "" {
+		log.Printf("objectbox: slow %s on %s took %v: %s\n%s", entry.Operation, entry.Entity, entry.Duration,
+			entry.Description, entry.Stack)
+	} else {
+		log.Printf("objectbox: slow %s took %v\n%s", entry.Operation, entry.Duration, entry.Stack)
+	}
+}
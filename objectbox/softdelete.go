@@ -0,0 +1,266 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// softDelete holds the soft-delete configuration of an entity, see ObjectBox.SetSoftDelete()
+type softDelete struct {
+	property *PropertyInt64
+	unit     time.Duration // time.Millisecond for "date" properties, time.Nanosecond for "date-nano"
+	field    []int         // index of the struct field, see reflect.Value.FieldByIndex()
+}
+
+// SetSoftDelete enables the soft-delete mode for the entity of the given date property, e.g. `DeletedAt int64`
+// annotated with `objectbox:"date"`, where zero means the object hasn't been removed. In this mode:
+//   - Remove(), RemoveId(), RemoveIds() and RemoveAll() don't delete objects but set the property to the current time
+//     (see Builder.Clock()); the change log records those as updates,
+//   - Query.Remove() marks the matching objects the same way,
+//   - queries created by Query() and QueryOrError() as well as Get(), GetMany(), GetAll(), Contains(), Count(),
+//     IsEmpty() and the Visit*() methods exclude the removed objects; use GetWithDeleted(), QueryWithDeleted() or
+//     QueryDeleted() to include them or to find only them,
+//   - Purge() deletes the removed objects permanently.
+//
+// Note: the mode is only available at runtime, enabled by this call; the generator (a separate module) recognizes
+// no annotation for it and doesn't generate query scopes for it. Enable it right after opening the store, before using
+// the entity.
+func (ob *ObjectBox) SetSoftDelete(property *PropertyInt64) error {
+	var entity = ob.entitiesById[property.entityId()]
+	if entity == nil {
+		return fmt.Errorf("entity %d not found", property.entityId())
+	}
+
+	var info = ob.schemaProperty(entity.id, property.propertyId())
+	if info == nil {
+		return fmt.Errorf("property %d not found in entity %s", property.propertyId(), entity.name)
+	}
+
+	var config = &softDelete{property: property}
+	switch info.Type {
+	case C.OBXPropertyType_Date:
+		config.unit = time.Millisecond
+	case C.OBXPropertyType_DateNano:
+		config.unit = time.Nanosecond
+	default:
+		return fmt.Errorf("property %s.%s is not a date property; annotate it with `objectbox:\"date\"`",
+			entity.name, info.Name)
+	}
+
+	field, err := entity.field(info)
+	if err != nil {
+		return err
+	}
+	// a zero time.Time isn't stored as zero, so it couldn't be told apart from a removed object
+	if field.Type.Kind() != reflect.Int64 {
+		return fmt.Errorf("field %s of %s must be an int64 to hold the deletion time, is %s",
+			field.Name, entity.name, field.Type)
+	}
+	config.field = field.Index
+
+	entity.softDelete = config
+	return nil
+}
+
+// QueryWithDeleted is like QueryOrError() but includes the objects removed in the soft-delete mode, see
+// ObjectBox.SetSoftDelete(). For other entities, it's the same as QueryOrError().
+func (box *Box) QueryWithDeleted(conditions ...Condition) (*Query, error) {
+	return box.query(conditions)
+}
+
+// QueryDeleted is like QueryOrError() but only matches the objects removed in the soft-delete mode, see
+// ObjectBox.SetSoftDelete().
+func (box *Box) QueryDeleted(conditions ...Condition) (*Query, error) {
+	var config = box.entity.softDelete
+	if config == nil {
+		return nil, fmt.Errorf("soft-delete mode is not enabled for entity %s", box.entity.name)
+	}
+	return box.query(append([]Condition{config.property.GreaterThan(0)}, conditions...))
+}
+
+// Purge permanently deletes the objects removed in the soft-delete mode (see ObjectBox.SetSoftDelete()) at or before
+// the given time; returns their number. Pass the current time to delete all of them.
+func (box *Box) Purge(removedBefore time.Time) (count uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.Purge", time.Now(), &err)
+	}
+
+	var config = box.entity.softDelete
+	if config == nil {
+		return 0, fmt.Errorf("soft-delete mode is not enabled for entity %s", box.entity.name)
+	}
+
+	var threshold = removedBefore.UnixNano() / int64(config.unit)
+	query, err := box.query([]Condition{config.property.Between(1, threshold)})
+	if err != nil {
+		return 0, err
+	}
+	defer query.Close()
+
+	if err := query.enter(); err != nil {
+		return 0, err
+	}
+	defer box.ObjectBox.leave()
+	return query.remove()
+}
+
+// softDeleteConditions adds the condition excluding removed objects, if the soft-delete mode is enabled
+func (box *Box) softDeleteConditions(conditions []Condition) []Condition {
+	if config := box.entity.softDelete; config != nil {
+		return append([]Condition{config.property.Equals(0)}, conditions...)
+	}
+	return conditions
+}
+
+// softRemove sets the deletion time on the given objects, skipping missing and already removed ones.
+// Returns the number of removed objects.
+func (box *Box) softRemove(ids []uint64) (count uint64, err error) {
+	var config = box.entity.softDelete
+	var now = box.ObjectBox.Now()
+
+	err = box.ObjectBox.RunInWriteTx(func() error {
+		for _, id := range ids {
+			// read a new instance rather than the one of an identity map, which may be the caller's object and would
+			// keep the deletion time even if the transaction is rolled back
+			var object interface{}
+			if _, err := box.GetBytes(id, func(data []byte) (err error) {
+				object, err = box.entity.load(box.ObjectBox, data)
+				return err
+			}); err != nil {
+				return err
+			} else if object == nil {
+				continue
+			}
+
+			field, err := settableField(object, config.field)
+			if err != nil {
+				return err
+			}
+			if field.Int() != 0 {
+				continue // already removed
+			}
+			field.SetInt(now.UnixNano() / int64(config.unit))
+
+			if _, err := box.put(object, true, cPutModePut); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// softRemoveAll sets the deletion time on all objects not removed yet
+func (box *Box) softRemoveAll() error {
+	return box.ObjectBox.RunInWriteTx(func() error {
+		query, err := box.QueryOrError()
+		if err != nil {
+			return err
+		}
+		defer query.Close()
+
+		ids, err := query.FindIds()
+		if err != nil {
+			return err
+		}
+		_, err = box.softRemove(ids)
+		return err
+	})
+}
+
+// softCount counts the objects not removed, up to the given limit (0 = unlimited)
+func (box *Box) softCount(limit uint64) (uint64, error) {
+	query, err := box.QueryOrError()
+	if err != nil {
+		return 0, err
+	}
+	defer query.Close()
+
+	count, err := query.Count()
+	if limit > 0 && count > limit {
+		count = limit
+	}
+	return count, err
+}
+
+// softGetAll reads all objects not removed
+func (box *Box) softGetAll() (interface{}, error) {
+	query, err := box.QueryOrError()
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+	return query.Find()
+}
+
+// softDeleted reports whether the object has been removed in the soft-delete mode
+func (box *Box) softDeleted(object interface{}) bool {
+	var value = reflect.ValueOf(object)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	return value.FieldByIndex(box.entity.softDelete.field).Int() != 0
+}
+
+// softContains checks whether all of the given objects exist and none of them has been removed
+func (box *Box) softContains(ids []uint64) (contains bool, err error) {
+	err = box.ObjectBox.RunInReadTx(func() error {
+		for _, id := range ids {
+			object, err := box.get(id)
+			if err != nil || object == nil || box.softDeleted(object) {
+				return err
+			}
+		}
+		contains = true
+		return nil
+	})
+	return contains, err
+}
+
+// softFilter excludes the removed objects from a slice read from the database: they're dropped if existingOnly is
+// set, otherwise they're replaced by nil, i.e. the same as a missing object
+func (box *Box) softFilter(slice interface{}, existingOnly bool) interface{} {
+	var value = reflect.ValueOf(slice)
+	var filtered = value
+	if existingOnly {
+		filtered = reflect.MakeSlice(value.Type(), 0, value.Len())
+	}
+	for i := 0; i < value.Len(); i++ {
+		var object = value.Index(i)
+		var missing = object.Kind() == reflect.Ptr && object.IsNil()
+		if !missing && box.softDeleted(object.Interface()) {
+			if !existingOnly {
+				object.Set(reflect.Zero(object.Type()))
+			}
+		} else if existingOnly {
+			filtered = reflect.Append(filtered, object)
+		}
+	}
+	return filtered.Interface()
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// StoreStats provides information about the store size and contents, see ObjectBox.Stats()
+type StoreStats struct {
+	// Directory the store was opened in
+	Directory string
+
+	// SizeOnDisk is the size of the main database file in bytes; 0 for in-memory databases
+	SizeOnDisk uint64
+
+	// MaxSizeInKb is the limit of the database size, see Builder.MaxSizeInKb()
+	MaxSizeInKb uint64
+
+	// MaxDataSizeInKb is the limit of the data stored in the database; 0 if not limited
+	MaxDataSizeInKb uint64
+
+	// EntityCounts maps entity names to the number of stored objects
+	EntityCounts map[string]uint64
+}
+
+// SizeUsage returns the size on disk relative to the maximum database size, i.e. a value between 0 and 1.
+// Note that the database file doesn't shrink after removing data - the space is reused by subsequent writes.
+func (stats *StoreStats) SizeUsage() float64 {
+	if stats.MaxSizeInKb == 0 {
+		return 0
+	}
+	return float64(stats.SizeOnDisk) / float64(stats.MaxSizeInKb*1024)
+}
+
+// SizeOnDisk returns the size of the main database file in bytes; 0 for in-memory databases.
+func (ob *ObjectBox) SizeOnDisk() uint64 {
+	if isInMemoryDirectory(ob.directory) {
+		return 0
+	}
+
+	var cDir = C.CString(ob.directory)
+	defer C.free(unsafe.Pointer(cDir))
+	return uint64(C.obx_db_file_size(cDir))
+}
+
+// Stats collects information about the store, e.g. for monitoring and capacity planning.
+// The object counts of all entities are read in a single read transaction.
+func (ob *ObjectBox) Stats() (*StoreStats, error) {
+	var stats = &StoreStats{
+		Directory:       ob.directory,
+		SizeOnDisk:      ob.SizeOnDisk(),
+		MaxSizeInKb:     ob.maxSizeInKb,
+		MaxDataSizeInKb: ob.maxDataSizeInKb,
+		EntityCounts:    make(map[string]uint64, len(ob.entitiesById)),
+	}
+
+	var err = ob.RunInReadTx(func() error {
+		for id, entity := range ob.entitiesById {
+			count, err := ob.InternalBox(id).Count()
+			if err != nil {
+				return err
+			}
+			stats.EntityCounts[entity.name] = count
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -72,10 +72,16 @@ func NewSyncClient(ob *ObjectBox, serverUri string, credentials *SyncCredentials
 		}
 	}()
 
+	cStore, err := ob.enterStore()
+	if err != nil {
+		return nil, err
+	}
+	defer ob.leave()
+
 	err = cCallBool(func() bool {
 		var cUri = C.CString(serverUri)
 		defer C.free(unsafe.Pointer(cUri))
-		client.cClient = C.obx_sync(ob.store, cUri)
+		client.cClient = C.obx_sync(cStore, cUri)
 		return client.cClient != nil
 	})
 
@@ -102,10 +108,12 @@ func (client *SyncClient) Close() error {
 
 	client.ob.syncClient = nil
 
-	return cCall(func() C.obx_err {
+	var err = cCall(func() C.obx_err {
 		defer func() { client.cClient = nil }()
 		return C.obx_sync_close(client.cClient)
 	})
+	client.ob.events.emit(StoreEvent{Kind: StoreSyncStateChanged, SyncState: SyncClientStateDead})
+	return err
 }
 
 // IsClosed returns true if this sync client is closed and can no longer be used.
@@ -182,9 +190,13 @@ func (client *SyncClient) State() SyncClientState {
 // Start initiates the connection to the server and begins the synchronization
 func (client *SyncClient) Start() error {
 	client.started = true
-	return cCall(func() C.obx_err {
+	var err = cCall(func() C.obx_err {
 		return C.obx_sync_start(client.cClient)
 	})
+	if err == nil {
+		client.ob.events.emit(StoreEvent{Kind: StoreSyncStateChanged, SyncState: client.State()})
+	}
+	return err
 }
 
 // Stop stops the synchronization and closes the connection to the server Does nothing if it is already stopped.
@@ -193,6 +205,9 @@ func (client *SyncClient) Stop() error {
 		return C.obx_sync_stop(client.cClient)
 	})
 	client.started = false
+	if err == nil {
+		client.ob.events.emit(StoreEvent{Kind: StoreSyncStateChanged, SyncState: client.State()})
+	}
 	return err
 }
 
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ScopedBox restricts a Box to the objects of a single tenant, see Box.Scoped().
+// Queries only match the tenant's objects and writes are rejected if they'd create or modify another tenant's object.
+type ScopedBox struct {
+	box       *Box
+	tenantId  interface{} // normalized: string or int64
+	property  projectedProperty
+	name      string
+	condition Condition
+}
+
+// isTenantPropertyType reports whether a property of the given type can identify a tenant: a string, integer or
+// relation property
+func isTenantPropertyType(propertyType int) bool {
+	switch propertyType {
+	case C.OBXPropertyType_String, C.OBXPropertyType_Byte, C.OBXPropertyType_Short, C.OBXPropertyType_Int,
+		C.OBXPropertyType_Long, C.OBXPropertyType_Relation:
+		return true
+	}
+	return false
+}
+
+// Scoped returns a view of the box limited to the objects whose given property, identifying the tenant owning an
+// object, equals tenantId. The property must be a string, integer or relation property, e.g. declared as
+//
+//	TenantId string `objectbox:"index"`
+//
+// The tenantId must be a string for a string property and an integer otherwise. Index the property to keep the scoped
+// queries fast.
+func (box *Box) Scoped(property Property, tenantId interface{}) (*ScopedBox, error) {
+	if property.entityId() != box.entity.id {
+		return nil, fmt.Errorf("property from a different entity %d passed, expected %d",
+			property.entityId(), box.entity.id)
+	}
+
+	var info = box.ObjectBox.schemaProperty(box.entity.id, property.propertyId())
+	if info == nil {
+		return nil, fmt.Errorf("property %d not found in entity %s", property.propertyId(), box.entity.name)
+	}
+	if !isTenantPropertyType(info.Type) {
+		return nil, fmt.Errorf("property %s of type %s can't identify a tenant", info.Name, propertyTypeName(info.Type))
+	}
+
+	var scoped = &ScopedBox{
+		box:      box,
+		name:     info.Name,
+		property: newProjectedProperty(info),
+	}
+
+	var base = &BaseProperty{Id: info.Id, Entity: &Entity{Id: box.entity.id}}
+	if info.Type == C.OBXPropertyType_String {
+		text, ok := tenantId.(string)
+		if !ok {
+			return nil, fmt.Errorf("tenant ID for the string property %s must be a string, got %T", info.Name, tenantId)
+		}
+		scoped.tenantId = text
+
+		// queries match the stored value, i.e. encoded if the property has a codec
+		stored, err := box.entity.codec.encodeValue(info.Id, text)
+		if err != nil {
+			return nil, err
+		}
+		scoped.condition = PropertyString{base}.Equals(stored.(string), true)
+	} else {
+		value, ok := integerValue(tenantId)
+		if !ok {
+			return nil, fmt.Errorf("tenant ID for the integer property %s must be an integer, got %T",
+				info.Name, tenantId)
+		}
+		scoped.tenantId = value
+		scoped.condition = PropertyInt64{base}.Equals(value)
+	}
+	return scoped, nil
+}
+
+// Box returns the underlying, unrestricted box.
+func (scoped *ScopedBox) Box() *Box {
+	return scoped.box
+}
+
+// TenantId returns the tenant this box is scoped to; integers are returned as int64.
+func (scoped *ScopedBox) TenantId() interface{} {
+	return scoped.tenantId
+}
+
+// Query creates a query limited to the tenant's objects, with the given conditions combined using AND.
+func (scoped *ScopedBox) Query(conditions ...Condition) *Query {
+	query, err := scoped.QueryOrError(conditions...)
+	if err != nil {
+		panic(err)
+	}
+	return query
+}
+
+// QueryOrError is like Query() but with error handling; e.g. when you build conditions dynamically that may fail.
+func (scoped *ScopedBox) QueryOrError(conditions ...Condition) (*Query, error) {
+	return scoped.box.QueryOrError(scoped.scopedCondition(conditions))
+}
+
+func (scoped *ScopedBox) scopedCondition(conditions []Condition) Condition {
+	if len(conditions) == 0 {
+		return scoped.condition
+	}
+	return All(append([]Condition{scoped.condition}, conditions...)...)
+}
+
+// Get reads the object with the given ID; returns nil (and no error) if it doesn't exist or belongs to another tenant.
+func (scoped *ScopedBox) Get(id uint64) (interface{}, error) {
+	object, err := scoped.box.Get(id)
+	if err != nil || object == nil {
+		return nil, err
+	}
+	if owned, err := scoped.owns(object, id); err != nil || !owned {
+		return nil, err
+	}
+	return object, nil
+}
+
+// GetAll reads all the tenant's objects.
+func (scoped *ScopedBox) GetAll() (interface{}, error) {
+	query, err := scoped.QueryOrError()
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+	return query.Find()
+}
+
+// Count returns the number of the tenant's objects.
+func (scoped *ScopedBox) Count() (uint64, error) {
+	query, err := scoped.QueryOrError()
+	if err != nil {
+		return 0, err
+	}
+	defer query.Close()
+	return query.Count()
+}
+
+// Put inserts/updates an object of the tenant. Fails if the object's tenant property doesn't match or if it would
+// overwrite an existing object of another tenant.
+func (scoped *ScopedBox) Put(object interface{}) (id uint64, err error) {
+	err = scoped.box.ObjectBox.RunInWriteTx(func() error {
+		if err := scoped.checkPut(object); err != nil {
+			return err
+		}
+		id, err = scoped.box.Put(object)
+		return err
+	})
+	return id, err
+}
+
+// PutMany inserts/updates objects of the tenant in a single transaction; fails without changes if any object is
+// rejected the same way as by Put().
+func (scoped *ScopedBox) PutMany(objects interface{}) (ids []uint64, err error) {
+	var slice = reflect.ValueOf(objects)
+	if slice.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("expected a slice, got %T", objects)
+	}
+
+	err = scoped.box.ObjectBox.RunInWriteTx(func() error {
+		for i := 0; i < slice.Len(); i++ {
+			if err := scoped.checkPut(slice.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		ids, err = scoped.box.PutMany(objects)
+		return err
+	})
+	return ids, err
+}
+
+// RemoveId removes the tenant's object with the given ID; fails if it doesn't exist or belongs to another tenant.
+func (scoped *ScopedBox) RemoveId(id uint64) error {
+	return scoped.box.ObjectBox.RunInWriteTx(func() error {
+		object, err := scoped.Get(id)
+		if err != nil {
+			return err
+		}
+		if object == nil {
+			return fmt.Errorf("object with ID %d not found", id)
+		}
+		return scoped.box.RemoveId(id)
+	})
+}
+
+// RemoveAll removes all the tenant's objects, keeping those of other tenants; returns their number.
+func (scoped *ScopedBox) RemoveAll() (uint64, error) {
+	query, err := scoped.QueryOrError()
+	if err != nil {
+		return 0, err
+	}
+	defer query.Close()
+	return query.Remove()
+}
+
+// checkPut verifies the object and the existing object with the same ID, if any, belong to the tenant
+func (scoped *ScopedBox) checkPut(object interface{}) error {
+	id, err := scoped.box.entity.binding.GetId(object)
+	if err != nil {
+		return err
+	}
+
+	if owned, err := scoped.owns(object, id); err != nil {
+		return err
+	} else if !owned {
+		return fmt.Errorf("object's %s doesn't match the tenant %v", scoped.name, scoped.tenantId)
+	}
+
+	if id != 0 {
+		existing, err := scoped.box.get(id) // including objects removed in the soft-delete mode
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if owned, err := scoped.owns(existing, id); err != nil {
+				return err
+			} else if !owned {
+				return fmt.Errorf("object with ID %d belongs to another tenant", id)
+			}
+		}
+	}
+	return nil
+}
+
+// owns reports whether the object's tenant property matches the tenant
+func (scoped *ScopedBox) owns(object interface{}, id uint64) (bool, error) {
+	value, err := scoped.box.objectProperty(object, id, scoped.property)
+	if err != nil {
+		return false, err
+	}
+	if text, isString := value.(string); isString {
+		return text == scoped.tenantId, nil
+	}
+	number, _ := integerValue(value)
+	return number == scoped.tenantId, nil
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"github.com/objectbox/objectbox-go/objectbox/obxerr"
+	"runtime"
+	"sync/atomic"
+)
+
+// ThreadPinning defines when a goroutine is locked to its OS thread (runtime.LockOSThread()) around native calls.
+//
+// The native library keeps the error information of the last failed call in thread-local storage. To read it reliably,
+// the goroutine must not be moved to another OS thread between the failed call and reading the error.
+// Pinning itself is cheap (a few nanoseconds, see BenchmarkLockOsThread) but with many goroutines doing native calls
+// concurrently, the runtime can't move them freely between threads which may increase scheduler churn.
+//
+// Transactions are always pinned for their whole duration, regardless of the policy; so are all calls made inside one.
+type ThreadPinning int32
+
+const (
+	// ThreadPinningAlways locks the goroutine to its OS thread for each native call. The error message of a failed
+	// call is always accurate. This is the default.
+	ThreadPinningAlways ThreadPinning = iota
+
+	// ThreadPinningOnError executes native calls without pinning; the goroutine is only locked after a call has failed,
+	// in order to read the error information. If the goroutine has been moved to another thread in the meantime, the
+	// error information isn't available anymore: a generic error containing the error code (if known) is returned
+	// instead of the native error message. Use this for highly concurrent workloads where errors are rare and their
+	// exact message is less important than scheduler throughput.
+	ThreadPinningOnError
+)
+
+func (policy ThreadPinning) String() string {
+	switch policy {
+	case ThreadPinningAlways:
+		return "Always"
+	case ThreadPinningOnError:
+		return "OnError"
+	default:
+		return fmt.Sprintf("ThreadPinning(%d)", int32(policy))
+	}
+}
+
+// threadPinning holds the current ThreadPinning policy, accessed atomically
+var threadPinning int32
+
+// SetThreadPinning sets the policy used for all stores in this process; see ThreadPinning for the tradeoffs.
+// It may be changed at any time; calls already in progress finish using the previous policy.
+func SetThreadPinning(policy ThreadPinning) {
+	atomic.StoreInt32(&threadPinning, int32(policy))
+}
+
+// GetThreadPinning returns the currently configured policy, see SetThreadPinning().
+func GetThreadPinning() ThreadPinning {
+	return ThreadPinning(atomic.LoadInt32(&threadPinning))
+}
+
+// pinThread locks the goroutine to its OS thread if required by the current policy; returns whether it has done so.
+func pinThread() bool {
+	if ThreadPinning(atomic.LoadInt32(&threadPinning)) == ThreadPinningOnError {
+		return false
+	}
+	runtime.LockOSThread()
+	return true
+}
+
+func unpinThread(pinned bool) {
+	if pinned {
+		runtime.UnlockOSThread()
+	}
+}
+
+// createCallError returns the error of a failed native call, see createError().
+// If the call wasn't executed pinned, the thread-local error information may belong to a different call: the error
+// code (rc) is used to verify it, if known (non-zero).
+func createCallError(pinned bool, rc C.obx_err) error {
+	if pinned {
+		return createError()
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var lastCode C.obx_err
+	var lastMessage *C.char
+	var secondary = C.obx_last_error_secondary() // obx_last_error_pop() clears the error state
+	if !C.obx_last_error_pop(&lastCode, &lastMessage) || (rc != 0 && lastCode != rc) || lastMessage == nil {
+		if rc != 0 {
+			return &obxerr.Error{Code: int(rc), Message: fmt.Sprintf("native call failed with error code %d; "+
+				"error details are not available with ThreadPinningOnError", int(rc))}
+		}
+		return fmt.Errorf("native call failed; error details are not available with ThreadPinningOnError")
+	}
+	return &obxerr.Error{Code: int(lastCode), Secondary: int(secondary), Message: C.GoString(lastMessage)}
+}
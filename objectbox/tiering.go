@@ -0,0 +1,242 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Tiering splits the objects of an entity by a date property between a primary store, keeping recent objects, and
+// an archive store for older ones, see NewTiering(). Use Archive() to move objects to the archive and Find() to query
+// a date range transparently across both stores.
+type Tiering struct {
+	primary   *Box
+	archive   *Box
+	property  *PropertyInt64
+	unit      time.Duration // time.Millisecond for "date" properties, time.Nanosecond for "date-nano"
+	batchSize uint64
+}
+
+// NewTiering creates a tiering helper for the entity of the given date property. Both stores must be opened with the
+// same model, e.g. an archive in a separate directory, possibly on a slower but larger disk.
+//
+// Objects keep their IDs when moved to the archive, therefore the entity's ID must be self-assignable (annotate it
+// with `objectbox:"id(assignable)"`) and IDs must not be reused in the primary store.
+func NewTiering(primary, archive *ObjectBox, date *PropertyInt64) (*Tiering, error) {
+	var info = primary.schemaProperty(date.entityId(), date.propertyId())
+	if info == nil {
+		return nil, fmt.Errorf("property %d of entity %d not found in the model", date.Id, date.Entity.Id)
+	}
+
+	if entity := primary.getEntityById(date.entityId()); !entity.idSelfAssignable {
+		return nil, fmt.Errorf("can't archive objects of entity %s, its ID isn't self-assignable; "+
+			"annotate the ID field with `objectbox:\"id(assignable)\"`", entity.name)
+	}
+
+	var tiering = &Tiering{property: date, batchSize: defaultExpirerBatchSize}
+	switch info.Type {
+	case C.OBXPropertyType_Date:
+		tiering.unit = time.Millisecond
+	case C.OBXPropertyType_DateNano:
+		tiering.unit = time.Nanosecond
+	default:
+		return nil, fmt.Errorf("property %s is not a date property; annotate it with `objectbox:\"date\"`", info.Name)
+	}
+
+	if archived := archive.schemaProperty(date.entityId(), date.propertyId()); archived == nil ||
+		archived.Uid != info.Uid || archived.Type != info.Type {
+		return nil, fmt.Errorf("the archive store doesn't have the same model as the primary one")
+	}
+
+	var err error
+	if tiering.primary, err = primary.BoxOrError(date.entityId()); err != nil {
+		return nil, err
+	}
+	if tiering.archive, err = archive.BoxOrError(date.entityId()); err != nil {
+		return nil, err
+	}
+	return tiering, nil
+}
+
+// BatchSize sets the maximum number of objects moved in a single step by Archive() (default: 1000).
+func (tiering *Tiering) BatchSize(size uint64) *Tiering {
+	if size > 0 {
+		tiering.batchSize = size
+	}
+	return tiering
+}
+
+// Archive moves all objects dated before the given time from the primary store to the archive; returns their number.
+//
+// Objects are put to the archive before they're removed from the primary store, batch by batch. The stores don't
+// share transactions so if the process is interrupted, a batch may be present in both stores, but it's never lost;
+// archiving again replaces the archived copies (same IDs) and removes the batch from the primary store.
+func (tiering *Tiering) Archive(before time.Time) (moved uint64, err error) {
+	query, err := tiering.primary.QueryOrError(tiering.property.LessThan(before.UnixNano() / int64(tiering.unit)))
+	if err != nil {
+		return 0, err
+	}
+	defer query.Close()
+	query.Limit(tiering.batchSize)
+
+	var binding = tiering.primary.entity.binding
+	for {
+		objects, err := query.Find()
+		if err != nil {
+			return moved, err
+		}
+
+		var slice = reflect.ValueOf(objects)
+		var ids = make([]uint64, slice.Len())
+		for i := range ids {
+			var object = slice.Index(i).Interface()
+			if ids[i], err = binding.GetId(object); err != nil {
+				return moved, err
+			}
+		}
+
+		if len(ids) > 0 {
+			if _, err = tiering.archive.PutMany(objects); err != nil {
+				return moved, err
+			}
+			if _, err = tiering.primary.RemoveIds(ids...); err != nil {
+				return moved, err
+			}
+			moved += uint64(len(ids))
+		}
+
+		if uint64(len(ids)) < tiering.batchSize {
+			return moved, nil
+		}
+	}
+}
+
+// Find returns the objects dated within the given range (inclusive) and matching the given conditions, ordered by
+// the date. The archive is queried whenever the range starts before the newest archived object; that date is read
+// from the archive itself, so it's kept across restarts. Objects present in both stores, e.g. after an interrupted
+// Archive(), are only returned once, preferring the primary copy.
+// Returns a slice of objects that should be cast to the appropriate type.
+func (tiering *Tiering) Find(from, to time.Time, conditions ...Condition) (objects interface{}, err error) {
+	var fromValue = from.UnixNano() / int64(tiering.unit)
+	var toValue = to.UnixNano() / int64(tiering.unit)
+	conditions = append(conditions, tiering.property.Between(fromValue, toValue), tiering.property.OrderAsc())
+
+	recent, recentDates, err := tiering.find(tiering.primary, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	end, found, err := tiering.archiveEnd()
+	if err != nil {
+		return nil, err
+	} else if !found || fromValue > end {
+		return recent.Interface(), nil
+	}
+
+	archived, archivedDates, err := tiering.find(tiering.archive, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	var binding = tiering.primary.entity.binding
+	var recentIds = make(map[uint64]bool, recent.Len())
+	for i := 0; i < recent.Len(); i++ {
+		id, err := binding.GetId(recent.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		recentIds[id] = true
+	}
+
+	// merge both slices by the date; on equal dates, archived objects come first
+	objects = binding.MakeSlice(archived.Len() + recent.Len())
+	var a, r = 0, 0
+	for a < archived.Len() || r < recent.Len() {
+		if r == recent.Len() || (a < archived.Len() && archivedDates[a] <= recentDates[r]) {
+			var object = archived.Index(a).Interface()
+			a++
+			id, err := binding.GetId(object)
+			if err != nil {
+				return nil, err
+			} else if !recentIds[id] {
+				objects = binding.AppendToSlice(objects, object)
+			}
+		} else {
+			objects = binding.AppendToSlice(objects, recent.Index(r).Interface())
+			r++
+		}
+	}
+	return objects, nil
+}
+
+// find returns the objects matching the conditions together with their dates, read in a single transaction
+func (tiering *Tiering) find(box *Box, conditions []Condition) (objects reflect.Value, dates []int64, err error) {
+	query, err := box.QueryOrError(conditions...)
+	if err != nil {
+		return objects, nil, err
+	}
+	defer query.Close()
+
+	err = box.ObjectBox.RunInReadTx(func() error {
+		found, err := query.Find()
+		if err != nil {
+			return err
+		}
+		projections, err := query.FindProjected(tiering.property)
+		if err != nil {
+			return err
+		}
+
+		objects = reflect.ValueOf(found)
+		if objects.Len() != len(projections) {
+			return fmt.Errorf("query returned %d objects but %d dates", objects.Len(), len(projections))
+		}
+		dates = make([]int64, len(projections))
+		for i, projection := range projections {
+			dates[i] = tiering.date(projection)
+		}
+		return nil
+	})
+	return objects, dates, err
+}
+
+// archiveEnd returns the date of the newest object in the archive; found=false if it's empty
+func (tiering *Tiering) archiveEnd() (end int64, found bool, err error) {
+	query, err := tiering.archive.QueryOrError(tiering.property.OrderDesc())
+	if err != nil {
+		return 0, false, err
+	}
+	defer query.Close()
+	query.Limit(1)
+
+	projections, err := query.FindProjected(tiering.property)
+	if err != nil || len(projections) == 0 {
+		return 0, false, err
+	}
+	return tiering.date(projections[0]), true, nil
+}
+
+func (tiering *Tiering) date(projection Projection) int64 {
+	return reflect.ValueOf(projection[0]).Convert(reflect.TypeOf(int64(0))).Int()
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Transfer copies all objects from srcBox to dstBox, optionally transforming them on the way, and returns the number
+// of objects written. The boxes may belong to different entities and even to different stores, e.g. when splitting an
+// entity during a migration or copying data to a new store.
+//
+// The objects are read and written in batches of batchSize (1000 if zero or negative); each batch is read in a read
+// transaction and written in a write transaction of the destination store so memory usage stays bounded.
+// If an error occurs, objects written by previous batches stay in the destination box.
+//
+// The transform function receives an object read from srcBox and returns the object to put to dstBox; returning nil
+// skips the object. If transform is nil, objects are copied as they are, which requires both boxes to have the same
+// entity type. If the ID of the destination entity is self-assignable (`objectbox:"id(assignable)"`), IDs are kept
+// unless the transform function resets them to 0; otherwise the destination box assigns new IDs to all objects
+// because it only accepts IDs it has issued itself.
+func Transfer(srcBox, dstBox *Box, transform func(src interface{}) interface{}, batchSize int) (count uint64, err error) {
+	if srcBox == nil || dstBox == nil {
+		return 0, errors.New("source and destination boxes must not be nil")
+	}
+	if transform == nil && srcBox.objectType() != dstBox.objectType() {
+		return 0, errors.New("can't transfer objects between boxes of different types without a transform function")
+	}
+	if batchSize <= 0 {
+		batchSize = importBatchSize
+	}
+
+	// collect the IDs first so that there's no read transaction open while writing (e.g. to the same store)
+	query, err := srcBox.QueryOrError()
+	if err != nil {
+		return 0, err
+	}
+	ids, err := query.FindIds()
+	if closeErr := query.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var dstBinding = dstBox.entity.binding
+	for start := 0; start < len(ids); start += batchSize {
+		var end = start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		objects, err := srcBox.GetManyExisting(ids[start:end]...)
+		if err != nil {
+			return count, err
+		}
+
+		var slice = reflect.ValueOf(objects)
+		var batch = dstBinding.MakeSlice(slice.Len())
+		var batchLen = 0
+		for i := 0; i < slice.Len(); i++ {
+			var object = slice.Index(i).Interface()
+			if transform != nil {
+				if object = transform(object); object == nil {
+					continue
+				}
+			}
+			if !dstBox.entity.idSelfAssignable {
+				if err := dstBinding.SetId(object, 0); err != nil {
+					return count, err
+				}
+			}
+			batch = dstBinding.AppendToSlice(batch, object)
+			batchLen++
+		}
+
+		if batchLen > 0 {
+			if _, err := dstBox.PutMany(batch); err != nil {
+				return count, err
+			}
+			count += uint64(batchLen)
+		}
+	}
+	return count, nil
+}
+
+// Archive moves all objects matching the query to archiveBox, typically a box of the same entity in a separate store
+// (e.g. in a different directory), keeping the primary store small. Returns the number of moved objects.
+//
+// Objects are moved in batches of batchSize (1000 if zero or negative). For each batch, a write transaction of the
+// primary store reads the objects, writes them to the archive (committed in its own transaction first) and removes
+// them from the primary store. If the process is interrupted, nothing is lost: objects already archived but not yet
+// removed are written again (under the same ID) when Archive is called again, i.e. archiving is resumable.
+// After each batch, progress (if not nil) is called with the total number of objects moved so far; returning false
+// stops archiving.
+//
+// Objects keep their IDs so the entity must have a self-assignable ID (`objectbox:"id(assignable)"`); other entities
+// are rejected because their IDs can't be put to the archive store.
+//
+// Note: Archive overrides the offset and limit previously set on the query; both are reset to 0 when it returns.
+func Archive(query *Query, archiveBox *Box, batchSize int, progress func(moved uint64) bool) (moved uint64, err error) {
+	if query == nil || archiveBox == nil {
+		return 0, errors.New("query and archive box must not be nil")
+	}
+	if query.box.objectType() != archiveBox.objectType() {
+		return 0, errors.New("can't archive objects to a box of a different type")
+	}
+	if !archiveBox.entity.idSelfAssignable {
+		return 0, fmt.Errorf("can't archive objects of entity %s, its ID isn't self-assignable; "+
+			"annotate the ID field with `objectbox:\"id(assignable)\"`", archiveBox.entity.name)
+	}
+	if batchSize <= 0 {
+		batchSize = importBatchSize
+	}
+
+	if err := query.check(); err != nil {
+		return 0, err
+	}
+
+	defer query.Offset(0).Limit(0)
+	query.Offset(0).Limit(uint64(batchSize))
+
+	var binding = query.box.entity.binding
+	for {
+		var count int
+		err = query.objectBox.RunInWriteTx(func() error {
+			objects, err := query.find()
+			if err != nil {
+				return err
+			}
+
+			var slice = reflect.ValueOf(objects)
+			if count = slice.Len(); count == 0 {
+				return nil
+			}
+
+			var ids = make([]uint64, count)
+			for i := range ids {
+				if ids[i], err = binding.GetId(slice.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+
+			if _, err := archiveBox.PutMany(objects); err != nil {
+				return err
+			}
+			_, err = query.box.RemoveIds(ids...)
+			return err
+		})
+		if err != nil {
+			return moved, err
+		}
+		moved += uint64(count)
+
+		if count > 0 && progress != nil && !progress(moved) {
+			return moved, nil
+		}
+		if count < batchSize {
+			return moved, nil
+		}
+	}
+}
@@ -0,0 +1,485 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/flatbuffers/go"
+	"strings"
+	"unsafe"
+)
+
+// TreeOptions configure a Tree, see NewTree()
+type TreeOptions struct {
+	// PathDelimiter separates the branch names in a path; '/' if zero
+	PathDelimiter byte
+
+	// EnforceUniquePath prevents a path from addressing a branch and a leaf at the same time, e.g. "a/b" being a leaf
+	// while "a/b/c" exists; by default both may exist
+	EnforceUniquePath bool
+}
+
+// Tree provides access to hierarchical key-value data: leaves holding a value, organized in branches and addressed
+// by paths, e.g. "device/sensor/threshold". Branches are created on the fly when putting a leaf.
+//
+// The tree is stored in four entities which must be part of the model (i.e. defined in your package and generated):
+//
+//	type DataBranch struct {
+//		Id           uint64
+//		Uid          string `objectbox:"unique"`
+//		ParentId     uint64 `objectbox:"link:DataBranch"`
+//		MetaBranchId uint64 `objectbox:"link:MetaBranch"`
+//	}
+//
+//	type MetaBranch struct {
+//		Id          uint64
+//		ParentId    uint64 `objectbox:"link:MetaBranch"`
+//		Name        string
+//		Description string
+//	}
+//
+//	type DataLeaf struct {
+//		Id           uint64
+//		DataBranchId uint64 `objectbox:"link:DataBranch"`
+//		MetaLeafId   uint64 `objectbox:"link:MetaLeaf"`
+//		ValueInt     int64
+//		ValueDouble  float64
+//		ValueString  string
+//	}
+//
+//	type MetaLeaf struct {
+//		Id          uint64
+//		BranchId    uint64 `objectbox:"link:MetaBranch"`
+//		Name        string
+//		Description string
+//		ValueType   int16
+//	}
+type Tree struct {
+	objectBox   *ObjectBox
+	cTree       *C.OBX_tree
+	delimiter   byte
+	dataLeaf    *ModelEntityInfo
+	metaLeaf    *ModelEntityInfo
+	dataLeafBox *C.OBX_box
+
+	// FlatBuffers slots of the DataLeaf and MetaLeaf properties
+	leafId      int
+	valueInt    int
+	valueDouble int
+	valueString int
+	metaName    int
+	metaType    int
+
+	// slots of the IDs and relations, which must be present (zero) when putting a leaf
+	dataLeafIds []int
+	metaLeafIds []int
+}
+
+// TreeLeaf is a value stored in a Tree
+type TreeLeaf struct {
+	Id    uint64
+	Path  string
+	Value interface{} // int64, float64 or string
+}
+
+// NewTree opens the tree stored in the given store, see Tree for the required entities.
+// Close the tree when it's not needed anymore, before closing the store.
+func NewTree(ob *ObjectBox, options TreeOptions) (*Tree, error) {
+	cStore, err := ob.enterStore()
+	if err != nil {
+		return nil, err
+	}
+	defer ob.leave()
+
+	var tree = &Tree{objectBox: ob, delimiter: '/'}
+	if options.PathDelimiter != 0 {
+		tree.delimiter = options.PathDelimiter
+	}
+	if err := tree.resolveModel(); err != nil {
+		return nil, err
+	}
+
+	var cOptions = C.obx_tree_options()
+	if cOptions == nil {
+		return nil, createError()
+	}
+
+	var flags uint32
+	if options.EnforceUniquePath {
+		flags |= C.OBXTreeOptionFlags_EnforceUniquePath
+	}
+	if err := cCall(func() C.obx_err { return C.obx_tree_opt_flags(cOptions, C.uint32_t(flags)) }); err != nil {
+		C.obx_tree_options_free(cOptions)
+		return nil, err
+	}
+
+	if options.PathDelimiter != 0 {
+		if err := cCall(func() C.obx_err {
+			return C.obx_tree_opt_path_delimiter(cOptions, C.char(options.PathDelimiter))
+		}); err != nil {
+			C.obx_tree_options_free(cOptions)
+			return nil, err
+		}
+	}
+
+	// obx_tree() frees the options, regardless of the outcome
+	if err := cCallBool(func() bool {
+		tree.cTree = C.obx_tree(cStore, cOptions)
+		return tree.cTree != nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := cCallBool(func() bool {
+		tree.dataLeafBox = C.obx_box(cStore, C.obx_schema_id(tree.dataLeaf.Id))
+		return tree.dataLeafBox != nil
+	}); err != nil {
+		C.obx_tree_close(tree.cTree)
+		return nil, err
+	}
+	return tree, nil
+}
+
+// resolveModel finds the leaf entities and properties in the model the store was opened with
+func (tree *Tree) resolveModel() (err error) {
+	if tree.dataLeaf, err = tree.entity("DataLeaf"); err != nil {
+		return err
+	}
+	if tree.metaLeaf, err = tree.entity("MetaLeaf"); err != nil {
+		return err
+	}
+	for _, name := range []string{"DataBranch", "MetaBranch"} {
+		if _, err = tree.entity(name); err != nil {
+			return err
+		}
+	}
+
+	var slots = []struct {
+		entity *ModelEntityInfo
+		name   string
+		slot   *int
+	}{
+		{tree.dataLeaf, "Id", &tree.leafId},
+		{tree.dataLeaf, "ValueInt", &tree.valueInt},
+		{tree.dataLeaf, "ValueDouble", &tree.valueDouble},
+		{tree.dataLeaf, "ValueString", &tree.valueString},
+		{tree.metaLeaf, "Name", &tree.metaName},
+		{tree.metaLeaf, "ValueType", &tree.metaType},
+	}
+	for _, s := range slots {
+		if *s.slot, err = treeSlot(s.entity, s.name); err != nil {
+			return err
+		}
+	}
+
+	tree.dataLeafIds = treeIdSlots(tree.dataLeaf)
+	tree.metaLeafIds = treeIdSlots(tree.metaLeaf)
+	return nil
+}
+
+func (tree *Tree) entity(name string) (*ModelEntityInfo, error) {
+	for _, e := range tree.objectBox.schema.Entities {
+		if strings.EqualFold(e.Name, name) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("can't use a tree: entity %s is not part of the model", name)
+}
+
+func treeSlot(entity *ModelEntityInfo, name string) (int, error) {
+	for _, p := range entity.Properties {
+		if strings.EqualFold(p.Name, name) {
+			return int(p.Id) - 1, nil
+		}
+	}
+	return 0, fmt.Errorf("can't use a tree: property %s.%s is not part of the model", entity.Name, name)
+}
+
+// treeIdSlots returns the slots of the ID and relation properties
+func treeIdSlots(entity *ModelEntityInfo) []int {
+	var slots []int
+	for _, p := range entity.Properties {
+		if p.Flags&C.OBXPropertyFlags_ID != 0 || p.Type == C.OBXPropertyType_Relation {
+			slots = append(slots, int(p.Id)-1)
+		}
+	}
+	return slots
+}
+
+// Close frees the native resources of the tree; it can't be used afterwards.
+func (tree *Tree) Close() error {
+	if tree.cTree != nil {
+		if tree.objectBox.store != nil {
+			C.obx_tree_close(tree.cTree)
+		}
+		tree.cTree = nil
+	}
+	return nil
+}
+
+func (tree *Tree) check() error {
+	if tree.cTree == nil {
+		return errors.New("tree has been closed")
+	}
+	return tree.objectBox.checkOpen()
+}
+
+// inTx executes the given function with a tree cursor in a new transaction, committing write transactions on success
+func (tree *Tree) inTx(readOnly bool, fn func(cursor *C.OBX_tree_cursor) error) (err error) {
+	if err := tree.check(); err != nil {
+		return err
+	}
+
+	tx, err := tree.objectBox.beginTx(readOnly)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := tx.Abort(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	// beginTx() has locked the OS thread so the native error info can be read directly
+	var cursor = C.obx_tree_cursor(tree.cTree, tx.cTxn)
+	if cursor == nil {
+		return createError()
+	}
+	err = fn(cursor)
+	C.obx_tree_cursor_close(cursor) // must be closed before the transaction
+
+	if err == nil && !readOnly {
+		err = tx.Commit()
+	}
+	return err
+}
+
+// Put sets the value of the leaf at the given path, creating the leaf and any missing branches.
+// The value must be an int, int32, int64, float32, float64 or a string and it can't change its type once put.
+// Returns the ID of the leaf.
+func (tree *Tree) Put(path string, value interface{}) (id uint64, err error) {
+	var valueType C.OBXPropertyType
+	var fbb = acquireFbb()
+	defer releaseFbb(fbb)
+
+	var offsetString flatbuffers.UOffsetT
+	if str, isString := value.(string); isString {
+		offsetString = fbb.CreateString(str)
+	}
+	fbb.StartObject(int(tree.dataLeaf.LastPropertyId.Id))
+	for _, slot := range tree.dataLeafIds {
+		fbb.PrependUint64(0)
+		fbb.Slot(slot)
+	}
+	switch v := value.(type) {
+	case int:
+		valueType = C.OBXPropertyType_Long
+		fbb.PrependInt64Slot(tree.valueInt, int64(v), 0)
+	case int32:
+		valueType = C.OBXPropertyType_Long
+		fbb.PrependInt64Slot(tree.valueInt, int64(v), 0)
+	case int64:
+		valueType = C.OBXPropertyType_Long
+		fbb.PrependInt64Slot(tree.valueInt, v, 0)
+	case float32:
+		valueType = C.OBXPropertyType_Double
+		fbb.PrependFloat64Slot(tree.valueDouble, float64(v), 0)
+	case float64:
+		valueType = C.OBXPropertyType_Double
+		fbb.PrependFloat64Slot(tree.valueDouble, v, 0)
+	case string:
+		valueType = C.OBXPropertyType_String
+		fbb.PrependUOffsetTSlot(tree.valueString, offsetString, 0)
+	default:
+		return 0, fmt.Errorf("unsupported tree value type %T", value)
+	}
+	fbb.Finish(fbb.EndObject())
+	var leaf = fbb.FinishedBytes()
+
+	var cPath = C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	err = tree.inTx(false, func(cursor *C.OBX_tree_cursor) error {
+		var cId C.obx_id
+		var rc = C.obx_tree_cursor_put_raw(cursor, cPath, unsafe.Pointer(&leaf[0]), C.size_t(len(leaf)), valueType,
+			&cId, nil, 0, C.OBXPutMode_PUT)
+		if rc == C.OBX_NOT_FOUND {
+			// the leaf doesn't exist yet: try again, letting the core create the missing branches and meta nodes
+			var meta = tree.metaLeafBytes(path, valueType)
+			rc = C.obx_tree_cursor_put_raw(cursor, cPath, unsafe.Pointer(&leaf[0]), C.size_t(len(leaf)), valueType,
+				&cId, unsafe.Pointer(&meta[0]), C.size_t(len(meta)), C.OBXPutMode_PUT)
+		}
+		if rc != 0 {
+			return createError()
+		}
+		id = uint64(cId)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// metaLeafBytes creates the FlatBuffers of a meta leaf, named after the last path element
+func (tree *Tree) metaLeafBytes(path string, valueType C.OBXPropertyType) []byte {
+	var name = path[strings.LastIndexByte(path, tree.delimiter)+1:]
+
+	var fbb = flatbuffers.NewBuilder(64)
+	var offsetName = fbb.CreateString(name)
+	fbb.StartObject(int(tree.metaLeaf.LastPropertyId.Id))
+	for _, slot := range tree.metaLeafIds {
+		fbb.PrependUint64(0)
+		fbb.Slot(slot)
+	}
+	fbb.PrependUOffsetTSlot(tree.metaName, offsetName, 0)
+	fbb.PrependInt16(int16(valueType))
+	fbb.Slot(tree.metaType)
+	fbb.Finish(fbb.EndObject())
+	return fbb.FinishedBytes()
+}
+
+// Get reads the leaf at the given path; returns nil if there's no such leaf.
+func (tree *Tree) Get(path string) (leaf *TreeLeaf, err error) {
+	err = tree.inTx(true, func(cursor *C.OBX_tree_cursor) error {
+		leaf, err = tree.get(cursor, path)
+		return err
+	})
+	return leaf, err
+}
+
+func (tree *Tree) get(cursor *C.OBX_tree_cursor, path string) (*TreeLeaf, error) {
+	var cPath = C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var data, metadata unsafe.Pointer
+	var size, metadataSize C.size_t
+	var rc = C.obx_tree_cursor_get_raw(cursor, cPath, &data, &size, &metadata, &metadataSize)
+	if rc == C.OBX_NOT_FOUND {
+		return nil, nil
+	} else if rc != 0 {
+		return nil, createError()
+	} else if data == nil || metadata == nil {
+		return nil, fmt.Errorf("no data received for tree leaf %s", path)
+	}
+
+	var leafBytes = C.GoBytes(data, C.int(size))
+	var metaBytes = C.GoBytes(metadata, C.int(metadataSize))
+	if err := checkTable(leafBytes); err != nil {
+		return nil, fmt.Errorf("can't read tree leaf %s: %s", path, err)
+	} else if err := checkTable(metaBytes); err != nil {
+		return nil, fmt.Errorf("can't read the metadata of tree leaf %s: %s", path, err)
+	}
+
+	var meta = &flatbuffers.Table{Bytes: metaBytes, Pos: flatbuffers.GetUOffsetT(metaBytes)}
+	var valueType = int(meta.GetInt16Slot(slotOffset(tree.metaType), 0))
+
+	var table = &flatbuffers.Table{Bytes: leafBytes, Pos: flatbuffers.GetUOffsetT(leafBytes)}
+	var leaf = &TreeLeaf{Id: table.GetUint64Slot(slotOffset(tree.leafId), 0), Path: path}
+	switch valueType {
+	case C.OBXPropertyType_Long:
+		leaf.Value = table.GetInt64Slot(slotOffset(tree.valueInt), 0)
+	case C.OBXPropertyType_Double:
+		leaf.Value = table.GetFloat64Slot(slotOffset(tree.valueDouble), 0)
+	case C.OBXPropertyType_String:
+		if o := flatbuffers.UOffsetT(table.Offset(slotOffset(tree.valueString))); o != 0 {
+			leaf.Value = string(table.ByteVector(o + table.Pos))
+		} else {
+			leaf.Value = ""
+		}
+	default:
+		return nil, fmt.Errorf("unsupported value type %s of tree leaf %s", propertyTypeName(valueType), path)
+	}
+	return leaf, nil
+}
+
+// slotOffset returns the vtable offset of the given FlatBuffers slot
+func slotOffset(slot int) flatbuffers.VOffsetT {
+	return flatbuffers.VOffsetT(4 + 2*slot)
+}
+
+// Leaves reads all leaves in the subtree of the given branch path, ordered by the path depth; pass an empty path to
+// read the whole tree.
+func (tree *Tree) Leaves(path string) (leaves []*TreeLeaf, err error) {
+	var cPath *C.char
+	if path != "" {
+		cPath = C.CString(path)
+		defer C.free(unsafe.Pointer(cPath))
+	}
+
+	err = tree.inTx(true, func(cursor *C.OBX_tree_cursor) error {
+		var info = C.obx_tree_cursor_get_child_leaves_info(cursor, cPath)
+		if info == nil {
+			return createError()
+		}
+		defer C.obx_tree_leaves_info_free(info)
+
+		var count = int(C.obx_tree_leaves_info_size(info))
+		leaves = make([]*TreeLeaf, 0, count)
+		for i := 0; i < count; i++ {
+			var leafPath = C.GoString(C.obx_tree_leaves_info_path(info, C.size_t(i)))
+			leaf, err := tree.get(cursor, leafPath)
+			if err != nil {
+				return err
+			} else if leaf != nil {
+				leaves = append(leaves, leaf)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// LeafPath returns the full path of the leaf with the given ID.
+func (tree *Tree) LeafPath(id uint64) (path string, err error) {
+	err = tree.inTx(true, func(cursor *C.OBX_tree_cursor) error {
+		var cPath = C.obx_tree_cursor_get_leaf_path(cursor, C.obx_id(id))
+		if cPath == nil {
+			return createError()
+		}
+		path = C.GoString(cPath)
+		C.free(unsafe.Pointer(cPath))
+		return nil
+	})
+	return path, err
+}
+
+// Remove removes the leaf at the given path; returns false if there's no such leaf. Branches are kept.
+func (tree *Tree) Remove(path string) (removed bool, err error) {
+	err = tree.inTx(false, func(cursor *C.OBX_tree_cursor) error {
+		leaf, err := tree.get(cursor, path)
+		if err != nil || leaf == nil {
+			return err
+		}
+		if rc := C.obx_box_remove(tree.dataLeafBox, C.obx_id(leaf.Id)); rc != 0 {
+			return createError()
+		}
+		removed = true
+		return nil
+	})
+	return removed, err
+}
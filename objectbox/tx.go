@@ -0,0 +1,253 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+
+// the number of transactions started by beginTx() and not finished yet on the current thread; as the goroutine owning
+// a transaction is locked to its thread, a non-zero value tells that a new transaction is nested in another one
+static __thread int obx_go_tx_depth = 0;
+
+static int obx_go_tx_depth_add(int delta) {
+	obx_go_tx_depth += delta;
+	return obx_go_tx_depth;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// Tx is an explicitly managed transaction, started by ObjectBox.BeginTx() or ObjectBox.BeginReadTx().
+// Use it when the transaction lifecycle spans function boundaries, e.g. a request-scoped unit of work;
+// otherwise, prefer the closure based ObjectBox.RunInWriteTx() and ObjectBox.RunInReadTx().
+//
+// A transaction is bound to the goroutine that started it: the goroutine is locked to its OS thread until the
+// transaction is finished by Commit() or Abort(), which must be called by the same goroutine.
+// All Box, Query, etc. operations executed by this goroutine in the meantime are part of the transaction.
+// Always make sure the transaction is finished, e.g. `defer tx.Abort()` right after starting it.
+type Tx struct {
+	objectBox *ObjectBox
+	cTxn      *C.OBX_txn
+	readOnly  bool
+
+	// only set if a MetricsCollector, a slow log or diagnostics are configured
+	metrics MetricsCollector
+	started time.Time
+
+	// only set for write transactions if enabled by Builder.RecordContention()
+	contention *contentionHolder
+}
+
+// BeginTx starts a write transaction. Only one write transaction may be active at a time (concurrently).
+// Commit() makes the changes persistent, Abort() discards them.
+func (ob *ObjectBox) BeginTx() (*Tx, error) {
+	return ob.beginTx(false)
+}
+
+// BeginReadTx starts a read transaction. Multiple read transaction may be executed concurrently.
+// Finish it by calling either Commit() or Abort(), there's no difference for read transactions.
+func (ob *ObjectBox) BeginReadTx() (*Tx, error) {
+	return ob.beginTx(true)
+}
+
+func (ob *ObjectBox) beginTx(readOnly bool) (*Tx, error) {
+	// NOTE if runtime.LockOSThread() is about to be removed, evaluate use of createError() inside transactions
+	runtime.LockOSThread()
+
+	// counted as active before the native transaction is started so that close() keeps the native store meanwhile;
+	// while CloseWithTimeout() drains the store, transactions nested in an active one are accepted so it can finish
+	ob.storeMutex.RLock()
+	var store = ob.store
+	var err error
+	if C.obx_go_tx_depth_add(0) > 0 {
+		err = ob.checkOpenLocked()
+	} else {
+		err = ob.checkAcceptingLocked()
+	}
+	if err == nil {
+		atomic.AddInt32(&ob.activeTxCount, 1)
+	}
+	ob.storeMutex.RUnlock()
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+
+	var tx = &Tx{objectBox: ob, readOnly: readOnly}
+	if tx.metrics = metricsCollector(); tx.metrics != nil || ob.options.slowLog != nil || ob.diagnostics != nil {
+		tx.started = time.Now()
+	}
+	if readOnly {
+		tx.cTxn = C.obx_txn_read(store)
+	} else if ob.contention != nil {
+		var blocker = ob.contention.waiting()
+		var waitStarted = time.Now()
+		tx.cTxn = C.obx_txn_write(store)
+		if tx.cTxn != nil {
+			tx.contention = ob.contention.acquired(blocker, time.Since(waitStarted))
+		}
+	} else {
+		tx.cTxn = C.obx_txn_write(store)
+	}
+
+	if tx.cTxn == nil {
+		var err = createError()
+		runtime.UnlockOSThread()
+		ob.txFinished()
+		return nil, err
+	}
+
+	C.obx_go_tx_depth_add(1)
+	if !readOnly {
+		ob.changeLog.txStarted()
+	}
+	return tx, nil
+}
+
+// inTx returns true if the calling goroutine is running inside a transaction; as the goroutine owning a transaction
+// is locked to its thread, no other goroutine can observe its depth
+func inTx() bool {
+	return C.obx_go_tx_depth_add(0) > 0
+}
+
+// txFinished is called when a transaction counted as active by beginTx() has finished; closes the native store if
+// close() has been called meanwhile and nothing else is active anymore
+func (ob *ObjectBox) txFinished() {
+	if atomic.AddInt32(&ob.activeTxCount, -1) == 0 {
+		ob.closeIfIdle()
+	}
+}
+
+// IsActive returns true until the transaction is finished, i.e. Commit() or Abort() is called.
+func (tx *Tx) IsActive() bool {
+	return tx.cTxn != nil
+}
+
+// IsReadOnly returns true for read transactions started by BeginReadTx().
+func (tx *Tx) IsReadOnly() bool {
+	return tx.readOnly
+}
+
+// Box provides access to a Box for the given entity; a shortcut for tx.ObjectBox().InternalBox(entityId).
+// There's nothing special about the returned Box, all operations executed by the goroutine owning the transaction
+// become part of it, regardless of how the Box was obtained.
+func (tx *Tx) Box(entityId TypeId) (*Box, error) {
+	if !tx.IsActive() {
+		return nil, errors.New("transaction is not active")
+	}
+	return tx.objectBox.BoxOrError(entityId)
+}
+
+// ObjectBox returns the store this transaction belongs to.
+func (tx *Tx) ObjectBox() *ObjectBox {
+	return tx.objectBox
+}
+
+// Commit makes the changes done in a write transaction persistent and finishes the transaction.
+// For read transactions, it just finishes the transaction.
+func (tx *Tx) Commit() error {
+	if !tx.IsActive() {
+		return errors.New("transaction is not active")
+	}
+
+	if tx.readOnly {
+		return tx.close()
+	} else if err := tx.objectBox.checkOpen(); err != nil {
+		// the native store is kept until the transaction is closed, see ObjectBox.close()
+		_ = tx.close()
+		return err
+	}
+
+	var cTxn = tx.cTxn
+	tx.cTxn = nil
+	defer runtime.UnlockOSThread()
+
+	// obx_txn_success() also closes the transaction, regardless of the outcome
+	var err error
+	tx.objectBox.changeLog.txFinished(func() bool {
+		if rc := C.obx_txn_success(cTxn); rc != 0 {
+			err = createError()
+		}
+		return err == nil
+	})
+	tx.finished(err == nil)
+	return err
+}
+
+// Abort discards the changes done in a write transaction and finishes the transaction.
+// Calling Abort() on an already finished transaction is a no-op so it's safe to `defer tx.Abort()`.
+func (tx *Tx) Abort() error {
+	if !tx.IsActive() {
+		return nil
+	}
+	return tx.close()
+}
+
+// close closes the native transaction; that's fine even if the store has been closed meanwhile because the native
+// store is kept until all transactions have finished, see ObjectBox.close()
+func (tx *Tx) close() error {
+	var cTxn = tx.cTxn
+	tx.cTxn = nil
+	defer runtime.UnlockOSThread()
+
+	var err error
+	var closeFn = func() bool {
+		if rc := C.obx_txn_close(cTxn); rc != 0 {
+			err = createError()
+		}
+		return false
+	}
+	if tx.readOnly {
+		closeFn()
+	} else {
+		tx.objectBox.changeLog.txFinished(closeFn)
+	}
+	tx.finished(tx.readOnly) // closing a write transaction without committing aborts it
+	return err
+}
+
+// finished is called after the native transaction has been closed
+func (tx *Tx) finished(committed bool) {
+	C.obx_go_tx_depth_add(-1)
+	tx.objectBox.txFinished()
+	if !tx.readOnly && !committed {
+		tx.objectBox.invalidateQuotas()
+	}
+	if tx.contention != nil {
+		tx.objectBox.contention.released(tx.contention)
+	}
+	if tx.started.IsZero() {
+		return
+	}
+	var duration = time.Since(tx.started)
+	if tx.metrics != nil {
+		tx.metrics.TransactionDone(tx.readOnly, committed, duration)
+	}
+	if slowLog := tx.objectBox.options.slowLog; slowLog != nil {
+		slowLog.transactionDone(tx.readOnly, duration)
+	}
+	if diagnostics := tx.objectBox.diagnostics; diagnostics != nil {
+		diagnostics.transactionDone(tx.readOnly, committed, duration)
+	}
+}
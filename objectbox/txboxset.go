@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// TxBoxSet gives access to the boxes of all entities inside a transaction started by ObjectBox.RunInWriteTxMulti().
+// Boxes are resolved either by the entity ID or by the type of the objects, e.g. boxes.BoxFor(&Account{}).
+type TxBoxSet struct {
+	objectBox *ObjectBox
+	tx        *Tx
+}
+
+// Box returns the box for the given entity ID; see Tx.Box()
+func (boxes TxBoxSet) Box(entityId TypeId) (*Box, error) {
+	return boxes.tx.Box(entityId)
+}
+
+// BoxFor returns the box storing objects of the same type as the given object (or pointer to it), e.g. &Account{}
+func (boxes TxBoxSet) BoxFor(object interface{}) (*Box, error) {
+	if !boxes.tx.IsActive() {
+		return nil, errors.New("transaction is not active")
+	}
+	if object == nil {
+		return nil, errors.New("can't determine the box for a nil object")
+	}
+
+	var objectType = reflect.TypeOf(object)
+	for objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+
+	for id := range boxes.objectBox.entitiesById {
+		box, err := boxes.objectBox.box(id)
+		if err != nil {
+			return nil, err
+		}
+		if box.objectType() == objectType {
+			return box, nil
+		}
+	}
+	return nil, fmt.Errorf("no entity registered for type %s", objectType)
+}
+
+// Put puts the object to the box of its type, see Box.Put()
+func (boxes TxBoxSet) Put(object interface{}) (uint64, error) {
+	box, err := boxes.BoxFor(object)
+	if err != nil {
+		return 0, err
+	}
+	return box.put(object, true, cPutModePut)
+}
+
+// Remove removes the object from the box of its type, see Box.Remove()
+func (boxes TxBoxSet) Remove(object interface{}) error {
+	box, err := boxes.BoxFor(object)
+	if err != nil {
+		return err
+	}
+	return box.Remove(object)
+}
+
+// RunInWriteTxMulti executes the given function inside a write transaction, giving it access to the boxes of all
+// entities, e.g. to keep an invariant spanning multiple entities (an account balance and its ledger entries):
+//
+//	err := ob.RunInWriteTxMulti(func(boxes objectbox.TxBoxSet) error {
+//		if _, err := boxes.Put(entry); err != nil {
+//			return err
+//		}
+//		account.Balance += entry.Amount
+//		_, err := boxes.Put(account)
+//		return err
+//	})
+//
+// The following is guaranteed:
+//   - all changes done by fn, through any box, are committed together when fn returns nil, or none of them;
+//   - if fn returns an error or panics, the transaction is rolled back and the error is returned (the panic continues);
+//   - other transactions see either none or all of the changes; write transactions are executed one at a time.
+//
+// As with RunInWriteTx(), fn must execute sequentially in the calling goroutine: work done in other goroutines isn't
+// part of the transaction. Don't keep using the boxes after fn returns: each call would run in its own transaction.
+func (ob *ObjectBox) RunInWriteTxMulti(fn func(boxes TxBoxSet) error) error {
+	return ob.runInTx(false, func(tx *Tx) error {
+		return fn(TxBoxSet{objectBox: ob, tx: tx})
+	})
+}
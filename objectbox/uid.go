@@ -0,0 +1,115 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// UidProperties declares the properties holding an external ID (e.g. a UUID) of their entities, enabling lookups by
+// Box.GetByUid(). Each must be a unique string or byte vector property, e.g. declared as
+//
+//	Uid string `objectbox:"unique"`
+//
+// and there can only be one such property per entity.
+func (builder *Builder) UidProperties(properties ...Property) *Builder {
+	builder.uidProperties = append(builder.uidProperties, properties...)
+	return builder
+}
+
+// applyUidProperties remembers the external ID properties declared by UidProperties() in their entities
+func (model *Model) applyUidProperties(properties []Property) error {
+	for _, property := range properties {
+		var entity = model.entitiesById[property.entityId()]
+		if entity == nil {
+			return fmt.Errorf("can't use property %d as an external ID - entity %d not found",
+				property.propertyId(), property.entityId())
+		}
+
+		var info *ModelPropertyInfo
+		for _, e := range model.schema.Entities {
+			if e.Id == entity.id {
+				for _, p := range e.Properties {
+					if p.Id == property.propertyId() {
+						info = p
+					}
+				}
+			}
+		}
+		if info == nil {
+			return fmt.Errorf("can't use property %d of entity %s as an external ID - not found",
+				property.propertyId(), entity.name)
+		}
+
+		if info.Flags&C.OBXPropertyFlags_UNIQUE == 0 ||
+			(info.Type != C.OBXPropertyType_String && info.Type != C.OBXPropertyType_ByteVector) {
+			return fmt.Errorf("can't use property %s.%s as an external ID - it must be a unique string or byte "+
+				"vector property", entity.name, info.Name)
+		}
+
+		if entity.uidPropertyId != 0 && entity.uidPropertyId != info.Id {
+			return fmt.Errorf("entity %s has multiple external ID properties, only one is allowed", entity.name)
+		}
+		entity.uidPropertyId = info.Id
+	}
+	return nil
+}
+
+// GetByUid reads the object with the given external ID (e.g. a UUID), using the unique index of the entity's external
+// ID property, which must have been declared by Builder.UidProperties(). For a byte vector property, the raw bytes of
+// uid are compared. Returns nil (and no error) if there's no such object.
+// Objects are returned as pointers the same way as Get() does, e.g. *Event.
+func (box *Box) GetByUid(uid string) (object interface{}, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.GetByUid", time.Now(), &err)
+	}
+
+	if box.entity.uidPropertyId == 0 {
+		return nil, fmt.Errorf("entity %s has no external ID property; declare it using Builder.UidProperties()",
+			box.entity.name)
+	}
+
+	var property = &BaseProperty{Id: box.entity.uidPropertyId, Entity: &Entity{Id: box.entity.id}}
+	var condition Condition
+	if box.ObjectBox.schemaProperty(box.entity.id, property.Id).Type == C.OBXPropertyType_ByteVector {
+		condition = PropertyByteVector{property}.Equals([]byte(uid))
+	} else {
+		condition = PropertyString{property}.Equals(uid, true)
+	}
+
+	query, err := box.QueryOrError(condition)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	objects, err := query.Limit(1).Find()
+	if err != nil {
+		return nil, err
+	}
+	if slice := reflect.ValueOf(objects); slice.Len() > 0 {
+		return slice.Index(0).Interface(), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+// propertyUpdate is a single property value set by Query.UpdateProperties()
+type propertyUpdate struct {
+	fieldIndex []int
+	value      reflect.Value
+}
+
+// UpdateProperties sets the given properties to the given values on all objects matching the query and stores the
+// objects, all inside a single write transaction. Returns the number of updated objects.
+// It's a shortcut for the read-modify-write loop of a mass update, e.g. archiving all tasks created before a certain
+// date; the loop itself still runs, objects are read and put as a whole:
+//
+//	count, err := box.Query(Task_.Created.LessThan(threshold)).UpdateProperties(map[objectbox.Property]interface{}{
+//		Task_.Status: StatusArchived,
+//	})
+//
+// A value must be assignable or convertible (numbers only) to the type of the struct field, nil sets the zero value.
+// The ID and relation properties can't be updated. The offset and limit of the query apply.
+func (query *Query) UpdateProperties(values map[Property]interface{}) (count uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Query.UpdateProperties", time.Now(), &err)
+	}
+
+	defer runtime.KeepAlive(query)
+
+	if err := query.enter(); err != nil {
+		return 0, err
+	}
+	defer query.objectBox.leave()
+
+	var updates = make([]propertyUpdate, 0, len(values))
+	for property, value := range values {
+		update, err := query.newPropertyUpdate(property, value)
+		if err != nil {
+			return 0, err
+		}
+		updates = append(updates, update)
+	}
+
+	err = query.objectBox.RunInWriteTx(func() error {
+		objects, err := query.find()
+		if err != nil {
+			return err
+		}
+
+		var slice = reflect.ValueOf(objects)
+		for i := 0; i < slice.Len(); i++ {
+			var object = slice.Index(i)
+			if object.Kind() == reflect.Ptr {
+				object = object.Elem()
+			}
+			for _, update := range updates {
+				object.FieldByIndex(update.fieldIndex).Set(update.value)
+			}
+		}
+
+		if _, err := query.box.PutMany(objects); err != nil {
+			return err
+		}
+		count = uint64(slice.Len())
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (query *Query) newPropertyUpdate(property Property, value interface{}) (propertyUpdate, error) {
+	if query.entity.id != property.entityId() {
+		return propertyUpdate{}, fmt.Errorf("property from a different entity %d passed, expected %d",
+			property.entityId(), query.entity.id)
+	}
+
+	var info = query.objectBox.schemaProperty(property.entityId(), property.propertyId())
+	if info == nil {
+		return propertyUpdate{}, fmt.Errorf("property %d of entity %d not found in the model",
+			property.propertyId(), property.entityId())
+	}
+
+	if info.Flags&C.OBXPropertyFlags_ID != 0 {
+		return propertyUpdate{}, fmt.Errorf("can't update property %s - it's the ID", info.Name)
+	} else if info.Type == C.OBXPropertyType_Relation {
+		return propertyUpdate{}, fmt.Errorf("can't update property %s - it's a relation", info.Name)
+	}
+
+	field, err := query.entity.field(info)
+	if err != nil {
+		return propertyUpdate{}, fmt.Errorf("can't update property %s - %v", info.Name, err)
+	}
+
+	var update = propertyUpdate{fieldIndex: field.Index}
+	if value == nil {
+		update.value = reflect.Zero(field.Type)
+		return update, nil
+	}
+
+	update.value = reflect.ValueOf(value)
+	if update.value.Type().AssignableTo(field.Type) {
+		return update, nil
+	} else if isNumberKind(update.value.Kind()) && isNumberKind(field.Type.Kind()) {
+		update.value = update.value.Convert(field.Type)
+		return update, nil
+	}
+	return propertyUpdate{}, fmt.Errorf("can't update property %s - value of type %s can't be assigned to %s",
+		info.Name, update.value.Type(), field.Type)
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
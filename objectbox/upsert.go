@@ -0,0 +1,141 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PutByUnique inserts the object or, if an object with the same value of the entity's unique property already exists,
+// updates that object instead, reusing its ID (which is also set on the given object). The lookup and the put are
+// executed in a single write transaction. The entity must have exactly one unique property (besides the ID); use
+// PutByUniqueProperty() to choose among several.
+// Note: there's no typed variant in the generated code as the generator is a separate module; the generated boxes
+// embed *Box so this method is available on them, taking the object as interface{}.
+func (box *Box) PutByUnique(object interface{}) (id uint64, err error) {
+	var unique []*ModelPropertyInfo
+	for _, entity := range box.ObjectBox.schema.Entities {
+		if entity.Id != box.entity.id {
+			continue
+		}
+		for _, property := range entity.Properties {
+			if property.Flags&C.OBXPropertyFlags_UNIQUE != 0 && property.Flags&C.OBXPropertyFlags_ID == 0 {
+				unique = append(unique, property)
+			}
+		}
+	}
+
+	if len(unique) != 1 {
+		var names = make([]string, len(unique))
+		for i, property := range unique {
+			names[i] = property.Name
+		}
+		return 0, fmt.Errorf("entity %s must have exactly one unique property to use PutByUnique(), found %d [%s]; "+
+			"use PutByUniqueProperty() instead", box.entity.name, len(unique), strings.Join(names, ", "))
+	}
+	return box.putByUnique(unique[0], object)
+}
+
+// PutByUniqueProperty is like PutByUnique() but looks up the existing object by the given property.
+// The property should be unique (or at least indexed, for the lookup to be fast); if several objects match, the one
+// with the lowest ID is updated.
+func (box *Box) PutByUniqueProperty(property Property, object interface{}) (id uint64, err error) {
+	if property.entityId() != box.entity.id {
+		return 0, fmt.Errorf("property from a different entity %d passed, expected %d",
+			property.entityId(), box.entity.id)
+	}
+	var info = box.ObjectBox.schemaProperty(box.entity.id, property.propertyId())
+	if info == nil {
+		return 0, fmt.Errorf("property %d not found in entity %s", property.propertyId(), box.entity.name)
+	}
+	return box.putByUnique(info, object)
+}
+
+func (box *Box) putByUnique(info *ModelPropertyInfo, object interface{}) (id uint64, err error) {
+	if collector := metricsCollector(); collector != nil {
+		defer observeOperation(collector, "Box.PutByUnique", time.Now(), &err)
+	}
+
+	objectId, err := box.entity.binding.GetId(object)
+	if err != nil {
+		return 0, err
+	}
+
+	err = box.ObjectBox.RunInWriteTx(func() error {
+		value, err := box.objectProperty(object, objectId, newProjectedProperty(info))
+		if err == nil {
+			value, err = box.entity.codec.encodeValue(info.Id, value)
+		}
+		if err != nil {
+			return err
+		}
+
+		condition, err := propertyEquals(box.entity.id, info, value)
+		if err != nil {
+			return err
+		}
+
+		query, err := box.QueryOrError(condition)
+		if err != nil {
+			return err
+		}
+		defer query.Close()
+
+		ids, err := query.Limit(1).FindIds()
+		if err != nil {
+			return err
+		}
+
+		if len(ids) > 0 && ids[0] != objectId {
+			if err := box.entity.binding.SetId(object, ids[0]); err != nil {
+				return err
+			}
+		}
+		id, err = box.Put(object)
+		return err
+	})
+
+	if err != nil {
+		// the transaction was rolled back, don't leave the ID of the existing object on the given one
+		_ = box.entity.binding.SetId(object, objectId)
+		return 0, err
+	}
+	return id, nil
+}
+
+// propertyEquals creates an equality condition for a property value as decoded by projectedProperty
+func propertyEquals(entityId TypeId, info *ModelPropertyInfo, value interface{}) (Condition, error) {
+	var base = &BaseProperty{Id: info.Id, Entity: &Entity{Id: entityId}}
+	switch v := value.(type) {
+	case string:
+		return PropertyString{base}.Equals(v, true), nil
+	case []byte:
+		return PropertyByteVector{base}.Equals(v), nil
+	}
+	if number, ok := integerValue(value); ok {
+		return PropertyInt64{base}.Equals(number), nil
+	}
+	return nil, fmt.Errorf("property %s of type %s can't be used to look up objects", info.Name,
+		propertyTypeName(info.Type))
+}
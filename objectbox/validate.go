@@ -0,0 +1,167 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"sort"
+	"unsafe"
+)
+
+// ValidateOptions configure database validation, see Builder.ValidateOnOpen() and ObjectBox.Validate()
+type ValidateOptions struct {
+	// PageLimit is the number of database pages checked by the core when opening the store; 0 disables page checks.
+	// Reliable file systems already guarantee consistency so usually a low number (e.g. 1-20) is sufficient, however
+	// after an unclean shutdown on unreliable hardware, a higher limit may be appropriate.
+	PageLimit uint
+
+	// LeafPages includes leaf pages in the page checks; by default only branch pages are validated
+	LeafPages bool
+
+	// KeyValues enables the validation of the stored key/value pairs when opening the store
+	KeyValues bool
+
+	// MaxProblems makes ObjectBox.Validate() stop after the given number of problems; 0 means no limit
+	MaxProblems int
+}
+
+// ValidateResult is the outcome of ObjectBox.Validate()
+type ValidateResult struct {
+	// ObjectCounts maps entity names to the number of objects read
+	ObjectCounts map[string]uint64
+
+	// Problems describes the inconsistencies found, e.g. objects that can't be loaded
+	Problems []string
+}
+
+// Valid returns true if no problems were found
+func (result ValidateResult) Valid() bool {
+	return len(result.Problems) == 0
+}
+
+// ValidateOnOpen makes the core check the database pages and key/value pairs (see ValidateOptions) when opening the
+// store; Build() fails if the validation fails. Combined with ObjectBox.Validate(), this allows a service to verify
+// the database integrity at startup, e.g. after an unclean shutdown, and decide whether to restore from a backup.
+func (builder *Builder) ValidateOnOpen(options ValidateOptions) *Builder {
+	builder.validateOnOpen = &options
+	return builder
+}
+
+// Validate reads and decodes all objects of all entities in a single read transaction, checking that each of them
+// can be loaded and has a valid ID and that the number of objects read matches the count stored in the database.
+// Returns an error only if the validation couldn't be performed; inconsistencies are reported in the result.
+// Note: the page and key/value checks of the core are only available when opening the store, see
+// Builder.ValidateOnOpen(); only ValidateOptions.MaxProblems applies here.
+func (ob *ObjectBox) Validate(options ValidateOptions) (result ValidateResult, err error) {
+	result.ObjectCounts = make(map[string]uint64, len(ob.entitiesById))
+
+	// report the problems in a stable order
+	var ids = make([]TypeId, 0, len(ob.entitiesById))
+	for id := range ob.entitiesById {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	err = ob.RunInReadTx(func() error {
+		for _, id := range ids {
+			box, err := ob.box(id)
+			if err != nil {
+				return err
+			}
+			if err = box.validate(&result, options.MaxProblems); err != nil {
+				return err
+			}
+			if options.MaxProblems > 0 && len(result.Problems) >= options.MaxProblems {
+				break
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// validate reads all objects of the box, see ObjectBox.Validate(); must be called inside a read transaction
+func (box *Box) validate(result *ValidateResult, maxProblems int) error {
+	var name = box.entity.name
+	var binding = box.entity.binding
+	var count uint64
+	var problem = func(format string, args ...interface{}) bool {
+		result.Problems = append(result.Problems, name+": "+fmt.Sprintf(format, args...))
+		return maxProblems <= 0 || len(result.Problems) < maxProblems
+	}
+
+	var complete = true
+	visitor, err := dataVisitorRegister(func(bytes []byte) bool {
+		count++
+		object, err := box.entity.load(box.ObjectBox, bytes)
+		if loadErr, ok := err.(*LoadError); ok {
+			err = loadErr.Err // the entity name is already part of the problem
+		}
+		if err != nil {
+			complete = problem("object #%d can't be loaded: %s", count, err)
+			return complete
+		}
+		if id, err := binding.GetId(object); err != nil {
+			complete = problem("object #%d has an invalid ID: %s", count, err)
+		} else if id == 0 {
+			complete = problem("object #%d has ID 0", count)
+		}
+		return complete
+	})
+	if err != nil {
+		return err
+	}
+	defer dataVisitorUnregister(visitor)
+
+	// a failing read is usually caused by corrupted data so it's reported as a problem as well
+	if err := cCall(func() C.obx_err {
+		return C.obx_box_visit_all(box.cBox, dataVisitor, unsafe.Pointer(&visitor))
+	}); err != nil {
+		problem("reading objects failed: %s", err)
+		complete = false
+	}
+	result.ObjectCounts[name] = count
+
+	if complete {
+		var cCount C.uint64_t
+		if err := cCall(func() C.obx_err { return C.obx_box_count(box.cBox, 0, &cCount) }); err != nil {
+			problem("counting objects failed: %s", err)
+		} else if uint64(cCount) != count {
+			problem("the stored count %d doesn't match the %d objects read", uint64(cCount), count)
+		}
+	}
+	return nil
+}
+
+// applyValidateOnOpen configures the validation of the core when opening the store
+func applyValidateOnOpen(cOptions *C.OBX_store_options, options *ValidateOptions) {
+	if options.PageLimit > 0 {
+		var flags C.uint32_t = C.OBXValidateOnOpenPagesFlags_None
+		if options.LeafPages {
+			flags |= C.OBXValidateOnOpenPagesFlags_VisitLeafPages
+		}
+		C.obx_opt_validate_on_open_pages(cOptions, C.size_t(options.PageLimit), flags)
+	}
+	if options.KeyValues {
+		C.obx_opt_validate_on_open_kv(cOptions, C.OBXValidateOnOpenKvFlags_None)
+	}
+}
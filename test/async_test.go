@@ -20,6 +20,7 @@ import (
 	"github.com/objectbox/objectbox-go/objectbox"
 	"github.com/objectbox/objectbox-go/test/model"
 	"testing"
+	"time"
 
 	"github.com/objectbox/objectbox-go/test/assert"
 )
@@ -52,6 +53,18 @@ func TestAsyncBoxGenerated(t *testing.T) {
 	})
 }
 
+func TestAsyncBoxInvalid(t *testing.T) {
+	var env = model.NewTestEnv(t)
+
+	// unknown entities and closed stores are reported as errors, not panics
+	_, err := objectbox.NewAsyncBox(env.ObjectBox, 9999, timeoutMs)
+	assert.Err(t, err)
+
+	env.Close()
+	_, err = objectbox.NewAsyncBox(env.ObjectBox, model.TestEntityInlineBinding.Id, timeoutMs)
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+}
+
 // testAsync tests all AsyncBox operations
 func testAsync(t *testing.T, asyncF func(box *model.TestEntityInlineBox) *model.TestEntityInlineAsyncBox) {
 	var env = model.NewTestEnv(t)
@@ -126,3 +139,74 @@ func testAsync(t *testing.T, asyncF func(box *model.TestEntityInlineBox) *model.
 	assert.NoErr(t, async.RemoveId(object.Id))
 	waitAndCount(1)
 }
+
+func TestAsyncBoxPutManyRemoveIds(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityInline(env.ObjectBox)
+	var async = box.Async()
+
+	var objects = make([]*model.TestEntityInline, 100)
+	for i := range objects {
+		objects[i] = &model.TestEntityInline{BaseWithValue: &model.BaseWithValue{Value: float64(i)}}
+	}
+
+	ids, err := async.PutMany(objects)
+	assert.NoErr(t, err)
+	assert.Eq(t, len(objects), len(ids))
+	for i, object := range objects {
+		assert.Eq(t, ids[i], object.Id)
+	}
+
+	assert.NoErr(t, async.AwaitSubmitted())
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(len(objects)), count)
+
+	// empty input is a no-op
+	ids, err = async.PutMany([]*model.TestEntityInline{})
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(ids))
+
+	assert.NoErr(t, async.RemoveIds(objects[0].Id, objects[1].Id, objects[2].Id))
+	assert.NoErr(t, async.AwaitSubmitted())
+	count, err = box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(len(objects)-3), count)
+}
+
+func TestAsyncBoxPutManyRelations(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	_, err := env.Box.Async().PutMany([]*model.Entity{model.Entity47()})
+	assert.Err(t, err)
+}
+
+func TestAsyncBoxPutWithTimeout(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityInline(env.ObjectBox)
+	var async = box.Async()
+
+	var failures []error
+	async.SetErrorListener(func(err error, entityId objectbox.TypeId, id uint64) {
+		assert.Eq(t, model.TestEntityInlineBinding.Id, entityId)
+		failures = append(failures, err)
+	})
+
+	var object = &model.TestEntityInline{BaseWithValue: &model.BaseWithValue{Value: 47}}
+	id, err := async.PutWithTimeout(object, 50*time.Millisecond)
+	assert.NoErr(t, err)
+	assert.Eq(t, id, object.Id)
+
+	assert.NoErr(t, async.AwaitSubmitted())
+	read, err := box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, float64(47), read.Value)
+	assert.Eq(t, 0, len(failures))
+
+	async.SetErrorListener(nil)
+}
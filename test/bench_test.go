@@ -227,3 +227,29 @@ func BenchmarkSlowGet(b *testing.B) {
 		env.check(err)
 	}
 }
+
+// BenchmarkThreadPinning executes short native calls from many goroutines concurrently, with each ThreadPinning policy
+func BenchmarkThreadPinning(b *testing.B) {
+	for _, policy := range []objectbox.ThreadPinning{objectbox.ThreadPinningAlways, objectbox.ThreadPinningOnError} {
+		b.Run(policy.String(), func(b *testing.B) {
+			objectbox.SetThreadPinning(policy)
+			defer objectbox.SetThreadPinning(objectbox.ThreadPinningAlways)
+
+			var env = newBenchEnv(b)
+			defer env.close()
+
+			var inserts = prepareBenchData(b, 1)
+			b.StopTimer()
+			_, err := env.box.Put(inserts[0])
+			env.check(err)
+			b.StartTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_, err := env.box.Contains(inserts[0].ID)
+					env.check(err)
+				}
+			})
+		})
+	}
+}
@@ -17,11 +17,26 @@
 package objectbox_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/objectbox/obxerr"
 	"github.com/objectbox/objectbox-go/test/assert"
 	"github.com/objectbox/objectbox-go/test/model"
 	"github.com/objectbox/objectbox-go/test/model/iot"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 func TestBox(t *testing.T) {
@@ -33,6 +48,171 @@ func TestBox(t *testing.T) {
 	assert.Eq(t, box1.Box, box2.Box)
 }
 
+func TestBoxOrError(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	box, err := env.BoxOrError(iot.EventBinding.Id)
+	assert.NoErr(t, err)
+	assert.True(t, box == iot.BoxForEvent(env.ObjectBox).Box)
+
+	box, err = env.BoxOrError(9999)
+	assert.Err(t, err)
+	assert.True(t, box == nil)
+}
+
+func TestBoxCache(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	assert.NoErr(t, env.PreallocateBoxes())
+	assert.Err(t, env.PreallocateBoxes(9999))
+
+	box1 := iot.BoxForEvent(env.ObjectBox)
+	env.ClearBoxCache()
+	box2 := iot.BoxForEvent(env.ObjectBox)
+	assert.True(t, box1.Box != box2.Box)
+
+	// boxes obtained before clearing the cache still work
+	_, err := box1.Put(&iot.Event{})
+	assert.NoErr(t, err)
+	count, err := box2.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+}
+
+func TestBoxCacheClearedAsyncClosed(t *testing.T) {
+	ob, err := objectbox.NewBuilder().InMemory("box-cache").Model(iot.ObjectBoxModel()).
+		AsyncEnqueueTimeout(time.Second).BuildOrError()
+	assert.NoErr(t, err)
+
+	box1 := iot.BoxForEvent(ob)
+	ob.ClearBoxCache()
+	box2 := iot.BoxForEvent(ob)
+
+	_, err = box1.Async().Put(&iot.Event{})
+	assert.NoErr(t, err)
+	assert.NoErr(t, ob.AwaitAsyncCompletion())
+	count, err := box2.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	// the recreated box shares the async queue of the dropped one, also when clearing repeatedly
+	for i := 0; i < 3; i++ {
+		ob.ClearBoxCache()
+		ob.ClearBoxCache()
+		_, err = iot.BoxForEvent(ob).Async().Put(&iot.Event{})
+		assert.NoErr(t, err)
+	}
+	assert.NoErr(t, ob.AwaitAsyncCompletion())
+	count, err = box2.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(4), count)
+
+	// the async queue is released together with the store
+	ob.Close()
+	_, err = box1.Put(&iot.Event{})
+	assert.Err(t, err)
+	_, err = box1.Async().Put(&iot.Event{})
+	assert.Err(t, err)
+}
+
+func TestBoxWithCache(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	var events = iot.PutEvents(env.ObjectBox, 3)
+	assert.NoErr(t, box.WithCache(2))
+
+	// cache hits don't read from the database, i.e. don't start a read transaction
+	var collector = objectbox.NewExpvarMetricsCollector("objectbox-test-box-cache")
+	objectbox.SetMetricsCollector(collector)
+	defer objectbox.SetMetricsCollector(nil)
+	var readTxCount = func() string {
+		if v := collector.Vars().Get("tx.read.count"); v != nil {
+			return v.String()
+		}
+		return "0"
+	}
+
+	first, err := box.Get(events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "1", readTxCount())
+	second, err := box.Get(events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "1", readTxCount()) // served from the cache
+	assert.Eq(t, first, second)
+
+	// each read returns a new instance so changing it doesn't affect other readers
+	assert.True(t, first != second)
+	second.Device = "modified"
+	third, err := box.Get(events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, first.Device, third.Device)
+
+	// read-through, including objects that don't exist
+	slice, err := box.GetMany(events[0].Id, events[1].Id, 999)
+	assert.NoErr(t, err)
+	assert.Eq(t, "2", readTxCount()) // only the missing ones are read
+	assert.Eq(t, 3, len(slice))
+	assert.Eq(t, first, slice[0])
+	assert.True(t, slice[0] != first)
+	assert.Eq(t, events[1].Device, slice[1].Device)
+	assert.True(t, slice[2] == nil)
+
+	// committed changes invalidate the cache
+	events[0].Device = "changed"
+	_, err = box.Put(events[0])
+	assert.NoErr(t, err)
+	third, err = box.Get(events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "changed", third.Device)
+
+	// transactions bypass the cache
+	assert.Err(t, env.RunInWriteTx(func() error {
+		events[0].Device = "uncommitted"
+		_, err := box.Put(events[0])
+		assert.NoErr(t, err)
+		object, err := box.Get(events[0].Id)
+		assert.NoErr(t, err)
+		assert.Eq(t, "uncommitted", object.Device)
+		return errors.New("rollback")
+	}))
+	object, err := box.Get(events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "changed", object.Device)
+
+	// a transaction held by another goroutine doesn't bypass the cache for reads outside of it
+	var txStarted = make(chan struct{})
+	var txFinish = make(chan struct{})
+	var txDone = make(chan error)
+	go func() {
+		txDone <- env.ObjectBox.RunInWriteTx(func() error {
+			_, err := box.Put(&iot.Event{Device: "uncommitted"})
+			close(txStarted)
+			<-txFinish
+			return err
+		})
+	}()
+	<-txStarted
+	var readTxBefore = readTxCount()
+	cached, err := box.Get(events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, readTxBefore, readTxCount()) // served from the cache
+	assert.Eq(t, "changed", cached.Device)
+	close(txFinish)
+	assert.NoErr(t, <-txDone)
+
+	// disabled
+	assert.NoErr(t, box.WithCache(0))
+	readTxBefore = readTxCount()
+	object, err = box.Get(events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "changed", object.Device)
+	assert.True(t, readTxBefore != readTxCount())
+}
+
 func TestPutAsync(t *testing.T) {
 	env := iot.NewTestEnv()
 	defer env.Close()
@@ -94,10 +274,620 @@ func TestUnique(t *testing.T) {
 	if err == nil {
 		assert.Failf(t, "put() passed instead of an expected unique constraint violation")
 	}
+	assert.Eq(t, obxerr.UniqueViolated, obxerr.Code(err))
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+}
+
+func TestBoxGetByUid(t *testing.T) {
+	ob, err := objectbox.NewBuilder().InMemory("get-by-uid").Model(iot.ObjectBoxModel()).
+		UidProperties(iot.Event_.Uid).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+	box := iot.BoxForEvent(ob)
+
+	ids, err := box.PutMany([]*iot.Event{
+		{Device: "first", Uid: "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"},
+		{Device: "second", Uid: "1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed"},
+	})
+	assert.NoErr(t, err)
+
+	object, err := box.GetByUid("1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed")
+	assert.NoErr(t, err)
+	assert.Eq(t, ids[1], object.(*iot.Event).Id)
+	assert.Eq(t, "second", object.(*iot.Event).Device)
+
+	object, err = box.GetByUid("00000000-0000-0000-0000-000000000000")
+	assert.NoErr(t, err)
+	assert.True(t, object == nil)
+
+	// an entity without an external ID property
+	_, err = iot.BoxForReading(ob).GetByUid("any")
+	assert.Err(t, err)
+
+	// external ID properties are opt-in
+	env := iot.NewTestEnv()
+	defer env.Close()
+	_, err = iot.BoxForEvent(env.ObjectBox).GetByUid("any")
+	assert.Err(t, err)
+
+	// not a unique property
+	_, err = objectbox.NewBuilder().InMemory("get-by-uid-device").Model(iot.ObjectBoxModel()).
+		UidProperties(iot.Event_.Device).BuildOrError()
+	assert.Err(t, err)
+}
+
+func TestBoxPutByUnique(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	id, err := box.PutByUnique(&iot.Event{Device: "first", Uid: "a"})
+	assert.NoErr(t, err)
+	assert.True(t, id != 0)
+
+	// the same Uid updates the existing object, reusing its ID
+	var update = &iot.Event{Device: "updated", Uid: "a"}
+	updatedId, err := box.PutByUnique(update)
+	assert.NoErr(t, err)
+	assert.Eq(t, id, updatedId)
+	assert.Eq(t, id, update.Id)
+
+	otherId, err := box.PutByUniqueProperty(iot.Event_.Uid, &iot.Event{Device: "other", Uid: "b"})
+	assert.NoErr(t, err)
+	assert.True(t, otherId != id)
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	object, err := box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "updated", object.Device)
+
+	// an entity without a unique property
+	_, err = iot.BoxForReading(env.ObjectBox).PutByUnique(&iot.Reading{})
+	assert.Err(t, err)
+
+	// a property of another entity
+	_, err = box.PutByUniqueProperty(iot.Reading_.ValueName, &iot.Event{})
+	assert.Err(t, err)
+}
+
+func TestBoxGetBytes(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	id, err := box.Put(&iot.Event{Device: "dev", Date: 42, Picture: []byte{1, 2, 3}})
+	assert.NoErr(t, err)
+
+	var data []byte
+	found, err := box.GetBytes(id, func(bytes []byte) error {
+		data = append([]byte{}, bytes...) // the slice is only valid during the callback
+		return nil
+	})
+	assert.NoErr(t, err)
+	assert.True(t, found)
+
+	object, err := iot.EventBinding.Load(env.ObjectBox, data)
+	assert.NoErr(t, err)
+	assert.Eq(t, &iot.Event{Id: id, Device: "dev", Date: 42, Picture: []byte{1, 2, 3}}, object)
+
+	var called = false
+	found, err = box.GetBytes(id+1, func(bytes []byte) error {
+		called = true
+		return nil
+	})
+	assert.NoErr(t, err)
+	assert.True(t, !found)
+	assert.True(t, !called)
+
+	var errCallback = errors.New("callback error")
+	found, err = box.GetBytes(id, func(bytes []byte) error {
+		return errCallback
+	})
+	assert.Eq(t, errCallback, err)
+	assert.True(t, found)
+}
+
+// base64Codec "encrypts" values by XOR and base64, keeping strings valid UTF-8
+type base64Codec struct{}
+
+func (base64Codec) Encode(propertyId objectbox.TypeId, value []byte) ([]byte, error) {
+	var result = make([]byte, base64.StdEncoding.EncodedLen(len(value)))
+	base64.StdEncoding.Encode(result, xor(value))
+	return result, nil
+}
+
+func (base64Codec) Decode(propertyId objectbox.TypeId, value []byte) ([]byte, error) {
+	var result = make([]byte, base64.StdEncoding.DecodedLen(len(value)))
+	n, err := base64.StdEncoding.Decode(result, value)
+	return xor(result[:n]), err
+}
+
+func xor(value []byte) []byte {
+	var result = make([]byte, len(value))
+	for i := range value {
+		result[i] = value[i] ^ 0x5a
+	}
+	return result
+}
+
+func TestBoxPropertyCodec(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	assert.NoErr(t, env.ObjectBox.SetPropertyCodec(iot.EventBinding.Id, base64Codec{},
+		iot.Event_.Device.Id, iot.Event_.Picture.Id))
+
+	// only String and ByteVector properties are supported
+	assert.Err(t, env.ObjectBox.SetPropertyCodec(iot.EventBinding.Id, base64Codec{}, iot.Event_.Date.Id))
+
+	var event = &iot.Event{Device: "secret device", Uid: "public", Date: 42, Picture: []byte("secret picture")}
+	id, err := box.Put(event)
+	assert.NoErr(t, err)
+
+	read, err := box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, event, read)
+
+	all, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, []*iot.Event{event}, all)
+
+	// the stored data only contains the encoded values
+	_, err = box.GetBytes(id, func(data []byte) error {
+		assert.True(t, !bytes.Contains(data, []byte("secret")))
+		assert.True(t, bytes.Contains(data, []byte("public")))
+		return nil
+	})
+	assert.NoErr(t, err)
+
+	encoded, _ := base64Codec{}.Encode(iot.Event_.Device.Id, []byte("secret device"))
+	count, err := box.Query(iot.Event_.Device.Equals(string(encoded), true)).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	// PutMany encodes the values as well
+	var many = []*iot.Event{{Device: "secret 1", Uid: "many-1"}, {Device: "secret 2", Uid: "many-2"}}
+	ids, err := box.PutMany(many)
+	assert.NoErr(t, err)
+	readMany, err := box.GetMany(ids...)
+	assert.NoErr(t, err)
+	assert.Eq(t, many, readMany)
+	_, err = box.GetBytes(ids[0], func(data []byte) error {
+		assert.True(t, !bytes.Contains(data, []byte("secret")))
+		return nil
+	})
+	assert.NoErr(t, err)
+
+	// PutByUnique finds the existing object by the encoded value
+	assert.NoErr(t, env.ObjectBox.SetPropertyCodec(iot.EventBinding.Id, base64Codec{}, iot.Event_.Uid.Id))
+	var unique = &iot.Event{Device: "first", Uid: "unique"}
+	uniqueId, err := box.PutByUnique(unique)
+	assert.NoErr(t, err)
+	updatedId, err := box.PutByUnique(&iot.Event{Device: "updated", Uid: "unique"})
+	assert.NoErr(t, err)
+	assert.Eq(t, uniqueId, updatedId)
+	assert.NoErr(t, env.ObjectBox.SetPropertyCodec(iot.EventBinding.Id, base64Codec{},
+		iot.Event_.Device.Id, iot.Event_.Picture.Id))
+
+	// without the codec, the encoded values are read
+	assert.NoErr(t, env.ObjectBox.SetPropertyCodec(iot.EventBinding.Id, nil))
+	read, err = box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(encoded), read.Device)
+	assert.Eq(t, "public", read.Uid)
+	assert.Eq(t, int64(42), read.Date)
+}
+
+func TestBoxPutManyBestEffort(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	_, err := box.Put(&iot.Event{Device: "existing", Uid: "taken"})
+	assert.NoErr(t, err)
+
+	var events = []*iot.Event{
+		{Device: "first", Uid: "a"},
+		{Device: "duplicate", Uid: "taken"},
+		{Device: "second", Uid: "b"},
+		{Device: "duplicate in batch", Uid: "a"},
+	}
+	ids, errs := box.PutManyBestEffort(events)
+	assert.Eq(t, 4, len(ids))
+	assert.Eq(t, 4, len(errs))
+	assert.NoErr(t, errs[0])
+	assert.Eq(t, obxerr.UniqueViolated, obxerr.Code(errs[1]))
+	assert.NoErr(t, errs[2])
+	assert.Eq(t, obxerr.UniqueViolated, obxerr.Code(errs[3]))
+
+	assert.True(t, ids[0] != 0 && ids[2] != 0)
+	assert.Eq(t, uint64(0), ids[1])
+	assert.Eq(t, uint64(0), ids[3])
+	assert.Eq(t, ids[0], events[0].Id)
+	assert.Eq(t, uint64(0), events[1].Id) // reset after the rollback
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+
+	// no errors at all
+	ids, errs = box.PutManyBestEffort([]*iot.Event{{Device: "third", Uid: "c"}})
+	assert.True(t, errs == nil)
+	assert.Eq(t, 1, len(ids))
+}
+
+func TestUniqueReplaceFlagRequiresUnique(t *testing.T) {
+	var m = objectbox.NewModel()
+	m.Entity("Conflicting", 1, 10001)
+	m.Property("Id", 6, 1, 10002)
+	m.PropertyFlags(1)
+	m.Property("Name", 9, 2, 10003)
+	m.PropertyFlags(32768) // UNIQUE_ON_CONFLICT_REPLACE without UNIQUE
+	assert.Err(t, m.Error)
+
+	m = objectbox.NewModel()
+	m.Entity("Replacing", 1, 10001)
+	m.Property("Id", 6, 1, 10002)
+	m.PropertyFlags(1)
+	m.Property("Name", 9, 2, 10003)
+	m.PropertyFlags(32768 | 32 | 8) // UNIQUE_ON_CONFLICT_REPLACE | UNIQUE | INDEXED
+	m.PropertyIndex(1, 10004)
+	assert.NoErr(t, m.Error)
+}
+
+func TestModelValidationReportsAllProblems(t *testing.T) {
+	var m = objectbox.NewModel()
+	m.GeneratorVersion(6)
+	m.RegisterBinding(iot.EventBinding)
+	m.RegisterBinding(iot.ReadingBinding)
+	m.LastEntityId(1, 5284076134434938613) // Reading's UID; Event (ID 1) has a different one & Reading (ID 2) is higher
+	// LastIndexId() not called - both indexes (Event.Uid, Reading.EventId) are higher than the last index ID
+	assert.NoErr(t, m.Error)
+
+	_, err := objectbox.NewBuilder().Model(m).BuildOrError()
+	assert.Err(t, err)
+
+	validationErr, ok := err.(*objectbox.ModelValidationError)
+	assert.True(t, ok)
+	assert.Eq(t, 4, len(validationErr.Problems))
+	assert.Eq(t, "Event", validationErr.Problems[0].Entity)
+	assert.Eq(t, "", validationErr.Problems[0].Property)
+	assert.Eq(t, "Reading", validationErr.Problems[1].Entity)
+	assert.Eq(t, "Uid", validationErr.Problems[2].Property)
+	assert.Eq(t, "EventId", validationErr.Problems[3].Property)
+	assert.True(t, strings.Contains(err.Error(), "expected 5284076134434938613, found"))
+}
+
+func TestMergeModels(t *testing.T) {
+	// the iot entities as if they were generated in separate packages, each with its own ID range
+	var eventsModel = objectbox.NewModel()
+	eventsModel.GeneratorVersion(6)
+	eventsModel.RegisterBinding(iot.EventBinding)
+	eventsModel.LastEntityId(1, 1468539308767086854)
+	eventsModel.LastIndexId(1, 3297791712577314158)
+
+	var readingsModel = objectbox.NewModel()
+	readingsModel.GeneratorVersion(6)
+	readingsModel.RegisterBinding(iot.ReadingBinding) // with a relation to Event of the other "package"
+	readingsModel.LastEntityId(2, 5284076134434938613)
+	readingsModel.LastIndexId(2, 2642563953244304959)
+
+	ob, err := objectbox.NewBuilder().Directory("memory:merge-models").MergeModels(readingsModel, eventsModel).
+		BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	eventId, err := iot.BoxForEvent(ob).Put(&iot.Event{Device: "merged", Uid: "merged"})
+	assert.NoErr(t, err)
+	_, err = iot.BoxForReading(ob).Put(&iot.Reading{EventId: eventId, ValueName: "temperature"})
+	assert.NoErr(t, err)
+
+	readings, err := iot.BoxForReading(ob).Query(iot.Reading_.EventId.Equals(eventId)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(readings))
+
+	// both packages starting their IDs at 1
+	_, err = objectbox.NewBuilder().Model(iot.ObjectBoxModel()).MergeModels(model.ObjectBoxModel()).BuildOrError()
+	validationErr, ok := err.(*objectbox.ModelValidationError)
+	assert.True(t, ok)
+	assert.True(t, strings.Contains(validationErr.Problems[0].Message, "entity ID 1 is already used by Event"))
+}
+
+func TestStoreStats(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(10)
+
+	stats, err := env.ObjectBox.Stats()
+	assert.NoErr(t, err)
+	assert.Eq(t, env.Directory, stats.Directory)
+	assert.Eq(t, uint64(10), stats.EntityCounts["Entity"])
+	assert.Eq(t, uint64(0), stats.EntityCounts["TestEntityInline"])
+	assert.True(t, stats.SizeOnDisk > 0)
+	assert.Eq(t, stats.SizeOnDisk, env.ObjectBox.SizeOnDisk())
+	assert.True(t, stats.MaxSizeInKb > 0)
+	assert.True(t, stats.SizeUsage() > 0 && stats.SizeUsage() < 1)
+}
+
+func TestValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).Build()
+	assert.NoErr(t, err)
+	_, err = model.BoxForTestEntityEnum(ob).PutMany([]*model.TestEntityEnum{{}, {}, {}})
+	assert.NoErr(t, err)
+	ob.Close()
+
+	// reopen, checking all pages as would be done after an unclean shutdown
+	var options = objectbox.ValidateOptions{PageLimit: 1000, LeafPages: true, KeyValues: true}
+	ob, err = objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).ValidateOnOpen(options).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	result, err := ob.Validate(options)
+	assert.NoErr(t, err)
+	assert.True(t, result.Valid())
+	assert.Eq(t, 0, len(result.Problems))
+	assert.Eq(t, uint64(3), result.ObjectCounts["TestEntityEnum"])
+	assert.Eq(t, uint64(0), result.ObjectCounts["Entity"])
+}
+
+func TestDiagnosticBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		DiagnosticsOnCorruption(filepath.Join(dir, "diagnostics")).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	_, err = model.BoxForEntity(ob).Put(&model.Entity{})
+	assert.NoErr(t, err)
+
+	var buffer bytes.Buffer
+	assert.NoErr(t, ob.DiagnosticBundle(&buffer))
+
+	var bundle objectbox.DiagnosticBundle
+	assert.NoErr(t, json.Unmarshal(buffer.Bytes(), &bundle))
+	assert.Eq(t, "", bundle.Reason)
+	assert.Eq(t, dir, bundle.Directory)
+	assert.True(t, bundle.Model != nil)
+	assert.True(t, bundle.Stats != nil)
+	assert.Eq(t, uint64(1), bundle.Stats.EntityCounts["Entity"])
+	assert.True(t, len(bundle.Transactions) > 0)
+
+	var last = bundle.Transactions[len(bundle.Transactions)-1]
+	assert.True(t, !last.ReadOnly)
+	assert.True(t, last.Committed)
+}
+
+func TestStoreEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	var kinds []objectbox.StoreEventKind
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		OnEvent(func(event objectbox.StoreEvent) {
+			assert.True(t, !event.Time.IsZero())
+			kinds = append(kinds, event.Kind)
+		}).BuildOrError()
+	assert.NoErr(t, err)
+	assert.Eq(t, []objectbox.StoreEventKind{objectbox.StoreOpened}, kinds)
+
+	var retention []objectbox.StoreEvent
+	var unsubscribe = ob.SubscribeEvents(func(event objectbox.StoreEvent) {
+		if event.Kind == objectbox.StoreRetentionRun {
+			retention = append(retention, event)
+		}
+	})
+
+	_, err = model.BoxForEntity(ob).Put(&model.Entity{Date: time.Unix(1, 0)})
+	assert.NoErr(t, err)
+	var expirer = objectbox.NewExpirer(ob, time.Hour)
+	assert.NoErr(t, expirer.Expire(model.Entity_.Date))
+	_, err = expirer.RemoveExpired(time.Now())
+	assert.NoErr(t, err)
+	expirer.Close()
+
+	assert.Eq(t, 1, len(retention))
+	assert.Eq(t, uint64(1), retention[0].Count)
+	assert.NoErr(t, retention[0].Err)
+
+	unsubscribe()
+	ob.Close()
+	assert.Eq(t, 1, len(retention))
+	assert.Eq(t, []objectbox.StoreEventKind{objectbox.StoreOpened, objectbox.StoreRetentionRun, objectbox.StoreClosed},
+		kinds)
+}
+
+func TestClock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	var now = time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).ChangeLog(10).
+		Clock(func() time.Time { return now }).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+	assert.Eq(t, now, ob.Now())
+
+	var box = model.BoxForEntity(ob)
+	_, err = box.PutMany([]*model.Entity{{Date: now.Add(-2 * time.Hour)}, {Date: now.Add(-time.Minute)}})
+	assert.NoErr(t, err)
+
+	changes, err := ob.Changes(0)
+	assert.NoErr(t, err)
+	assert.Eq(t, now, changes[0].Timestamp)
+
+	var query = box.Query(model.Entity_.Date.GreaterThan(0))
+	assert.NoErr(t, query.SetTimeParamsFromNow(model.Entity_.Date, -time.Hour))
+	count, err := query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	// moving the clock forward
+	now = now.Add(time.Hour)
+	assert.NoErr(t, query.SetTimeParamsFromNow(model.Entity_.Date, -time.Hour))
+	count, err = query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), count)
+}
+
+func TestChangeLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).ChangeLog(5).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+	ids, err := box.PutMany([]*model.Entity{{}, {}})
+	assert.NoErr(t, err)
+	assert.NoErr(t, box.RemoveId(ids[0]))
+
+	changes, err := ob.Changes(0)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(changes))
+	assert.Eq(t, uint64(1), changes[0].Seq)
+	assert.Eq(t, model.EntityBinding.Id, changes[0].EntityId)
+	assert.Eq(t, ids[0], changes[0].ObjectId)
+	assert.Eq(t, objectbox.ChangePut, changes[0].Operation)
+	assert.Eq(t, ids[0], changes[2].ObjectId)
+	assert.Eq(t, objectbox.ChangeRemove, changes[2].Operation)
+
+	// changes of an aborted transaction are not recorded
+	assert.Err(t, ob.RunInWriteTx(func() error {
+		_, err := box.Put(&model.Entity{})
+		assert.NoErr(t, err)
+		return errors.New("rollback")
+	}))
+	changes, err = ob.Changes(3)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(changes))
+
+	assert.NoErr(t, box.RemoveAll())
+	_, err = box.Put(&model.Entity{})
+	assert.NoErr(t, err)
+	changes, err = ob.Changes(3)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(changes))
+	assert.Eq(t, objectbox.ChangeRemoveAll, changes[0].Operation)
+
+	// the capacity is 5, the first change is dropped
+	_, err = box.Put(&model.Entity{})
+	assert.NoErr(t, err)
+	_, err = ob.Changes(0)
+	assert.Eq(t, objectbox.ErrChangesUnavailable, err)
+	_, err = ob.Changes(7)
+	assert.Eq(t, objectbox.ErrChangesUnavailable, err)
+	changes, err = ob.Changes(1)
+	assert.NoErr(t, err)
+	assert.Eq(t, 5, len(changes))
+	assert.Eq(t, uint64(6), changes[4].Seq)
+}
+
+func TestBuilderOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		MaxSizeInKb(100*1024).
+		MaxDataSizeInKb(50*1024).
+		MaxReaders(500).
+		FileMode(0600).
+		AsyncEnqueueTimeout(5*time.Second).
+		AsyncMaxQueueLength(1000).
+		AsyncThrottle(500, time.Millisecond).
+		AsyncMaxInTx(100*time.Millisecond, 100).
+		BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	stats, err := ob.Stats()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(100*1024), stats.MaxSizeInKb)
+	assert.Eq(t, uint64(50*1024), stats.MaxDataSizeInKb)
+
+	info, err := os.Stat(filepath.Join(dir, "data.mdb"))
+	assert.NoErr(t, err)
+	assert.Eq(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the async box uses the configured enqueue timeout
+	var box = model.BoxForEntity(ob)
+	id, err := box.PutAsync(&model.Entity{})
+	assert.NoErr(t, err)
+	assert.NoErr(t, box.Async().AwaitCompletion())
+
+	contains, err := box.Contains(id)
+	assert.NoErr(t, err)
+	assert.True(t, contains)
+}
+
+// replacingEventBinding declares Event.Uid as `objectbox:"unique:replace"`, which the generator doesn't support yet
+type replacingEventBinding struct {
+	objectbox.ObjectBinding
+}
+
+func (replacingEventBinding) AddToModel(model *objectbox.Model) {
+	model.Entity("Event", 1, 1468539308767086854)
+	model.Property("Id", 6, 1, 3098166604415018001)
+	model.PropertyFlags(1)
+	model.Property("Device", 9, 2, 1213411729427304641)
+	model.Property("Date", 10, 3, 5907655274386702697)
+	model.Property("Uid", 9, 4, 472416569173577818)
+	model.PropertyFlags(2080 | 32768) // UNIQUE | INDEX_HASH | UNIQUE_ON_CONFLICT_REPLACE
+	model.PropertyIndex(1, 3297791712577314158)
+	model.Property("Picture", 23, 5, 6024563395733984005)
+	model.EntityLastPropertyId(5, 6024563395733984005)
+}
+
+func TestUniqueReplace(t *testing.T) {
+	var m = objectbox.NewModel()
+	m.GeneratorVersion(6)
+	m.RegisterBinding(replacingEventBinding{iot.EventBinding})
+	m.LastEntityId(1, 1468539308767086854)
+	m.LastIndexId(1, 3297791712577314158)
+
+	ob, err := objectbox.NewBuilder().InMemory("unique-replace").Model(m).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+	box := iot.BoxForEvent(ob)
+
+	id1, err := box.Put(&iot.Event{Uid: "duplicate", Device: "first"})
+	assert.NoErr(t, err)
+
+	// instead of failing with a unique violation, the existing object is replaced
+	id2, err := box.Put(&iot.Event{Uid: "duplicate", Device: "second"})
+	assert.NoErr(t, err)
+	assert.True(t, id1 != id2)
 
 	count, err := box.Count()
 	assert.NoErr(t, err)
 	assert.Eq(t, uint64(1), count)
+
+	event, err := box.Get(id1)
+	assert.NoErr(t, err)
+	assert.True(t, event == nil)
+
+	event, err = box.Get(id2)
+	assert.NoErr(t, err)
+	assert.Eq(t, "second", event.Device)
 }
 
 func TestBoxBulk(t *testing.T) {
@@ -180,6 +970,52 @@ func TestPutInMemoryDB(t *testing.T) {
 	RunTestPut(t, env)
 }
 
+func TestPersistTo(t *testing.T) {
+	ob, err := objectbox.NewBuilder().InMemory("persist-test").Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+	assert.True(t, ob.IsInMemory())
+
+	var box = model.BoxForEntity(ob)
+	_, err = box.Put(&model.Entity{String: "first"})
+	assert.NoErr(t, err)
+	id, err := box.Put(&model.Entity{String: "second", RelatedSlice: []model.EntityByValue{{Text: "related"}}})
+	assert.NoErr(t, err)
+
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	// a directory with other files must not be replaced
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte{}, 0644))
+	assert.Err(t, ob.PersistTo(dir))
+
+	var path = filepath.Join(dir, "fixture")
+	assert.NoErr(t, ob.PersistTo(path))
+
+	// an existing snapshot is replaced; the remaining object keeps its ID although Entity's ID isn't self-assignable
+	assert.NoErr(t, box.RemoveId(1))
+	assert.NoErr(t, ob.PersistTo(path))
+
+	persisted, err := objectbox.NewBuilder().Directory(path).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer persisted.Close()
+	assert.True(t, !persisted.IsInMemory())
+
+	objects, err := model.BoxForEntity(persisted).GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(objects))
+	assert.Eq(t, id, objects[0].Id)
+	assert.Eq(t, "second", objects[0].String)
+	assert.Eq(t, 1, len(objects[0].RelatedSlice))
+	assert.Eq(t, "related", objects[0].RelatedSlice[0].Text)
+
+	// IDs of new objects continue after the persisted ones
+	newId, err := model.BoxForEntity(persisted).Put(&model.Entity{})
+	assert.NoErr(t, err)
+	assert.True(t, newId > id)
+}
+
 // Not sure if this is the best way to "parameterize" test...
 func RunTestPut(t *testing.T, env *iot.TestEnv) {
 	defer env.Close()
@@ -255,6 +1091,102 @@ func TestBoxUpdate(t *testing.T) {
 	assert.Eq(t, object, objectRead)
 }
 
+func TestBoxPutIfChanged(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityEnum(env.ObjectBox)
+	var object = &model.TestEntityEnum{Status: model.StatusActive, Color: model.ColorRed}
+
+	// new objects are always written
+	id, changed, err := box.PutIfChanged(object)
+	assert.NoErr(t, err)
+	assert.True(t, changed)
+	assert.Eq(t, uint64(1), id)
+	assert.Eq(t, id, object.Id)
+
+	hash, err := box.ContentHash(object)
+	assert.NoErr(t, err)
+
+	id, changed, err = box.PutIfChanged(&model.TestEntityEnum{Id: 1, Status: model.StatusActive, Color: model.ColorRed})
+	assert.NoErr(t, err)
+	assert.True(t, !changed)
+	assert.Eq(t, uint64(1), id)
+
+	object.Color = model.ColorGreen
+	changedHash, err := box.ContentHash(object)
+	assert.NoErr(t, err)
+	assert.True(t, hash != changedHash)
+
+	_, changed, err = box.PutIfChanged(object)
+	assert.NoErr(t, err)
+	assert.True(t, changed)
+
+	read, err := box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, model.ColorGreen, read.Color)
+
+	// an ID set but not stored yet
+	_, changed, err = box.PutIfChanged(&model.TestEntityEnum{Id: 10})
+	assert.NoErr(t, err)
+	assert.True(t, changed)
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+}
+
+// nonceCodec encodes values differently each time, like an encryption with a random nonce would
+type nonceCodec struct {
+	nonce *byte
+}
+
+func (codec nonceCodec) Encode(propertyId objectbox.TypeId, value []byte) ([]byte, error) {
+	*codec.nonce++
+	return base64Codec{}.Encode(propertyId, append([]byte{*codec.nonce}, value...))
+}
+
+func (codec nonceCodec) Decode(propertyId objectbox.TypeId, value []byte) ([]byte, error) {
+	decoded, err := base64Codec{}.Decode(propertyId, value)
+	if err != nil || len(decoded) == 0 {
+		return decoded, err
+	}
+	return decoded[1:], nil
+}
+
+func TestBoxPutIfChangedWithCodec(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	var nonce byte
+	assert.NoErr(t, env.ObjectBox.SetPropertyCodec(iot.EventBinding.Id, nonceCodec{&nonce}, iot.Event_.Device.Id))
+
+	var object = &iot.Event{Device: "sensor", Uid: "a"}
+	_, changed, err := box.PutIfChanged(object)
+	assert.NoErr(t, err)
+	assert.True(t, changed)
+
+	// the same content, although encoded differently
+	hash, err := box.ContentHash(object)
+	assert.NoErr(t, err)
+	sameHash, err := box.ContentHash(object)
+	assert.NoErr(t, err)
+	assert.Eq(t, hash, sameHash)
+
+	_, changed, err = box.PutIfChanged(&iot.Event{Id: object.Id, Device: "sensor", Uid: "a"})
+	assert.NoErr(t, err)
+	assert.True(t, !changed)
+
+	object.Device = "other sensor"
+	_, changed, err = box.PutIfChanged(object)
+	assert.NoErr(t, err)
+	assert.True(t, changed)
+
+	read, err := box.Get(object.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "other sensor", read.Device)
+}
+
 func TestBoxCount(t *testing.T) {
 	var env = model.NewTestEnv(t)
 	defer env.Close()
@@ -376,3 +1308,657 @@ func TestBoxGetMany(t *testing.T) {
 	assert.Eq(t, 1, len(objects))
 	assert.True(t, objects[0].Id == 1)
 }
+
+func TestThreadPinning(t *testing.T) {
+	assert.Eq(t, objectbox.ThreadPinningAlways, objectbox.GetThreadPinning())
+	defer objectbox.SetThreadPinning(objectbox.ThreadPinningAlways)
+
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+	env.Populate(3)
+
+	for _, policy := range []objectbox.ThreadPinning{objectbox.ThreadPinningAlways, objectbox.ThreadPinningOnError} {
+		objectbox.SetThreadPinning(policy)
+		assert.Eq(t, policy, objectbox.GetThreadPinning())
+
+		contains, err := env.Box.Contains(2)
+		assert.NoErr(t, err)
+		assert.True(t, contains)
+
+		count, err := env.Box.Count()
+		assert.NoErr(t, err)
+		assert.Eq(t, uint64(3), count)
+
+		// failures are reported regardless of the policy
+		assert.Err(t, env.Box.RemoveId(100))
+	}
+}
+
+func TestBoxAppendMany(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	// more than a single append batch
+	env.Populate(150)
+
+	// the Entity binding implements ObjectBindingAppendMany
+	all, err := env.Box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 150, len(all))
+	for i, object := range all {
+		assert.Eq(t, uint64(i+1), object.Id)
+	}
+
+	many, err := env.Box.GetMany(1, 1000, 150)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(many))
+	assert.Eq(t, uint64(1), many[0].Id)
+	assert.True(t, many[1] == nil)
+	assert.Eq(t, uint64(150), many[2].Id)
+
+	// the visitor based path
+	found, err := env.Box.Query().FindWithContext(context.Background())
+	assert.NoErr(t, err)
+	assert.Eq(t, all, found.([]*model.Entity))
+}
+
+func TestBoxInternStrings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(iot.ObjectBoxModel()).
+		InternStrings(iot.Event_.Device).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = iot.BoxForEvent(ob)
+	for i := 0; i < 10; i++ {
+		iot.PutEvent(ob, fmt.Sprintf("device %d", i%2), int64(i))
+	}
+
+	var stringData = func(s string) uintptr {
+		return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+	}
+
+	events, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 10, len(events))
+	for i := 2; i < len(events); i++ {
+		assert.Eq(t, events[i-2].Device, events[i].Device)
+		assert.Eq(t, stringData(events[i-2].Device), stringData(events[i].Device))
+	}
+
+	event, err := box.Get(events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, stringData(events[0].Device), stringData(event.Device))
+
+	// only string properties can be interned
+	_, err = objectbox.NewBuilder().Directory(dir).Model(iot.ObjectBoxModel()).
+		InternStrings(&objectbox.PropertyString{BaseProperty: iot.Event_.Date.BaseProperty}).BuildOrError()
+	assert.Err(t, err)
+}
+
+func TestUseAfterClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).Build()
+	assert.NoErr(t, err)
+	var box = model.BoxForTestEntityEnum(ob)
+	id, err := box.Put(&model.TestEntityEnum{})
+	assert.NoErr(t, err)
+	var query = box.Query(model.TestEntityEnum_.Id.Equals(id))
+	var propertyQuery = query.Property(model.TestEntityEnum_.Status)
+	async, err := objectbox.NewAsyncBox(ob, model.TestEntityEnumBinding.Id, 100)
+	assert.NoErr(t, err)
+	var entityBox = model.BoxForEntity(ob)
+	ob.ClearBoxCache() // the dropped boxes are invalidated by closing the store as well
+	ob.Close()
+
+	// e.g. a box kept by a test after the store was closed and reopened
+	ob, err = objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).Build()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	_, err = box.Get(id)
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = box.Put(&model.TestEntityEnum{})
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = box.Count()
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	assert.Eq(t, objectbox.ErrStoreClosed, box.RemoveId(id))
+	_, err = query.Find()
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = box.QueryOrError()
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = propertyQuery.Count()
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = query.Clone()
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = query.Describe()
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	assert.Eq(t, objectbox.ErrStoreClosed, query.SetInt64Params(model.TestEntityEnum_.Id, int64(id)))
+	_, err = entityBox.RelationIds(model.Entity_.RelatedPtrSlice, 1)
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = entityBox.BacklinkIds(model.Entity_.RelatedPtrSlice, 1)
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = async.Put(&model.TestEntityEnum{})
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	assert.Eq(t, objectbox.ErrStoreClosed, async.AwaitCompletion())
+	assert.Eq(t, objectbox.ErrStoreClosed, box.Async().RemoveId(id))
+	assert.NoErr(t, async.Close()) // already closed together with the store
+	assert.NoErr(t, propertyQuery.Close())
+	assert.NoErr(t, query.Close())
+
+	// a transaction running while the store is closed keeps the native store until it's finished
+	other, err := objectbox.NewBuilder().Directory("memory:closed-tx").Model(model.ObjectBoxModel()).Build()
+	assert.NoErr(t, err)
+	tx, err := other.BeginTx()
+	assert.NoErr(t, err)
+	other.Close()
+	assert.Eq(t, objectbox.ErrStoreClosed, tx.Commit())
+	assert.True(t, !tx.IsActive())
+
+	count, err := model.BoxForTestEntityEnum(ob).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	// a relation not defined by the model of the store
+	var relation = &objectbox.RelationToMany{Id: 99, Source: &model.EntityBinding.Entity,
+		Target: &model.EntityBinding.Entity}
+	_, err = model.BoxForEntity(ob).RelationIds(relation, 1)
+	assert.Eq(t, "relation 99 isn't part of entity Entity in the model of this store", err.Error())
+}
+
+func TestBoxVisit(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityEnum(env.ObjectBox)
+	ids, err := box.PutMany([]*model.TestEntityEnum{{Color: model.ColorRed}, {Color: model.ColorGreen}, {}})
+	assert.NoErr(t, err)
+
+	var colors []model.Color
+	assert.NoErr(t, box.VisitAll(func(object interface{}) bool {
+		colors = append(colors, object.(*model.TestEntityEnum).Color)
+		return true
+	}))
+	assert.Eq(t, []model.Color{model.ColorRed, model.ColorGreen, ""}, colors)
+
+	// stops early
+	var visited = 0
+	assert.NoErr(t, box.VisitAll(func(object interface{}) bool {
+		visited++
+		return false
+	}))
+	assert.Eq(t, 1, visited)
+
+	var objects []interface{}
+	assert.NoErr(t, box.VisitIds([]uint64{ids[1], 999, ids[0]}, func(object interface{}) bool {
+		objects = append(objects, object)
+		return true
+	}))
+	assert.Eq(t, 3, len(objects))
+	assert.Eq(t, model.ColorGreen, objects[0].(*model.TestEntityEnum).Color)
+	assert.True(t, objects[1] == nil)
+	assert.Eq(t, ids[0], objects[2].(*model.TestEntityEnum).Id)
+}
+
+func TestBoxApplyBatch(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = env.Box
+	ids, err := box.PutMany([]*model.Entity{{String: "keep"}, {String: "remove"}, {String: "update"}})
+	assert.NoErr(t, err)
+
+	var created = &model.Entity{String: "new"}
+	var updated = &model.Entity{Id: ids[2], String: "updated"}
+	batchIds, err := box.ApplyBatch([]objectbox.Operation{
+		{Kind: objectbox.OperationPut, Object: created},
+		{Kind: objectbox.OperationRemove, Id: ids[1]},
+		{Kind: objectbox.OperationUpdate, Object: updated},
+		{Kind: objectbox.OperationRemove, Id: ids[1]}, // already removed
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{created.Id, ids[1], ids[2], ids[1]}, batchIds)
+
+	objects, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(objects))
+	assert.Eq(t, "keep", objects[0].String)
+	assert.Eq(t, "updated", objects[1].String)
+	assert.Eq(t, "new", objects[2].String)
+
+	// a failing operation rolls back the whole batch
+	_, err = box.ApplyBatch([]objectbox.Operation{
+		{Kind: objectbox.OperationRemove, Object: objects[0]},
+		{Kind: objectbox.OperationUpdate, Object: &model.Entity{Id: 1000, String: "missing"}},
+	})
+	assert.Err(t, err)
+	opErr, isOpErr := err.(*objectbox.OperationError)
+	assert.True(t, isOpErr)
+	assert.Eq(t, 1, opErr.Index)
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+}
+
+func TestCloseWithTimeout(t *testing.T) {
+	var env = iot.NewTestEnv()
+	defer env.Close()
+
+	var box = iot.BoxForEvent(env.ObjectBox)
+
+	// a transaction still running keeps the store open
+	var started = make(chan struct{})
+	var release = make(chan struct{})
+	var finished = make(chan error)
+	go func() {
+		finished <- env.ObjectBox.RunInReadTx(func() error {
+			close(started)
+			<-release
+
+			// nested transactions are still accepted so that the running one can finish
+			return env.ObjectBox.RunInReadTx(func() error {
+				_, err := box.Count()
+				return err
+			})
+		})
+	}()
+	<-started
+
+	err := env.ObjectBox.CloseWithTimeout(10 * time.Millisecond)
+	assert.Err(t, err)
+	timeoutErr, isTimeout := err.(*objectbox.CloseTimeoutError)
+	assert.True(t, isTimeout)
+	assert.Eq(t, 1, timeoutErr.ActiveTransactions)
+
+	// no new transactions or async operations are accepted, the running one may finish
+	assert.Eq(t, objectbox.ErrStoreClosing, env.ObjectBox.RunInReadTx(func() error { return nil }))
+	_, err = box.Async().Put(&iot.Event{Device: "late"})
+	assert.Eq(t, objectbox.ErrStoreClosing, err)
+	_, err = box.Async().PutMany([]*iot.Event{{Device: "late"}})
+	assert.Eq(t, objectbox.ErrStoreClosing, err)
+	close(release)
+	assert.NoErr(t, <-finished)
+
+	// Close() finishes the closing
+	env.ObjectBox.Close()
+	_, err = box.Count()
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	assert.NoErr(t, env.ObjectBox.CloseWithTimeout(time.Second))
+}
+
+func TestCloseWithTimeoutDrainsAsync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	var env = iot.NewTestEnvWithDir(t, dir)
+	var box = iot.BoxForEvent(env.ObjectBox)
+	for i := 0; i < 100; i++ {
+		_, err := box.Async().Put(&iot.Event{Device: "async"})
+		assert.NoErr(t, err)
+	}
+	assert.NoErr(t, env.ObjectBox.CloseWithTimeout(10*time.Second))
+
+	// all async puts submitted before closing have been written
+	env = iot.NewTestEnvWithDir(t, dir)
+	defer env.Close()
+	count, err := iot.BoxForEvent(env.ObjectBox).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(100), count)
+}
+
+func TestContentionReport(t *testing.T) {
+	ob, err := objectbox.NewBuilder().Directory("memory:contention").Model(iot.ObjectBoxModel()).
+		RecordContention(5).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	// transactions started at the same call site by different goroutines are reported as a single blocker
+	for i := 0; i < 2; i++ {
+		var started = make(chan struct{})
+		var finished = make(chan error)
+		go func() {
+			finished <- ob.RunInWriteTx(func() error {
+				close(started)
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			})
+		}()
+		<-started
+
+		// waits for the transaction of the goroutine above
+		assert.NoErr(t, ob.RunInWriteTx(func() error { return nil }))
+		assert.NoErr(t, <-finished)
+	}
+
+	report, err := ob.ContentionReport(true)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(4), report.WriteTransactions)
+	assert.True(t, report.MaxWait >= 10*time.Millisecond)
+	assert.Eq(t, 1, len(report.Blockers))
+	assert.Eq(t, uint64(2), report.Blockers[0].Waits)
+	assert.True(t, strings.Contains(report.Blockers[0].Stack, "TestContentionReport"))
+	assert.True(t, strings.Contains(report.Blockers[0].Stack, "box_test.go:"))
+	assert.True(t, !strings.Contains(report.Blockers[0].Stack, "goroutine"))
+	assert.True(t, strings.Contains(report.String(), "4 write transactions"))
+
+	// the recording has been reset
+	report, err = ob.ContentionReport(false)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), report.WriteTransactions)
+	assert.Eq(t, 0, len(report.Blockers))
+
+	// not enabled
+	var env = iot.NewTestEnv()
+	defer env.Close()
+	_, err = env.ObjectBox.ContentionReport(false)
+	assert.Err(t, err)
+}
+
+func TestBoxOptimisticLocking(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	// any integer property can be used as a version, e.g. ValueInteger here
+	assert.NoErr(t, env.ObjectBox.SetVersionProperty(iot.Reading_.ValueInteger))
+	assert.Err(t, env.ObjectBox.SetVersionProperty(iot.Reading_.ValueName))
+
+	var reading = &iot.Reading{ValueName: "temp"}
+	id, err := box.Put(reading)
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(1), reading.ValueInteger)
+
+	stale, err := box.Get(id)
+	assert.NoErr(t, err)
+
+	_, err = box.Put(reading)
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(2), reading.ValueInteger)
+
+	// the copy read before the last put is outdated
+	stale.ValueName = "stale"
+	_, err = box.Put(stale)
+	assert.Eq(t, objectbox.ErrConcurrentModification, err)
+	assert.Eq(t, int64(1), stale.ValueInteger)
+	assert.Eq(t, objectbox.ErrConcurrentModification, box.Update(stale))
+
+	_, err = box.PutMany([]*iot.Reading{reading, {ValueName: "new"}})
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(3), reading.ValueInteger)
+
+	stored, err := box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "temp", stored.ValueName)
+	assert.Eq(t, int64(3), stored.ValueInteger)
+
+	// a failed PutMany restores the versions of the objects put before the failing one...
+	var fresh = &iot.Reading{ValueName: "fresh"}
+	_, err = box.PutMany([]*iot.Reading{reading, fresh, stale})
+	assert.Eq(t, objectbox.ErrConcurrentModification, err)
+	assert.Eq(t, int64(3), reading.ValueInteger)
+	assert.Eq(t, int64(0), fresh.ValueInteger)
+
+	// ... so does PutManyBestEffort before putting the objects of the failed chunk one by one
+	fresh = &iot.Reading{ValueName: "fresh"} // the rolled back PutMany has left an ID assigned
+	ids, errs := box.PutManyBestEffort([]*iot.Reading{reading, fresh, stale})
+	assert.Eq(t, 3, len(errs))
+	assert.NoErr(t, errs[0])
+	assert.NoErr(t, errs[1])
+	assert.Eq(t, objectbox.ErrConcurrentModification, errs[2])
+	assert.Eq(t, int64(4), reading.ValueInteger)
+	assert.Eq(t, int64(1), fresh.ValueInteger)
+	assert.Eq(t, int64(1), stale.ValueInteger)
+
+	stored, err = box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(4), stored.ValueInteger)
+	stored, err = box.Get(ids[1])
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(1), stored.ValueInteger)
+
+	_, err = box.Async().Put(&iot.Reading{})
+	assert.Err(t, err)
+	_, err = box.Async().PutMany([]*iot.Reading{{}, {}})
+	assert.Err(t, err)
+}
+
+func TestBoxSoftDelete(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	// Date serves as the deletion time here
+	assert.NoErr(t, env.ObjectBox.SetSoftDelete(iot.Event_.Date))
+	assert.Err(t, env.ObjectBox.SetSoftDelete(iot.Reading_.ValueInteger))
+
+	ids, err := box.PutMany([]*iot.Event{{Uid: "1"}, {Uid: "2"}, {Uid: "3"}})
+	assert.NoErr(t, err)
+
+	assert.NoErr(t, box.RemoveId(ids[0]))
+	assert.Err(t, box.RemoveId(ids[0])) // already removed
+
+	// the removed object still exists...
+	removed, err := box.GetWithDeleted(ids[0])
+	assert.NoErr(t, err)
+	assert.True(t, removed.(*iot.Event).Date > 0)
+
+	// ... but is excluded from reads by ID, queries, GetAll and Count
+	event, err := box.Get(ids[0])
+	assert.NoErr(t, err)
+	assert.True(t, event == nil)
+	contains, err := box.Contains(ids[0])
+	assert.NoErr(t, err)
+	assert.True(t, !contains)
+	contains, err = box.ContainsIds(ids[1], ids[2])
+	assert.NoErr(t, err)
+	assert.True(t, contains)
+	many, err := box.GetMany(ids...)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(many))
+	assert.True(t, many[0] == nil && many[1] != nil && many[2] != nil)
+	many, err = box.GetManyExisting(ids...)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(many))
+	var visited []*iot.Event
+	assert.NoErr(t, box.Box.VisitIds(ids, func(object interface{}) bool {
+		event, _ := object.(*iot.Event)
+		visited = append(visited, event)
+		return true
+	}))
+	assert.True(t, len(visited) == 3 && visited[0] == nil && visited[1] != nil)
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+	all, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(all))
+	found, err := box.Query(iot.Event_.Uid.Equals("1", true)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(found))
+
+	query, err := box.QueryWithDeleted()
+	assert.NoErr(t, err)
+	count, err = query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+
+	query, err = box.QueryDeleted()
+	assert.NoErr(t, err)
+	deletedIds, err := query.FindIds()
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{ids[0]}, deletedIds)
+
+	// ordering keeps the soft-delete condition of the query
+	deletedIds, err = query.Order(iot.Event_.Id.Desc()).FindIds()
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{ids[0]}, deletedIds)
+	query, err = box.QueryWithDeleted()
+	assert.NoErr(t, err)
+	allIds, err := query.Order(iot.Event_.Id.Desc()).FindIds()
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{ids[2], ids[1], ids[0]}, allIds)
+	count, err = box.Query().Order(iot.Event_.Id.Desc()).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	// async removal would delete permanently
+	assert.Err(t, box.Async().RemoveId(ids[1]))
+	assert.Err(t, box.Async().RemoveIds(ids[1], ids[2]))
+
+	// removing by a query only marks the objects, too
+	removedCount, err := box.Query(iot.Event_.Uid.Equals("2", true)).Remove()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), removedCount)
+	query, err = box.QueryWithDeleted()
+	assert.NoErr(t, err)
+	count, err = query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+
+	removedCount, err = box.RemoveIds(ids...)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), removedCount)
+	empty, err := box.IsEmpty()
+	assert.NoErr(t, err)
+	assert.True(t, empty)
+
+	// purge deletes permanently, only those removed before the given time
+	purged, err := box.Purge(time.Unix(0, 1))
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), purged)
+	purged, err = box.Purge(time.Now().Add(time.Second))
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), purged)
+
+	query, err = box.QueryWithDeleted()
+	assert.NoErr(t, err)
+	count, err = query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), count)
+
+	// not enabled for Reading
+	_, err = iot.BoxForReading(env.ObjectBox).Purge(time.Now())
+	assert.Err(t, err)
+}
+
+func TestBoxQuota(t *testing.T) {
+	ob, err := objectbox.NewBuilder().Directory("memory:quota").Model(iot.ObjectBoxModel()).
+		BoxQuota(iot.EventBinding.Id, objectbox.BoxQuota{MaxCount: 3}).
+		BoxQuota(iot.ReadingBinding.Id, objectbox.BoxQuota{MaxBytes: 2000}).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var events = iot.BoxForEvent(ob)
+	_, err = events.PutMany([]*iot.Event{{Device: "1", Uid: "1"}, {Device: "2", Uid: "2"}, {Device: "3", Uid: "3"}})
+	assert.NoErr(t, err)
+
+	_, err = events.Put(&iot.Event{Device: "4", Uid: "4"})
+	quotaErr, isQuotaErr := err.(*objectbox.QuotaExceededError)
+	assert.True(t, isQuotaErr)
+	assert.Eq(t, "Event", quotaErr.Entity)
+	assert.Eq(t, uint64(4), quotaErr.Count)
+
+	// updates don't add objects
+	event, err := events.Get(1)
+	assert.NoErr(t, err)
+	event.Device = "updated"
+	_, err = events.Put(event)
+	assert.NoErr(t, err)
+
+	// nothing is written by a failing PutMany
+	assert.NoErr(t, events.RemoveId(1))
+	_, err = events.PutMany([]*iot.Event{{Device: "5", Uid: "5"}, {Device: "6", Uid: "6"}})
+	assert.Err(t, err)
+	count, err := events.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	// the size is tracked across puts and removals
+	var readings = iot.BoxForReading(ob)
+	for i := 0; i < 3; i++ {
+		_, err = readings.Put(&iot.Reading{ValueName: strings.Repeat("x", 500)})
+		assert.NoErr(t, err)
+	}
+	_, err = readings.Put(&iot.Reading{ValueName: strings.Repeat("x", 500)})
+	quotaErr, isQuotaErr = err.(*objectbox.QuotaExceededError)
+	assert.True(t, isQuotaErr)
+	assert.True(t, quotaErr.Bytes > 2000)
+	assert.True(t, strings.Contains(err.Error(), "bytes"))
+
+	// an aborted transaction doesn't count
+	assert.Err(t, ob.RunInWriteTx(func() error {
+		_, err := readings.Put(&iot.Reading{ValueName: "small"})
+		assert.NoErr(t, err)
+		return errors.New("rollback")
+	}))
+	assert.NoErr(t, readings.RemoveId(1))
+	_, err = readings.Put(&iot.Reading{ValueName: strings.Repeat("x", 500)})
+	assert.NoErr(t, err)
+
+	// neither does one aborted by a clone of the store
+	assert.NoErr(t, readings.RemoveAll())
+	_, err = readings.Put(&iot.Reading{ValueName: strings.Repeat("x", 1000)})
+	assert.NoErr(t, err)
+	clone, err := ob.Clone()
+	assert.NoErr(t, err)
+	defer clone.Close()
+	assert.Err(t, clone.RunInWriteTx(func() error {
+		_, err := iot.BoxForReading(clone).Put(&iot.Reading{ValueName: strings.Repeat("x", 800)})
+		assert.NoErr(t, err)
+		return errors.New("rollback")
+	}))
+	_, err = readings.Put(&iot.Reading{ValueName: strings.Repeat("x", 500)})
+	assert.NoErr(t, err)
+
+	// removing by a query makes room as well
+	_, err = readings.Put(&iot.Reading{ValueName: strings.Repeat("y", 500)})
+	assert.Err(t, err)
+	removed, err := readings.Query(iot.Reading_.ValueName.HasPrefix("x", true)).Remove()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), removed)
+	_, err = readings.Put(&iot.Reading{ValueName: strings.Repeat("y", 1500)})
+	assert.NoErr(t, err)
+}
+
+func TestBoxQuotaCallback(t *testing.T) {
+	var calls = 0
+	ob, err := objectbox.NewBuilder().Directory("memory:quota-callback").Model(iot.ObjectBoxModel()).
+		BoxQuota(iot.EventBinding.Id, objectbox.BoxQuota{MaxCount: 2}).
+		OnQuotaExceeded(func(box *objectbox.Box, err *objectbox.QuotaExceededError) error {
+			calls++
+			// evict the oldest object
+			ids, findErr := box.Query().Limit(1).FindIds()
+			if findErr != nil {
+				return findErr
+			}
+			_, findErr = box.RemoveIds(ids...)
+			return findErr
+		}).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var events = iot.BoxForEvent(ob)
+	for i := 1; i <= 4; i++ {
+		_, err = events.Put(&iot.Event{Device: strconv.Itoa(i), Uid: strconv.Itoa(i)})
+		assert.NoErr(t, err)
+	}
+	assert.Eq(t, 2, calls)
+
+	all, err := events.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(all))
+	assert.Eq(t, "3", all[0].Device)
+	assert.Eq(t, "4", all[1].Device)
+
+	_, err = objectbox.NewBuilder().Directory("memory:quota-invalid").Model(iot.ObjectBoxModel()).
+		BoxQuota(99, objectbox.BoxQuota{MaxCount: 1}).BuildOrError()
+	assert.Err(t, err)
+}
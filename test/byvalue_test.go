@@ -19,6 +19,7 @@ package objectbox_test
 import (
 	"testing"
 
+	"github.com/objectbox/objectbox-go/objectbox"
 	"github.com/objectbox/objectbox-go/test/assert"
 	"github.com/objectbox/objectbox-go/test/model"
 )
@@ -81,3 +82,47 @@ func TestEntityByValue(t *testing.T) {
 	assert.Eq(t, uint64(3), objects[1].Id)
 	assert.Eq(t, uint64(4), objects[2].Id)
 }
+
+func TestEntityByValueGetAllInto(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	box := model.BoxForEntityByValue(env.ObjectBox)
+	for i := 0; i < 3; i++ {
+		_, err := box.Put(&model.EntityByValue{Text: "value"})
+		assert.NoErr(t, err)
+	}
+
+	var objects = make([]model.EntityByValue, 0, 10)
+	var backingArray = &objects[:1][0]
+	assert.NoErr(t, box.GetAllInto(&objects))
+	assert.Eq(t, 3, len(objects))
+	assert.Eq(t, uint64(3), objects[2].Id)
+	assert.Eq(t, "value", objects[0].Text)
+	assert.True(t, backingArray == &objects[0])
+
+	// the slice is truncated before reading
+	assert.NoErr(t, box.Remove(&objects[0]))
+	assert.NoErr(t, box.GetAllInto(&objects))
+	assert.Eq(t, 2, len(objects))
+	assert.Eq(t, uint64(2), objects[0].Id)
+	assert.True(t, backingArray == &objects[0])
+
+	// the elements are kept if the read fails after reading some of the objects
+	objects[0].Text = "local"
+	env.ObjectBox.SetMemoryPressureHook(func() bool { return true }, 1)
+	assert.Eq(t, objectbox.ErrResultBudgetExceeded, box.GetAllInto(&objects))
+	env.ObjectBox.SetMemoryPressureHook(nil, 0)
+	assert.Eq(t, 2, len(objects))
+	assert.Eq(t, "local", objects[0].Text)
+	assert.Eq(t, uint64(3), objects[1].Id)
+
+	// a nil slice is allocated
+	var empty []model.EntityByValue
+	assert.NoErr(t, box.GetAllInto(&empty))
+	assert.Eq(t, 2, len(empty))
+
+	// the type must match
+	assert.Err(t, box.GetAllInto(objects))
+	assert.Err(t, box.GetAllInto(&[]*model.EntityByValue{}))
+}
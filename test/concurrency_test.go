@@ -17,12 +17,16 @@
 package objectbox_test
 
 import (
+	"fmt"
 	"runtime"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/objectbox/objectbox-go/objectbox"
 	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
 	"github.com/objectbox/objectbox-go/test/model/iot"
 )
 
@@ -194,3 +198,83 @@ func TestConcurrentQuery(t *testing.T) {
 	}
 	assert.Eq(t, 0, len(errors))
 }
+
+func TestConcurrentBatchedReads(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+	env.Populate(10)
+
+	// reopen the store with read batching enabled
+	env.ObjectBox.Close()
+	ob, err := objectbox.NewBuilder().Directory(env.Directory).Model(model.ObjectBoxModel()).
+		BatchReads(5 * time.Millisecond).Build()
+	assert.NoErr(t, err)
+	env.ObjectBox = ob
+	var box = model.BoxForEntity(ob)
+
+	var wg sync.WaitGroup
+	var errs = make(chan error, 100)
+	for i := uint64(1); i <= 20; i++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			var exists = id <= 10
+
+			if object, err := box.Get(id); err != nil {
+				errs <- err
+			} else if exists != (object != nil) || (exists && object.Id != id) {
+				errs <- fmt.Errorf("unexpected object %v for ID %d", object, id)
+			}
+
+			if contains, err := box.Contains(id); err != nil {
+				errs <- err
+			} else if contains != exists {
+				errs <- fmt.Errorf("unexpected contains %v for ID %d", contains, id)
+			}
+
+			if count, err := box.Count(); err != nil {
+				errs <- err
+			} else if count != 10 {
+				errs <- fmt.Errorf("unexpected count %d", count)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoErr(t, err)
+	}
+
+	// reads inside a transaction aren't batched and see its changes
+	assert.NoErr(t, ob.RunInWriteTx(func() error {
+		id, err := box.Put(&model.Entity{})
+		assert.NoErr(t, err)
+
+		object, err := box.Get(id)
+		assert.NoErr(t, err)
+		assert.True(t, object != nil)
+
+		count, err := box.Count()
+		assert.NoErr(t, err)
+		assert.Eq(t, uint64(11), count)
+		return nil
+	}))
+	// a transaction held by another goroutine doesn't affect reads outside of it
+	var txStarted = make(chan struct{})
+	var txFinish = make(chan struct{})
+	var txDone = make(chan error)
+	go func() {
+		txDone <- ob.RunInWriteTx(func() error {
+			_, err := box.Put(&model.Entity{})
+			close(txStarted)
+			<-txFinish
+			return err
+		})
+	}()
+	<-txStarted
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(11), count)
+	close(txFinish)
+	assert.NoErr(t, <-txDone)
+}
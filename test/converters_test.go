@@ -161,3 +161,65 @@ func TestTimeBinaryConverter(t *testing.T) {
 		assert.Eq(t, date, value)
 	}
 }
+
+func TestEnumTypes(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityEnum(env.ObjectBox)
+
+	_, err := box.PutMany([]*model.TestEntityEnum{
+		{Status: model.StatusActive, Color: model.ColorRed},
+		{Status: model.StatusArchived, Color: model.ColorGreen},
+		{Status: model.StatusActive, Color: model.ColorGreen},
+		{},
+	})
+	assert.NoErr(t, err)
+
+	read, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 4, len(read))
+	assert.Eq(t, model.StatusActive, read[0].Status)
+	assert.Eq(t, model.ColorGreen, read[1].Color)
+	assert.Eq(t, model.StatusNew, read[3].Status)
+	assert.Eq(t, model.Color(""), read[3].Color)
+
+	// the underlying value is stored so the usual conditions apply to enums as well
+	found, err := box.Query(model.TestEntityEnum_.Status.Equals(int(model.StatusActive)),
+		model.TestEntityEnum_.Color.Equals(string(model.ColorGreen), true)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(found))
+	assert.Eq(t, uint64(3), found[0].Id)
+}
+
+func TestDurationProperty(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityDuration(env.ObjectBox)
+	_, err := box.PutMany([]*model.TestEntityDuration{
+		{Name: "short", Timeout: 1500 * time.Millisecond},
+		{Name: "medium", Timeout: time.Minute},
+		{Name: "long", Timeout: 2 * time.Hour},
+	})
+	assert.NoErr(t, err)
+
+	read, err := box.Get(2)
+	assert.NoErr(t, err)
+	assert.Eq(t, time.Minute, read.Timeout)
+
+	var timeout = model.TestEntityDuration_.Timeout.Duration()
+	found, err := box.Query(timeout.GreaterThan(time.Second), timeout.LessOrEqual(time.Hour)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(found))
+
+	found, err = box.Query(timeout.Between(time.Minute, 3*time.Hour), timeout.OrderDesc()).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(found))
+	assert.Eq(t, "long", found[0].Name)
+
+	found, err = box.Query(timeout.In(time.Minute, time.Hour)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(found))
+	assert.Eq(t, "medium", found[0].Name)
+}
@@ -71,3 +71,37 @@ func TestStructEmbeddingNilPtr(t *testing.T) {
 	// nil if none of the slots was set.
 	assert.True(t, read.BaseWithValue != nil)
 }
+
+func TestStructNesting(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	box := model.BoxForTestEntityNested(env.ObjectBox)
+
+	entity := &model.TestEntityNested{
+		Name: "Customer",
+		Address: model.Address{
+			Street: "Main Street 1",
+			City:   "Springfield",
+			Geo: model.GeoLocation{
+				Lat: 48.1,
+				Lon: 11.6,
+			},
+		},
+	}
+
+	id, err := box.Put(entity)
+	assert.NoErr(t, err)
+	assert.Eq(t, id, entity.Id)
+
+	read, err := box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, *entity, *read)
+
+	// nested fields are stored as prefixed properties and can be used in queries
+	found, err := box.Query(model.TestEntityNested_.Address_City.Equals("Springfield", true),
+		model.TestEntityNested_.Address_Geo_Lat.GreaterThan(48)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(found))
+	assert.Eq(t, id, found[0].Id)
+}
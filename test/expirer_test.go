@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestExpirer(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	var now = time.Now()
+	var millis = func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+
+	var never = iot.PutEvent(env.ObjectBox, "never", 0)
+	var future = iot.PutEvent(env.ObjectBox, "future", millis(now.Add(time.Hour)))
+	for i := 0; i < 25; i++ {
+		iot.PutEvent(env.ObjectBox, "past", millis(now.Add(-time.Duration(i+1)*time.Minute)))
+	}
+
+	// a long interval so that the background worker doesn't interfere
+	var expirer = objectbox.NewExpirer(env.ObjectBox, time.Hour).BatchSize(10)
+	defer expirer.Close()
+
+	assert.Err(t, expirer.Expire(iot.Reading_.ValueInteger)) // not a date
+	assert.NoErr(t, expirer.Expire(iot.Event_.Date))
+
+	removed, err := expirer.RemoveExpired(now)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(25), removed)
+
+	ids, err := box.Query().FindIds()
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{never.Id, future.Id}, ids)
+
+	removed, err = expirer.RemoveExpired(now.Add(2 * time.Hour))
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), removed)
+
+	// the zero value never expires
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+}
+
+func TestExpirerBackground(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	iot.PutEvent(env.ObjectBox, "past", time.Now().Add(-time.Minute).UnixNano()/int64(time.Millisecond))
+
+	var expirer = objectbox.NewExpirer(env.ObjectBox, 10*time.Millisecond)
+	assert.NoErr(t, expirer.Expire(iot.Event_.Date))
+
+	var deadline = time.Now().Add(5 * time.Second)
+	for {
+		count, err := box.Count()
+		assert.NoErr(t, err)
+		if count == 0 {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatal("expired object hasn't been removed in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	expirer.Close()
+	expirer.Close() // may be called repeatedly
+}
+
+func TestExpirerCloseConcurrently(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var expirer = objectbox.NewExpirer(env.ObjectBox, time.Millisecond)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			expirer.Close()
+		}()
+	}
+	wg.Wait()
+}
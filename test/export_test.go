@@ -0,0 +1,197 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestExportImportJSON(t *testing.T) {
+	var source = iot.NewTestEnv()
+	defer source.Close()
+	var sourceBox = iot.BoxForEvent(source.ObjectBox)
+
+	var buffer bytes.Buffer
+	assert.NoErr(t, sourceBox.ExportJSON(&buffer))
+	assert.Eq(t, "[]\n", buffer.String())
+
+	iot.PutEvents(source.ObjectBox, 10)
+	buffer.Reset()
+	assert.NoErr(t, sourceBox.ExportJSON(&buffer))
+	assert.Eq(t, 12, strings.Count(buffer.String(), "\n"))
+
+	var target = iot.NewTestEnv()
+	defer target.Close()
+	var targetBox = iot.BoxForEvent(target.ObjectBox)
+
+	var exported = buffer.String()
+	assert.NoErr(t, targetBox.ImportJSON(strings.NewReader(exported), objectbox.PutModeInsert))
+
+	expected, err := sourceBox.GetAll()
+	assert.NoErr(t, err)
+	actual, err := targetBox.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, expected, actual)
+
+	// objects already exist
+	assert.Err(t, targetBox.ImportJSON(strings.NewReader(exported), objectbox.PutModeInsert))
+	assert.NoErr(t, targetBox.ImportJSON(strings.NewReader(exported), objectbox.PutModeUpdate))
+	assert.NoErr(t, targetBox.ImportJSON(strings.NewReader(exported), objectbox.PutModePut))
+
+	count, err := targetBox.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+
+	assert.Err(t, targetBox.ImportJSON(strings.NewReader(`{"Id": 1}`), objectbox.PutModePut))
+	assert.Err(t, targetBox.ImportJSON(strings.NewReader(`[{"Id": "x"}]`), objectbox.PutModePut))
+}
+
+func TestQueryExportCSV(t *testing.T) {
+	var env = iot.NewTestEnv()
+	defer env.Close()
+	var box = iot.BoxForEvent(env.ObjectBox)
+
+	iot.PutEvents(env.ObjectBox, 3)
+	_, err := box.Put(&iot.Event{Device: `with "quotes", comma`, Date: 5})
+	assert.NoErr(t, err)
+
+	var buffer bytes.Buffer
+	var query = box.Query(iot.Event_.Id.GreaterThan(1))
+	assert.NoErr(t, query.ExportCSV(&buffer, iot.Event_.Id, iot.Event_.Device, iot.Event_.Date))
+	assert.Eq(t, "Id,Device,Date\n"+
+		"2,device 2,10002\n"+
+		"3,device 3,10003\n"+
+		"4,\"with \"\"quotes\"\", comma\",5\n", buffer.String())
+
+	assert.Err(t, query.ExportCSV(&buffer))
+	assert.Err(t, query.ExportCSV(&buffer, iot.Reading_.ValueName))
+	assert.Err(t, query.ExportCSV(&buffer, iot.Event_.Picture))
+}
+
+func TestTransfer(t *testing.T) {
+	var source = iot.NewTestEnv()
+	defer source.Close()
+	var target = iot.NewTestEnv()
+	defer target.Close()
+
+	var events = iot.PutEvents(source.ObjectBox, 25)
+	var srcBox = iot.BoxForEvent(source.ObjectBox)
+	var dstBox = iot.BoxForEvent(target.ObjectBox)
+
+	// store to store copy; Event IDs aren't self-assignable, the new store assigns the same ones as it's empty
+	count, err := objectbox.Transfer(srcBox.Box, dstBox.Box, nil, 10)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(25), count)
+	copied, err := dstBox.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, events, copied)
+
+	// copying again adds the objects under new IDs
+	count, err = objectbox.Transfer(srcBox.Box, dstBox.Box, nil, 10)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(25), count)
+	copied, err = dstBox.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 50, len(copied))
+	assert.Eq(t, uint64(50), copied[49].Id)
+
+	// a different entity requires a transform
+	var readingBox = iot.BoxForReading(source.ObjectBox)
+	_, err = objectbox.Transfer(srcBox.Box, readingBox.Box, nil, 0)
+	assert.Err(t, err)
+
+	// transform to another entity, skipping some of the objects
+	count, err = objectbox.Transfer(srcBox.Box, readingBox.Box, func(src interface{}) interface{} {
+		var event = src.(*iot.Event)
+		if event.Id%2 == 0 {
+			return nil
+		}
+		return &iot.Reading{EventId: event.Id, Date: event.Date, ValueName: event.Device}
+	}, 0)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(13), count)
+
+	readings, err := readingBox.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 13, len(readings))
+	assert.Eq(t, events[2].Id, readings[1].EventId)
+	assert.Eq(t, events[2].Device, readings[1].ValueName)
+}
+
+func TestArchive(t *testing.T) {
+	primary, err := objectbox.NewBuilder().InMemory("archive-primary").Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer primary.Close()
+	archive, err := objectbox.NewBuilder().InMemory("archive-archive").Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer archive.Close()
+
+	// archived objects keep their IDs, TestStringIdEntity has a self-assignable one
+	var objects = make([]*model.TestStringIdEntity, 25)
+	for i := range objects {
+		objects[i] = &model.TestStringIdEntity{Id: strconv.Itoa(i + 1)}
+	}
+	var box = model.BoxForTestStringIdEntity(primary)
+	var archiveBox = model.BoxForTestStringIdEntity(archive)
+	_, err = box.PutMany(objects)
+	assert.NoErr(t, err)
+
+	// archive the oldest objects, stopping after the first batch
+	var query = box.Query(model.TestStringIdEntity_.Id.LessThan(21))
+	moved, err := objectbox.Archive(query.Query, archiveBox.Box, 8, func(moved uint64) bool { return false })
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(8), moved)
+
+	// resume
+	var progress []uint64
+	moved, err = objectbox.Archive(query.Query, archiveBox.Box, 8, func(moved uint64) bool {
+		progress = append(progress, moved)
+		return true
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(12), moved)
+	assert.Eq(t, []uint64{8, 12}, progress)
+
+	remaining, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, objects[20:], remaining)
+
+	archived, err := archiveBox.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, objects[:20], archived)
+
+	// IDs of other entities can't be kept
+	var env = iot.NewTestEnv()
+	defer env.Close()
+	var archiveEnv = iot.NewTestEnv()
+	defer archiveEnv.Close()
+	iot.PutEvents(env.ObjectBox, 3)
+	var events = iot.BoxForEvent(env.ObjectBox)
+	_, err = objectbox.Archive(events.Query().Query, iot.BoxForEvent(archiveEnv.ObjectBox).Box, 0, nil)
+	assert.Err(t, err)
+	count, err := events.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"fmt"
+	"github.com/objectbox/objectbox-go/objectbox/obxtest"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var eventBox = iot.BoxForEvent(env.ObjectBox)
+	var readingBox = iot.BoxForReading(env.ObjectBox)
+
+	eventIds, err := obxtest.Generate(env.ObjectBox, iot.EventBinding.Id, 100, obxtest.Rules{
+		Seed: 42,
+		Properties: map[string]obxtest.Rule{
+			"Device": {Values: []interface{}{"phone", "tablet"}},
+			"Date":   {Min: 1000, Max: 2000},
+		},
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, 100, len(eventIds))
+
+	events, err := eventBox.GetAll()
+	assert.NoErr(t, err)
+	var uids = make(map[string]bool)
+	for _, event := range events {
+		assert.True(t, event.Device == "phone" || event.Device == "tablet")
+		assert.True(t, event.Date >= 1000 && event.Date < 2000)
+		assert.True(t, !uids[event.Uid]) // unique
+		uids[event.Uid] = true
+	}
+
+	// relations point to existing objects
+	_, err = obxtest.Generate(env.ObjectBox, iot.ReadingBinding.Id, 50, obxtest.Rules{
+		Properties: map[string]obxtest.Rule{
+			"ValueInteger": {Func: func(random *rand.Rand, index int) interface{} { return index }},
+		},
+	})
+	assert.NoErr(t, err)
+
+	readings, err := readingBox.Query(iot.Reading_.ValueInteger.Equals(7)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(readings))
+	event, err := eventBox.Get(readings[0].EventId)
+	assert.NoErr(t, err)
+	assert.True(t, event != nil)
+
+	// invalid rules
+	_, err = obxtest.Generate(env.ObjectBox, iot.EventBinding.Id, 1, obxtest.Rules{
+		Properties: map[string]obxtest.Rule{"Missing": {}},
+	})
+	assert.Err(t, err)
+
+	_, err = obxtest.Generate(env.ObjectBox, iot.EventBinding.Id, 1, obxtest.Rules{
+		Properties: map[string]obxtest.Rule{"Date": {Values: []interface{}{"not a date"}}},
+	})
+	assert.Err(t, err)
+}
+
+// fatalRecorder implements obxtest.TestingT, recording the failures instead of stopping the test
+type fatalRecorder struct {
+	failures []string
+}
+
+func (r *fatalRecorder) Helper() {}
+
+func (r *fatalRecorder) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestFixtures(t *testing.T) {
+	var ob = obxtest.NewStore(t, iot.ObjectBoxModel())
+	defer ob.Close()
+
+	// each store is a new, empty database
+	var other = obxtest.NewStore(t, iot.ObjectBoxModel())
+	defer other.Close()
+
+	var events = []*iot.Event{{Device: "a", Uid: "1"}, {Device: "b", Uid: "2"}}
+	var ids = obxtest.Put(t, ob, iot.EventBinding.Id, events)
+	assert.Eq(t, []uint64{1, 2}, ids)
+	obxtest.PutJSON(t, ob, iot.EventBinding.Id, strings.NewReader(`[{"Device": "c", "Uid": "3"}]`))
+
+	obxtest.AssertCount(t, ob, iot.EventBinding.Id, 3)
+	obxtest.AssertCount(t, ob, iot.EventBinding.Id, 1, iot.Event_.Device.Equals("c", true))
+	obxtest.AssertCount(t, other, iot.EventBinding.Id, 0)
+	obxtest.AssertObjects(t, ob, iot.EventBinding.Id, append(events, &iot.Event{Id: 3, Device: "c", Uid: "3"}))
+
+	var recorder = &fatalRecorder{}
+	obxtest.AssertCount(recorder, ob, iot.EventBinding.Id, 2)
+	obxtest.AssertObjects(recorder, ob, iot.EventBinding.Id, events)
+	obxtest.AssertObjects(recorder, ob, iot.EventBinding.Id, []*iot.Event{events[0], events[0], events[0]})
+	obxtest.PutJSON(recorder, ob, iot.EventBinding.Id, strings.NewReader(`{}`))
+	obxtest.PutJSONFile(recorder, ob, iot.EventBinding.Id, "testdata/missing.json")
+	assert.Eq(t, 5, len(recorder.failures))
+	assert.True(t, strings.Contains(recorder.failures[2], "object 1 differs"))
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestHTTPService(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	var service = objectbox.NewHTTPService(env.ObjectBox)
+	var request = func(method, path, body string, result interface{}) int {
+		var recorder = httptest.NewRecorder()
+		service.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		if result != nil {
+			assert.NoErr(t, json.NewDecoder(recorder.Body).Decode(result))
+		}
+		return recorder.Code
+	}
+
+	var names []string
+	assert.Eq(t, http.StatusOK, request(http.MethodGet, "/", "", &names))
+	assert.Eq(t, []string{"Event", "Reading"}, names)
+
+	var created map[string]uint64
+	assert.Eq(t, http.StatusOK, request(http.MethodPost, "/Event", `{"Device": "a", "Uid": "1"}`, &created))
+	assert.Eq(t, uint64(1), created["id"])
+	assert.Eq(t, http.StatusOK, request(http.MethodPost, "/Event", `{"Device": "b", "Uid": "2"}`, nil))
+
+	var event iot.Event
+	assert.Eq(t, http.StatusOK, request(http.MethodGet, "/Event/1", "", &event))
+	assert.Eq(t, "a", event.Device)
+
+	assert.Eq(t, http.StatusOK, request(http.MethodPut, "/Event/1", `{"Device": "c", "Uid": "1"}`, nil))
+	stored, err := box.Get(1)
+	assert.NoErr(t, err)
+	assert.Eq(t, "c", stored.Device)
+
+	var events []iot.Event
+	assert.Eq(t, http.StatusOK, request(http.MethodGet, "/Event?q="+url.QueryEscape("Device = 'b'"), "", &events))
+	assert.Eq(t, 1, len(events))
+	assert.Eq(t, "2", events[0].Uid)
+
+	assert.Eq(t, http.StatusOK, request(http.MethodGet, "/Event?offset=1&limit=1", "", &events))
+	assert.Eq(t, 1, len(events))
+
+	var count uint64
+	assert.Eq(t, http.StatusOK, request(http.MethodGet, "/Event/count", "", &count))
+	assert.Eq(t, uint64(2), count)
+
+	assert.Eq(t, http.StatusOK, request(http.MethodDelete, "/Event/2", "", nil))
+	assert.Eq(t, http.StatusNotFound, request(http.MethodGet, "/Event/2", "", nil))
+	assert.Eq(t, http.StatusNotFound, request(http.MethodPut, "/Event/2", `{}`, nil))
+	assert.Eq(t, http.StatusNotFound, request(http.MethodDelete, "/Event/2", "", nil))
+	assert.Eq(t, http.StatusNotFound, request(http.MethodGet, "/Unknown", "", nil))
+	assert.Eq(t, http.StatusBadRequest, request(http.MethodGet, "/Event?q=Unknown", "", nil))
+	assert.Eq(t, http.StatusBadRequest, request(http.MethodPost, "/Event", `{`, nil))
+
+	service.ReadOnly = true
+	assert.Eq(t, http.StatusMethodNotAllowed, request(http.MethodDelete, "/Event/1", "", nil))
+	assert.Eq(t, http.StatusOK, request(http.MethodGet, "/Event/1", "", nil))
+}
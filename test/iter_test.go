@@ -0,0 +1,59 @@
+//go:build go1.23
+
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestQueryAll(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(10)
+
+	var err error
+	var ids []uint64
+	for id, object := range env.Box.Query(model.Entity_.Id.GreaterThan(5)).All(&err) {
+		assert.Eq(t, id, object.(*model.Entity).Id)
+		ids = append(ids, id)
+	}
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{6, 7, 8, 9, 10}, ids)
+
+	// stop early
+	var count = 0
+	for range env.Box.All(&err) {
+		if count++; count == 3 {
+			break
+		}
+	}
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, count)
+
+	var query = env.Box.Query()
+	assert.NoErr(t, query.Close())
+	for range query.All(&err) {
+		assert.Failf(t, "closed query must not yield any objects")
+	}
+	assert.Err(t, err)
+}
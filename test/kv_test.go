@@ -0,0 +1,118 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestKV(t *testing.T) {
+	ob, err := objectbox.NewBuilder().InMemory("kv").Model(model.ObjectBoxModel()).KV().BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+	var box = model.BoxForEntity(ob)
+
+	kv, err := ob.KV()
+	assert.NoErr(t, err)
+
+	_, found, err := kv.Get("missing")
+	assert.NoErr(t, err)
+	assert.Eq(t, false, found)
+
+	assert.NoErr(t, kv.PutString("app/theme", "dark"))
+	assert.NoErr(t, kv.PutString("app/lang", "en"))
+	assert.NoErr(t, kv.Put("token", []byte{1, 2, 3}))
+	assert.NoErr(t, kv.PutString("app/theme", "light"))
+
+	value, found, err := kv.GetString("app/theme")
+	assert.NoErr(t, err)
+	assert.Eq(t, true, found)
+	assert.Eq(t, "light", value)
+
+	bytes, found, err := kv.Get("token")
+	assert.NoErr(t, err)
+	assert.Eq(t, true, found)
+	assert.Eq(t, []byte{1, 2, 3}, bytes)
+
+	var keys []string
+	assert.NoErr(t, kv.Iterate("app/", func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	}))
+	assert.Eq(t, []string{"app/lang", "app/theme"}, keys)
+
+	var stop = errors.New("stop")
+	keys = nil
+	assert.Eq(t, stop, kv.Iterate("", func(key string, value []byte) error {
+		keys = append(keys, key)
+		return stop
+	}))
+	assert.Eq(t, 1, len(keys))
+
+	removed, err := kv.Delete("app/lang")
+	assert.NoErr(t, err)
+	assert.Eq(t, true, removed)
+
+	removed, err = kv.Delete("app/lang")
+	assert.NoErr(t, err)
+	assert.Eq(t, false, removed)
+
+	// the KV data doesn't show up in the boxes of the application's entities
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), count)
+
+	// KV operations are part of the surrounding transaction
+	var rollback = errors.New("rollback")
+	assert.Eq(t, rollback, ob.RunInWriteTx(func() error {
+		assert.NoErr(t, kv.PutString("app/theme", "blue"))
+		_, err := box.Put(&model.Entity{})
+		assert.NoErr(t, err)
+		return rollback
+	}))
+
+	value, _, err = kv.GetString("app/theme")
+	assert.NoErr(t, err)
+	assert.Eq(t, "light", value)
+
+	count, err = box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), count)
+
+	// errors instead of panics once the store is closed
+	ob.Close()
+	_, _, err = kv.Get("app/theme")
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	_, err = kv.Delete("app/theme")
+	assert.Eq(t, objectbox.ErrStoreClosed, err)
+	assert.Eq(t, objectbox.ErrStoreClosed, kv.Iterate("", func(key string, value []byte) error {
+		return nil
+	}))
+}
+
+func TestKVNotEnabled(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	_, err := env.ObjectBox.KV()
+	assert.Err(t, err)
+}
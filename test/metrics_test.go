@@ -0,0 +1,136 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var box = iot.BoxForEvent(env.ObjectBox)
+
+	var collector = objectbox.NewExpvarMetricsCollector("objectbox-test-metrics")
+	objectbox.SetMetricsCollector(collector)
+	defer objectbox.SetMetricsCollector(nil)
+
+	var counter = func(name string) string {
+		if v := collector.Vars().Get(name); v != nil {
+			return v.String()
+		}
+		return "0"
+	}
+
+	id, err := box.Put(&iot.Event{Device: "a"})
+	assert.NoErr(t, err)
+	_, err = box.Get(id)
+	assert.NoErr(t, err)
+	_, err = box.Query(iot.Event_.Device.Equals("a", true)).Find()
+	assert.NoErr(t, err)
+	assert.Err(t, box.Update(&iot.Event{})) // ID 0 - fails
+
+	assert.Eq(t, "1", counter("Box.Put.count"))
+	assert.Eq(t, "0", counter("Box.Put.errors"))
+	assert.Eq(t, "1", counter("Box.Update.count"))
+	assert.Eq(t, "1", counter("Box.Update.errors"))
+	assert.Eq(t, "1", counter("Box.Get.count"))
+	assert.Eq(t, "1", counter("Query.Find.count"))
+	assert.Eq(t, "1", counter("fbb.acquired"))
+
+	assert.Err(t, env.RunInWriteTx(func() error {
+		return errors.New("rollback")
+	}))
+	assert.Eq(t, "1", counter("tx.write.aborted"))
+
+	// no more data is collected after removing the collector
+	objectbox.SetMetricsCollector(nil)
+	_, err = box.Put(&iot.Event{Device: "b"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "1", counter("Box.Put.count"))
+}
+
+// recordingLogger implements objectbox.Logger, keeping the messages with their level and attributes
+type recordingLogger struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (logger *recordingLogger) record(level, msg string, args []interface{}) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	logger.messages = append(logger.messages, fmt.Sprintf("%s %s %v", level, msg, args))
+}
+
+func (logger *recordingLogger) Debug(msg string, args ...interface{}) {
+	logger.record("DEBUG", msg, args)
+}
+func (logger *recordingLogger) Info(msg string, args ...interface{}) {
+	logger.record("INFO", msg, args)
+}
+func (logger *recordingLogger) Warn(msg string, args ...interface{}) {
+	logger.record("WARN", msg, args)
+}
+func (logger *recordingLogger) Error(msg string, args ...interface{}) {
+	logger.record("ERROR", msg, args)
+}
+
+func (logger *recordingLogger) find(prefix string) []string {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	var result []string
+	for _, message := range logger.messages {
+		if strings.HasPrefix(message, prefix) {
+			result = append(result, message)
+		}
+	}
+	return result
+}
+
+func TestSetLogger(t *testing.T) {
+	var logger = &recordingLogger{}
+	objectbox.SetLogger(logger)
+	defer objectbox.SetLogger(nil)
+
+	ob, err := objectbox.NewBuilder().Directory("memory:logger").Model(iot.ObjectBoxModel()).
+		SlowLog(time.Nanosecond, nil).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	_, err = iot.BoxForEvent(ob).Query(iot.Event_.Device.Equals("a", true)).Find()
+	assert.NoErr(t, err)
+
+	var slow = logger.find("WARN objectbox: slow Query.Find")
+	assert.Eq(t, 1, len(slow))
+	assert.True(t, strings.Contains(slow[0], "entity Event"))
+
+	// once removed, the logger isn't used anymore
+	objectbox.SetLogger(nil)
+	_, err = iot.BoxForEvent(ob).Query().Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(logger.find("WARN objectbox: slow Query.Find")))
+}
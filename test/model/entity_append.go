@@ -0,0 +1,31 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+// AppendManyToSlice implements objectbox.ObjectBindingAppendMany for the Entity binding, letting the tests cover
+// batched appending during bulk reads; other entities use the per-object AppendToSlice().
+func (entity_EntityInfo) AppendManyToSlice(slice interface{}, objects []interface{}) interface{} {
+	var result = slice.([]*Entity)
+	for _, object := range objects {
+		if object == nil {
+			result = append(result, nil)
+		} else {
+			result = append(result, object.(*Entity))
+		}
+	}
+	return result
+}
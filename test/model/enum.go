@@ -0,0 +1,43 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen
+
+// Status is an integer enum, stored as its underlying value
+type Status int
+
+const (
+	StatusNew Status = iota
+	StatusActive
+	StatusArchived
+)
+
+// Color is a string enum, stored as its underlying value
+type Color string
+
+const (
+	ColorRed   Color = "red"
+	ColorGreen Color = "green"
+)
+
+// TestEntityEnum covers enum types (named basic types) stored without converters
+type TestEntityEnum struct {
+	Id     uint64
+	Status Status `objectbox:"index"`
+	Color  Color
+}
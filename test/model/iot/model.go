@@ -27,6 +27,13 @@ type Event struct {
 	Picture []byte
 }
 
+// Sample model - like an Event but with a self-assigned ID, keeping its identity when moved between stores
+type Sample struct {
+	Id     uint64 `objectbox:"id(assignable)"`
+	Device string
+	Date   int64 `objectbox:"date"`
+}
+
 // Reading model
 type Reading struct {
 	Id   uint64 `objectbox:"id"`
@@ -35,6 +42,9 @@ type Reading struct {
 	/// to-one relation
 	EventId uint64 `objectbox:"link:Event"`
 
+	// loaded on demand by Query.Eager(Reading_.EventId)
+	Event *Event `objectbox:"-"`
+
 	ValueName string
 
 	/// Device sensor data value
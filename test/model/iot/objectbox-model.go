@@ -14,7 +14,8 @@ func ObjectBoxModel() *objectbox.Model {
 
 	model.RegisterBinding(EventBinding)
 	model.RegisterBinding(ReadingBinding)
-	model.LastEntityId(2, 5284076134434938613)
+	model.RegisterBinding(SampleBinding)
+	model.LastEntityId(3, 4187580651922583815)
 	model.LastIndexId(2, 2642563953244304959)
 
 	return model
@@ -20,8 +20,11 @@ func ObjectBoxModel() *objectbox.Model {
 	model.RegisterBinding(TSDateBinding)
 	model.RegisterBinding(TSDateNanoBinding)
 	model.RegisterBinding(TestEntitySyncedBinding)
-	model.LastEntityId(8, 1967687883385423038)
-	model.LastIndexId(4, 3414034888235702623)
+	model.RegisterBinding(TestEntityNestedBinding)
+	model.RegisterBinding(TestEntityEnumBinding)
+	model.RegisterBinding(TestEntityDurationBinding)
+	model.LastEntityId(11, 1422173780406869560)
+	model.LastIndexId(5, 8000488004630664786)
 	model.LastRelationId(6, 3119566795324383223)
 
 	return model
@@ -15,6 +15,19 @@ type BaseWithValue struct {
 	Value float64
 }
 
+// Address is stored as a part of the entities containing it
+type Address struct {
+	Street string
+	City   string
+	Geo    GeoLocation
+}
+
+// GeoLocation is nested two levels deep in TestEntityNested, i.e. stored as Address_Geo_Lat and Address_Geo_Lon
+type GeoLocation struct {
+	Lat float64
+	Lon float64
+}
+
 // decodes the given byte slice as a complex number
 func complex128BytesToEntityProperty(dbValue []byte) (complex128, error) {
 	// NOTE that constructing the decoder each time is inefficient and only serves as an example for the property converters
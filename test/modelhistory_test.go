@@ -0,0 +1,171 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestModelHistory(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(10)
+
+	history, err := env.ObjectBox.ModelHistory()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(history))
+	assert.Eq(t, 1, history[0].Version)
+	assert.Eq(t, model.EntityBinding.Id, history[0].Entities[0].Id)
+	assert.Eq(t, "Entity", history[0].Entities[0].Name)
+	assert.Eq(t, objectbox.TypeId(11), history[0].LastEntityId.Id)
+
+	// reopening with the same model doesn't add a new version
+	env.ObjectBox.Close()
+	env.ObjectBox, err = objectbox.NewBuilder().Directory(env.Directory).Model(model.ObjectBoxModel()).Build()
+	assert.NoErr(t, err)
+	history, err = env.ObjectBox.ModelHistory()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(history))
+
+	// open read-only with the model from the history
+	env.ObjectBox.Close()
+	env.ObjectBox, err = objectbox.NewBuilder().Directory(env.Directory).Model(model.ObjectBoxModel()).
+		OpenWithModelVersion(1).BuildOrError()
+	assert.NoErr(t, err)
+
+	var box = model.BoxForEntity(env.ObjectBox)
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+
+	object, err := box.Get(1)
+	assert.NoErr(t, err)
+	assert.True(t, object != nil)
+
+	_, err = box.Put(object)
+	assert.Err(t, err)
+
+	// unknown versions are rejected
+	env.ObjectBox.Close()
+	_, err = objectbox.NewBuilder().Directory(env.Directory).Model(model.ObjectBoxModel()).
+		OpenWithModelVersion(2).BuildOrError()
+	assert.Err(t, err)
+}
+
+// taskModel creates a model without bindings in one of its versions
+func TestModelHistoryUnreadable(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+	env.ObjectBox.Close()
+
+	// a damaged history doesn't prevent opening the store
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(env.Directory, "model-history.json"), []byte("{damaged"), 0644))
+
+	var err error
+	env.ObjectBox, err = objectbox.NewBuilder().Directory(env.Directory).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+
+	_, err = env.ObjectBox.ModelHistory()
+	assert.Err(t, err)
+}
+
+func taskModel(version int) *objectbox.Model {
+	const typeBool, typeInt, typeLong, typeString = 1, 5, 6, 9
+	const flagId = 1
+
+	var m = objectbox.NewModel()
+	m.GeneratorVersion(6)
+	if version == 1 {
+		m.Entity("Task", 1, 1001)
+	} else {
+		m.Entity("Todo", 1, 1001) // renamed
+	}
+	m.Property("Id", typeLong, 1, 2001)
+	m.PropertyFlags(flagId)
+	if version == 1 {
+		m.Property("Name", typeString, 2, 2002)
+	} else {
+		m.Property("Title", typeString, 4, 2004) // an accidental rename, i.e. a new property replacing "Name"
+	}
+	if version == 3 {
+		m.Property("Done", typeInt, 3, 2003) // type changed
+	} else {
+		m.Property("Done", typeBool, 3, 2003)
+	}
+	m.EntityLastPropertyId(4, 2004)
+	m.LastEntityId(1, 1001)
+	return m
+}
+
+func TestSchemaDiff(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	env.Close()
+
+	// a new directory, there's no history yet
+	var dir = env.Directory + "-schema"
+	changes, err := objectbox.NewBuilder().Directory(dir).Model(taskModel(1)).SchemaDiff()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(changes))
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(taskModel(1)).Build()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(ob.SchemaDiff()))
+	ob.Close()
+
+	changes, err = objectbox.NewBuilder().Directory(dir).Model(taskModel(3)).SchemaDiff()
+	assert.NoErr(t, err)
+	assert.Eq(t, []objectbox.SchemaChange{
+		{Kind: objectbox.SchemaEntityRenamed, Entity: "Todo", Previous: "Task"},
+		{Kind: objectbox.SchemaPropertyTypeChanged, Entity: "Todo", Property: "Done", Previous: "Bool", Current: "Int"},
+		{Kind: objectbox.SchemaPropertyReplaced, Entity: "Todo", Property: "Title", Previous: "Name"},
+	}, changes)
+	assert.Eq(t, "propertyReplaced: Todo.Title replaces Name, whose data will be lost", changes[2].String())
+
+	// reject the changes before the store is opened
+	_, err = objectbox.NewBuilder().Directory(dir).Model(taskModel(3)).
+		OnSchemaChange(func(changes []objectbox.SchemaChange) error {
+			return fmt.Errorf("unexpected schema changes: %v", changes)
+		}).Build()
+	assert.Err(t, err)
+
+	// accept them; the changes are available after opening
+	var called = false
+	ob, err = objectbox.NewBuilder().Directory(dir).Model(taskModel(2)).
+		OnSchemaChange(func(changes []objectbox.SchemaChange) error {
+			called = true
+			return nil
+		}).Build()
+	assert.NoErr(t, err)
+	assert.True(t, called)
+	assert.Eq(t, 2, len(ob.SchemaDiff()))
+	ob.Close()
+
+	// the model has been recorded so there are no more changes
+	changes, err = objectbox.NewBuilder().Directory(dir).Model(taskModel(2)).SchemaDiff()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(changes))
+	assert.NoErr(t, os.RemoveAll(dir))
+}
@@ -17,17 +17,25 @@
 package objectbox_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
 	"reflect"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/objectbox/objectbox-go/objectbox"
 	"github.com/objectbox/objectbox-go/test/assert"
 	"github.com/objectbox/objectbox-go/test/model"
+	"github.com/objectbox/objectbox-go/test/model/iot"
 )
 
 // Following methods use many test-cases defined as a list of queryTestCase and run all Query.* methods on each test case
@@ -68,6 +76,11 @@ func TestQueries(t *testing.T) {
 
 		{2, s{`StringVector contains "first-1"`}, box.Query(E.StringVector.Contains("first-1", true)), nil},
 		{2, s{`StringVector contains(i) "FIRST-1"`}, box.Query(E.StringVector.Contains("FIRST-1", false)), nil},
+		{2, s{`StringVector contains "second-1"`}, box.Query(E.StringVector.Contains("second-1", true)), nil},
+		{0, s{`StringVector contains "second"`}, box.Query(E.StringVector.Contains("second", true)), nil},
+		{2, s{`(StringVector contains "first-1" OR StringVector contains "none")`}, box.Query(E.StringVector.ContainsAnyElement(true, "first-1", "none")), nil},
+		{0, s{`(StringVector contains "first-1" AND StringVector contains "none")`}, box.Query(E.StringVector.ContainsAllElements(true, "first-1", "none")), nil},
+		{2, s{`(StringVector contains(i) "FIRST-1" AND StringVector contains(i) "SECOND-1")`}, box.Query(E.StringVector.ContainsAllElements(false, "FIRST-1", "SECOND-1")), nil},
 
 		{1, s{`Int64 == 0`}, box.Query(E.Int64.Equals(0)), nil},
 		{2, s{`Int64 == 47`}, box.Query(E.Int64.Equals(e.Int64)), nil},
@@ -241,37 +254,122 @@ func TestQueries(t *testing.T) {
 
 		{3, s{`(Bool == 1 AND Byte == 1)`}, box.Query(E.Bool.Equals(true), E.Byte.Equals(1)), nil},
 	})
+
+	_, err := box.QueryOrError(E.StringVector.ContainsAnyElement(true))
+	assert.Eq(t, "ContainsAnyElement requires at least one value", err.Error())
 }
 
 func TestQueryOffsetLimit(t *testing.T) {
 	env := model.NewTestEnv(t)
 	defer env.Close()
 
-	// skip Count() and Remove() - not supported in combination with `offset`
-	testQueries(t, env, queryTestOptions{baseCount: 10, skipCount: true, skipRemove: true}, []queryTestCase{
+	testQueries(t, env, queryTestOptions{baseCount: 10}, []queryTestCase{
 		{10, s{`TRUE`}, env.Box.Query(), nil},
 		{5, s{`TRUE`}, env.Box.Query().Offset(5), nil},
 		{3, s{`TRUE`}, env.Box.Query().Offset(3).Limit(3), nil},
 		{1, s{`TRUE`}, env.Box.Query().Offset(9).Limit(3), nil},
-	})
-
-	// Count() works fine in combination with `limit`
-	testQueries(t, env, queryTestOptions{baseCount: 10, skipCount: false, skipRemove: true}, []queryTestCase{
+		{0, s{`TRUE`}, env.Box.Query().Offset(12), nil},
 		{3, s{`TRUE`}, env.Box.Query().Limit(3), nil},
 	})
 
-	// test negative queries where offset/limit is not supported
-	var assertNotSupported = func(count uint64, err error) {
-		assert.Eq(t, count, uint64(0))
-		assert.Err(t, err)
-		assert.True(t, strings.Contains(err.Error(), "not supported"))
-	}
+	// retention: remove all but the 3 newest objects, i.e. keep the IDs 8, 9 and 10
+	var query = env.Box.Query()
+	query.Order(model.Entity_.Id.Desc())
+	count, err := query.Offset(3).Remove()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(7), count)
+
+	ids, err := env.Box.Query().FindIds()
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{8, 9, 10}, ids)
+
+	// the offset and limit stay set after counting
+	count, err = query.Offset(1).Limit(1).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+	objects, err := query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(objects))
+	assert.Eq(t, uint64(9), objects[0].Id)
+}
+
+func TestQueryPage(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(10)
+
+	var query = env.Box.Query(model.Entity_.Id.GreaterThan(2), model.Entity_.Id.OrderDesc())
+
+	objects, total, err := query.Page(0, 3)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(8), total)
+	var page = objects.([]*model.Entity)
+	assert.Eq(t, 3, len(page))
+	assert.Eq(t, uint64(10), page[0].Id)
+	assert.Eq(t, uint64(8), page[2].Id)
+
+	objects, total, err = query.Page(6, 3)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(8), total)
+	page = objects.([]*model.Entity)
+	assert.Eq(t, 2, len(page))
+	assert.Eq(t, uint64(4), page[0].Id)
+	assert.Eq(t, uint64(3), page[1].Id)
 
-	assertNotSupported(env.Box.Query().Offset(5).Count())
-	assertNotSupported(env.Box.Query().Offset(5).Remove())
-	assertNotSupported(env.Box.Query().Offset(1).Limit(2).Count())
-	assertNotSupported(env.Box.Query().Offset(1).Limit(2).Remove())
-	assertNotSupported(env.Box.Query().Limit(5).Remove())
+	objects, total, err = query.Page(10, 3)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(8), total)
+	assert.Eq(t, 0, len(objects.([]*model.Entity)))
+
+	// offset & limit are reset afterwards
+	count, err := query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(8), count)
+
+	// ... to the values set before
+	query.Offset(1).Limit(2)
+	_, total, err = query.Page(0, 5)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(8), total)
+	count, err = query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+	objects, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(9), objects.([]*model.Entity)[0].Id)
+}
+
+func TestMemoryPressureHook(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(10)
+
+	var underPressure = false
+	env.ObjectBox.SetMemoryPressureHook(func() bool { return underPressure }, 5)
+
+	all, err := env.Box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 10, len(all))
+
+	underPressure = true
+
+	_, err = env.Box.GetAll()
+	assert.Eq(t, objectbox.ErrResultBudgetExceeded, err)
+
+	_, err = env.Box.Query().Find()
+	assert.Eq(t, objectbox.ErrResultBudgetExceeded, err)
+
+	found, err := env.Box.Query(model.Entity_.Id.LessOrEqual(5)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 5, len(found))
+
+	env.ObjectBox.SetMemoryPressureHook(nil, 0)
+
+	all, err = env.Box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 10, len(all))
 }
 
 func TestQueryParams(t *testing.T) {
@@ -615,6 +713,49 @@ func TestQueryAndOr(t *testing.T) {
 	})
 }
 
+func TestQueryNot(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = env.Box
+	var E = model.Entity_
+	var e = model.Entity47()
+
+	testQueries(t, env, queryTestOptions{baseCount: 1000}, []queryTestCase{
+		{997, s{`Int != 47`}, box.Query(objectbox.Not(E.Int.Equals(e.Int))), nil},
+		{501, s{`Int >= 47`}, box.Query(objectbox.Not(E.Int.LessThan(e.Int))), nil},
+		{499, s{`Int < 47`}, box.Query(objectbox.Not(objectbox.Not(E.Int.LessThan(e.Int)))), nil},
+		{999, s{`(Int < -1 OR Int > 1)`}, box.Query(objectbox.Not(E.Int.Between(-1, 1))), nil},
+		{999, s{`String != "Val-1"`}, box.Query(objectbox.Not(E.String.Equals(e.String, true))), nil},
+		{498, s{`(Int >= 47 AND Int != 47)`}, box.Query(objectbox.Not(objectbox.Any(E.Int.LessThan(e.Int), E.Int.Equals(e.Int)))), nil},
+		{502, s{`(Int < 47 OR Int == 47)`}, box.Query(objectbox.Not(objectbox.All(objectbox.Not(E.Int.LessThan(e.Int)), E.Int.NotEquals(e.Int)))), nil},
+	})
+
+	// conditions built dynamically, e.g. from user-supplied filters
+	var filters []objectbox.Condition
+	query, err := box.QueryOrError(objectbox.All(filters...), objectbox.Any(filters...))
+	assert.NoErr(t, err)
+	count, err := query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1000), count)
+
+	filters = append(filters, E.Int.LessThan(e.Int), nil)
+	_, err = box.QueryOrError(objectbox.Any(filters...))
+	assert.Eq(t, "condition 1 of Any() is nil", err.Error())
+
+	_, err = box.QueryOrError(objectbox.Not(nil))
+	assert.Eq(t, "condition passed to Not() is nil", err.Error())
+
+	_, err = box.QueryOrError(objectbox.Not(E.String.Contains("Val", true)))
+	assert.Eq(t, "StringContains can't be used inside Not() - there's no opposite condition", err.Error())
+
+	_, err = box.QueryOrError(objectbox.Not(E.Related.Link(model.TestEntityRelated_.Name.Equals("", true))))
+	assert.Err(t, err)
+
+	_, err = box.QueryOrError(objectbox.Not(E.Int.Equals(0).Alias("int")))
+	assert.Eq(t, `using Alias/As("int") inside Not() is not supported`, err.Error())
+}
+
 func TestQueryLinks(t *testing.T) {
 	env := model.NewTestEnv(t).SetOptions(model.TestEnvOptions{PopulateRelations: true})
 	defer env.Close()
@@ -803,6 +944,498 @@ func TestQueryOrder(t *testing.T) {
 	})
 }
 
+func TestQueryOrderMultipleKeys(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var E = model.Entity_
+	_, err := env.Box.PutMany([]*model.Entity{
+		{Int: 1, String: "b"},
+		{Int: 2, String: "a"},
+		{Int: 1, String: "B"},
+		{Int: 2, String: "c"},
+		{Int: 1, String: "a"},
+	})
+	assert.NoErr(t, err)
+
+	var describe = func(objects []*model.Entity) string {
+		var result []string
+		for _, object := range objects {
+			result = append(result, fmt.Sprintf("%d%s", object.Int, object.String))
+		}
+		return strings.Join(result, " ")
+	}
+
+	// the priority must follow the order the keys are given in
+	// Order() returns the untyped query, keep the generated one to read typed results
+	var query = env.Box.Query()
+	query.Order(E.Int.Desc(), E.String.Asc(objectbox.OrderCaseSensitive))
+	objects, err := query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, "2a 2c 1B 1a 1b", describe(objects))
+
+	query = env.Box.Query(E.Int.Equals(1))
+	query.Order(E.String.Desc(objectbox.OrderCaseSensitive))
+	objects, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, "1b 1a 1B", describe(objects))
+
+	objects, err = env.Box.Query(E.String.Asc(objectbox.OrderCaseSensitive), E.Int.Asc()).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, "1B 1a 2a 1b 2c", describe(objects))
+
+	// another Order() replaces the previous one
+	query = env.Box.Query()
+	query.Order(E.Int.Asc())
+	query.Order(E.String.Desc(objectbox.OrderCaseSensitive), E.Int.Desc())
+	objects, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, "2c 1b 2a 1a 1B", describe(objects))
+
+	// the offset and limit are kept, Find() and Count() agree
+	query = env.Box.Query()
+	query.Offset(1).Limit(2)
+	query.Order(E.Int.Desc(), E.String.Asc(objectbox.OrderCaseSensitive))
+	objects, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, "2c 1B", describe(objects))
+	count, err := query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	_, err = env.Box.Query().Order(E.Int.Equals(1)).Find()
+	assert.Err(t, err)
+}
+
+func TestQueryDistinctBy(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	_, err := box.PutMany([]*iot.Event{
+		{Device: "a", Date: 1, Uid: "1"},
+		{Device: "b", Date: 2, Uid: "2"},
+		{Device: "a", Date: 3, Uid: "3"},
+		{Device: "c", Date: 4, Uid: "4"},
+		{Device: "b", Date: 5, Uid: "5"},
+	})
+	assert.NoErr(t, err)
+
+	var uids = func(objects interface{}) []string {
+		var result []string
+		for _, event := range objects.([]*iot.Event) {
+			result = append(result, event.Uid)
+		}
+		return result
+	}
+
+	// the latest event per device
+	latest, err := box.Query(iot.Event_.Date.OrderDesc()).DistinctBy(iot.Event_.Device)
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"5", "4", "3"}, uids(latest))
+
+	// the earliest event per device, among those matching the conditions
+	earliest, err := box.Query(iot.Event_.Date.GreaterThan(1), iot.Event_.Date.OrderAsc()).
+		DistinctBy(iot.Event_.Device)
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"2", "3", "4"}, uids(earliest))
+
+	// a property of another entity
+	_, err = box.Query().DistinctBy(iot.Reading_.ValueName)
+	assert.Err(t, err)
+}
+
+func TestQueryGroupBy(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	_, err := box.PutMany([]*iot.Reading{
+		{ValueName: "temp", ValueInteger: 20, ValueFloating: 20.5},
+		{ValueName: "temp", ValueInteger: 24, ValueFloating: 23.5},
+		{ValueName: "humidity", ValueInteger: 40, ValueFloating: 40},
+		{ValueName: "temp", ValueInteger: 22, ValueFloating: 22},
+	})
+	assert.NoErr(t, err)
+
+	var grouped = box.Query().GroupBy(iot.Reading_.ValueName)
+
+	counts, err := grouped.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, map[interface{}]uint64{"temp": 3, "humidity": 1}, counts)
+
+	sums, err := grouped.Sum(iot.Reading_.ValueInteger)
+	assert.NoErr(t, err)
+	assert.Eq(t, map[interface{}]int64{"temp": 66, "humidity": 40}, sums)
+
+	floatSums, err := grouped.SumFloat64(iot.Reading_.ValueFloating)
+	assert.NoErr(t, err)
+	assert.Eq(t, map[interface{}]float64{"temp": 66, "humidity": 40}, floatSums)
+
+	averages, err := grouped.Average(iot.Reading_.ValueInteger)
+	assert.NoErr(t, err)
+	assert.Eq(t, map[interface{}]float64{"temp": 22, "humidity": 40}, averages)
+
+	minimums, err := grouped.Min(iot.Reading_.ValueInteger)
+	assert.NoErr(t, err)
+	assert.Eq(t, map[interface{}]int64{"temp": 20, "humidity": 40}, minimums)
+
+	maximums, err := grouped.Max(iot.Reading_.ValueInteger)
+	assert.NoErr(t, err)
+	assert.Eq(t, map[interface{}]int64{"temp": 24, "humidity": 40}, maximums)
+
+	// the query conditions apply before grouping
+	counts, err = box.Query(iot.Reading_.ValueInteger.GreaterThan(21)).GroupBy(iot.Reading_.ValueName).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, map[interface{}]uint64{"temp": 2, "humidity": 1}, counts)
+
+	// integer aggregates of a floating-point property, non-numeric and foreign properties
+	_, err = grouped.Sum(iot.Reading_.ValueFloating)
+	assert.Err(t, err)
+	_, err = grouped.Sum(iot.Reading_.ValueString)
+	assert.Err(t, err)
+	_, err = grouped.Sum(iot.Event_.Date)
+	assert.Err(t, err)
+	_, err = box.Query().GroupBy(iot.Event_.Device).Count()
+	assert.Err(t, err)
+}
+
+func TestBoxQueryString(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	_, err := box.PutMany([]*iot.Event{
+		{Device: "a", Date: 1, Uid: "1"},
+		{Device: "b", Date: 2, Uid: "2"},
+		{Device: "a", Date: 3, Uid: "3"},
+		{Device: "it's", Date: 4, Uid: "4"},
+	})
+	assert.NoErr(t, err)
+
+	var count = func(expression string, params ...interface{}) uint64 {
+		query, err := box.QueryString(expression, params...)
+		assert.NoErr(t, err)
+		defer query.Close()
+		count, err := query.Count()
+		assert.NoErr(t, err)
+		return count
+	}
+
+	assert.Eq(t, uint64(2), count(`Device = ?`, "a"))
+	assert.Eq(t, uint64(1), count(`device = 'a' AND date > ?`, 1))
+	assert.Eq(t, uint64(3), count(`Device = 'b' OR (Device = 'a' and Date >= 1)`))
+	assert.Eq(t, uint64(1), count(`Device = 'it''s'`))
+	assert.Eq(t, uint64(3), count(`Date IN (1, ?) OR Device != 'a' AND Date < 3`, 3))
+	assert.Eq(t, uint64(1), count(`Uid IN ('1', ?, '4') AND Date NOT IN (1, 2)`, "3"))
+	assert.Eq(t, uint64(0), count(`Picture IS NOT NULL`))
+
+	for _, expression := range []string{
+		`Unknown = 1`,     // no such property
+		`Device = 1`,      // wrong value type
+		`Device = ?`,      // missing param
+		`Device = 'a' ?`,  // trailing token
+		`(Device = 'a'`,   // unbalanced parentheses
+		`Device LIKE 'a'`, // unsupported operator
+		`Picture = 'a'`,   // unsupported property type
+	} {
+		_, err = box.QueryString(expression)
+		assert.Err(t, err)
+	}
+
+	// unused params
+	_, err = box.QueryString(`Device = 'a'`, "b")
+	assert.Err(t, err)
+
+	// a sign inside a number ends it, i.e. arithmetic isn't taken for a malformed number
+	assert.Eq(t, uint64(4), count(`Date > -1`))
+	for _, expression := range []string{`Date < 5-3`, `Date = 1-2`} {
+		_, err = box.QueryString(expression)
+		assert.Err(t, err)
+		assert.True(t, strings.Contains(err.Error(), `unexpected "-`))
+	}
+}
+
+func TestQueryFindAfter(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	// dates in reverse order of IDs, with duplicates
+	var dates = []int64{50, 50, 40, 40, 40, 30, 20}
+	for i, date := range dates {
+		_, err := box.Put(&iot.Event{Device: "a", Date: date, Uid: strconv.Itoa(i)})
+		assert.NoErr(t, err)
+	}
+	_, err := box.Put(&iot.Event{Device: "b", Date: 10, Uid: "b"})
+	assert.NoErr(t, err)
+
+	var query = box.Query(iot.Event_.Device.Equals("a", true))
+
+	var ids []uint64
+	var lastId uint64
+	for {
+		page, err := query.FindAfter(lastId, 3)
+		assert.NoErr(t, err)
+		var events = page.([]*iot.Event)
+		if len(events) == 0 {
+			break
+		}
+		assert.True(t, len(events) <= 3)
+		for _, event := range events {
+			ids = append(ids, event.Id)
+		}
+		lastId = events[len(events)-1].Id
+	}
+	assert.Eq(t, []uint64{1, 2, 3, 4, 5, 6, 7}, ids)
+
+	ids = nil
+	var lastDate int64 = math.MinInt64
+	lastId = 0
+	for {
+		page, err := query.FindAfterBy(iot.Event_.Date, lastDate, lastId, 2)
+		assert.NoErr(t, err)
+		var events = page.([]*iot.Event)
+		if len(events) == 0 {
+			break
+		}
+		for _, event := range events {
+			ids = append(ids, event.Id)
+		}
+		lastDate, lastId = events[len(events)-1].Date, events[len(events)-1].Id
+	}
+	assert.Eq(t, []uint64{7, 6, 3, 4, 5, 1, 2}, ids)
+
+	// orders and properties of other entities are rejected
+	_, err = box.Query(iot.Event_.Date.OrderAsc()).FindAfter(0, 10)
+	assert.Err(t, err)
+	_, err = query.FindAfterBy(iot.Reading_.Date, 0, 0, 10)
+	assert.Err(t, err)
+}
+
+func TestQueryClone(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForEvent(env.ObjectBox)
+
+	_, err := box.PutMany([]*iot.Event{{Device: "a", Uid: "1"}, {Device: "b", Uid: "2"}, {Device: "a", Uid: "3"}})
+	assert.NoErr(t, err)
+
+	var query = box.Query(iot.Event_.Device.Equals("a", true).Alias("device"))
+	clone, err := query.Clone()
+	assert.NoErr(t, err)
+	assert.NoErr(t, clone.SetStringParams(objectbox.Alias("device"), "b"))
+
+	count, err := query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+	count, err = clone.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+	assert.NoErr(t, clone.Close())
+
+	// offset and limit are kept
+	clone, err = query.Limit(1).Clone()
+	assert.NoErr(t, err)
+	events, err := clone.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(events.([]*iot.Event)))
+
+	// a single query serving concurrent goroutines
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(device string) {
+			defer wg.Done()
+			clone, err := query.Clone()
+			assert.NoErr(t, err)
+			defer clone.Close()
+			assert.NoErr(t, clone.SetStringParams(objectbox.Alias("device"), device))
+			events, err := clone.Limit(0).Find()
+			assert.NoErr(t, err)
+			for _, event := range events.([]*iot.Event) {
+				assert.Eq(t, device, event.Device)
+			}
+		}([]string{"a", "b"}[i%2])
+	}
+	wg.Wait()
+
+	assert.NoErr(t, query.Close())
+	_, err = query.Clone()
+	assert.Err(t, err)
+}
+
+func TestBoxCachedQuery(t *testing.T) {
+	ob, err := objectbox.NewBuilder().Directory("memory:querycache").Model(iot.ObjectBoxModel()).
+		QueryCacheSize(2).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+	box := iot.BoxForEvent(ob)
+
+	_, err = box.PutMany([]*iot.Event{{Device: "a", Uid: "1"}, {Device: "b", Uid: "2"}, {Device: "a", Uid: "3"}})
+	assert.NoErr(t, err)
+
+	var ofDevice = []objectbox.Condition{iot.Event_.Device.Equals("", true).Alias("device")}
+	var count = func(device string) uint64 {
+		query, err := box.CachedQuery(ofDevice...)
+		assert.NoErr(t, err)
+		defer query.Close()
+		assert.NoErr(t, query.SetStringParams(objectbox.Alias("device"), device))
+		count, err := query.Count()
+		assert.NoErr(t, err)
+		return count
+	}
+
+	// params set on one query don't affect the others
+	assert.Eq(t, uint64(2), count("a"))
+	assert.Eq(t, uint64(1), count("b"))
+	assert.Eq(t, uint64(0), count(""))
+	var stats = ob.QueryCacheStats()
+	assert.Eq(t, uint64(2), stats.Hits)
+	assert.Eq(t, uint64(1), stats.Misses)
+	assert.Eq(t, 1, stats.Size)
+
+	// conditions are identified by their instances; the least recently used queries are evicted
+	for i := 0; i < 2; i++ {
+		query, err := box.CachedQuery(iot.Event_.Device.Equals("a", true))
+		assert.NoErr(t, err)
+		assert.NoErr(t, query.Close())
+	}
+	stats = ob.QueryCacheStats()
+	assert.Eq(t, uint64(3), stats.Misses)
+	assert.Eq(t, uint64(1), stats.Evictions)
+	assert.Eq(t, 2, stats.Size)
+	assert.Eq(t, uint64(1), count("b"))
+	assert.Eq(t, uint64(4), ob.QueryCacheStats().Misses)
+
+	// the same conditions on a different entity
+	_, err = iot.BoxForReading(ob).CachedQuery(ofDevice...)
+	assert.Err(t, err)
+}
+
+func TestQueryFindProjected(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var events = iot.PutEvents(env.ObjectBox, 3)
+	var readings = iot.PutReadings(env.ObjectBox, 2)
+
+	var query = iot.BoxForEvent(env.ObjectBox).Query(iot.Event_.Date.GreaterThan(events[0].Date))
+	projections, err := query.FindProjected(iot.Event_.Id, iot.Event_.Device, iot.Event_.Date)
+	assert.NoErr(t, err)
+	assert.Eq(t, []objectbox.Projection{
+		{events[1].Id, events[1].Device, events[1].Date},
+		{events[2].Id, events[2].Device, events[2].Date},
+	}, projections)
+
+	// a property of another entity
+	_, err = query.FindProjected(iot.Reading_.Date)
+	assert.Err(t, err)
+
+	projections, err = iot.BoxForReading(env.ObjectBox).Query().FindProjected(
+		iot.Reading_.ValueInt32, iot.Reading_.ValueFloating32, iot.Reading_.ValueName)
+	assert.NoErr(t, err)
+	assert.Eq(t, []objectbox.Projection{
+		{readings[0].ValueInt32, readings[0].ValueFloating32, readings[0].ValueName},
+		{readings[1].ValueInt32, readings[1].ValueFloating32, readings[1].ValueName},
+	}, projections)
+}
+
+func TestQueryRows(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var events = iot.PutEvents(env.ObjectBox, 2)
+
+	rows, err := iot.BoxForEvent(env.ObjectBox).Query().Rows(iot.Event_.Id, iot.Event_.Device, iot.Event_.Date)
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"Id", "Device", "Date"}, rows.Columns())
+
+	var i = 0
+	for rows.Next() {
+		var id int
+		var device []byte
+		var date time.Time
+		assert.NoErr(t, rows.Scan(&id, &device, &date))
+		assert.Eq(t, int(events[i].Id), id)
+		assert.Eq(t, events[i].Device, string(device))
+		assert.Eq(t, events[i].Date, date.UnixNano()/int64(time.Millisecond))
+
+		var small int8
+		var any interface{}
+		assert.Err(t, rows.Scan(&id, &device))
+		assert.Err(t, rows.Scan(&id, &small, &any))
+		i++
+	}
+	assert.NoErr(t, rows.Err())
+	assert.Eq(t, 2, i)
+	assert.NoErr(t, rows.Close())
+	assert.True(t, !rows.Next())
+
+	// all properties
+	rows, err = iot.BoxForEvent(env.ObjectBox).Query().Rows()
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"Id", "Device", "Date", "Uid", "Picture"}, rows.Columns())
+	assert.True(t, rows.Next())
+	var values = make([]interface{}, 5)
+	var pointers = make([]interface{}, 5)
+	for j := range values {
+		pointers[j] = &values[j]
+	}
+	assert.NoErr(t, rows.Scan(pointers...))
+	assert.Eq(t, events[0].Id, values[0])
+	assert.Eq(t, events[0].Device, values[1])
+}
+
+func TestQueryTemplate(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var events = iot.PutEvents(env.ObjectBox, 5)
+	var readingBox = iot.BoxForReading(env.ObjectBox)
+	for _, event := range events {
+		_, err := readingBox.Put(&iot.Reading{Date: event.Date, EventId: event.Id})
+		assert.NoErr(t, err)
+	}
+
+	// Event and Reading share the Date property
+	var since = objectbox.NewQueryTemplate(func(fields *objectbox.TemplateFields) []objectbox.Condition {
+		return []objectbox.Condition{
+			fields.Int64("Date").GreaterOrEqual(0).Alias("since"),
+			fields.Int64("Date").Desc(),
+		}
+	})
+
+	for _, box := range []*objectbox.Box{iot.BoxForEvent(env.ObjectBox).Box, readingBox.Box} {
+		query, err := since.Query(box)
+		assert.NoErr(t, err)
+		assert.NoErr(t, query.SetInt64Params(objectbox.Alias("since"), events[3].Date))
+		ids, err := query.FindIds()
+		assert.NoErr(t, err)
+		assert.Eq(t, 2, len(ids))
+		assert.Eq(t, true, ids[0] > ids[1])
+		assert.NoErr(t, query.Close())
+	}
+
+	// additional conditions
+	query, err := since.Query(readingBox.Box, iot.Reading_.EventId.Equals(events[4].Id))
+	assert.NoErr(t, err)
+	count, err := query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	// a property missing or of a different type
+	var byDevice = objectbox.NewQueryTemplate(func(fields *objectbox.TemplateFields) []objectbox.Condition {
+		return []objectbox.Condition{fields.String("Device").Equals("device 1", true)}
+	})
+	_, err = byDevice.Query(readingBox.Box)
+	assert.Err(t, err)
+	_, err = objectbox.NewQueryTemplate(func(fields *objectbox.TemplateFields) []objectbox.Condition {
+		return []objectbox.Condition{fields.String("Date").Equals("", true)}
+	}).Query(readingBox.Box)
+	assert.Err(t, err)
+}
+
 func TestQueryClose(t *testing.T) {
 	env := model.NewTestEnv(t)
 	defer env.Close()
@@ -911,6 +1544,52 @@ func TestQueryUint(t *testing.T) {
 	assert.Eq(t, uint64(1), count)
 }
 
+func TestQueryUint64Boundaries(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var E = model.Entity_
+	var values = []uint64{1, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64}
+	for _, value := range values {
+		var e = model.Entity47()
+		e.Uint64 = value
+		_, err := env.Box.Put(e)
+		assert.NoErr(t, err)
+	}
+
+	count, err := env.Box.Query(E.Uint64.GreaterThan(math.MaxInt64)).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	count, err = env.Box.Query(E.Uint64.Between(math.MaxInt64, math.MaxUint64)).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+
+	count, err = env.Box.Query(E.Uint64.In(1, math.MaxUint64)).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	// unsigned values must be ordered by their unsigned value
+	objects, err := env.Box.Query(E.Uint64.OrderAsc()).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, len(values), len(objects))
+	for i, value := range values {
+		assert.Eq(t, value, objects[i].Uint64)
+	}
+
+	var query = env.Box.Query(E.Uint64.Equals(0))
+	assert.NoErr(t, query.SetUint64Params(E.Uint64, math.MaxUint64))
+	count, err = query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	query = env.Box.Query(E.Uint64.In())
+	assert.NoErr(t, query.SetUint64ParamsIn(E.Uint64, math.MaxInt64+1, math.MaxUint64))
+	count, err = query.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+}
+
 func TestQueryNil(t *testing.T) {
 	env := model.NewTestEnv(t)
 	defer env.Close()
@@ -1223,3 +1902,340 @@ func matchAllEntityIds(t *testing.T, ids []uint64, items interface{}) {
 
 	assert.EqItems(t, ids, actualIds)
 }
+
+func TestQueryFindWithContext(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+	env.Populate(200)
+
+	var query = env.Box.Query()
+
+	objects, err := query.FindWithContext(context.Background())
+	assert.NoErr(t, err)
+	assert.Eq(t, 200, len(objects.([]*model.Entity)))
+
+	// an already cancelled context fails without reading anything
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	objects, err = query.FindWithContext(ctx)
+	assert.Eq(t, context.Canceled, err)
+	assert.True(t, objects == nil)
+
+	// cancelling during the visit aborts it early
+	objects, err = query.FindWithContext(&cancelledAfterStartContext{Context: context.Background()})
+	assert.Eq(t, context.Canceled, err)
+	assert.True(t, objects == nil)
+}
+
+func TestQueryFindAsync(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+	env.Populate(200)
+
+	var query = env.Box.Query()
+
+	results, cancel := query.FindAsync()
+	var result = <-results
+	assert.NoErr(t, result.Err)
+	assert.Eq(t, 200, len(result.Objects.([]*model.Entity)))
+	_, open := <-results
+	assert.True(t, !open)
+	cancel() // no-op after the result has been delivered
+
+	// cancelling right away may or may not be in time to stop the find
+	results, cancel = query.FindAsync()
+	cancel()
+	result = <-results
+	if result.Err != nil {
+		assert.Eq(t, context.Canceled, result.Err)
+	} else {
+		assert.Eq(t, 200, len(result.Objects.([]*model.Entity)))
+	}
+}
+
+// cancelledAfterStartContext is done from the beginning but only reports an error once it's been checked before
+type cancelledAfterStartContext struct {
+	context.Context
+	checked bool
+}
+
+func (ctx *cancelledAfterStartContext) Done() <-chan struct{} {
+	var done = make(chan struct{})
+	close(done)
+	return done
+}
+
+func (ctx *cancelledAfterStartContext) Err() error {
+	if !ctx.checked {
+		ctx.checked = true
+		return nil
+	}
+	return context.Canceled
+}
+
+func TestQueryHintResultCount(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(100)
+
+	var query = env.Box.Query(model.Entity_.Id.LessOrEqual(50))
+	assert.Eq(t, query, query.HintResultCount(50))
+
+	// the hint is used on the visitor path, e.g. with a context; it must not influence the result
+	found, err := query.FindWithContext(context.Background())
+	assert.NoErr(t, err)
+	assert.Eq(t, 50, len(found.([]*model.Entity)))
+	assert.Eq(t, 50, cap(found.([]*model.Entity)))
+
+	// an imprecise hint is fine too
+	found, err = query.HintResultCount(10).FindWithContext(context.Background())
+	assert.NoErr(t, err)
+	assert.Eq(t, 50, len(found.([]*model.Entity)))
+
+	found, err = query.HintResultCount(1000).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 50, len(found.([]*model.Entity)))
+}
+
+func TestQueryBytesDecoded(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(100)
+
+	var query = env.Box.Query(model.Entity_.Id.LessOrEqual(50))
+	assert.Eq(t, uint64(0), query.BytesDecoded())
+
+	found, err := query.Find()
+	assert.NoErr(t, err)
+	var decoded = query.BytesDecoded()
+	assert.True(t, decoded > 0)
+
+	// the counter is cumulative and counts the visitor path too
+	_, err = query.FindWithContext(context.Background())
+	assert.NoErr(t, err)
+	assert.Eq(t, 2*decoded, query.BytesDecoded())
+
+	// the estimated heap usage grows with the number of loaded objects
+	var size = objectbox.EstimateSize(found)
+	assert.True(t, size > 0)
+
+	all, err := env.Box.GetAll()
+	assert.NoErr(t, err)
+	assert.True(t, objectbox.EstimateSize(all) > size)
+	assert.Eq(t, uint64(0), objectbox.EstimateSize(nil))
+}
+
+func TestQueryUpdateProperties(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityEnum(env.ObjectBox)
+	_, err := box.PutMany([]*model.TestEntityEnum{
+		{Status: model.StatusActive, Color: model.ColorRed},
+		{Status: model.StatusActive, Color: model.ColorGreen},
+		{Status: model.StatusNew, Color: model.ColorGreen},
+	})
+	assert.NoErr(t, err)
+
+	var query = box.Query(model.TestEntityEnum_.Color.Equals(string(model.ColorGreen), true))
+	count, err := query.UpdateProperties(map[objectbox.Property]interface{}{
+		model.TestEntityEnum_.Status: model.StatusArchived,
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	objects, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, model.StatusActive, objects[0].Status)
+	assert.Eq(t, model.StatusArchived, objects[1].Status)
+	assert.Eq(t, model.StatusArchived, objects[2].Status)
+	assert.Eq(t, model.ColorGreen, objects[2].Color)
+
+	// numbers are converted, nil sets the zero value
+	count, err = box.Query(model.TestEntityEnum_.Id.Equals(1)).UpdateProperties(map[objectbox.Property]interface{}{
+		model.TestEntityEnum_.Status: 2,
+		model.TestEntityEnum_.Color:  nil,
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+	read, err := box.Get(1)
+	assert.NoErr(t, err)
+	assert.Eq(t, model.StatusArchived, read.Status)
+	assert.Eq(t, model.Color(""), read.Color)
+
+	// invalid values and properties of other entities
+	_, err = query.UpdateProperties(map[objectbox.Property]interface{}{model.TestEntityEnum_.Color: 1})
+	assert.Err(t, err)
+	_, err = query.UpdateProperties(map[objectbox.Property]interface{}{model.Entity_.Int: 1})
+	assert.Err(t, err)
+
+	// neither the ID nor relations
+	_, err = query.UpdateProperties(map[objectbox.Property]interface{}{model.TestEntityEnum_.Id: 10})
+	assert.Err(t, err)
+	_, err = env.Box.Query().UpdateProperties(map[objectbox.Property]interface{}{model.Entity_.Related: 1})
+	assert.Err(t, err)
+}
+
+func TestQueryRemoveChunked(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(100)
+
+	var query = env.Box.Query(model.Entity_.Id.GreaterThan(25))
+	var progress []uint64
+	removed, err := query.RemoveChunked(30, func(removed uint64) bool {
+		progress = append(progress, removed)
+		return true
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(75), removed)
+	assert.Eq(t, []uint64{30, 60, 75}, progress)
+
+	count, err := env.Box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(25), count)
+
+	// stop after the first chunk
+	var all = env.Box.Query()
+	removed, err = all.RemoveChunked(10, func(removed uint64) bool { return false })
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), removed)
+
+	// the limit is reset afterwards
+	count, err = all.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(15), count)
+
+	// ... to the one set before
+	all.Limit(3)
+	removed, err = all.RemoveChunked(10, func(removed uint64) bool { return false })
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), removed)
+	count, err = all.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+
+	_, err = query.RemoveChunked(0, nil)
+	assert.Err(t, err)
+}
+
+func TestQueryTrace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	var traces []objectbox.QueryTrace
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		TraceQueries(func(trace objectbox.QueryTrace) {
+			traces = append(traces, trace)
+		}, func(entity, property string) bool {
+			return entity == "Entity" && property == "String"
+		}).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+	_, err = box.PutMany([]*model.Entity{{String: "secret", Int: 47}, {String: "public", Int: 47}})
+	assert.NoErr(t, err)
+
+	var E = model.Entity_
+	found, err := box.Query(E.String.Equals("secret", true), E.Int.Equals(47)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(found))
+
+	count, err := box.Query(E.Int.Equals(47)).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	assert.Eq(t, 2, len(traces))
+	assert.Eq(t, "Query.Find", traces[0].Operation)
+	assert.Eq(t, "Entity", traces[0].Entity)
+	assert.Eq(t, `(String == ? AND Int == 47)`, traces[0].Description)
+	assert.Eq(t, uint64(1), traces[0].Count)
+	assert.NoErr(t, traces[0].Err)
+	assert.True(t, !strings.Contains(fmt.Sprint(traces), "secret"))
+	assert.Eq(t, "Query.Count", traces[1].Operation)
+	assert.Eq(t, `Int == 47`, traces[1].Description)
+	assert.Eq(t, uint64(2), traces[1].Count)
+}
+
+func TestSlowLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	var entries []objectbox.SlowOperation
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		SlowLog(time.Nanosecond, func(entry objectbox.SlowOperation) {
+			entries = append(entries, entry)
+		}).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+	_, err = box.Put(&model.Entity{String: "secret", Int: 47})
+	assert.NoErr(t, err)
+
+	entries = nil
+	found, err := box.Query(model.Entity_.String.Equals("secret", true)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(found))
+
+	// the query is reported last, after a read transaction it may have used internally
+	var entry = entries[len(entries)-1]
+	assert.Eq(t, "Query.Find", entry.Operation)
+	assert.Eq(t, "Entity", entry.Entity)
+	assert.Eq(t, `String == ?`, entry.Description)
+	assert.True(t, entry.Duration > 0)
+	assert.True(t, strings.Contains(entry.Stack, "TestSlowLog"))
+
+	entries = nil
+	assert.NoErr(t, ob.RunInWriteTx(func() error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}))
+	assert.Eq(t, 1, len(entries))
+	assert.Eq(t, "WriteTx", entries[0].Operation)
+	assert.True(t, entries[0].Duration >= time.Millisecond)
+}
+
+func TestQueryExplain(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityEnum(env.ObjectBox)
+	var E = model.TestEntityEnum_
+
+	query, err := box.QueryOrError(E.Color.Equals("red", true), E.Status.Equals(int(model.StatusActive)))
+	assert.NoErr(t, err)
+
+	description, err := query.Describe()
+	assert.NoErr(t, err)
+	assert.True(t, !strings.Contains(description, "red"))
+
+	plan, err := query.Explain()
+	assert.NoErr(t, err)
+	assert.Eq(t, `(Color == "red" AND Status == 1)`, plan.Description)
+	assert.True(t, !plan.FullScan)
+	assert.Eq(t, []objectbox.QueryPlanProperty{
+		{Name: "Color", Known: true},
+		{Name: "Status", Indexed: true, Known: true},
+	}, plan.Properties)
+
+	query, err = box.QueryOrError(E.Color.Equals("red", true))
+	assert.NoErr(t, err)
+	plan, err = query.Explain()
+	assert.NoErr(t, err)
+	assert.True(t, plan.FullScan)
+	assert.Eq(t, "Color == \"red\"\nfull scan: none of the conditions can use an index\nColor: not indexed", plan.String())
+
+	query, err = box.QueryOrError(E.Id.GreaterThan(1))
+	assert.NoErr(t, err)
+	plan, err = query.Explain()
+	assert.NoErr(t, err)
+	assert.True(t, !plan.FullScan)
+	assert.True(t, plan.Properties[0].Id)
+}
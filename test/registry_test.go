@@ -0,0 +1,317 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestGetOrOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	var builds = 0
+	var builderFn = func() *objectbox.Builder {
+		builds++
+		return objectbox.NewBuilder().Model(iot.ObjectBoxModel())
+	}
+
+	ob1, err := objectbox.GetOrOpen(dir, builderFn)
+	assert.NoErr(t, err)
+
+	// a different spelling of the same directory must resolve to the same store
+	ob2, err := objectbox.GetOrOpen(filepath.Join(dir, "."), builderFn)
+	assert.NoErr(t, err)
+	assert.True(t, ob1 == ob2)
+	assert.Eq(t, 1, builds)
+
+	var box = iot.BoxForEvent(ob1)
+	_, err = box.Put(&iot.Event{Device: "shared"})
+	assert.NoErr(t, err)
+
+	// releasing one reference keeps the store open for the other user
+	ob1.Close()
+	count, err := iot.BoxForEvent(ob2).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	// the last reference closes the store so the next call opens a new one
+	ob2.Close()
+	ob3, err := objectbox.GetOrOpen(dir, builderFn)
+	assert.NoErr(t, err)
+	defer ob3.Close()
+	assert.True(t, ob3 != ob1)
+	assert.Eq(t, 2, builds)
+}
+
+func TestGetOrOpenCloseWithTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	var builderFn = func() *objectbox.Builder {
+		return objectbox.NewBuilder().Model(iot.ObjectBoxModel())
+	}
+
+	ob1, err := objectbox.GetOrOpen(dir, builderFn)
+	assert.NoErr(t, err)
+	ob2, err := objectbox.GetOrOpen(dir, builderFn)
+	assert.NoErr(t, err)
+
+	// releases just one reference
+	assert.NoErr(t, ob1.CloseWithTimeout(time.Second))
+	_, err = iot.BoxForEvent(ob2).Count()
+	assert.NoErr(t, err)
+
+	// the last reference stays registered while a running transaction keeps the store open
+	var started = make(chan struct{})
+	var release = make(chan struct{})
+	var finished = make(chan error)
+	go func() {
+		finished <- ob2.RunInReadTx(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	_, isTimeout := ob2.CloseWithTimeout(10 * time.Millisecond).(*objectbox.CloseTimeoutError)
+	assert.True(t, isTimeout)
+	_, err = objectbox.GetOrOpen(dir, builderFn)
+	assert.Eq(t, objectbox.ErrStoreClosing, err)
+
+	close(release)
+	assert.NoErr(t, <-finished)
+
+	// Close() finishes the closing and releases the reference
+	ob2.Close()
+	ob3, err := objectbox.GetOrOpen(dir, builderFn)
+	assert.NoErr(t, err)
+	defer ob3.Close()
+	assert.True(t, ob3 != ob2)
+}
+
+func TestStoreRegistry(t *testing.T) {
+	var registry = objectbox.NewStoreRegistry()
+	defer registry.CloseAll()
+
+	var builds = 0
+	var builderFn = func(name string) func() *objectbox.Builder {
+		return func() *objectbox.Builder {
+			builds++
+			return objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory("memory:registry-" + name)
+		}
+	}
+
+	obA, err := registry.Open("a", builderFn("a"))
+	assert.NoErr(t, err)
+	obA2, err := registry.Open("a", builderFn("a"))
+	assert.NoErr(t, err)
+	assert.True(t, obA == obA2)
+	assert.Eq(t, 1, builds)
+
+	obB, err := registry.Open("b", builderFn("b"))
+	assert.NoErr(t, err)
+	assert.True(t, obA != obB)
+	assert.Eq(t, 2, builds)
+	assert.Eq(t, []string{"a", "b"}, registry.Names())
+	assert.True(t, registry.Get("a") == obA)
+	assert.True(t, registry.Get("c") == nil)
+
+	// the stores are independent
+	_, err = iot.BoxForEvent(obA).Put(&iot.Event{Device: "a"})
+	assert.NoErr(t, err)
+	count, err := iot.BoxForEvent(obB).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), count)
+
+	assert.True(t, registry.Close("b"))
+	assert.True(t, !registry.Close("b"))
+	assert.Eq(t, []string{"a"}, registry.Names())
+
+	// a store closed directly is dropped from the registry
+	obA.Close()
+	assert.True(t, registry.Get("a") == nil)
+	assert.Eq(t, 0, len(registry.Names()))
+
+	_, err = registry.Open("x", nil)
+	assert.Err(t, err)
+}
+
+func TestObjectBoxClone(t *testing.T) {
+	var env = iot.NewTestEnv()
+	defer env.Close()
+
+	iot.PutEvents(env.ObjectBox, 3)
+
+	clone, err := env.ObjectBox.Clone()
+	assert.NoErr(t, err)
+
+	count, err := iot.BoxForEvent(clone).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+
+	_, err = iot.BoxForEvent(clone).Put(&iot.Event{Device: "clone"})
+	assert.NoErr(t, err)
+
+	// closing the clone keeps the original open
+	clone.Close()
+	count, err = iot.BoxForEvent(env.ObjectBox).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(4), count)
+
+	_, err = clone.Clone()
+	assert.Err(t, err)
+}
+
+func TestLockInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	ob, err := objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory(dir).BuildOrError()
+	assert.NoErr(t, err)
+
+	info, err := ob.LockInfo()
+	assert.NoErr(t, err)
+	assert.True(t, info != nil)
+	assert.Eq(t, os.Getpid(), info.PID)
+
+	// closing removes the record
+	ob.Close()
+	var lockFile = filepath.Join(dir, "objectbox-lock.json")
+	_, err = os.Stat(lockFile)
+	assert.True(t, os.IsNotExist(err))
+
+	// a record left behind, e.g. by a crashed process, doesn't prevent opening
+	assert.NoErr(t, ioutil.WriteFile(lockFile, []byte(`{"pid":42,"host":"other-host"}`), 0644))
+	ob, err = objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory(dir).BuildOrError()
+	assert.NoErr(t, err)
+	ob.Close()
+
+	ob, err = objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory("memory:lock-info").BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+	info, err = ob.LockInfo()
+	assert.NoErr(t, err)
+	assert.True(t, info == nil)
+}
+
+func TestLockedByAnotherProcess(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	// the store is opened by a helper process running TestLockHelperProcess
+	var helper = exec.Command(os.Args[0], "-test.run=^TestLockHelperProcess$")
+	helper.Env = append(os.Environ(), lockHelperDirEnv+"="+dir)
+	release, err := helper.StdinPipe()
+	assert.NoErr(t, err)
+	output, err := helper.StdoutPipe()
+	assert.NoErr(t, err)
+	assert.NoErr(t, helper.Start())
+	defer helper.Wait()
+	defer release.Close()
+
+	line, err := bufio.NewReader(output).ReadString('\n')
+	assert.NoErr(t, err)
+	assert.Eq(t, "open\n", line)
+
+	var start = time.Now()
+	_, err = objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory(dir).
+		WaitForWritableLock(200 * time.Millisecond).BuildOrError()
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+	lockedErr, isLocked := err.(*objectbox.StoreLockedError)
+	assert.True(t, isLocked)
+	assert.Eq(t, helper.Process.Pid, lockedErr.Holder.PID)
+	assert.True(t, strings.Contains(err.Error(), "PID "+strconv.Itoa(helper.Process.Pid)))
+
+	// fall back to read-only
+	ob, err := objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory(dir).OpenIfLockedReadOnly().BuildOrError()
+	assert.NoErr(t, err)
+	info, err := ob.LockInfo()
+	assert.NoErr(t, err)
+	assert.Eq(t, helper.Process.Pid, info.PID)
+	count, err := iot.BoxForEvent(ob).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+	_, err = iot.BoxForEvent(ob).Put(&iot.Event{Device: "read-only"})
+	assert.Err(t, err)
+	ob.Close()
+
+	// the lock is detected without the lock info record as well, which is only used to report the holder
+	assert.NoErr(t, os.Remove(filepath.Join(dir, "objectbox-lock.json")))
+	start = time.Now()
+	_, err = objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory(dir).
+		WaitForWritableLock(200 * time.Millisecond).BuildOrError()
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+	lockedErr, isLocked = err.(*objectbox.StoreLockedError)
+	assert.True(t, isLocked)
+	assert.True(t, lockedErr.Holder == nil)
+
+	// wait for the helper to release the lock
+	assert.NoErr(t, release.Close())
+	ob, err = objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory(dir).
+		WaitForWritableLock(10 * time.Second).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+	info, err = ob.LockInfo()
+	assert.NoErr(t, err)
+	assert.Eq(t, os.Getpid(), info.PID)
+}
+
+// lockHelperDirEnv passes the store directory to TestLockHelperProcess
+const lockHelperDirEnv = "OBJECTBOX_TEST_LOCK_HELPER_DIR"
+
+// TestLockHelperProcess isn't a test: it holds a store open for TestLockedByAnotherProcess until stdin is closed
+func TestLockHelperProcess(t *testing.T) {
+	var dir = os.Getenv(lockHelperDirEnv)
+	if dir == "" {
+		t.Skip("only run as a helper process")
+	}
+
+	ob, err := objectbox.NewBuilder().Model(iot.ObjectBoxModel()).Directory(dir).BuildOrError()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	_, err = iot.BoxForEvent(ob).Put(&iot.Event{Device: "helper"})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println("open")
+
+	_, _ = ioutil.ReadAll(os.Stdin)
+	ob.Close()
+	os.Exit(0)
+}
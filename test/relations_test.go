@@ -17,10 +17,12 @@
 package objectbox_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/objectbox/objectbox-go/test/assert"
 	"github.com/objectbox/objectbox-go/test/model"
+	"github.com/objectbox/objectbox-go/test/model/iot"
 )
 
 func TestRelationsInsert(t *testing.T) {
@@ -259,3 +261,272 @@ func TestRelationsMissing(t *testing.T) {
 	assert.True(t, 0 == len(read.RelatedSlice))
 	assert.True(t, nil == read.RelatedPtrSlice)
 }
+
+func TestQueryEager(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var shared = &model.TestEntityRelated{Name: "Shared", NextSlice: []model.EntityByValue{}}
+	_, err := env.Box.PutMany([]*model.Entity{
+		{RelatedPtrSlice: []*model.TestEntityRelated{shared, {Name: "A", NextSlice: []model.EntityByValue{}}}},
+		{RelatedPtrSlice: []*model.TestEntityRelated{shared}},
+		{},
+	})
+	assert.NoErr(t, err)
+
+	// without Eager(), the lazy relation isn't loaded
+	objects, err := env.Box.Query().Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(objects))
+	assert.True(t, objects[0].RelatedPtrSlice == nil)
+
+	var query = env.Box.Query()
+	query.Eager(model.Entity_.RelatedPtrSlice)
+	objects, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(objects))
+	assert.Eq(t, 2, len(objects[0].RelatedPtrSlice))
+	assert.Eq(t, "Shared", objects[0].RelatedPtrSlice[0].Name)
+	assert.Eq(t, "A", objects[0].RelatedPtrSlice[1].Name)
+	assert.Eq(t, 1, len(objects[1].RelatedPtrSlice))
+	assert.Eq(t, shared.Id, objects[1].RelatedPtrSlice[0].Id)
+	assert.Eq(t, 0, len(objects[2].RelatedPtrSlice))
+
+	// the result is the same as fetching the relation explicitly
+	var fetched, _ = env.Box.GetAll()
+	assert.NoErr(t, env.Box.FetchRelatedPtrSlice(fetched...))
+	assert.Eq(t, fetched[0].RelatedPtrSlice, objects[0].RelatedPtrSlice)
+
+	// the field is found by the relation, not by its type: RelatedSlice is loaded by the binding anyway
+	query = env.Box.Query()
+	query.Eager(model.Entity_.RelatedSlice, model.Entity_.RelatedPtrSlice, model.Entity_.RelatedPtr)
+	objects, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(objects[0].RelatedPtrSlice))
+	assert.Eq(t, 0, len(objects[0].RelatedSlice))
+
+	// a relation of another entity is rejected
+	query = env.Box.Query()
+	query.Eager(model.TestEntityRelated_.NextSlice)
+	_, err = query.Find()
+	assert.Err(t, err)
+}
+
+func TestQueryEagerToOne(t *testing.T) {
+	var env = iot.NewTestEnv()
+	defer env.Close()
+
+	var events = iot.PutEvents(env.ObjectBox, 2)
+	var box = iot.BoxForReading(env.ObjectBox)
+	_, err := box.PutMany([]*iot.Reading{
+		{EventId: events[0].Id, ValueName: "first"},
+		{EventId: events[1].Id, ValueName: "second"},
+		{EventId: events[0].Id, ValueName: "third"},
+		{ValueName: "none"},
+	})
+	assert.NoErr(t, err)
+
+	// without Eager(), only the ID is set
+	readings, err := box.Query(iot.Reading_.Id.OrderAsc()).Find()
+	assert.NoErr(t, err)
+	assert.True(t, readings[0].Event == nil)
+
+	var query = box.Query(iot.Reading_.Id.OrderAsc())
+	query.Eager(iot.Reading_.EventId)
+	readings, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 4, len(readings))
+	assert.Eq(t, events[0].Id, readings[0].Event.Id)
+	assert.Eq(t, events[0].Device, readings[0].Event.Device)
+	assert.Eq(t, events[1].Id, readings[1].Event.Id)
+	assert.True(t, readings[0].Event == readings[2].Event) // loaded only once
+	assert.True(t, readings[3].Event == nil)
+
+	// a relation of another entity is rejected
+	query = box.Query()
+	query.Eager(model.Entity_.RelatedPtr)
+	_, err = query.Find()
+	assert.Err(t, err)
+}
+
+func TestQueryLinkIdProperty(t *testing.T) {
+	var env = iot.NewTestEnv()
+	defer env.Close()
+
+	var events = iot.PutEvents(env.ObjectBox, 3)
+	var readingBox = iot.BoxForReading(env.ObjectBox)
+	for i, event := range events {
+		for j := 0; j <= i; j++ {
+			_, err := readingBox.Put(&iot.Reading{EventId: event.Id, ValueName: fmt.Sprintf("reading %d/%d", i, j)})
+			assert.NoErr(t, err)
+		}
+	}
+
+	// readings of a device, in a single query instead of finding the event IDs first
+	readings, err := readingBox.Query(iot.Reading_.EventId.Link(iot.Event_.Device.Equals("device 2", true))).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(readings))
+	assert.Eq(t, events[1].Id, readings[0].EventId)
+	assert.Eq(t, events[1].Id, readings[1].EventId)
+
+	// and the other way around: events with a matching reading (a backlink)
+	found, err := iot.BoxForEvent(env.ObjectBox).Query(
+		iot.Reading_.EventId.Link(iot.Reading_.ValueName.HasPrefix("reading 2/", true))).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(found))
+	assert.Eq(t, "device 3", found[0].Device)
+}
+
+func TestBoxBacklinks(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var shared = &model.TestEntityRelated{Name: "Shared", NextSlice: []model.EntityByValue{}}
+	var single = &model.TestEntityRelated{Name: "Single", NextSlice: []model.EntityByValue{}}
+	ids, err := env.Box.PutMany([]*model.Entity{
+		{String: "first", RelatedPtrSlice: []*model.TestEntityRelated{shared, single}},
+		{String: "second", RelatedPtrSlice: []*model.TestEntityRelated{shared}},
+		{String: "third"},
+	})
+	assert.NoErr(t, err)
+
+	backlinkIds, err := env.Box.BacklinkIds(model.Entity_.RelatedPtrSlice, shared.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, ids[0:2], backlinkIds)
+
+	backlinkIds, err = env.Box.BacklinkIds(model.Entity_.RelatedPtrSlice, single.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, ids[0:1], backlinkIds)
+
+	sources, err := env.Box.Backlinks(model.Entity_.RelatedPtrSlice, single.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(sources.([]*model.Entity)))
+	assert.Eq(t, "first", sources.([]*model.Entity)[0].String)
+
+	// to-one relations
+	var iotEnv = iot.NewTestEnv()
+	defer iotEnv.Close()
+
+	var events = iot.PutEvents(iotEnv.ObjectBox, 2)
+	var readingBox = iot.BoxForReading(iotEnv.ObjectBox)
+	readingIds, err := readingBox.PutMany([]*iot.Reading{{EventId: events[1].Id}, {EventId: events[0].Id}, {EventId: events[1].Id}})
+	assert.NoErr(t, err)
+
+	backlinkIds, err = readingBox.PropertyBacklinkIds(iot.Reading_.EventId, events[1].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{readingIds[0], readingIds[2]}, backlinkIds)
+
+	readings, err := readingBox.Backlinks(iot.Reading_.EventId, events[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(readings.([]*iot.Reading)))
+	assert.Eq(t, readingIds[1], readings.([]*iot.Reading)[0].Id)
+
+	_, err = readingBox.Backlinks(iot.Reading_.EventId.Property, events[0].Id)
+	assert.Err(t, err)
+}
+
+func TestBoxRelationIdsMany(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var shared = &model.TestEntityRelated{Name: "Shared", NextSlice: []model.EntityByValue{}}
+	var single = &model.TestEntityRelated{Name: "Single", NextSlice: []model.EntityByValue{}}
+	ids, err := env.Box.PutMany([]*model.Entity{
+		{String: "first", RelatedPtrSlice: []*model.TestEntityRelated{shared, single}},
+		{String: "second", RelatedPtrSlice: []*model.TestEntityRelated{shared}},
+		{String: "third"},
+	})
+	assert.NoErr(t, err)
+
+	targetIds, err := env.Box.RelationIdsMany(model.Entity_.RelatedPtrSlice, ids)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(targetIds))
+	assert.Eq(t, []uint64{shared.Id, single.Id}, targetIds[ids[0]])
+	assert.Eq(t, []uint64{shared.Id}, targetIds[ids[1]])
+	assert.Eq(t, 0, len(targetIds[ids[2]]))
+
+	// the same as asking for each source separately
+	for _, id := range ids {
+		expected, err := env.Box.RelationIds(model.Entity_.RelatedPtrSlice, id)
+		assert.NoErr(t, err)
+		assert.Eq(t, len(expected), len(targetIds[id]))
+	}
+
+	targetIds, err = env.Box.RelationIdsMany(model.Entity_.RelatedPtrSlice, nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(targetIds))
+}
+
+func TestBoxRelationReplaceKnown(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var a = &model.TestEntityRelated{Name: "A", NextSlice: []model.EntityByValue{}}
+	var b = &model.TestEntityRelated{Name: "B", NextSlice: []model.EntityByValue{}}
+	id, err := env.Box.Put(&model.Entity{String: "source", RelatedPtrSlice: []*model.TestEntityRelated{a, b}})
+	assert.NoErr(t, err)
+
+	// replace B with C and a duplicate A, inserting C, without reading the previous targets
+	var c = &model.TestEntityRelated{Name: "C", NextSlice: []model.EntityByValue{}}
+	assert.NoErr(t, env.Box.RelationReplaceKnown(model.Entity_.RelatedPtrSlice, id, []uint64{a.Id, b.Id},
+		[]*model.TestEntityRelated{a, c, a}))
+	assert.True(t, c.Id != 0)
+
+	targetIds, err := env.Box.RelationIds(model.Entity_.RelatedPtrSlice, id)
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{a.Id, c.Id}, targetIds)
+
+	// nil is only accepted if there were no previous targets
+	assert.Err(t, env.Box.RelationReplaceKnown(model.Entity_.RelatedPtrSlice, id, targetIds,
+		[]*model.TestEntityRelated(nil)))
+
+	assert.NoErr(t, env.Box.RelationReplaceKnown(model.Entity_.RelatedPtrSlice, id, targetIds,
+		[]*model.TestEntityRelated{}))
+	targetIds, err = env.Box.RelationIds(model.Entity_.RelatedPtrSlice, id)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(targetIds))
+}
+
+func TestBoxRelationObjects(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var relation = model.Entity_.RelatedPtrSlice
+	var source = &model.Entity{String: "source"}
+	var a = &model.TestEntityRelated{Name: "A", NextSlice: []model.EntityByValue{}}
+	var b = &model.TestEntityRelated{Name: "B", NextSlice: []model.EntityByValue{}}
+
+	// the source must be stored first
+	assert.Err(t, env.Box.RelationAdd(relation, source, a))
+
+	_, err := env.Box.Put(source)
+	assert.NoErr(t, err)
+
+	// new targets are inserted
+	assert.NoErr(t, env.Box.RelationAdd(relation, source, a, b))
+	assert.True(t, a.Id != 0 && b.Id != 0)
+
+	targets, err := env.Box.RelationTargets(relation, source)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(targets.([]*model.TestEntityRelated)))
+
+	assert.NoErr(t, env.Box.RelationRemoveTargets(relation, source, a))
+	targets, err = env.Box.RelationTargets(relation, source)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(targets.([]*model.TestEntityRelated)))
+	assert.Eq(t, b.Id, targets.([]*model.TestEntityRelated)[0].Id)
+
+	// the target object is kept
+	count, err := model.BoxForTestEntityRelated(env.ObjectBox).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	assert.NoErr(t, env.Box.RelationSet(relation, source, []*model.TestEntityRelated{a}))
+	targetIds, err := env.Box.RelationIds(relation, source.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{a.Id}, targetIds)
+
+	// objects of the wrong type and relations of another entity are rejected
+	assert.Err(t, env.Box.RelationAdd(relation, source, source))
+	assert.Err(t, model.BoxForTestEntityRelated(env.ObjectBox).RelationAdd(relation, source, a))
+}
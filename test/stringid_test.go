@@ -18,6 +18,7 @@ package objectbox_test
 
 import (
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/objectbox/objectbox-go/test/assert"
@@ -112,4 +113,17 @@ func TestSelfAssignedId(t *testing.T) {
 	assert.Eq(t, "10", objects[1].Id)
 	assert.Eq(t, uint64(1), ids[0])
 	assert.Eq(t, uint64(10), ids[1])
+
+	// a single object with an ID way above the current ID sequence
+	id, err := box.Put(&model.TestStringIdEntity{Id: "1000000"})
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1000000), id)
+	object, err := box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "1000000", object.Id)
+
+	// entities without a self-assignable ID don't accept such IDs
+	_, err = model.BoxForEntity(env.ObjectBox).Put(&model.Entity{Id: 1000000})
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "id(assignable)"))
 }
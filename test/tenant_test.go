@@ -0,0 +1,90 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestBoxScoped(t *testing.T) {
+	var env = iot.NewTestEnv()
+	defer env.Close()
+
+	var box = iot.BoxForEvent(env.ObjectBox)
+
+	// the tenant ID must match the property type
+	_, err := box.Scoped(iot.Event_.Device, 1)
+	assert.Err(t, err)
+	// a property of another entity
+	_, err = box.Scoped(iot.Reading_.EventId, 1)
+	assert.Err(t, err)
+
+	a, err := box.Scoped(iot.Event_.Device, "a")
+	assert.NoErr(t, err)
+	b, err := box.Scoped(iot.Event_.Device, "b")
+	assert.NoErr(t, err)
+
+	idA, err := a.Put(&iot.Event{Device: "a", Uid: "1"})
+	assert.NoErr(t, err)
+	_, err = a.PutMany([]*iot.Event{{Device: "a", Uid: "2"}, {Device: "a", Uid: "3"}})
+	assert.NoErr(t, err)
+	idB, err := b.Put(&iot.Event{Device: "b", Uid: "4"})
+	assert.NoErr(t, err)
+
+	// writes for another tenant are rejected
+	_, err = a.Put(&iot.Event{Device: "b"})
+	assert.Err(t, err)
+	_, err = a.Put(&iot.Event{Id: idB, Device: "a", Uid: "4"})
+	assert.Err(t, err)
+	_, err = a.PutMany([]*iot.Event{{Device: "a", Uid: "5"}, {Device: "b", Uid: "6"}})
+	assert.Err(t, err)
+
+	count, err := a.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+	count, err = b.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	object, err := a.Get(idA)
+	assert.NoErr(t, err)
+	assert.Eq(t, "1", object.(*iot.Event).Uid)
+	object, err = a.Get(idB)
+	assert.NoErr(t, err)
+	assert.True(t, object == nil)
+
+	events, err := a.Query(iot.Event_.Uid.Equals("2", true)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(events.([]*iot.Event)))
+	events, err = a.Query(iot.Event_.Uid.Equals("4", true)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(events.([]*iot.Event)))
+
+	assert.Err(t, a.RemoveId(idB))
+	assert.NoErr(t, a.RemoveId(idA))
+
+	removed, err := a.RemoveAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), removed)
+
+	count, err = box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+}
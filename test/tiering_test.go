@@ -0,0 +1,115 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTiering(t *testing.T) {
+	primary := iot.NewTestEnv()
+	defer primary.Close()
+	archive := iot.NewTestEnv()
+	defer archive.Close()
+
+	// samples dated 10001 to 10010 (milliseconds)
+	var samples []*iot.Sample
+	for i := 1; i <= 10; i++ {
+		samples = append(samples, &iot.Sample{Id: uint64(i), Device: "device " + strconv.Itoa(i), Date: int64(10000 + i)})
+	}
+	_, err := iot.BoxForSample(primary.ObjectBox).PutMany(samples)
+	assert.NoErr(t, err)
+
+	tiering, err := objectbox.NewTiering(primary.ObjectBox, archive.ObjectBox, iot.Sample_.Date)
+	assert.NoErr(t, err)
+	tiering.BatchSize(3)
+
+	// not a date property
+	_, err = objectbox.NewTiering(primary.ObjectBox, archive.ObjectBox, iot.Reading_.ValueInteger)
+	assert.Err(t, err)
+
+	// IDs not self-assignable
+	_, err = objectbox.NewTiering(primary.ObjectBox, archive.ObjectBox, iot.Event_.Date)
+	assert.Err(t, err)
+
+	var millis = func(value int64) time.Time {
+		return time.Unix(0, value*int64(time.Millisecond))
+	}
+
+	// simulate an interrupted move: the first sample is already archived but still present in the primary store
+	_, err = iot.BoxForSample(archive.ObjectBox).Put(&iot.Sample{Id: 1, Device: samples[0].Device, Date: samples[0].Date})
+	assert.NoErr(t, err)
+
+	moved, err := tiering.Archive(millis(10008))
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(7), moved)
+
+	count, err := iot.BoxForSample(primary.ObjectBox).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+	count, err = iot.BoxForSample(archive.ObjectBox).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(7), count)
+
+	// the IDs are kept
+	archived, err := iot.BoxForSample(archive.ObjectBox).Get(7)
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(10007), archived.Date)
+
+	var dates = func(objects interface{}) []int64 {
+		var result []int64
+		for _, sample := range objects.([]*iot.Sample) {
+			result = append(result, sample.Date)
+		}
+		return result
+	}
+
+	// across both stores
+	objects, err := tiering.Find(millis(10006), millis(10009))
+	assert.NoErr(t, err)
+	assert.Eq(t, []int64{10006, 10007, 10008, 10009}, dates(objects))
+
+	// only the primary store
+	objects, err = tiering.Find(millis(10009), millis(20000))
+	assert.NoErr(t, err)
+	assert.Eq(t, []int64{10009, 10010}, dates(objects))
+
+	// with additional conditions
+	objects, err = tiering.Find(millis(0), millis(20000), iot.Sample_.Device.HasSuffix("0", true))
+	assert.NoErr(t, err)
+	assert.Eq(t, []int64{10010}, dates(objects))
+
+	// a backdated object in the primary store doesn't hide the archived ones
+	_, err = iot.BoxForSample(primary.ObjectBox).Put(&iot.Sample{Id: 11, Device: "backdated", Date: 10001})
+	assert.NoErr(t, err)
+	objects, err = tiering.Find(millis(10005), millis(10009))
+	assert.NoErr(t, err)
+	assert.Eq(t, []int64{10005, 10006, 10007, 10008, 10009}, dates(objects))
+
+	// objects present in both stores are returned once, merged by the date
+	_, err = iot.BoxForSample(primary.ObjectBox).Put(&iot.Sample{Id: 6, Device: "restored", Date: 10006})
+	assert.NoErr(t, err)
+	objects, err = tiering.Find(millis(0), millis(10008))
+	assert.NoErr(t, err)
+	assert.Eq(t, []int64{10001, 10001, 10002, 10003, 10004, 10005, 10006, 10007, 10008}, dates(objects))
+	assert.Eq(t, "restored", objects.([]*iot.Sample)[6].Device)
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018-2024 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+)
+
+// treeModel defines the entities storing a tree, see objectbox.Tree
+func treeModel() *objectbox.Model {
+	const typeShort, typeLong, typeDouble, typeString, typeRelation = 3, 6, 8, 9, 11
+	const flagId, flagIndexed, flagUnique, flagIndexPartialSkipZero = 1, 8, 32, 512
+
+	var m = objectbox.NewModel()
+	m.GeneratorVersion(6)
+
+	m.Entity("DataBranch", 1, 1001)
+	m.Property("Id", typeLong, 1, 1101)
+	m.PropertyFlags(flagId)
+	m.Property("Uid", typeString, 2, 1102)
+	m.PropertyFlags(flagIndexed | flagUnique)
+	m.PropertyIndex(1, 1201)
+	m.Property("ParentId", typeRelation, 3, 1103)
+	m.PropertyFlags(flagIndexed | flagIndexPartialSkipZero)
+	m.PropertyRelation("DataBranch", 2, 1202)
+	m.Property("MetaBranchId", typeRelation, 4, 1104)
+	m.PropertyFlags(flagIndexed | flagIndexPartialSkipZero)
+	m.PropertyRelation("MetaBranch", 3, 1203)
+	m.EntityLastPropertyId(4, 1104)
+
+	m.Entity("MetaBranch", 2, 1002)
+	m.Property("Id", typeLong, 1, 2101)
+	m.PropertyFlags(flagId)
+	m.Property("ParentId", typeRelation, 2, 2102)
+	m.PropertyFlags(flagIndexed | flagIndexPartialSkipZero)
+	m.PropertyRelation("MetaBranch", 4, 1204)
+	m.Property("Name", typeString, 3, 2103)
+	m.Property("Description", typeString, 4, 2104)
+	m.EntityLastPropertyId(4, 2104)
+
+	m.Entity("DataLeaf", 3, 1003)
+	m.Property("Id", typeLong, 1, 3101)
+	m.PropertyFlags(flagId)
+	m.Property("DataBranchId", typeRelation, 2, 3102)
+	m.PropertyFlags(flagIndexed | flagIndexPartialSkipZero)
+	m.PropertyRelation("DataBranch", 5, 1205)
+	m.Property("MetaLeafId", typeRelation, 3, 3103)
+	m.PropertyFlags(flagIndexed | flagIndexPartialSkipZero)
+	m.PropertyRelation("MetaLeaf", 6, 1206)
+	m.Property("ValueInt", typeLong, 4, 3104)
+	m.Property("ValueDouble", typeDouble, 5, 3105)
+	m.Property("ValueString", typeString, 6, 3106)
+	m.EntityLastPropertyId(6, 3106)
+
+	m.Entity("MetaLeaf", 4, 1004)
+	m.Property("Id", typeLong, 1, 4101)
+	m.PropertyFlags(flagId)
+	m.Property("BranchId", typeRelation, 2, 4102)
+	m.PropertyFlags(flagIndexed | flagIndexPartialSkipZero)
+	m.PropertyRelation("MetaBranch", 7, 1207)
+	m.Property("Name", typeString, 3, 4103)
+	m.Property("Description", typeString, 4, 4104)
+	m.Property("ValueType", typeShort, 5, 4105)
+	m.EntityLastPropertyId(5, 4105)
+
+	m.LastEntityId(4, 1004)
+	m.LastIndexId(7, 1207)
+	return m
+}
+
+func TestTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	defer os.RemoveAll(dir)
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(treeModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	tree, err := objectbox.NewTree(ob, objectbox.TreeOptions{})
+	assert.NoErr(t, err)
+	defer tree.Close()
+
+	id, err := tree.Put("device/sensor/threshold", 42)
+	assert.NoErr(t, err)
+	assert.True(t, id > 0)
+	_, err = tree.Put("device/sensor/unit", "celsius")
+	assert.NoErr(t, err)
+	_, err = tree.Put("device/gain", 0.5)
+	assert.NoErr(t, err)
+
+	leaf, err := tree.Get("device/sensor/threshold")
+	assert.NoErr(t, err)
+	assert.Eq(t, id, leaf.Id)
+	assert.Eq(t, int64(42), leaf.Value)
+
+	// update an existing leaf
+	_, err = tree.Put("device/sensor/threshold", 50)
+	assert.NoErr(t, err)
+	leaf, err = tree.Get("device/sensor/threshold")
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(50), leaf.Value)
+
+	leaf, err = tree.Get("device/sensor/missing")
+	assert.NoErr(t, err)
+	assert.True(t, leaf == nil)
+
+	path, err := tree.LeafPath(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "device/sensor/threshold", path)
+
+	leaves, err := tree.Leaves("device/sensor")
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(leaves))
+
+	leaves, err = tree.Leaves("")
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(leaves))
+	assert.Eq(t, "device/gain", leaves[0].Path) // ordered by depth
+	assert.Eq(t, 0.5, leaves[0].Value)
+
+	removed, err := tree.Remove("device/sensor/unit")
+	assert.NoErr(t, err)
+	assert.True(t, removed)
+	removed, err = tree.Remove("device/sensor/unit")
+	assert.NoErr(t, err)
+	assert.True(t, !removed)
+
+	_, err = tree.Put("device/invalid", []int{1})
+	assert.Err(t, err)
+}
@@ -20,7 +20,9 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/objectbox/objectbox-go/objectbox"
 	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
 	"github.com/objectbox/objectbox-go/test/model/iot"
 )
 
@@ -93,3 +95,147 @@ func TestTransactionRollback(t *testing.T) {
 	assert.Eq(t, 0, int(count))
 
 }
+
+func TestTransactionExplicit(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var box = iot.BoxForEvent(env.ObjectBox)
+
+	assert.NoErr(t, box.RemoveAll())
+
+	// aborted tx
+	tx, err := env.BeginTx()
+	assert.NoErr(t, err)
+	assert.True(t, tx.IsActive())
+	assert.True(t, !tx.IsReadOnly())
+	_, err = box.Put(&iot.Event{})
+	assert.NoErr(t, err)
+	assert.NoErr(t, tx.Abort())
+	assert.True(t, !tx.IsActive())
+	assert.NoErr(t, tx.Abort()) // no-op
+	assert.Err(t, tx.Commit())
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), count)
+
+	// committed tx, with the work split across functions
+	tx, err = env.BeginTx()
+	assert.NoErr(t, err)
+	var putInTx = func(tx *objectbox.Tx) error {
+		eventBox, err := tx.Box(iot.EventBinding.Id)
+		if err != nil {
+			return err
+		}
+		_, err = eventBox.Put(&iot.Event{})
+		return err
+	}
+	assert.NoErr(t, putInTx(tx))
+	assert.NoErr(t, putInTx(tx))
+	assert.NoErr(t, tx.Commit())
+	assert.True(t, !tx.IsActive())
+	_, err = tx.Box(iot.EventBinding.Id)
+	assert.Err(t, err)
+
+	// read tx
+	tx, err = env.BeginReadTx()
+	assert.NoErr(t, err)
+	assert.True(t, tx.IsReadOnly())
+	count, err = box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+	assert.NoErr(t, tx.Commit())
+}
+
+func TestTransactionMultiEntity(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var eventBox = iot.BoxForEvent(env.ObjectBox)
+	var readingBox = iot.BoxForReading(env.ObjectBox)
+
+	var event = &iot.Event{Device: "sensor"}
+	assert.NoErr(t, env.ObjectBox.RunInWriteTxMulti(func(boxes objectbox.TxBoxSet) error {
+		if _, err := boxes.Put(event); err != nil {
+			return err
+		}
+		box, err := boxes.BoxFor(iot.Reading{})
+		if err != nil {
+			return err
+		}
+		_, err = box.Put(&iot.Reading{EventId: event.Id, ValueName: "temperature"})
+		return err
+	}))
+
+	eventCount, err := eventBox.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), eventCount)
+	readingCount, err := readingBox.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), readingCount)
+
+	// all or nothing
+	var expectedErr = errors.New("invariant violated")
+	err = env.ObjectBox.RunInWriteTxMulti(func(boxes objectbox.TxBoxSet) error {
+		if _, err := boxes.Put(&iot.Reading{EventId: event.Id, ValueName: "humidity"}); err != nil {
+			return err
+		}
+		if err := boxes.Remove(event); err != nil {
+			return err
+		}
+		return expectedErr
+	})
+	assert.Eq(t, expectedErr, err)
+
+	eventCount, err = eventBox.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), eventCount)
+	readingCount, err = readingBox.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), readingCount)
+
+	assert.Err(t, env.ObjectBox.RunInWriteTxMulti(func(boxes objectbox.TxBoxSet) error {
+		_, err := boxes.BoxFor("not an entity")
+		return err
+	}))
+}
+
+func TestWithIdentityMap(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var related = &model.TestEntityRelated{Name: "shared"}
+	var id = env.PutEntity(&model.Entity{RelatedPtr: related, RelatedPtr2: related})
+	var relatedBox = model.BoxForTestEntityRelated(env.ObjectBox)
+
+	assert.NoErr(t, env.ObjectBox.WithIdentityMap(func() error {
+		first, err := env.Box.Get(id)
+		assert.NoErr(t, err)
+		second, err := env.Box.Get(id)
+		assert.NoErr(t, err)
+		assert.True(t, first == second)
+
+		// relation targets are decoded once as well
+		assert.True(t, first.RelatedPtr == first.RelatedPtr2)
+		target, err := relatedBox.Get(related.Id)
+		assert.NoErr(t, err)
+		assert.True(t, target == first.RelatedPtr)
+
+		// nested calls share the identity map
+		return env.ObjectBox.WithIdentityMap(func() error {
+			third, err := env.Box.Get(id)
+			assert.NoErr(t, err)
+			assert.True(t, first == third)
+			return nil
+		})
+	}))
+
+	// outside, each read decodes a new object
+	first, err := env.Box.Get(id)
+	assert.NoErr(t, err)
+	second, err := env.Box.Get(id)
+	assert.NoErr(t, err)
+	assert.True(t, first != second)
+	assert.True(t, first.RelatedPtr != first.RelatedPtr2)
+}